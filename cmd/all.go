@@ -8,7 +8,9 @@ func init() {
 	cmdObjectComplianceDetach := newCmdObjectComplianceDetach(kind)
 	cmdObjectComplianceShow := newCmdObjectComplianceShow(kind)
 	cmdObjectComplianceList := newCmdObjectComplianceList(kind)
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
@@ -21,11 +23,14 @@ func init() {
 	)
 	cmdObject.AddCommand(
 		cmdObjectCompliance,
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectPrint,
 		cmdObjectPush,
 		cmdObjectSet,
 		cmdObjectSync,
+		cmdObjectValidate,
 		newCmdObjectAbort(kind),
 		newCmdObjectClear(kind),
 		newCmdObjectCreate(kind),
@@ -37,14 +42,15 @@ func init() {
 		newCmdObjectGiveback(kind),
 		newCmdObjectLogs(kind),
 		newCmdObjectLs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectPurge(kind),
 		newCmdObjectProvision(kind),
 		newCmdObjectPRStart(kind),
 		newCmdObjectPRStop(kind),
+		newCmdObjectReload(kind),
 		newCmdObjectRestart(kind),
 		newCmdObjectRun(kind),
 		newCmdObjectShutdown(kind),
+		newCmdObjectSnooze(kind),
 		newCmdObjectStart(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectStop(kind),
@@ -55,9 +61,17 @@ func init() {
 		newCmdObjectUnprovision(kind),
 		newCmdObjectUnset(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectSet.AddCommand(
 		newCmdObjectSetProvisioned(kind),
 		newCmdObjectSetUnprovisioned(kind),
@@ -4,7 +4,9 @@ func init() {
 	kind := "ccfg"
 
 	cmdObject := newCmdCcfg()
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
@@ -14,7 +16,9 @@ func init() {
 		cmdObject,
 	)
 	cmdObject.AddCommand(
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectSet,
 		cmdObjectPrint,
 		cmdObjectValidate,
@@ -25,13 +29,20 @@ func init() {
 		newCmdObjectGet(kind),
 		newCmdObjectLogs(kind),
 		newCmdObjectLs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectUnset(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectPrint.AddCommand(
 		cmdObjectPrintConfig,
 		newCmdObjectPrintStatus(kind),
@@ -4,17 +4,22 @@ func init() {
 	kind := "cfg"
 
 	cmdObject := newCmdCfg()
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
 	cmdObjectValidate := newCmdObjectValidate(kind)
+	cmdKeystoreRotate := newCmdKeystoreRotate(kind)
 
 	root.AddCommand(
 		cmdObject,
 	)
 	cmdObject.AddCommand(
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectSet,
 		cmdObjectPrint,
 		cmdObjectValidate,
@@ -22,6 +27,8 @@ func init() {
 		newCmdKeystoreChange(kind),
 		newCmdKeystoreDecode(kind),
 		newCmdKeystoreKeys(kind),
+		newCmdKeystorePrune(kind),
+		cmdKeystoreRotate,
 		newCmdKeystoreInstall(kind),
 		newCmdKeystoreRemove(kind),
 		newCmdObjectCreate(kind),
@@ -31,13 +38,20 @@ func init() {
 		newCmdObjectGet(kind),
 		newCmdObjectLs(kind),
 		newCmdObjectLogs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectUnset(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectPrint.AddCommand(
 		cmdObjectPrintConfig,
 		newCmdObjectPrintStatus(kind),
@@ -45,6 +59,9 @@ func init() {
 	cmdObjectPrintConfig.AddCommand(
 		newCmdObjectPrintConfigMtime(kind),
 	)
+	cmdKeystoreRotate.AddCommand(
+		newCmdKeystoreRotateKey(kind),
+	)
 	cmdObjectValidate.AddCommand(
 		newCmdObjectValidateConfig(kind),
 	)
@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var (
+	cmdCluster = &cobra.Command{
+		Use:   "cluster",
+		Short: "Manage the cluster",
+	}
+
+	cmdClusterPlan = &cobra.Command{
+		Use:   "plan",
+		Short: "orchestrate an action on a selection of objects, in dependency-aware waves",
+	}
+)
+
+func init() {
+	root.AddCommand(
+		cmdCluster,
+	)
+	cmdCluster.AddCommand(
+		cmdClusterPlan,
+		newCmdClusterSimulate(),
+	)
+	cmdClusterPlan.AddCommand(
+		newCmdClusterPlanAbort(),
+		newCmdClusterPlanResume(),
+		newCmdClusterPlanStart(),
+		newCmdClusterPlanStatus(),
+	)
+}
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/spf13/cobra"
 
 	"opensvc.com/opensvc/core/commands"
@@ -127,6 +129,102 @@ func newCmdArrayLs() *cobra.Command {
 	return cmd
 }
 
+func newCmdClusterPlanAbort() *cobra.Command {
+	var options commands.CmdClusterPlanAbort
+	cmd := &cobra.Command{
+		Use:   "abort <id>",
+		Short: "abort the orchestrations of a running plan",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.ID = args[0]
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
+func newCmdClusterPlanResume() *cobra.Command {
+	var options commands.CmdClusterPlanResume
+	cmd := &cobra.Command{
+		Use:   "resume <id>",
+		Short: "resume a failed, aborted or interrupted plan from its first wave not done",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			options.ID = args[0]
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagPlanWaveTimeout(flags, &options.WaveTimeout)
+	return cmd
+}
+
+func newCmdClusterPlanStart() *cobra.Command {
+	var options commands.CmdClusterPlanStart
+	cmd := &cobra.Command{
+		Use:   "start",
+		Short: "create and run a plan",
+		Long: "Order the selected objects in waves, parents before children and smaller priorities first, " +
+			"and orchestrate the action on the objects of each wave in parallel. " +
+			"A wave starts when all objects of the previous wave reached the action target state. " +
+			"The stop action orders the children before their parents and the greater priorities first.\n\n" +
+			"The plan stops on the first wave with a failed object. " +
+			"Use 'om cluster plan resume <id>' to retry from this wave.\n\n" +
+			"The plan is stored on the local node only: follow, abort and resume it from this node.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagPlanAction(flags, &options.Action)
+	addFlagSwitchTo(flags, &options.To)
+	addFlagPlanWaveTimeout(flags, &options.WaveTimeout)
+	return cmd
+}
+
+func newCmdClusterPlanStatus() *cobra.Command {
+	var options commands.CmdClusterPlanStatus
+	cmd := &cobra.Command{
+		Use:   "status [<id>]",
+		Short: "show the plans, or the waves progress of a plan",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) > 0 {
+				options.ID = args[0]
+			}
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
+func newCmdClusterSimulate() *cobra.Command {
+	var options commands.CmdClusterSimulate
+	cmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "show the orchestration decisions after a hypothetical change, without executing anything",
+		Long: "Apply a hypothetical change to the current cluster data and show, for each selected object, " +
+			"the resulting leader, candidate order and the action each instance monitor would plan. " +
+			"All objects are simulated if no object selector is specified.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagSimulateFlexTarget(flags, &options.FlexTarget)
+	addFlagSimulateNodesDown(flags, &options.NodesDown)
+	addFlagSimulateNodesFrozen(flags, &options.NodesFrozen)
+	addFlagSimulatePlacement(flags, &options.Placement)
+	return cmd
+}
+
 func newCmdDaemonAuth() *cobra.Command {
 	var options commands.CmdDaemonAuth
 	cmd := &cobra.Command{
@@ -269,6 +367,7 @@ func newCmdDaemonStats() *cobra.Command {
 	}
 	flagSet := cmd.Flags()
 	addFlagsGlobal(flagSet, &options.OptsGlobal)
+	addFlagHeartbeats(flagSet, &options.Heartbeats)
 	return cmd
 }
 
@@ -301,7 +400,8 @@ func newCmdKeystoreAdd(kind string) *cobra.Command {
 	addFlagKey(flags, &options.Key)
 	addFlagFrom(flags, &options.From)
 	addFlagValue(flags, &options.Value)
-	cmd.MarkFlagsMutuallyExclusive("from", "value")
+	addFlagKeyRef(flags, &options.Ref)
+	cmd.MarkFlagsMutuallyExclusive("from", "value", "ref")
 	return cmd
 }
 
@@ -319,7 +419,8 @@ func newCmdKeystoreChange(kind string) *cobra.Command {
 	addFlagKey(flags, &options.Key)
 	addFlagFrom(flags, &options.From)
 	addFlagValue(flags, &options.Value)
-	cmd.MarkFlagsMutuallyExclusive("from", "value")
+	addFlagKeyRef(flags, &options.Ref)
+	cmd.MarkFlagsMutuallyExclusive("from", "value", "ref")
 	return cmd
 }
 
@@ -366,6 +467,50 @@ func newCmdKeystoreKeys(kind string) *cobra.Command {
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
 	addFlagMatch(flags, &options.Match)
+	addFlagKeyVersions(flags, &options.Versions)
+	return cmd
+}
+
+func newCmdKeystorePrune(kind string) *cobra.Command {
+	var options commands.CmdKeystorePrune
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "remove the key versions replaced by the active version",
+		Long:  "Remove the versions of the rotated keys activated before their active version. The versions pending activation are kept.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagMatch(flags, &options.Match)
+	return cmd
+}
+
+func newCmdKeystoreRotate(kind string) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rotate",
+		Short: "key rotation command group",
+	}
+}
+
+func newCmdKeystoreRotateKey(kind string) *cobra.Command {
+	var options commands.CmdKeystoreRotateKey
+	cmd := &cobra.Command{
+		Use:   "key",
+		Short: "add a new version of an existing key",
+		Long:  "Add a new version of an existing key, activated now or at the --at time. The consumers of the key follow the active version. The previous versions are kept until pruned.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagKey(flags, &options.Key)
+	addFlagFrom(flags, &options.From)
+	addFlagValue(flags, &options.Value)
+	addFlagKeyActivateAt(flags, &options.At)
+	cmd.MarkFlagsMutuallyExclusive("from", "value")
 	return cmd
 }
 
@@ -532,6 +677,7 @@ func newCmdNodeComplianceAttachModuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -549,6 +695,7 @@ func newCmdNodeComplianceAttachRuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -565,6 +712,7 @@ func newCmdNodeComplianceAuto() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -584,6 +732,7 @@ func newCmdNodeComplianceCheck() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -602,6 +751,7 @@ func newCmdNodeComplianceFix() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -621,6 +771,7 @@ func newCmdNodeComplianceFixable() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -641,6 +792,7 @@ func newCmdNodeComplianceDetachModuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -658,6 +810,7 @@ func newCmdNodeComplianceDetachRuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -674,6 +827,7 @@ func newCmdNodeComplianceEnv() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagModule(flags, &options.Module)
 	return cmd
@@ -691,6 +845,7 @@ func newCmdNodeComplianceListModules() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	return cmd
 }
 
@@ -706,6 +861,7 @@ func newCmdNodeComplianceListModuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -722,6 +878,7 @@ func newCmdNodeComplianceListRuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -738,6 +895,7 @@ func newCmdNodeComplianceShowModuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -754,6 +912,7 @@ func newCmdNodeComplianceShowRuleset() *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	return cmd
 }
 
@@ -794,7 +953,9 @@ func newCmdNodeDrain() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "drain",
 		Short: "freeze node and shutdown all its object instances",
-		Long:  "If not specified with --node, the local node is selected for drain.",
+		Long: "If not specified with --node, the local node is selected for drain.\n\n" +
+			"With --evacuate, the failover objects running on the node are first switched to the peer node selected by their placement policy, " +
+			"with at most node.evacuation_concurrency switches in parallel. The switch progress is reported in the node monitor.",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return options.Run()
 		},
@@ -802,6 +963,7 @@ func newCmdNodeDrain() *cobra.Command {
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
 	addFlagsAsync(flags, &options.OptsAsync)
+	addFlagEvacuate(flags, &options.Evacuate)
 	return cmd
 }
 
@@ -953,6 +1115,40 @@ func newCmdNodeLs() *cobra.Command {
 	return cmd
 }
 
+func newCmdNodeMaintenanceEnter() *cobra.Command {
+	var options commands.CmdNodeMaintenanceEnter
+	cmd := &cobra.Command{
+		Use:   "enter",
+		Short: "declare the node in maintenance",
+		Long: "The node keeps running its object instances, but the peer nodes don't take over its objects " +
+			"while the maintenance is active, even if all heartbeats from the node are stale, " +
+			"and the node is not a candidate for the objects placement.\n\n" +
+			"With --expire, the node automatically leaves the maintenance after the specified duration. " +
+			"Without --expire, the peer nodes preserve the data of an unreachable node in maintenance until it rejoins.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagMaintenanceExpire(flags, &options.Expire)
+	return cmd
+}
+
+func newCmdNodeMaintenanceLeave() *cobra.Command {
+	var options commands.CmdNodeMaintenanceLeave
+	cmd := &cobra.Command{
+		Use:   "leave",
+		Short: "declare the node maintenance ended",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
 func newCmdNodePrintCapabilities() *cobra.Command {
 	var options commands.CmdNodePrintCapabilities
 	cmd := &cobra.Command{
@@ -1119,15 +1315,58 @@ func newCmdNodeSysreport() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:     "sysreport",
 		Short:   "collect system data and push it to the collector",
-		Long:    "Push system report to the collector for archiving and diff analysis. The --force option resend all monitored files and outputs to the collector instead of only those that changed since the last sysreport.",
+		Long:    "Keep a local snapshot of the system report and push it to the collector, if configured, for archiving and diff analysis. The --force option resend all monitored files and outputs to the collector instead of only those that changed since the last sysreport. The --diff and --show options browse the local snapshots without collecting.",
 		Aliases: []string{"sysrepor", "sysrepo", "sysrep", "sysre", "sysr", "sys", "sy"},
-		RunE: func(cmd *cobra.Command, args []string) error {
+		Args:    cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// support the "--diff <since>" form, where pflag sees <since>
+			// as a positional argument because the flag value is optional.
+			if len(args) > 0 {
+				if options.Diff != commands.SysreportDiffPrevious {
+					return fmt.Errorf("unexpected argument: %s", args[0])
+				}
+				options.Diff = args[0]
+			}
 			return options.Run()
 		},
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
 	addFlagForce(flags, &options.Force)
+	addFlagSysreportDiff(flags, &options.Diff)
+	addFlagSysreportShow(flags, &options.Show)
+	cmd.MarkFlagsMutuallyExclusive("diff", "show")
+	return cmd
+}
+
+func newCmdNodeTemplateList() *cobra.Command {
+	var options commands.CmdNodeTemplateList
+	cmd := &cobra.Command{
+		Use:     "list",
+		Short:   "list the object configuration templates of the catalog",
+		Aliases: []string{"lis", "li", "ls", "l"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
+func newCmdNodeTemplateShow() *cobra.Command {
+	var options commands.CmdNodeTemplateShow
+	cmd := &cobra.Command{
+		Use:     "show",
+		Short:   "show an object configuration template and its parameters",
+		Aliases: []string{"sho", "sh", "s"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagTemplate(flags, &options.Template)
 	return cmd
 }
 
@@ -1180,6 +1419,26 @@ func newCmdNodeUnset() *cobra.Command {
 	return cmd
 }
 
+func newCmdNodeUpgrade() *cobra.Command {
+	var options commands.CmdNodeUpgrade
+	cmd := &cobra.Command{
+		Use:   "upgrade",
+		Short: "upgrade the agent on the cluster nodes, one node at a time",
+		Long: "Upgrade the agent on the cluster nodes, one node at a time, in the cluster.nodes order.\n\n" +
+			"Each node runs the node.upgrade_command, optionally after switching its failover objects to peer nodes " +
+			"(node.upgrade_evacuate), then restarts its daemon. The next node waits for the upgraded node to rejoin " +
+			"the cluster with a compatible heartbeat compat version. The orchestration aborts on the first failure.\n\n" +
+			"With --local, only run the node.upgrade_command on the local node.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run()
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagsAsync(flags, &options.OptsAsync)
+	return cmd
+}
+
 func newCmdNodeValidateConfig() *cobra.Command {
 	var options commands.CmdNodeValidateConfig
 	cmd := &cobra.Command{
@@ -1355,6 +1614,7 @@ func newCmdObjectComplianceAttachModuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -1372,6 +1632,7 @@ func newCmdObjectComplianceAttachRuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -1388,6 +1649,7 @@ func newCmdObjectComplianceAuto(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -1407,6 +1669,7 @@ func newCmdObjectComplianceCheck(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -1425,6 +1688,7 @@ func newCmdObjectComplianceFix(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -1444,6 +1708,7 @@ func newCmdObjectComplianceFixable(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModule(flags, &options.Module)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagComplianceAttach(flags, &options.Attach)
@@ -1464,6 +1729,7 @@ func newCmdObjectComplianceDetachModuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -1481,6 +1747,7 @@ func newCmdObjectComplianceDetachRuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -1497,6 +1764,7 @@ func newCmdObjectComplianceEnv(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	addFlagModule(flags, &options.Module)
 	return cmd
@@ -1514,6 +1782,7 @@ func newCmdObjectComplianceListModules(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	return cmd
 }
 
@@ -1529,6 +1798,7 @@ func newCmdObjectComplianceListModuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -1545,6 +1815,7 @@ func newCmdObjectComplianceListRuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagRuleset(flags, &options.Ruleset)
 	return cmd
 }
@@ -1561,6 +1832,7 @@ func newCmdObjectComplianceShowModuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
 	addFlagModuleset(flags, &options.Moduleset)
 	return cmd
 }
@@ -1577,6 +1849,73 @@ func newCmdObjectComplianceShowRuleset(kind string) *cobra.Command {
 	}
 	flags := cmd.Flags()
 	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagComplianceSource(flags, &options.Source)
+	return cmd
+}
+
+func newCmdObjectConfig(kind string) *cobra.Command {
+	return &cobra.Command{
+		Use:     "config",
+		Short:   "configuration revision history command group",
+		Aliases: []string{"confi", "conf", "cf"},
+	}
+}
+
+func newCmdObjectConfigDiff(kind string) *cobra.Command {
+	var options commands.CmdObjectConfigDiff
+	cmd := &cobra.Command{
+		Use:   "diff <rev1> <rev2>",
+		Short: "show the changes between two configuration revisions",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if options.From, err = parseRevision(args[0]); err != nil {
+				return err
+			}
+			if options.To, err = parseRevision(args[1]); err != nil {
+				return err
+			}
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
+func newCmdObjectConfigHistory(kind string) *cobra.Command {
+	var options commands.CmdObjectConfigHistory
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "list the configuration revisions, with their author, date and command",
+		Aliases: []string{"histor", "histo", "hist", "his", "log"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	return cmd
+}
+
+func newCmdObjectConfigRollback(kind string) *cobra.Command {
+	var options commands.CmdObjectConfigRollback
+	cmd := &cobra.Command{
+		Use:   "rollback <rev>",
+		Short: "install the configuration of a revision",
+		Long:  "The installed configuration is validated and recorded as a new revision, and propagated to the peer nodes like any other configuration change.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var err error
+			if options.Revision, err = parseRevision(args[0]); err != nil {
+				return err
+			}
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagsLock(flags, &options.OptsLock)
 	return cmd
 }
 
@@ -1597,6 +1936,8 @@ func newCmdObjectCreate(kind string) *cobra.Command {
 	addFlagCreateNamespace(flags, &options.Namespace)
 	addFlagCreateRestore(flags, &options.Restore)
 	addFlagKeywords(flags, &options.Keywords)
+	addFlagTemplate(flags, &options.Template)
+	addFlagTemplateParams(flags, &options.Params)
 	addFlagEnv(flags, &options.Env)
 	addFlagInteractive(flags, &options.Interactive)
 	addFlagProvision(flags, &options.Provision)
@@ -1764,6 +2105,24 @@ func newCmdObjectMonitor(kind string) *cobra.Command {
 	return cmd
 }
 
+func newCmdObjectMonitorHistory(kind string) *cobra.Command {
+	var options commands.CmdObjectMonitorHistory
+	cmd := &cobra.Command{
+		Use:     "history",
+		Short:   "list the instance monitor state and expect transitions, with their date, node and trigger",
+		Aliases: []string{"histor", "histo", "hist", "his"},
+		Long: "Each node records the transitions of its instances monitor. " +
+			"The transitions are fetched from all the object nodes, unless --local is set, and merged in date order.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagMonitorHistorySince(flags, &options.Since)
+	return cmd
+}
+
 func newCmdObjectPrintConfig(kind string) *cobra.Command {
 	var options commands.CmdObjectPrintConfig
 	cmd := &cobra.Command{
@@ -1778,6 +2137,8 @@ func newCmdObjectPrintConfig(kind string) *cobra.Command {
 	addFlagsGlobal(flags, &options.OptsGlobal)
 	addFlagEval(flags, &options.Eval)
 	addFlagImpersonate(flags, &options.Impersonate)
+	addFlagRevision(flags, &options.Revision)
+	cmd.MarkFlagsMutuallyExclusive("eval", "revision")
 	return cmd
 }
 
@@ -1957,6 +2318,24 @@ func newCmdObjectPushResInfo(kind string) *cobra.Command {
 	return cmd
 }
 
+func newCmdObjectReload(kind string) *cobra.Command {
+	var options commands.CmdObjectReload
+	cmd := &cobra.Command{
+		Use:   "reload",
+		Short: "apply the resources reload policy",
+		Long:  "Send the reload signal or execute the reload command of the selected app resources, as configured by their reload_policy keyword. The daemon executes this action when a sec or cfg key consumed by an app resource changes.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagsLock(flags, &options.OptsLock)
+	addFlagsResourceSelector(flags, &options.OptsResourceSelector)
+	addFlagDryRun(flags, &options.DryRun)
+	return cmd
+}
+
 func newCmdObjectRestart(kind string) *cobra.Command {
 	var options commands.CmdObjectRestart
 	cmd := &cobra.Command{
@@ -2090,6 +2469,24 @@ func newCmdObjectShutdown(kind string) *cobra.Command {
 	return cmd
 }
 
+func newCmdObjectSnooze(kind string) *cobra.Command {
+	var options commands.CmdObjectSnooze
+	cmd := &cobra.Command{
+		Use:   "snooze",
+		Short: "silence the task status alert",
+		Long:  "Silence the status alert of the selected task resources, for example while the failure of their last run is investigated. A zero duration ends the snooze.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return options.Run(selectorFlag, kind)
+		},
+	}
+	flags := cmd.Flags()
+	addFlagsGlobal(flags, &options.OptsGlobal)
+	addFlagsLock(flags, &options.OptsLock)
+	addFlagsResourceSelector(flags, &options.OptsResourceSelector)
+	addFlagSnoozeDuration(flags, &options.Duration)
+	return cmd
+}
+
 func newCmdObjectStart(kind string) *cobra.Command {
 	var options commands.CmdObjectStart
 	cmd := &cobra.Command{
@@ -2257,7 +2654,7 @@ func newCmdObjectValidateConfig(kind string) *cobra.Command {
 	var options commands.CmdObjectValidateConfig
 	cmd := &cobra.Command{
 		Use:     "config",
-		Short:   "verify the object configuration syntax",
+		Short:   "verify the object configuration syntax and semantic",
 		Aliases: []string{"confi", "conf", "con", "co", "c"},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return options.Run(selectorFlag, kind)
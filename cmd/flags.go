@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/spf13/pflag"
@@ -44,6 +46,10 @@ func addFlagComplianceAttach(flagSet *pflag.FlagSet, p *bool) {
 	flagSet.BoolVar(p, "attach", false, "Attach the modulesets selected for the compliance run.")
 }
 
+func addFlagComplianceSource(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "source", "collector", "The origin of the rulesets, modulesets and attachments: collector or local. The local source uses the <var>/compliance/rulesets/<name>.json and <var>/compliance/modulesets/<name>.json files, and the rulesets/<name> and modulesets/<name> keys of the cfg objects in the compliance namespace.")
+}
+
 func addFlagComplianceForce(flagSet *pflag.FlagSet, p *bool) {
 	flagSet.BoolVar(p, "force", false, "Don't check before fix.")
 }
@@ -105,6 +111,10 @@ func addFlagDuration(flagSet *pflag.FlagSet, p *time.Duration) {
 	flagSet.DurationVar(p, "duration", 0*time.Second, "duration.")
 }
 
+func addFlagSnoozeDuration(flagSet *pflag.FlagSet, p *time.Duration) {
+	flagSet.DurationVar(p, "duration", time.Hour, "Silence the status alert for this duration. A zero duration ends the snooze.")
+}
+
 func addFlagEnv(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "env", "", "Export the uppercased variable in the os environment. With the create action only, set a env section parameter in the service configuration file. Multiple `--env <key>=<val>` can be specified.")
 }
@@ -125,10 +135,27 @@ func addFlagForeground(flagSet *pflag.FlagSet, p *bool) {
 	flagSet.BoolVarP(p, "foreground", "f", false, "Restart the daemon in foreground mode.")
 }
 
+func addFlagEvacuate(flagSet *pflag.FlagSet, p *bool) {
+	flagSet.BoolVar(p, "evacuate", false, "Switch the failover objects to peer nodes before the freeze and shutdown.")
+}
+
+func addFlagHeartbeats(flagSet *pflag.FlagSet, p *bool) {
+	flagSet.BoolVar(p, "heartbeats", false, "Print the heartbeat latency, loss and flapping statistics per peer and per path.")
+}
+
 func addFlagForce(flagSet *pflag.FlagSet, p *bool) {
 	flagSet.BoolVar(p, "force", false, "Allow dangerous operations.")
 }
 
+func addFlagSysreportDiff(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "diff", "", "Show the changes of the collected files since the local sysreport snapshot taken at or before the date or duration ago (ex: 2d, 2006-01-02). Without value, show the changes since the previous snapshot.")
+	flagSet.Lookup("diff").NoOptDefVal = commands.SysreportDiffPrevious
+}
+
+func addFlagSysreportShow(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "show", "", "Show the content of a collected file or command output in the local sysreport snapshot taken at or before the date or duration ago, using the <path>[@<date>] format (ex: /etc/hosts@1d, cmd/lsmod@2006-01-02).")
+}
+
 func addFlagImpersonate(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "impersonate", "", "The name of a peer node to impersonate when evaluating keywords.")
 }
@@ -141,6 +168,18 @@ func addFlagKey(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "key", "", "A keystore key name.")
 }
 
+func addFlagKeyActivateAt(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "at", "", "The activation time of the key version, as a RFC3339 date, a '2006-01-02 15:04' local date or a duration from now. Default is now.")
+}
+
+func addFlagKeyRef(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "ref", "", "A reference to a secret in the external secret backend, resolved when the key is decoded or installed, using the vault://<path>[#<field>] format (sec only).")
+}
+
+func addFlagKeyVersions(flagSet *pflag.FlagSet, p *bool) {
+	flagSet.BoolVar(p, "versions", false, "List the key versions, with their activation time.")
+}
+
 func addFlagKeyword(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "kw", "", "A configuration keyword, [<section>].<option>.")
 }
@@ -169,10 +208,18 @@ func addFlagLogsSID(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "sid", "", "Filter on the session id of an action.")
 }
 
+func addFlagMaintenanceExpire(flagSet *pflag.FlagSet, p *time.Duration) {
+	flagSet.DurationVar(p, "expire", 0, "Leave the maintenance after this duration. The maintenance does not expire if not specified.")
+}
+
 func addFlagMatch(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "match", "**", "A fnmatch key name filter.")
 }
 
+func addFlagMonitorHistorySince(flagSet *pflag.FlagSet, p *time.Duration) {
+	flagSet.DurationVar(p, "since", 0, "Only list the transitions more recent than this duration. All the retained transitions are listed if not specified.")
+}
+
 func addFlagNetworkStatusName(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "name", "", "Filter on a network name.")
 }
@@ -197,6 +244,14 @@ func addFlagObjectSelector(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVarP(p, "service", "s", "", "An object selector expression. `**/s[12]+!*/vol/*`.")
 }
 
+func addFlagPlanAction(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "action", "", "The action to orchestrate on the selected objects: provision|start|stop|switch.")
+}
+
+func addFlagPlanWaveTimeout(flagSet *pflag.FlagSet, p *time.Duration) {
+	flagSet.DurationVar(p, "wave-timeout", 30*time.Minute, "Fail the plan if a wave does not complete within this duration. Zero disables the timeout.")
+}
+
 func addFlagPoolStatusName(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "name", "", "Filter on a pool name.")
 }
@@ -221,10 +276,31 @@ func addFlagRelay(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "relay", "", "The name of the relay to query. If not specified, all known relays are queried.")
 }
 
+func addFlagRevision(flagSet *pflag.FlagSet, p *int) {
+	flagSet.IntVar(p, "revision", 0, "The configuration revision id, as listed by the config history command.")
+}
+
+// parseRevision converts a configuration revision id command line argument.
+func parseRevision(s string) (int, error) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i < 1 {
+		return 0, fmt.Errorf("invalid revision %s: expected a positive integer", s)
+	}
+	return i, nil
+}
+
 func addFlagRID(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "rid", "", "Resource selector expression (ip#1,app,disk.type=zvol).")
 }
 
+func addFlagTemplate(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "template", "", "The name of a template of the local catalog, in <etc>/templates/<name>.conf or the template key of the templates/cfg/<name> object.")
+}
+
+func addFlagTemplateParams(flagSet *pflag.FlagSet, p *[]string) {
+	flagSet.StringSliceVar(p, "param", []string{}, "A template parameter value, <name>=<value>. Parameters not set use the default declared by the template.")
+}
+
 func addFlagTime(flagSet *pflag.FlagSet, p *time.Duration) {
 	flagSet.DurationVar(p, "time", 5*time.Minute, "Stop waiting for the object to reach the target state after a duration.")
 }
@@ -257,6 +333,22 @@ func addFlagRuleset(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "ruleset", "", "the rulesets to limit the action to. the special value `all` can be used in conjonction with detach.")
 }
 
+func addFlagSimulateFlexTarget(flagSet *pflag.FlagSet, p *int) {
+	flagSet.IntVar(p, "flex-target", -1, "Simulate this flex target on the selected flex objects.")
+}
+
+func addFlagSimulateNodesDown(flagSet *pflag.FlagSet, p *[]string) {
+	flagSet.StringSliceVar(p, "node-down", []string{}, "Simulate the loss of this node. Can be repeated or comma-separated.")
+}
+
+func addFlagSimulateNodesFrozen(flagSet *pflag.FlagSet, p *[]string) {
+	flagSet.StringSliceVar(p, "node-frozen", []string{}, "Simulate the freeze of this node. Can be repeated or comma-separated.")
+}
+
+func addFlagSimulatePlacement(flagSet *pflag.FlagSet, p *string) {
+	flagSet.StringVar(p, "placement", "", "Simulate this placement policy on the selected objects.")
+}
+
 func addFlagSubset(flagSet *pflag.FlagSet, p *string) {
 	flagSet.StringVar(p, "subset", "", "A subset selector expression (g1,g2).")
 }
@@ -35,6 +35,11 @@ var (
 		Short:   "show modules, modulesets, rulesets, modules, attachments",
 		Aliases: []string{"sho", "sh", "s"},
 	}
+	cmdNodeMaintenance = &cobra.Command{
+		Use:     "maintenance",
+		Short:   "declare the node maintenance to the cluster",
+		Aliases: []string{"maint"},
+	}
 	cmdNodePrint = &cobra.Command{
 		Use:     "print",
 		Short:   "print node discover information",
@@ -48,6 +53,11 @@ var (
 		Use:   "scan",
 		Short: "node discover",
 	}
+	cmdNodeTemplate = &cobra.Command{
+		Use:     "template",
+		Short:   "object configuration templates catalog commands",
+		Aliases: []string{"templat", "templa", "templ", "tpl"},
+	}
 	cmdNodeValidate = &cobra.Command{
 		Use:     "validate",
 		Short:   "validate the node config syntax",
@@ -93,9 +103,11 @@ func init() {
 	)
 	cmdNode.AddCommand(
 		cmdNodeEdit,
+		cmdNodeMaintenance,
 		cmdNodePrint,
 		cmdNodePush,
 		cmdNodeScan,
+		cmdNodeTemplate,
 		cmdNodeValidate,
 		newCmdNodeAbort(),
 		newCmdNodeChecks(),
@@ -115,6 +127,11 @@ func init() {
 		newCmdNodeSysreport(),
 		newCmdNodeUnfreeze(),
 		newCmdNodeUnset(),
+		newCmdNodeUpgrade(),
+	)
+	cmdNodeMaintenance.AddCommand(
+		newCmdNodeMaintenanceEnter(),
+		newCmdNodeMaintenanceLeave(),
 	)
 	cmdNodePrint.AddCommand(
 		newCmdNodePrintCapabilities(),
@@ -130,6 +147,10 @@ func init() {
 	cmdNodeScan.AddCommand(
 		newCmdNodeScanCapabilities(),
 	)
+	cmdNodeTemplate.AddCommand(
+		newCmdNodeTemplateList(),
+		newCmdNodeTemplateShow(),
+	)
 	cmdNodeValidate.AddCommand(
 		newCmdNodeValidateConfig(),
 	)
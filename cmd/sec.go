@@ -4,17 +4,22 @@ func init() {
 	kind := "sec"
 
 	cmdObject := newCmdSec()
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
 	cmdObjectValidate := newCmdObjectValidate(kind)
+	cmdKeystoreRotate := newCmdKeystoreRotate(kind)
 
 	root.AddCommand(
 		cmdObject,
 	)
 	cmdObject.AddCommand(
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectSet,
 		cmdObjectPrint,
 		cmdObjectValidate,
@@ -22,6 +27,8 @@ func init() {
 		newCmdKeystoreChange(kind),
 		newCmdKeystoreDecode(kind),
 		newCmdKeystoreKeys(kind),
+		newCmdKeystorePrune(kind),
+		cmdKeystoreRotate,
 		newCmdKeystoreInstall(kind),
 		newCmdKeystoreRemove(kind),
 		newCmdObjectCreate(kind),
@@ -31,16 +38,23 @@ func init() {
 		newCmdObjectGet(kind),
 		newCmdObjectLs(kind),
 		newCmdObjectLogs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectUnset(kind),
 		newCmdSecFullPEM(kind),
 		newCmdSecGenCert(kind),
 		newCmdSecPKCS(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectPrint.AddCommand(
 		cmdObjectPrintConfig,
 		newCmdObjectPrintStatus(kind),
@@ -48,6 +62,9 @@ func init() {
 	cmdObjectPrintConfig.AddCommand(
 		newCmdObjectPrintConfigMtime(kind),
 	)
+	cmdKeystoreRotate.AddCommand(
+		newCmdKeystoreRotateKey(kind),
+	)
 	cmdObjectValidate.AddCommand(
 		newCmdObjectValidateConfig(kind),
 	)
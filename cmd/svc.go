@@ -9,7 +9,9 @@ func init() {
 	cmdObjectComplianceDetach := newCmdObjectComplianceDetach(kind)
 	cmdObjectComplianceShow := newCmdObjectComplianceShow(kind)
 	cmdObjectComplianceList := newCmdObjectComplianceList(kind)
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
@@ -22,11 +24,14 @@ func init() {
 	)
 	cmdObject.AddCommand(
 		cmdObjectCompliance,
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectPrint,
 		cmdObjectPush,
 		cmdObjectSet,
 		cmdObjectSync,
+		cmdObjectValidate,
 		newCmdObjectAbort(kind),
 		newCmdObjectClear(kind),
 		newCmdObjectCreate(kind),
@@ -39,14 +44,15 @@ func init() {
 		newCmdObjectGiveback(kind),
 		newCmdObjectLogs(kind),
 		newCmdObjectLs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectPurge(kind),
 		newCmdObjectProvision(kind),
 		newCmdObjectPRStart(kind),
 		newCmdObjectPRStop(kind),
+		newCmdObjectReload(kind),
 		newCmdObjectRestart(kind),
 		newCmdObjectRun(kind),
 		newCmdObjectShutdown(kind),
+		newCmdObjectSnooze(kind),
 		newCmdObjectStart(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectStop(kind),
@@ -57,9 +63,17 @@ func init() {
 		newCmdObjectUnprovision(kind),
 		newCmdObjectUnset(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectSet.AddCommand(
 		newCmdObjectSetProvisioned(kind),
 		newCmdObjectSetUnprovisioned(kind),
@@ -4,7 +4,9 @@ func init() {
 	kind := "vol"
 
 	cmdObject := newCmdVol()
+	cmdObjectConfig := newCmdObjectConfig(kind)
 	cmdObjectEdit := newCmdObjectEdit(kind)
+	cmdObjectMonitor := newCmdObjectMonitor(kind)
 	cmdObjectSet := newCmdObjectSet(kind)
 	cmdObjectPrint := newCmdObjectPrint(kind)
 	cmdObjectPrintConfig := newCmdObjectPrintConfig(kind)
@@ -16,11 +18,14 @@ func init() {
 		cmdObject,
 	)
 	cmdObject.AddCommand(
+		cmdObjectConfig,
 		cmdObjectEdit,
+		cmdObjectMonitor,
 		cmdObjectPrint,
 		cmdObjectPush,
 		cmdObjectSet,
 		cmdObjectSync,
+		cmdObjectValidate,
 		newCmdObjectAbort(kind),
 		newCmdObjectClear(kind),
 		newCmdObjectCreate(kind),
@@ -33,14 +38,15 @@ func init() {
 		newCmdObjectGiveback(kind),
 		newCmdObjectLogs(kind),
 		newCmdObjectLs(kind),
-		newCmdObjectMonitor(kind),
 		newCmdObjectPurge(kind),
 		newCmdObjectProvision(kind),
 		newCmdObjectPRStart(kind),
 		newCmdObjectPRStop(kind),
+		newCmdObjectReload(kind),
 		newCmdObjectRestart(kind),
 		newCmdObjectRun(kind),
 		newCmdObjectShutdown(kind),
+		newCmdObjectSnooze(kind),
 		newCmdObjectStart(kind),
 		newCmdObjectStatus(kind),
 		newCmdObjectStop(kind),
@@ -51,9 +57,17 @@ func init() {
 		newCmdObjectUnprovision(kind),
 		newCmdObjectUnset(kind),
 	)
+	cmdObjectConfig.AddCommand(
+		newCmdObjectConfigDiff(kind),
+		newCmdObjectConfigHistory(kind),
+		newCmdObjectConfigRollback(kind),
+	)
 	cmdObjectEdit.AddCommand(
 		newCmdObjectEditConfig(kind),
 	)
+	cmdObjectMonitor.AddCommand(
+		newCmdObjectMonitorHistory(kind),
+	)
 	cmdObjectSet.AddCommand(
 		newCmdObjectSetProvisioned(kind),
 		newCmdObjectSetUnprovisioned(kind),
@@ -83,6 +83,12 @@ var (
 		Local:    true,
 		MustLock: true,
 	}
+	Snooze = Properties{
+		Name:     "snooze",
+		Local:    true,
+		MustLock: true,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+	}
 	Status = Properties{
 		Name:      "status",
 		PG:        true,
@@ -106,6 +112,11 @@ var (
 		Name:       "keys",
 		RelayToAny: true,
 	}
+	RollbackConfig = Properties{
+		Name:     "rollback_config",
+		Local:    true,
+		MustLock: true,
+	}
 	ValidateConfig = Properties{
 		Name:       "validate_config",
 		RelayToAny: true,
@@ -159,6 +170,13 @@ var (
 		Kinds:           []kind.T{kind.Svc, kind.Vol, kind.Usr, kind.Sec, kind.Cfg},
 		TimeoutKeywords: []string{"unprovision_timeout", "timeout"},
 	}
+	Reload = Properties{
+		Name:     "reload",
+		Local:    true,
+		MustLock: true,
+		Kinds:    []kind.T{kind.Svc, kind.Vol},
+		PG:       true,
+	}
 	Restart = Properties{
 		Name:            "restart",
 		Target:          "restarted",
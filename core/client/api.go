@@ -62,6 +62,10 @@ func (t T) NewGetObjectConfigFile() *api.GetObjectConfigFile {
 	return api.NewGetObjectConfigFile(t)
 }
 
+func (t T) NewGetObjectMonitorHistory() *api.GetObjectMonitorHistory {
+	return api.NewGetObjectMonitorHistory(t)
+}
+
 func (t T) NewGetObjectSelector() *api.GetObjectSelector {
 	return api.NewGetObjectSelector(t)
 }
@@ -94,6 +98,10 @@ func (t T) NewPostNodeClear() *api.PostNodeClear {
 	return api.NewPostNodeClear(t)
 }
 
+func (t T) NewPostNodeMaintenance() *api.PostNodeMaintenance {
+	return api.NewPostNodeMaintenance(t)
+}
+
 func (t T) NewPostNodeMonitor() *api.PostNodeMonitor {
 	return api.NewPostNodeMonitor(t)
 }
@@ -122,6 +130,10 @@ func (t T) NewPostObjectProgress() *api.PostObjectProgress {
 	return api.NewPostObjectProgress(t)
 }
 
+func (t T) NewPostObjectSimulate() *api.PostObjectSimulate {
+	return api.NewPostObjectSimulate(t)
+}
+
 func (t T) NewPostObjectStatus() *api.PostObjectStatus {
 	return api.NewPostObjectStatus(t)
 }
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"time"
+
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// GetObjectMonitorHistory describes the options of the object monitor
+// transitions history request.
+type GetObjectMonitorHistory struct {
+	Base
+	ObjectSelector string    `json:"-"`
+	Since          time.Time `json:"-"`
+}
+
+// NewGetObjectMonitorHistory allocates a GetObjectMonitorHistory struct and
+// sets default values to its keys.
+func NewGetObjectMonitorHistory(t Getter) *GetObjectMonitorHistory {
+	r := &GetObjectMonitorHistory{}
+	r.SetClient(t)
+	r.SetAction("/object/monitor/history")
+	r.SetMethod("GET")
+	return r
+}
+
+// Do submits the request.
+func (t GetObjectMonitorHistory) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	req.Values.Set("path", t.ObjectSelector)
+	if !t.Since.IsZero() {
+		req.Values.Set("since", t.Since.Format(time.RFC3339Nano))
+	}
+	return Route(t.client, *req)
+}
@@ -0,0 +1,31 @@
+package api
+
+import (
+	"time"
+
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostNodeMaintenance describes the node maintenance enter or leave request
+// options.
+type PostNodeMaintenance struct {
+	Base
+	Enter  bool       `json:"enter"`
+	Expire *time.Time `json:"expire,omitempty"`
+}
+
+// NewPostNodeMaintenance allocates a PostNodeMaintenance struct and sets
+// default values to its keys.
+func NewPostNodeMaintenance(t Poster) *PostNodeMaintenance {
+	r := &PostNodeMaintenance{}
+	r.SetClient(t)
+	r.SetMethod("POST")
+	r.SetAction("/node/maintenance")
+	return r
+}
+
+// Do ...
+func (t PostNodeMaintenance) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.client, *req)
+}
@@ -0,0 +1,32 @@
+package api
+
+import (
+	"opensvc.com/opensvc/core/client/request"
+)
+
+// PostObjectSimulate describes the options of the orchestration simulation
+// request: the objects and the hypothetical change of the cluster data.
+type PostObjectSimulate struct {
+	Base
+	ObjectSelector string   `json:"selector,omitempty"`
+	NodesDown      []string `json:"nodes_down,omitempty"`
+	NodesFrozen    []string `json:"nodes_frozen,omitempty"`
+	Placement      string   `json:"placement,omitempty"`
+	FlexTarget     *int     `json:"flex_target,omitempty"`
+}
+
+// NewPostObjectSimulate allocates a PostObjectSimulate struct and sets
+// default values to its keys.
+func NewPostObjectSimulate(t Poster) *PostObjectSimulate {
+	r := &PostObjectSimulate{}
+	r.SetClient(t)
+	r.SetAction("/object/simulate")
+	r.SetMethod("POST")
+	return r
+}
+
+// Do submits the request.
+func (t PostObjectSimulate) Do() ([]byte, error) {
+	req := request.NewFor(t)
+	return Route(t.client, *req)
+}
@@ -2,6 +2,7 @@ package reqjsonrpc
 
 import (
 	"bytes"
+	"compress/gzip"
 	"compress/zlib"
 	"crypto/aes"
 	"crypto/cipher"
@@ -24,6 +25,11 @@ type (
 		NodeName    string
 		Key         string
 		Data        []byte
+
+		// Compression is the algorithm compressing Data before encryption,
+		// CompressionZlib if empty. The decryption detects the algorithm.
+		// Peers running an older agent only support CompressionZlib.
+		Compression string
 	}
 	encryptedMessage struct {
 		ClusterName string `json:"clustername"`
@@ -33,6 +39,12 @@ type (
 	}
 )
 
+const (
+	CompressionZlib = "zlib"
+	CompressionGzip = "gzip"
+	CompressionNone = "none"
+)
+
 // NewMessage allocates a new Message configured for the local node and cluster context
 func NewMessage(b []byte) *Message {
 	cluster := rawconfig.ClusterSection()
@@ -82,7 +94,7 @@ func (m *Message) Encrypt() ([]byte, error) {
 		err       error
 	)
 	key := []byte(m.Key)
-	if encoded, encodedIV, err = encode(m.Data, key); err != nil {
+	if encoded, encodedIV, err = encode(m.Data, key, m.Compression); err != nil {
 		return nil, err
 	}
 	msg := &encryptedMessage{
@@ -115,13 +127,13 @@ func decode(encoded string, iv string, key []byte) ([]byte, error) {
 	return decompress(decoded)
 }
 
-func encode(data []byte, key []byte) (string, string, error) {
+func encode(data []byte, key []byte, compression string) (string, string, error) {
 	var (
 		b   []byte
 		iv  []byte
 		err error
 	)
-	b, err = compress(data)
+	b, err = compress(data, compression)
 	if err != nil {
 		return "", "", err
 	}
@@ -197,13 +209,22 @@ func newIV() []byte {
 	return b
 }
 
-func compress(b []byte) ([]byte, error) {
-	var bb bytes.Buffer
-	w := zlib.NewWriter(&bb)
-	if _, err := w.Write(b); err != nil {
-		return nil, err
+func compress(b []byte, compression string) ([]byte, error) {
+	var (
+		bb bytes.Buffer
+		w  io.WriteCloser
+	)
+	switch compression {
+	case "", CompressionZlib:
+		w = zlib.NewWriter(&bb)
+	case CompressionGzip:
+		w = gzip.NewWriter(&bb)
+	case CompressionNone:
+		return b, nil
+	default:
+		return nil, errors.Errorf("unsupported compression %s", compression)
 	}
-	if err := w.Flush(); err != nil {
+	if _, err := w.Write(b); err != nil {
 		return nil, err
 	}
 	if err := w.Close(); err != nil {
@@ -212,9 +233,26 @@ func compress(b []byte) ([]byte, error) {
 	return bb.Bytes(), nil
 }
 
+// decompress detects the compression algorithm from the header magic bytes.
+// The uncompressed data are hb or json messages, starting with a '\x00' or
+// a '{', so they can not be mistaken for compressed data. Other headers are
+// refused.
 func decompress(b []byte) ([]byte, error) {
+	var (
+		r   io.ReadCloser
+		err error
+	)
 	bb := bytes.NewReader(b)
-	r, err := zlib.NewReader(bb)
+	switch {
+	case len(b) >= 2 && b[0] == 0x1f && b[1] == 0x8b:
+		r, err = gzip.NewReader(bb)
+	case len(b) >= 1 && b[0] == 0x78:
+		r, err = zlib.NewReader(bb)
+	case len(b) >= 1 && (b[0] == 0x00 || b[0] == '{'):
+		return b, nil
+	default:
+		return nil, errors.Errorf("unsupported compression")
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,46 @@
+package reqjsonrpc
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptDecrypt(t *testing.T) {
+	data := []byte(`{"kind": "full", "data": "` + strings.Repeat("abcd", 100) + `"}`)
+	for _, compression := range []string{"", CompressionZlib, CompressionGzip, CompressionNone} {
+		t.Run(compression, func(t *testing.T) {
+			m := &Message{
+				ClusterName: "c1",
+				NodeName:    "node1",
+				Key:         "0123456789abcdef0123456789abcdef",
+				Data:        data,
+				Compression: compression,
+			}
+			b, err := m.Encrypt()
+			require.NoError(t, err)
+			encMsg := &Message{Key: m.Key, Data: b}
+			decoded, nodename, err := encMsg.DecryptWithNode()
+			require.NoError(t, err)
+			assert.Equal(t, "node1", nodename)
+			assert.Equal(t, data, decoded)
+		})
+	}
+	t.Run("unsupported compression", func(t *testing.T) {
+		m := &Message{Key: "0123456789abcdef0123456789abcdef", Data: data, Compression: "lz4"}
+		_, err := m.Encrypt()
+		assert.Error(t, err)
+	})
+}
+
+func TestDecompress(t *testing.T) {
+	for _, b := range [][]byte{[]byte(`{"kind": "full"}`), []byte("\x00hb\x01")} {
+		decoded, err := decompress(b)
+		require.NoError(t, err)
+		assert.Equal(t, b, decoded)
+	}
+	_, err := decompress([]byte("garbage"))
+	assert.Error(t, err)
+}
@@ -51,6 +51,35 @@ type (
 	SubHb struct {
 		Heartbeats []HeartbeatThreadStatus `json:"heartbeats"`
 		Modes      []HbMode                `json:"modes"`
+		Encoding   HbEncoding              `json:"encoding"`
+	}
+
+	// HbEncoding describes the encoding negotiated for the hb messages sent
+	// by the local node, and the size of these messages.
+	HbEncoding struct {
+		Format      string `json:"format"`
+		Compression string `json:"compression"`
+
+		// Sizes is the size stats of the messages, indexed by message kind.
+		Sizes map[string]HbMsgSizes `json:"sizes"`
+	}
+
+	// HbMsgSizes describes the size in bytes of the hb messages of a kind.
+	HbMsgSizes struct {
+		Count uint64 `json:"count"`
+
+		// Encoded is the size of the last message in the negotiated
+		// format, before compression and encryption.
+		Encoded uint64 `json:"encoded"`
+
+		// Last is the size of the last message sent to the hb drivers.
+		Last uint64 `json:"last"`
+
+		// Max is the size of the largest message sent to the hb drivers.
+		Max uint64 `json:"max"`
+
+		// Total is the size of all the messages sent to the hb drivers.
+		Total uint64 `json:"total"`
 	}
 
 	HbMode struct {
@@ -64,6 +93,27 @@ type (
 	}
 )
 
+// WithMsgSize returns a copy of t with the size stats of the message kind
+// updated with a new message of encoded bytes in the negotiated format,
+// sent as size bytes.
+func (t HbEncoding) WithMsgSize(kind string, encoded, size int) HbEncoding {
+	sizes := make(map[string]HbMsgSizes, len(t.Sizes)+1)
+	for k, v := range t.Sizes {
+		sizes[k] = v
+	}
+	s := sizes[kind]
+	s.Count++
+	s.Encoded = uint64(encoded)
+	s.Last = uint64(size)
+	s.Total += uint64(size)
+	if s.Last > s.Max {
+		s.Max = s.Last
+	}
+	sizes[kind] = s
+	t.Sizes = sizes
+	return t
+}
+
 func (s *Status) DeepCopy() *Status {
 	b, err := json.Marshal(s)
 	if err != nil {
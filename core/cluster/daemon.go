@@ -48,7 +48,92 @@ type (
 	// HeartbeatPeerStatus describes the status of the communication
 	// with a specific peer node.
 	HeartbeatPeerStatus struct {
-		Beating bool      `json:"beating"`
-		Last    time.Time `json:"last"`
+		Beating bool               `json:"beating"`
+		Last    time.Time          `json:"last"`
+		Stats   HeartbeatPeerStats `json:"stats"`
+	}
+
+	// HeartbeatPeerStats describes the quality of the communication with a
+	// specific peer node through a heartbeat path, since the path start.
+	HeartbeatPeerStats struct {
+		// Received is the count of messages received from, or sent to, the peer.
+		Received uint64 `json:"received"`
+
+		// Missed is the count of intervals elapsed without message.
+		Missed uint64 `json:"missed"`
+
+		// Transitions is the count of beating state changes.
+		Transitions uint64 `json:"transitions"`
+
+		// LastGen is the generation of the last message received from the peer.
+		LastGen uint64 `json:"last_gen"`
+
+		// LastAt is the time of the last message received from, or sent to, the peer.
+		LastAt time.Time `json:"last_at"`
+
+		// Intervals is the histogram of the durations between two messages.
+		Intervals []HeartbeatIntervalBucket `json:"intervals"`
+	}
+
+	// HeartbeatIntervalBucket is a cumulative histogram bucket, counting the
+	// intervals lower or equal to Le.
+	HeartbeatIntervalBucket struct {
+		Le    string `json:"le"`
+		Count uint64 `json:"count"`
 	}
 )
+
+var (
+	// HeartbeatIntervalBounds are the upper bounds of the heartbeat
+	// intervals histogram buckets. The last bucket is unbounded.
+	HeartbeatIntervalBounds = []time.Duration{
+		100 * time.Millisecond,
+		250 * time.Millisecond,
+		500 * time.Millisecond,
+		time.Second,
+		2 * time.Second,
+		5 * time.Second,
+		10 * time.Second,
+		30 * time.Second,
+	}
+)
+
+// WithMessage returns a copy of the stats updated with a message received at
+// tm with the generation gen. The interval is the expected duration between
+// two messages, used to count the missed intervals. A zero interval disables
+// the missed intervals accounting.
+func (t HeartbeatPeerStats) WithMessage(tm time.Time, gen uint64, interval time.Duration) HeartbeatPeerStats {
+	if !t.LastAt.IsZero() && tm.After(t.LastAt) {
+		d := tm.Sub(t.LastAt)
+		intervals := make([]HeartbeatIntervalBucket, len(HeartbeatIntervalBounds)+1)
+		copy(intervals, t.Intervals)
+		for i, bound := range HeartbeatIntervalBounds {
+			intervals[i].Le = bound.String()
+			if d <= bound {
+				intervals[i].Count++
+			}
+		}
+		intervals[len(HeartbeatIntervalBounds)].Le = "+Inf"
+		intervals[len(HeartbeatIntervalBounds)].Count++
+		t.Intervals = intervals
+		if interval > 0 {
+			if n := (d + interval/2) / interval; n > 1 {
+				t.Missed += uint64(n - 1)
+			}
+		}
+	}
+	t.Received++
+	t.LastAt = tm
+	if gen > 0 {
+		t.LastGen = gen
+	}
+	return t
+}
+
+// Loss returns the ratio of missed intervals, in percent.
+func (t HeartbeatPeerStats) Loss() float64 {
+	if t.Missed == 0 {
+		return 0
+	}
+	return 100 * float64(t.Missed) / float64(t.Missed+t.Received)
+}
@@ -0,0 +1,74 @@
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartbeatPeerStatsWithMessage(t *testing.T) {
+	t0 := time.Now()
+	interval := time.Second
+	stats := HeartbeatPeerStats{}
+	stats = stats.WithMessage(t0, 10, interval)
+	assert.Equal(t, uint64(1), stats.Received)
+	assert.Equal(t, uint64(10), stats.LastGen)
+	assert.Empty(t, stats.Intervals, "no interval without a previous message")
+
+	stats = stats.WithMessage(t0.Add(time.Second), 11, interval)
+	stats = stats.WithMessage(t0.Add(1200*time.Millisecond), 0, interval)
+	assert.Equal(t, uint64(3), stats.Received)
+	assert.Equal(t, uint64(11), stats.LastGen, "a zero gen must not reset the last gen")
+	assert.Equal(t, uint64(0), stats.Missed)
+
+	previous := stats
+	stats = stats.WithMessage(t0.Add(5200*time.Millisecond), 12, interval)
+	assert.Equal(t, uint64(3), stats.Missed, "4s gap with a 1s interval misses 3 intervals")
+	assert.Equal(t, uint64(0), previous.Missed)
+	assert.Equal(t, uint64(2), previous.Intervals[len(previous.Intervals)-1].Count,
+		"the previous stats histogram must not be modified")
+
+	counts := make(map[string]uint64)
+	for _, bucket := range stats.Intervals {
+		counts[bucket.Le] = bucket.Count
+	}
+	assert.Equal(t, map[string]uint64{
+		"100ms": 0,
+		"250ms": 1,
+		"500ms": 1,
+		"1s":    2,
+		"2s":    2,
+		"5s":    3,
+		"10s":   3,
+		"30s":   3,
+		"+Inf":  3,
+	}, counts)
+	assert.InDelta(t, 100*3.0/7.0, stats.Loss(), 0.001)
+}
+
+func TestHeartbeatPeerStatsWithMessageNoInterval(t *testing.T) {
+	t0 := time.Now()
+	stats := HeartbeatPeerStats{}.
+		WithMessage(t0, 1, 0).
+		WithMessage(t0.Add(time.Minute), 2, 0)
+	assert.Equal(t, uint64(0), stats.Missed)
+	assert.Equal(t, float64(0), stats.Loss())
+}
+
+func TestHeartbeatPeerStatsWithMessageOutOfOrder(t *testing.T) {
+	t0 := time.Now()
+	interval := time.Second
+	stats := HeartbeatPeerStats{}.
+		WithMessage(t0, 1, interval).
+		WithMessage(t0.Add(time.Second), 2, interval)
+	require.Len(t, stats.Intervals, len(HeartbeatIntervalBounds)+1)
+
+	stats = stats.WithMessage(t0.Add(500*time.Millisecond), 3, interval)
+	assert.Equal(t, uint64(3), stats.Received)
+	assert.Equal(t, uint64(0), stats.Missed)
+	assert.Equal(t, uint64(1), stats.Intervals[len(stats.Intervals)-1].Count,
+		"a message older than the last one does not count an interval")
+	assert.Equal(t, t0.Add(500*time.Millisecond), stats.LastAt)
+}
@@ -2,6 +2,7 @@ package cluster
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/golang-collections/collections/set"
 
@@ -45,6 +46,7 @@ func (f Frame) sNodeWarningsLine() string {
 	s := fmt.Sprintf(" %s\t\t\t%s\t", bold("state"), f.info.separator)
 	for _, n := range f.Current.Cluster.Config.Nodes {
 		s += f.sNodeMonState(n)
+		s += f.sNodeMaintenance(n)
 		s += f.sNodeFrozen(n)
 		s += f.sNodeMonTarget(n)
 		s += "\t"
@@ -151,6 +153,19 @@ func (f Frame) sNodeMonState(n string) string {
 	return ""
 }
 
+func (f Frame) sNodeMaintenance(n string) string {
+	if val, ok := f.Current.Cluster.Node[n]; ok {
+		if val.Monitor.State == node.MonitorStateMaintenance {
+			// already displayed as the monitor state
+			return ""
+		}
+		if val.Monitor.Maintenance.IsActive(time.Now()) {
+			return yellow("maintenance")
+		}
+	}
+	return ""
+}
+
 func (f Frame) sNodeFrozen(n string) string {
 	if val, ok := f.Current.Cluster.Node[n]; ok {
 		if !val.Status.Frozen.IsZero() {
@@ -108,7 +108,7 @@ func (f Frame) wThreadHeartbeats() string {
 		default:
 			s += red("unknown") + sThreadAlerts(hbStatus.Alerts)
 		}
-		s += "\t\t"
+		s += "\t" + sHeartbeatLoss(hbStatus.Peers) + "\t"
 		s += f.info.separator + "\t"
 		for _, peer := range f.Current.Cluster.Config.Nodes {
 			if peer == hostname.Hostname() {
@@ -131,6 +131,21 @@ func (f Frame) wThreadHeartbeats() string {
 	return s
 }
 
+// sHeartbeatLoss returns the highest missed intervals ratio of the
+// heartbeat peers, if any.
+func sHeartbeatLoss(peers map[string]HeartbeatPeerStatus) string {
+	var loss float64
+	for _, peerStatus := range peers {
+		if l := peerStatus.Stats.Loss(); l > loss {
+			loss = l
+		}
+	}
+	if loss == 0 {
+		return ""
+	}
+	return yellow(fmt.Sprintf("loss %.1f%%", loss))
+}
+
 func sThreadAlerts(data []ThreadAlert) string {
 	if len(data) > 0 {
 		return yellow("!")
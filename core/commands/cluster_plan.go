@@ -0,0 +1,273 @@
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/objectselector"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/plan"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	CmdClusterPlanStart struct {
+		OptsGlobal
+		Action      string
+		To          string
+		WaveTimeout time.Duration
+	}
+	CmdClusterPlanResume struct {
+		OptsGlobal
+		ID          string
+		WaveTimeout time.Duration
+	}
+	CmdClusterPlanStatus struct {
+		OptsGlobal
+		ID string
+	}
+	CmdClusterPlanAbort struct {
+		OptsGlobal
+		ID string
+	}
+
+	// planBackend submits the plan orchestrations to the daemon api.
+	planBackend struct {
+		client *client.T
+	}
+
+	plans []*plan.T
+)
+
+func (t planBackend) Status() (cluster.Status, error) {
+	var clusterStatus cluster.Status
+	b, err := t.client.NewGetDaemonStatus().Do()
+	if err != nil {
+		return clusterStatus, err
+	}
+	err = json.Unmarshal(b, &clusterStatus)
+	return clusterStatus, err
+}
+
+func (t planBackend) Submit(action plan.Action, p path.T, destination []string) error {
+	if action == plan.ActionSwitch {
+		req := t.client.NewPostObjectSwitchTo()
+		req.ObjectSelector = p.String()
+		req.Destination = destination
+		_, err := req.Do()
+		return err
+	}
+	req := t.client.NewPostObjectMonitor()
+	req.ObjectSelector = p.String()
+	req.GlobalExpect = action.GlobalExpect().String()
+	_, err := req.Do()
+	return err
+}
+
+func (t planBackend) Abort(p path.T) error {
+	req := t.client.NewPostObjectAbort()
+	req.Path = p
+	_, err := req.Do()
+	return err
+}
+
+func (t *CmdClusterPlanStart) Run() error {
+	action, err := plan.ParseAction(t.Action)
+	if err != nil {
+		return err
+	}
+	if t.ObjectSelector == "" {
+		return fmt.Errorf("an object selector is required")
+	}
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	paths, err := objectselector.NewSelection(
+		t.ObjectSelector,
+		objectselector.SelectionWithClient(c),
+	).Expand()
+	if err != nil {
+		return err
+	}
+	if len(paths) == 0 {
+		return fmt.Errorf("no object selected")
+	}
+	backend := planBackend{client: c}
+	clusterStatus, err := backend.Status()
+	if err != nil {
+		return err
+	}
+	objects := make([]plan.Object, len(paths))
+	for i, p := range paths {
+		objects[i] = planObject(clusterStatus, p)
+	}
+	pl, err := plan.New(action, t.ObjectSelector, objects)
+	if err != nil {
+		return err
+	}
+	if action == plan.ActionSwitch && t.To != "" {
+		pl.Destination = strings.Split(t.To, ",")
+	}
+	fmt.Fprintf(os.Stderr, "plan %s: %d objects in %d waves\n", pl.ID, len(paths), len(pl.Waves))
+	return runPlan(pl, backend, t.WaveTimeout)
+}
+
+// planObject returns the object relations and priority used to order the
+// plan waves.
+func planObject(clusterStatus cluster.Status, p path.T) plan.Object {
+	digest := clusterStatus.GetObjectStatus(p)
+	o := plan.Object{
+		Path:     p,
+		Priority: digest.Object.Priority,
+	}
+	relatives := func(l []path.Relation) []path.T {
+		paths := make([]path.T, 0)
+		for _, relation := range l {
+			if relative, err := relation.Path(); err == nil {
+				paths = append(paths, relative)
+			}
+		}
+		return paths
+	}
+	for _, instanceStates := range digest.Instances {
+		o.Parents = append(o.Parents, relatives(instanceStates.Status.Parents)...)
+		o.Children = append(o.Children, relatives(instanceStates.Status.Children)...)
+	}
+	return o
+}
+
+// runPlan runs the plan until its end, logging the waves progress. An
+// interrupt signal stops the run, leaving the plan resumable.
+func runPlan(pl *plan.T, backend plan.Backend, waveTimeout time.Duration) error {
+	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer cancel()
+	reported := make(map[string]plan.Status)
+	return pl.Run(ctx, backend, plan.RunOptions{
+		WaveTimeout: waveTimeout,
+		OnUpdate: func(pl *plan.T) {
+			for i, wave := range pl.Waves {
+				for _, item := range wave.Items {
+					ps := item.Path.String()
+					if reported[ps] == item.Status {
+						continue
+					}
+					reported[ps] = item.Status
+					if item.Error != "" {
+						fmt.Fprintf(os.Stderr, "wave %d: %s %s: %s\n", i+1, ps, item.Status, item.Error)
+					} else {
+						fmt.Fprintf(os.Stderr, "wave %d: %s %s\n", i+1, ps, item.Status)
+					}
+				}
+			}
+		},
+	})
+}
+
+func (t *CmdClusterPlanResume) Run() error {
+	pl, err := plan.Load(t.ID)
+	if err != nil {
+		return err
+	}
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	return runPlan(pl, planBackend{client: c}, t.WaveTimeout)
+}
+
+func (t *CmdClusterPlanAbort) Run() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	return plan.RequestAbort(t.ID, planBackend{client: c})
+}
+
+func (t *CmdClusterPlanStatus) Run() error {
+	var data plans
+	if t.ID != "" {
+		pl, err := plan.Load(t.ID)
+		if err != nil {
+			return err
+		}
+		data = plans{pl}
+	} else {
+		l, err := plan.List()
+		if err != nil {
+			return err
+		}
+		data = l
+	}
+	output.Renderer{
+		Format:   t.Format,
+		Color:    t.Color,
+		Data:     data,
+		Colorize: rawconfig.Colorize,
+		HumanRenderer: func() string {
+			return data.Render(t.ID != "")
+		},
+	}.Print()
+	return nil
+}
+
+func (t plans) Render(detailed bool) string {
+	tree := tree.New()
+	tree.AddColumn().AddText("Plan").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Action").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Status").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Selector").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Updated").SetColor(rawconfig.Color.Bold)
+	for _, pl := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(pl.ID).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(string(pl.Action))
+		reason := pl.Error
+		if pl.Status == plan.StatusRunning && !pl.IsRunnerAlive() {
+			reason = "runner dead, resume or abort"
+		}
+		n.AddColumn().AddText(planStatusText(pl.Status, reason))
+		n.AddColumn().AddText(pl.Selector)
+		n.AddColumn().AddText(pl.Updated.Format(time.RFC3339))
+		if !detailed {
+			continue
+		}
+		for i, wave := range pl.Waves {
+			waveNode := n.AddNode()
+			waveNode.AddColumn().AddText(fmt.Sprintf("wave %d", i+1)).SetColor(rawconfig.Color.Secondary)
+			for _, item := range wave.Items {
+				itemNode := waveNode.AddNode()
+				itemNode.AddColumn().AddText(item.Path.String())
+				itemNode.AddColumn()
+				itemNode.AddColumn().AddText(planStatusText(item.Status, item.Error))
+			}
+		}
+	}
+	return tree.Render()
+}
+
+func planStatusText(s plan.Status, reason string) string {
+	var text string
+	switch s {
+	case plan.StatusDone:
+		text = rawconfig.Colorize.Optimal(s)
+	case plan.StatusFailed, plan.StatusAborted:
+		text = rawconfig.Colorize.Error(s)
+	default:
+		text = rawconfig.Colorize.Secondary(s)
+	}
+	if reason != "" {
+		text += " " + reason
+	}
+	return text
+}
@@ -0,0 +1,106 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/daemon/monitor/imon"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	CmdClusterSimulate struct {
+		OptsGlobal
+		NodesDown   []string
+		NodesFrozen []string
+		Placement   string
+
+		// FlexTarget is the simulated flex target, not simulated if
+		// negative.
+		FlexTarget int
+	}
+
+	simulations []imon.Simulation
+)
+
+func (t *CmdClusterSimulate) Run() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	req := c.NewPostObjectSimulate()
+	req.ObjectSelector = t.ObjectSelector
+	req.NodesDown = t.NodesDown
+	req.NodesFrozen = t.NodesFrozen
+	req.Placement = t.Placement
+	if t.FlexTarget >= 0 {
+		req.FlexTarget = &t.FlexTarget
+	}
+	b, err := req.Do()
+	if err != nil {
+		return err
+	}
+	var resp struct {
+		Simulations simulations `json:"simulations"`
+	}
+	if err := json.Unmarshal(b, &resp); err != nil {
+		return err
+	}
+	data := resp.Simulations
+	output.Renderer{
+		Format:   t.Format,
+		Color:    t.Color,
+		Data:     data,
+		Colorize: rawconfig.Colorize,
+		HumanRenderer: func() string {
+			return data.Render()
+		},
+	}.Print()
+	return nil
+}
+
+func (t simulations) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText("Object").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Avail").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Leader").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Action").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Reason").SetColor(rawconfig.Color.Bold)
+	for _, simulation := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(simulation.Path.String()).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(simulation.Avail.String())
+		n.AddColumn().AddText(strings.Join(simulation.Leaders, ","))
+		n.AddColumn()
+		n.AddColumn().AddText("candidates: " + strings.Join(simulation.Candidates, ",")).SetColor(rawconfig.Color.Secondary)
+		for _, action := range simulation.Actions {
+			actionNode := n.AddNode()
+			actionNode.AddColumn().AddText(action.Node)
+			actionNode.AddColumn()
+			if action.IsHALeader {
+				actionNode.AddColumn().AddText("ha leader")
+			} else if action.IsLeader {
+				actionNode.AddColumn().AddText("leader")
+			} else {
+				actionNode.AddColumn()
+			}
+			actionNode.AddColumn().AddText(simulationActionText(action.Action))
+			actionNode.AddColumn().AddText(action.Reason).SetColor(rawconfig.Color.Secondary)
+		}
+	}
+	return tree.Render()
+}
+
+func simulationActionText(action string) string {
+	switch action {
+	case "none":
+		return rawconfig.Colorize.Secondary(action)
+	case "wait":
+		return rawconfig.Colorize.Warning(action)
+	default:
+		return rawconfig.Colorize.Optimal(action)
+	}
+}
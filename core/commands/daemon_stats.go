@@ -2,20 +2,37 @@ package commands
 
 import (
 	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
 
 	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/cluster"
 	"opensvc.com/opensvc/core/output"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/render/tree"
 )
 
 type (
 	CmdDaemonStats struct {
 		OptsGlobal
+		Heartbeats bool
 	}
+
+	// hbPeerStats is the heartbeat statistics of a peer on a heartbeat path.
+	hbPeerStats struct {
+		Id      string                     `json:"id"`
+		Peer    string                     `json:"peer"`
+		Beating bool                       `json:"beating"`
+		Stats   cluster.HeartbeatPeerStats `json:"stats"`
+	}
+	hbPeerStatsList []hbPeerStats
 )
 
 func (t *CmdDaemonStats) Run() error {
+	if t.Heartbeats {
+		return t.runHeartbeats()
+	}
 	var (
 		err  error
 		b    []byte
@@ -67,3 +84,105 @@ func parseDaemonStats(b []byte) (cluster.Stats, error) {
 	}
 	return ds, nil
 }
+
+// runHeartbeats prints the heartbeat statistics of the local node peers, per
+// heartbeat path.
+func (t *CmdDaemonStats) runHeartbeats() error {
+	var clusterStatus cluster.Status
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	b, err := c.NewGetDaemonStatus().Do()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &clusterStatus); err != nil {
+		return err
+	}
+	data := newHbPeerStatsList(clusterStatus.Sub.Hb.Heartbeats)
+	output.Renderer{
+		Format:   t.Format,
+		Color:    t.Color,
+		Data:     data,
+		Colorize: rawconfig.Colorize,
+		HumanRenderer: func() string {
+			return data.Render()
+		},
+	}.Print()
+	return nil
+}
+
+func newHbPeerStatsList(heartbeats []cluster.HeartbeatThreadStatus) hbPeerStatsList {
+	l := make(hbPeerStatsList, 0)
+	for _, hbStatus := range heartbeats {
+		peers := make([]string, 0, len(hbStatus.Peers))
+		for peer := range hbStatus.Peers {
+			peers = append(peers, peer)
+		}
+		sort.Strings(peers)
+		for _, peer := range peers {
+			peerStatus := hbStatus.Peers[peer]
+			l = append(l, hbPeerStats{
+				Id:      hbStatus.Id,
+				Peer:    peer,
+				Beating: peerStatus.Beating,
+				Stats:   peerStatus.Stats,
+			})
+		}
+	}
+	return l
+}
+
+func (t hbPeerStatsList) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText("Id").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Peer").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Beating").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Received").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Missed").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Loss").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Transitions").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("LastGen").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("LastAt").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Intervals").SetColor(rawconfig.Color.Bold)
+	for _, e := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(e.Id).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(e.Peer).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(fmt.Sprint(e.Beating))
+		n.AddColumn().AddText(fmt.Sprint(e.Stats.Received))
+		n.AddColumn().AddText(fmt.Sprint(e.Stats.Missed))
+		n.AddColumn().AddText(fmt.Sprintf("%.1f%%", e.Stats.Loss()))
+		n.AddColumn().AddText(fmt.Sprint(e.Stats.Transitions))
+		n.AddColumn().AddText(fmt.Sprint(e.Stats.LastGen))
+		if e.Stats.LastAt.IsZero() {
+			n.AddColumn().AddText("-")
+		} else {
+			n.AddColumn().AddText(e.Stats.LastAt.Format(time.RFC3339))
+		}
+		n.AddColumn().AddText(sHbIntervals(e.Stats.Intervals))
+	}
+	return tree.Render()
+}
+
+// sHbIntervals returns the non-cumulative counts of the intervals histogram,
+// formatted like "<=1s:120 <=2s:3".
+func sHbIntervals(buckets []cluster.HeartbeatIntervalBucket) string {
+	var (
+		s    string
+		prev uint64
+	)
+	for _, bucket := range buckets {
+		count := bucket.Count - prev
+		prev = bucket.Count
+		if count == 0 {
+			continue
+		}
+		if s != "" {
+			s += " "
+		}
+		s += fmt.Sprintf("<=%s:%d", bucket.Le, count)
+	}
+	return s
+}
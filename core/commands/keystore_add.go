@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"fmt"
+
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/objectaction"
 	"opensvc.com/opensvc/core/path"
@@ -13,6 +15,7 @@ type (
 		Key   string
 		From  string
 		Value string
+		Ref   string
 	}
 )
 
@@ -30,6 +33,7 @@ func (t *CmdKeystoreAdd) Run(selector, kind string) error {
 			"key":   t.Key,
 			"from":  t.From,
 			"value": t.Value,
+			"ref":   t.Ref,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			store, err := object.NewKeystore(p)
@@ -37,6 +41,12 @@ func (t *CmdKeystoreAdd) Run(selector, kind string) error {
 				return nil, err
 			}
 			switch {
+			case t.Ref != "":
+				refStore, ok := store.(object.RefKeystore)
+				if !ok {
+					return nil, fmt.Errorf("%s does not support key references", p)
+				}
+				return nil, refStore.AddKeyRef(t.Key, t.Ref)
 			case t.From != "":
 				return nil, store.AddKeyFrom(t.Key, t.From)
 			default:
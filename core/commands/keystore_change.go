@@ -1,6 +1,8 @@
 package commands
 
 import (
+	"fmt"
+
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/objectaction"
 	"opensvc.com/opensvc/core/path"
@@ -12,6 +14,7 @@ type (
 		Key   string
 		From  string
 		Value string
+		Ref   string
 	}
 )
 
@@ -29,6 +32,7 @@ func (t *CmdKeystoreChange) Run(selector, kind string) error {
 			"key":   t.Key,
 			"from":  t.From,
 			"value": t.Value,
+			"ref":   t.Ref,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			store, err := object.NewKeystore(p)
@@ -36,6 +40,12 @@ func (t *CmdKeystoreChange) Run(selector, kind string) error {
 				return nil, err
 			}
 			switch {
+			case t.Ref != "":
+				refStore, ok := store.(object.RefKeystore)
+				if !ok {
+					return nil, fmt.Errorf("%s does not support key references", p)
+				}
+				return nil, refStore.ChangeKeyRef(t.Key, t.Ref)
 			case t.From != "":
 				return nil, store.ChangeKeyFrom(t.Key, t.From)
 			default:
@@ -9,7 +9,8 @@ import (
 type (
 	CmdKeystoreKeys struct {
 		OptsGlobal
-		Match string
+		Match    string
+		Versions bool
 	}
 )
 
@@ -24,13 +25,17 @@ func (t *CmdKeystoreKeys) Run(selector, kind string) error {
 		objectaction.WithRemoteNodes(t.NodeSelector),
 		objectaction.WithRemoteAction("keys"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
-			"match": t.Match,
+			"match":    t.Match,
+			"versions": t.Versions,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			store, err := object.NewKeystore(p)
 			if err != nil {
 				return nil, err
 			}
+			if t.Versions {
+				return store.KeyVersions(t.Match)
+			}
 			return store.MatchingKeys(t.Match)
 		}),
 	).Do()
@@ -0,0 +1,74 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	CmdKeystoreRotateKey struct {
+		OptsGlobal
+		Key   string
+		From  string
+		Value string
+		At    string
+	}
+)
+
+// parseActivationTime returns the time described by s: a RFC3339 date, a
+// "2006-01-02 15:04" local date, or a duration from now. An empty string
+// is a zero time, meaning now.
+func parseActivationTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if tm, err := time.Parse(time.RFC3339, s); err == nil {
+		return tm, nil
+	}
+	if tm, err := time.ParseInLocation("2006-01-02 15:04", s, time.Local); err == nil {
+		return tm, nil
+	}
+	if d, err := time.ParseDuration(s); err == nil {
+		return now.Add(d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid activation time %s: expect a RFC3339 date, a '2006-01-02 15:04' date or a duration", s)
+}
+
+func (t *CmdKeystoreRotateKey) Run(selector, kind string) error {
+	at, err := parseActivationTime(t.At, time.Now())
+	if err != nil {
+		return err
+	}
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithColor(t.Color),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.NodeSelector),
+		objectaction.WithRemoteAction("rotate key"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"key":   t.Key,
+			"from":  t.From,
+			"value": t.Value,
+			"at":    t.At,
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			store, err := object.NewKeystore(p)
+			if err != nil {
+				return nil, err
+			}
+			switch {
+			case t.From != "":
+				return nil, store.RotateKeyFrom(t.Key, t.From, at)
+			default:
+				return nil, store.RotateKey(t.Key, []byte(t.Value), at)
+			}
+		}),
+	).Do()
+}
@@ -9,6 +9,7 @@ type (
 	CmdNodeComplianceAttachModuleset struct {
 		OptsGlobal
 		Moduleset string
+		Source    string
 	}
 )
 
@@ -22,13 +23,14 @@ func (t *CmdNodeComplianceAttachModuleset) Run() error {
 		nodeaction.WithRemoteAction("compliance attach moduleset"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
+			"source": t.Source,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			n, err := object.NewNode()
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
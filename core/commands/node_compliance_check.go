@@ -12,6 +12,7 @@ type (
 		Module    string
 		Force     bool
 		Attach    bool
+		Source    string
 	}
 )
 
@@ -25,6 +26,7 @@ func (t *CmdNodeComplianceCheck) Run() error {
 		nodeaction.WithRemoteAction("compliance check"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"force":     t.Force,
 			"module":    t.Module,
 			"moduleset": t.Moduleset,
@@ -35,7 +37,7 @@ func (t *CmdNodeComplianceCheck) Run() error {
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
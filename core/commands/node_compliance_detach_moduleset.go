@@ -9,6 +9,7 @@ type (
 	CmdNodeComplianceDetachModuleset struct {
 		OptsGlobal
 		Moduleset string
+		Source    string
 	}
 )
 
@@ -22,6 +23,7 @@ func (t *CmdNodeComplianceDetachModuleset) Run() error {
 		nodeaction.WithRemoteAction("compliance detach moduleset"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"moduleset": t.Moduleset,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
@@ -29,7 +31,7 @@ func (t *CmdNodeComplianceDetachModuleset) Run() error {
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
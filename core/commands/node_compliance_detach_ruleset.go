@@ -9,6 +9,7 @@ type (
 	CmdNodeComplianceDetachRuleset struct {
 		OptsGlobal
 		Ruleset string
+		Source  string
 	}
 )
 
@@ -22,13 +23,14 @@ func (t *CmdNodeComplianceDetachRuleset) Run() error {
 		nodeaction.WithRemoteAction("compliance detach ruleset"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
+			"source": t.Source,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			n, err := object.NewNode()
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
@@ -10,6 +10,7 @@ type (
 		OptsGlobal
 		Moduleset string
 		Module    string
+		Source    string
 	}
 )
 
@@ -23,6 +24,7 @@ func (t *CmdNodeComplianceEnv) Run() error {
 		nodeaction.WithRemoteAction("compliance env"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"moduleset": t.Moduleset,
 			"module":    t.Module,
 		}),
@@ -31,7 +33,7 @@ func (t *CmdNodeComplianceEnv) Run() error {
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
@@ -10,6 +10,7 @@ type (
 	CmdNodeComplianceShowModuleset struct {
 		OptsGlobal
 		Moduleset string
+		Source    string
 	}
 )
 
@@ -23,6 +24,7 @@ func (t *CmdNodeComplianceShowModuleset) Run() error {
 		nodeaction.WithRemoteAction("compliance show moduleset"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"moduleset": t.Moduleset,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
@@ -30,7 +32,7 @@ func (t *CmdNodeComplianceShowModuleset) Run() error {
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
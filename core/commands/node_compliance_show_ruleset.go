@@ -8,6 +8,7 @@ import (
 type (
 	CmdNodeComplianceShowRuleset struct {
 		OptsGlobal
+		Source string
 	}
 )
 
@@ -21,13 +22,14 @@ func (t *CmdNodeComplianceShowRuleset) Run() error {
 		nodeaction.WithRemoteAction("compliance show ruleset"),
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
+			"source": t.Source,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			n, err := object.NewNode()
 			if err != nil {
 				return nil, err
 			}
-			comp, err := n.NewCompliance()
+			comp, err := n.NewComplianceFrom(t.Source)
 			if err != nil {
 				return nil, err
 			}
@@ -7,13 +7,21 @@ import (
 type CmdNodeDrain struct {
 	OptsGlobal
 	OptsAsync
+	Evacuate bool
 }
 
 func (t *CmdNodeDrain) Run() error {
+	target := "drained"
+	if t.Evacuate {
+		target = "evacuated"
+	}
 	return nodeaction.New(
 		nodeaction.WithRemoteNodes(t.NodeSelector),
 		nodeaction.WithRemoteAction("drain"),
-		nodeaction.WithAsyncTarget("drained"),
+		nodeaction.WithRemoteOptions(map[string]any{
+			"evacuate": t.Evacuate,
+		}),
+		nodeaction.WithAsyncTarget(target),
 		nodeaction.WithAsyncWatch(t.Watch),
 		nodeaction.WithFormat(t.Format),
 		nodeaction.WithColor(t.Color),
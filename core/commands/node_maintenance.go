@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/client"
+)
+
+type (
+	CmdNodeMaintenanceEnter struct {
+		OptsGlobal
+		Expire time.Duration
+	}
+	CmdNodeMaintenanceLeave struct {
+		OptsGlobal
+	}
+)
+
+func (t *CmdNodeMaintenanceEnter) Run() error {
+	if t.Expire < 0 {
+		return fmt.Errorf("invalid expire duration: %s", t.Expire)
+	}
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	req := c.NewPostNodeMaintenance()
+	req.Enter = true
+	if t.Expire > 0 {
+		expire := time.Now().Add(t.Expire)
+		req.Expire = &expire
+	}
+	_, err = req.Do()
+	return err
+}
+
+func (t *CmdNodeMaintenanceLeave) Run() error {
+	c, err := client.New(client.WithURL(t.Server))
+	if err != nil {
+		return err
+	}
+	req := c.NewPostNodeMaintenance()
+	req.Enter = false
+	_, err = req.Do()
+	return err
+}
@@ -9,9 +9,15 @@ type (
 	CmdNodeSysreport struct {
 		OptsGlobal
 		Force bool
+		Diff  string
+		Show  string
 	}
 )
 
+// SysreportDiffPrevious is the --diff value set when the flag has no
+// argument, selecting the snapshot before the last.
+const SysreportDiffPrevious = "previous"
+
 func (t *CmdNodeSysreport) Run() error {
 	return nodeaction.New(
 		nodeaction.WithLocal(t.Local),
@@ -23,16 +29,35 @@ func (t *CmdNodeSysreport) Run() error {
 		nodeaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
 			"force":  t.Force,
+			"diff":   t.Diff,
+			"show":   t.Show,
 		}),
 		nodeaction.WithLocalRun(func() (interface{}, error) {
 			n, err := object.NewNode()
 			if err != nil {
 				return nil, err
 			}
-			if t.Force {
+			switch {
+			case t.Show != "":
+				b, err := n.SysreportShow(t.Show)
+				if err != nil {
+					return nil, err
+				}
+				return b, nil
+			case t.Diff != "":
+				since := t.Diff
+				if since == SysreportDiffPrevious {
+					since = ""
+				}
+				diff, err := n.SysreportDiff(since)
+				if err != nil {
+					return nil, err
+				}
+				return diff, nil
+			case t.Force:
 				err := n.ForceSysreport()
 				return nil, err
-			} else {
+			default:
 				err := n.Sysreport()
 				return nil, err
 			}
@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"opensvc.com/opensvc/core/nodeaction"
+	"opensvc.com/opensvc/core/objecttemplate"
+)
+
+type (
+	CmdNodeTemplateList struct {
+		OptsGlobal
+	}
+)
+
+func (t *CmdNodeTemplateList) Run() error {
+	return nodeaction.New(
+		nodeaction.WithLocal(t.Local),
+		nodeaction.WithRemoteNodes(t.NodeSelector),
+		nodeaction.WithFormat(t.Format),
+		nodeaction.WithColor(t.Color),
+		nodeaction.WithServer(t.Server),
+		nodeaction.WithRemoteAction("template list"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format": t.Format,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return objecttemplate.List()
+		}),
+	).Do()
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"opensvc.com/opensvc/core/nodeaction"
+	"opensvc.com/opensvc/core/objecttemplate"
+)
+
+type (
+	CmdNodeTemplateShow struct {
+		OptsGlobal
+		Template string
+	}
+)
+
+func (t *CmdNodeTemplateShow) Run() error {
+	return nodeaction.New(
+		nodeaction.WithLocal(t.Local),
+		nodeaction.WithRemoteNodes(t.NodeSelector),
+		nodeaction.WithFormat(t.Format),
+		nodeaction.WithColor(t.Color),
+		nodeaction.WithServer(t.Server),
+		nodeaction.WithRemoteAction("template show"),
+		nodeaction.WithRemoteOptions(map[string]interface{}{
+			"format":   t.Format,
+			"template": t.Template,
+		}),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			return objecttemplate.Get(t.Template)
+		}),
+	).Do()
+}
@@ -0,0 +1,30 @@
+package commands
+
+import (
+	"opensvc.com/opensvc/core/nodeaction"
+	"opensvc.com/opensvc/core/object"
+)
+
+type CmdNodeUpgrade struct {
+	OptsGlobal
+	OptsAsync
+}
+
+func (t *CmdNodeUpgrade) Run() error {
+	return nodeaction.New(
+		nodeaction.WithRemoteNodes(t.NodeSelector),
+		nodeaction.WithRemoteAction("upgrade"),
+		nodeaction.WithAsyncTarget("upgraded"),
+		nodeaction.WithAsyncWatch(t.Watch),
+		nodeaction.WithFormat(t.Format),
+		nodeaction.WithColor(t.Color),
+		nodeaction.WithLocal(t.Local),
+		nodeaction.WithLocalRun(func() (interface{}, error) {
+			n, err := object.NewNode()
+			if err != nil {
+				return nil, err
+			}
+			return nil, n.Upgrade()
+		}),
+	).Do()
+}
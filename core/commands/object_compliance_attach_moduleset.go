@@ -10,6 +10,7 @@ type (
 	CmdObjectComplianceAttachModuleset struct {
 		OptsGlobal
 		Moduleset string
+		Source    string
 	}
 )
 
@@ -26,12 +27,13 @@ func (t *CmdObjectComplianceAttachModuleset) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance attach moduleset"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
+			"source": t.Source,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
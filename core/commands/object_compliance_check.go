@@ -13,6 +13,7 @@ type (
 		Module    string
 		Force     bool
 		Attach    bool
+		Source    string
 	}
 )
 
@@ -29,6 +30,7 @@ func (t *CmdObjectComplianceCheck) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance check"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"force":     t.Force,
 			"module":    t.Module,
 			"moduleset": t.Moduleset,
@@ -38,7 +40,7 @@ func (t *CmdObjectComplianceCheck) Run(selector, kind string) error {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
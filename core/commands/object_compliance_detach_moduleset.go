@@ -10,6 +10,7 @@ type (
 	CmdObjectComplianceDetachModuleset struct {
 		OptsGlobal
 		Moduleset string
+		Source    string
 	}
 )
 
@@ -26,13 +27,14 @@ func (t *CmdObjectComplianceDetachModuleset) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance detach moduleset"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"moduleset": t.Moduleset,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
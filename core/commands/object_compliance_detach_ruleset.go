@@ -10,6 +10,7 @@ type (
 	CmdObjectComplianceDetachRuleset struct {
 		OptsGlobal
 		Ruleset string
+		Source  string
 	}
 )
 
@@ -26,13 +27,14 @@ func (t *CmdObjectComplianceDetachRuleset) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance detach ruleset"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format":  t.Format,
+			"source":  t.Source,
 			"ruleset": t.Ruleset,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
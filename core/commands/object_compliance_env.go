@@ -11,6 +11,7 @@ type (
 		OptsGlobal
 		Moduleset string
 		Module    string
+		Source    string
 	}
 )
 
@@ -27,6 +28,7 @@ func (t *CmdObjectComplianceEnv) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance env"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format":    t.Format,
+			"source":    t.Source,
 			"moduleset": t.Moduleset,
 			"module":    t.Module,
 		}),
@@ -34,7 +36,7 @@ func (t *CmdObjectComplianceEnv) Run(selector, kind string) error {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
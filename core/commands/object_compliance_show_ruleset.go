@@ -9,6 +9,7 @@ import (
 type (
 	CmdObjectComplianceShowRuleset struct {
 		OptsGlobal
+		Source string
 	}
 )
 
@@ -25,12 +26,13 @@ func (t *CmdObjectComplianceShowRuleset) Run(selector, kind string) error {
 		objectaction.WithRemoteAction("compliance show ruleset"),
 		objectaction.WithRemoteOptions(map[string]interface{}{
 			"format": t.Format,
+			"source": t.Source,
 		}),
 		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
 			if o, err := object.NewSvc(p); err != nil {
 				return nil, err
 			} else {
-				comp, err := o.NewCompliance()
+				comp, err := o.NewComplianceFrom(t.Source)
 				if err != nil {
 					return nil, err
 				}
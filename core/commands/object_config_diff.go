@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
+)
+
+type (
+	CmdObjectConfigDiff struct {
+		OptsGlobal
+		From int
+		To   int
+	}
+)
+
+func (t *CmdObjectConfigDiff) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithColor(t.Color),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, err := object.NewConfigurer(p)
+			if err != nil {
+				return nil, err
+			}
+			s, err := o.DiffConfigRevisions(t.From, t.To)
+			if err != nil {
+				return nil, err
+			}
+			return xconfig.RevisionDiff(s), nil
+		}),
+	).Do()
+}
@@ -0,0 +1,31 @@
+package commands
+
+import (
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	CmdObjectConfigHistory struct {
+		OptsGlobal
+	}
+)
+
+func (t *CmdObjectConfigHistory) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithColor(t.Color),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, err := object.NewConfigurer(p)
+			if err != nil {
+				return nil, err
+			}
+			return o.ConfigRevisions()
+		}),
+	).Do()
+}
@@ -0,0 +1,39 @@
+package commands
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	CmdObjectConfigRollback struct {
+		OptsGlobal
+		OptsLock
+		Revision int
+	}
+)
+
+func (t *CmdObjectConfigRollback) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithColor(t.Color),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, err := object.NewConfigurer(p)
+			if err != nil {
+				return nil, err
+			}
+			ctx := context.Background()
+			ctx = actioncontext.WithLockDisabled(ctx, t.Disable)
+			ctx = actioncontext.WithLockTimeout(ctx, t.Timeout)
+			return nil, o.RollbackConfig(ctx, t.Revision)
+		}),
+	).Do()
+}
@@ -17,6 +17,7 @@ import (
 	"opensvc.com/opensvc/core/keyop"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/objectselector"
+	"opensvc.com/opensvc/core/objecttemplate"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/core/xconfig"
@@ -30,6 +31,8 @@ type (
 		OptsLock
 		From        string
 		Keywords    []string
+		Template    string
+		Params      []string
 		Env         string
 		Interactive bool
 		Provision   bool
@@ -86,6 +89,9 @@ func (t *CmdObjectCreate) parseSelector(selector, kind string) (path.T, error) {
 }
 
 func (t *CmdObjectCreate) getTemplate() string {
+	if t.Template != "" {
+		return t.Template
+	}
 	if strings.HasPrefix(t.From, schemeTemplate) {
 		return t.From[len(schemeTemplate):]
 	}
@@ -108,12 +114,12 @@ func (t *CmdObjectCreate) Do() error {
 	template := t.getTemplate()
 	paths := t.getSourcePaths()
 	switch {
+	case template != "":
+		return t.fromTemplate(template)
 	case t.From == "":
 		return t.fromScratch()
 	case t.From == "-" || t.From == "/dev/stdin" || t.From == "stdin":
 		return t.fromStdin()
-	case template != "":
-		return t.fromTemplate(template)
 	case len(paths) > 0:
 		return t.fromPaths(paths)
 	default:
@@ -212,8 +218,33 @@ func (t CmdObjectCreate) fromData(pivot Pivot) error {
 	return t.localFromData(pivot)
 }
 
-func (t CmdObjectCreate) rawFromTemplate(template string) (Pivot, error) {
-	return nil, fmt.Errorf("TODO: collector requester")
+func (t CmdObjectCreate) rawFromTemplate(name string) (Pivot, error) {
+	if t.path.IsZero() {
+		return nil, errors.Errorf("Need a target object path.")
+	}
+	params := make(map[string]string)
+	for _, s := range t.Params {
+		l := strings.SplitN(s, "=", 2)
+		if len(l) != 2 || l[0] == "" {
+			return nil, errors.Errorf("invalid template parameter %s: expected <name>=<value>", s)
+		}
+		params[l[0]] = l[1]
+	}
+	tmpl, err := objecttemplate.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	p := t.path
+	if t.Namespace != "" {
+		p.Namespace = t.Namespace
+	}
+	c, err := tmpl.Instantiate(p, params)
+	if err != nil {
+		return nil, err
+	}
+	pivot := make(Pivot)
+	pivot[p.String()] = c
+	return pivot, nil
 }
 
 func (t CmdObjectCreate) rawFromConfig() (Pivot, error) {
@@ -0,0 +1,144 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/objectselector"
+	"opensvc.com/opensvc/core/output"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/render/tree"
+)
+
+type (
+	CmdObjectMonitorHistory struct {
+		OptsGlobal
+		Since time.Duration
+	}
+
+	// objectMonitorTransition is an instance monitor transition of an
+	// object.
+	objectMonitorTransition struct {
+		instance.MonitorTransition
+		Path path.T `json:"path"`
+	}
+
+	objectMonitorTransitions []objectMonitorTransition
+)
+
+func (t *CmdObjectMonitorHistory) since() time.Time {
+	if t.Since <= 0 {
+		return time.Time{}
+	}
+	return time.Now().Add(-t.Since)
+}
+
+func (t *CmdObjectMonitorHistory) local(paths path.L) (objectMonitorTransitions, error) {
+	l := make(objectMonitorTransitions, 0)
+	for _, p := range paths {
+		history, err := instance.LoadMonitorHistory(p)
+		if err != nil {
+			return l, err
+		}
+		for _, e := range history.Since(t.since()) {
+			l = append(l, objectMonitorTransition{MonitorTransition: e, Path: p})
+		}
+	}
+	return l, nil
+}
+
+func (t *CmdObjectMonitorHistory) remote(node string, paths path.L) (objectMonitorTransitions, error) {
+	l := make(objectMonitorTransitions, 0)
+	c, err := client.New(
+		client.WithURL(node),
+		client.WithUsername(hostname.Hostname()),
+		client.WithPassword(rawconfig.ClusterSection().Secret),
+	)
+	if err != nil {
+		return l, err
+	}
+	for _, p := range paths {
+		req := c.NewGetObjectMonitorHistory()
+		req.ObjectSelector = p.String()
+		req.Since = t.since()
+		b, err := req.Do()
+		if err != nil {
+			return l, err
+		}
+		var data struct {
+			Transitions instance.MonitorHistory `json:"transitions"`
+		}
+		if err := json.Unmarshal(b, &data); err != nil {
+			return l, err
+		}
+		for _, e := range data.Transitions {
+			l = append(l, objectMonitorTransition{MonitorTransition: e, Path: p})
+		}
+	}
+	return l, nil
+}
+
+func (t *CmdObjectMonitorHistory) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	paths, err := objectselector.NewSelection(
+		mergedSelector,
+		objectselector.SelectionWithLocal(true),
+	).Expand()
+	if err != nil {
+		return err
+	}
+	var data objectMonitorTransitions
+	if t.Local {
+		if data, err = t.local(paths); err != nil {
+			return err
+		}
+	} else {
+		for _, node := range nodesFromPaths(paths) {
+			if more, err := t.remote(node, paths); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: monitor history fetch error: %s\n", node, err)
+			} else {
+				data = append(data, more...)
+			}
+		}
+	}
+	sort.SliceStable(data, func(i, j int) bool {
+		return data[i].At.Before(data[j].At)
+	})
+	output.Renderer{
+		Format:   t.Format,
+		Color:    t.Color,
+		Data:     data,
+		Colorize: rawconfig.Colorize,
+		HumanRenderer: func() string {
+			return data.Render()
+		},
+	}.Print()
+	return nil
+}
+
+func (t objectMonitorTransitions) Render() string {
+	tree := tree.New()
+	tree.AddColumn().AddText("At").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Node").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Object").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Field").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Transition").SetColor(rawconfig.Color.Bold)
+	tree.AddColumn().AddText("Trigger").SetColor(rawconfig.Color.Bold)
+	for _, e := range t {
+		n := tree.AddNode()
+		n.AddColumn().AddText(e.At.Format(time.RFC3339))
+		n.AddColumn().AddText(e.Node).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(e.Path.String()).SetColor(rawconfig.Color.Primary)
+		n.AddColumn().AddText(e.Field)
+		n.AddColumn().AddText(e.From + " -> " + e.To)
+		n.AddColumn().AddText(e.Trigger).SetColor(rawconfig.Color.Secondary)
+	}
+	return tree.Render()
+}
@@ -21,6 +21,7 @@ type (
 		OptsGlobal
 		Eval        bool
 		Impersonate string
+		Revision    int
 	}
 )
 
@@ -46,6 +47,10 @@ func (t *CmdObjectPrintConfig) extract(selector string, c *client.T) (result, er
 }
 
 func (t *CmdObjectPrintConfig) extractOne(p path.T, c *client.T) (rawconfig.T, error) {
+	if t.Revision > 0 {
+		// the revision history is local to the node
+		return t.extractRevision(p)
+	}
 	if data, err := t.extractFromDaemon(p, c); err == nil {
 		return data, nil
 	}
@@ -69,6 +74,14 @@ func (t *CmdObjectPrintConfig) extractLocal(p path.T) (rawconfig.T, error) {
 	return obj.PrintConfig()
 }
 
+func (t *CmdObjectPrintConfig) extractRevision(p path.T) (rawconfig.T, error) {
+	obj, err := object.NewConfigurer(p)
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	return obj.ConfigRevision(t.Revision)
+}
+
 func (t *CmdObjectPrintConfig) extractFromDaemon(p path.T, c *client.T) (rawconfig.T, error) {
 	var (
 		err error
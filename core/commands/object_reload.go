@@ -0,0 +1,48 @@
+package commands
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	CmdObjectReload struct {
+		OptsGlobal
+		OptsLock
+		OptsResourceSelector
+		DryRun bool
+	}
+)
+
+func (t *CmdObjectReload) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRID(t.RID),
+		objectaction.WithTag(t.Tag),
+		objectaction.WithSubset(t.Subset),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithColor(t.Color),
+		objectaction.WithRemoteNodes(t.NodeSelector),
+		objectaction.WithRemoteAction("reload"),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, err := object.NewActor(p)
+			if err != nil {
+				return nil, err
+			}
+			ctx := context.Background()
+			ctx = actioncontext.WithLockDisabled(ctx, t.Disable)
+			ctx = actioncontext.WithLockTimeout(ctx, t.Timeout)
+			ctx = actioncontext.WithRID(ctx, t.RID)
+			ctx = actioncontext.WithTag(ctx, t.Tag)
+			ctx = actioncontext.WithSubset(ctx, t.Subset)
+			ctx = actioncontext.WithDryRun(ctx, t.DryRun)
+			return nil, o.Reload(ctx)
+		}),
+	).Do()
+}
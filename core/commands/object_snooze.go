@@ -0,0 +1,52 @@
+package commands
+
+import (
+	"context"
+	"time"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/objectaction"
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	CmdObjectSnooze struct {
+		OptsGlobal
+		OptsLock
+		OptsResourceSelector
+		Duration time.Duration
+	}
+)
+
+func (t *CmdObjectSnooze) Run(selector, kind string) error {
+	mergedSelector := mergeSelector(selector, t.ObjectSelector, kind, "")
+	return objectaction.New(
+		objectaction.LocalFirst(),
+		objectaction.WithLocal(t.Local),
+		objectaction.WithColor(t.Color),
+		objectaction.WithFormat(t.Format),
+		objectaction.WithObjectSelector(mergedSelector),
+		objectaction.WithRemoteNodes(t.NodeSelector),
+		objectaction.WithRemoteAction("snooze"),
+		objectaction.WithRemoteOptions(map[string]interface{}{
+			"rid":      t.RID,
+			"tag":      t.Tag,
+			"subset":   t.Subset,
+			"duration": t.Duration.String(),
+		}),
+		objectaction.WithLocalRun(func(p path.T) (interface{}, error) {
+			o, err := object.NewActor(p)
+			if err != nil {
+				return nil, err
+			}
+			ctx := context.Background()
+			ctx = actioncontext.WithLockDisabled(ctx, t.Disable)
+			ctx = actioncontext.WithLockTimeout(ctx, t.Timeout)
+			ctx = actioncontext.WithRID(ctx, t.RID)
+			ctx = actioncontext.WithTag(ctx, t.Tag)
+			ctx = actioncontext.WithSubset(ctx, t.Subset)
+			return nil, o.Snooze(ctx, t.Duration)
+		}),
+	).Do()
+}
@@ -112,6 +112,13 @@ func (t Group) String() string {
 	return ""
 }
 
+// IsResource returns true if the drivers of the group are resource
+// drivers, configured in object sections. The zero value is not a
+// resource group.
+func (t Group) IsResource() bool {
+	return t != 0 && (t&resourceGroups) == t
+}
+
 // MarshalJSON marshals the enum as a quoted json string
 func (t Group) MarshalJSON() ([]byte, error) {
 	buffer := bytes.NewBufferString(`"`)
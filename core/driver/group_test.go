@@ -0,0 +1,16 @@
+package driver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupIsResource(t *testing.T) {
+	assert.True(t, GroupFS.IsResource())
+	assert.True(t, (GroupFS | GroupDisk).IsResource())
+	assert.False(t, Group(0).IsResource())
+	assert.False(t, GroupUnknown.IsResource())
+	assert.False(t, GroupPool.IsResource())
+	assert.False(t, NewGroup("foo").IsResource())
+}
@@ -6,14 +6,19 @@ import (
 	_ "opensvc.com/opensvc/drivers/networkbridge"
 	_ "opensvc.com/opensvc/drivers/networklo"
 	_ "opensvc.com/opensvc/drivers/networkroutedbridge"
+	_ "opensvc.com/opensvc/drivers/poolbtrfs"
+	_ "opensvc.com/opensvc/drivers/pooldrbd"
 	_ "opensvc.com/opensvc/drivers/poolloop"
 	_ "opensvc.com/opensvc/drivers/poolvg"
 	_ "opensvc.com/opensvc/drivers/rescontainerdocker"
 	_ "opensvc.com/opensvc/drivers/rescontainerkvm"
 	_ "opensvc.com/opensvc/drivers/rescontainerlxc"
 	_ "opensvc.com/opensvc/drivers/resdiskcrypt"
+	_ "opensvc.com/opensvc/drivers/resdiskdrbd"
 	_ "opensvc.com/opensvc/drivers/resdiskzpool"
 	_ "opensvc.com/opensvc/drivers/resdiskzvol"
+	_ "opensvc.com/opensvc/drivers/resfsbtrfs"
 	_ "opensvc.com/opensvc/drivers/resipcni"
 	_ "opensvc.com/opensvc/drivers/resipnetns"
+	_ "opensvc.com/opensvc/drivers/restaskdocker"
 )
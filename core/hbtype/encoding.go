@@ -0,0 +1,73 @@
+package hbtype
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	gojson "github.com/goccy/go-json"
+
+	"opensvc.com/opensvc/util/jsonpack"
+)
+
+const (
+	// EncodingVersion is the hb message encoding version announced by the
+	// nodes able to decode the messages in the FormatBinary format, and
+	// compressed with an algorithm other than zlib. The nodes not announcing
+	// an encoding version only decode the legacy json format, zlib
+	// compressed.
+	EncodingVersion uint64 = 1
+
+	// FormatJSON is the legacy message format, decoded by all nodes.
+	FormatJSON = "json"
+
+	// FormatBinary is the compact binary message format.
+	FormatBinary = "binary"
+)
+
+var (
+	// binaryMagic is the header of the messages in FormatBinary format,
+	// followed by the format version.
+	binaryMagic = []byte("\x00hb")
+
+	binaryVersion byte = 1
+)
+
+// Marshal returns the encoding of msg in the format.
+func Marshal(msg Msg, format string) ([]byte, error) {
+	b, err := gojson.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+	switch format {
+	case "", FormatJSON:
+		return b, nil
+	case FormatBinary:
+		packed, err := jsonpack.Pack(b)
+		if err != nil {
+			return nil, err
+		}
+		buf := make([]byte, 0, len(binaryMagic)+1+len(packed))
+		buf = append(buf, binaryMagic...)
+		buf = append(buf, binaryVersion)
+		return append(buf, packed...), nil
+	default:
+		return nil, fmt.Errorf("unsupported hb message format %s", format)
+	}
+}
+
+// Unmarshal decodes b, in any supported format, into msg.
+func Unmarshal(b []byte, msg *Msg) error {
+	if !bytes.HasPrefix(b, binaryMagic) {
+		return json.Unmarshal(b, msg)
+	}
+	b = b[len(binaryMagic):]
+	if len(b) == 0 || b[0] != binaryVersion {
+		return fmt.Errorf("unsupported hb message binary format version")
+	}
+	unpacked, err := jsonpack.Unpack(b[1:])
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(unpacked, msg)
+}
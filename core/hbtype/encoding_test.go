@@ -0,0 +1,59 @@
+package hbtype
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/status"
+)
+
+func TestMarshalUnmarshal(t *testing.T) {
+	instances := make(map[string]instance.Instance)
+	for _, s := range []string{"svc1", "svc2", "svc3"} {
+		instances[s] = instance.Instance{
+			Status: &instance.Status{Avail: status.Up, Overall: status.Up},
+		}
+	}
+	msg := Msg{
+		Kind:     "full",
+		Compat:   12,
+		Encoding: EncodingVersion,
+		Gen:      map[string]uint64{"node1": 10, "node2": 3},
+		Updated:  time.Now().Round(0),
+		Nodename: "node1",
+		Full: node.Node{
+			Instance: instances,
+			Monitor:  node.Monitor{State: node.MonitorStateIdle},
+		},
+	}
+	legacy, err := json.Marshal(msg)
+	require.NoError(t, err)
+	for _, format := range []string{FormatJSON, FormatBinary} {
+		t.Run(format, func(t *testing.T) {
+			b, err := Marshal(msg, format)
+			require.NoError(t, err)
+			if format == FormatBinary {
+				assert.Less(t, len(b), len(legacy))
+			}
+			var decoded Msg
+			require.NoError(t, Unmarshal(b, &decoded))
+			decodedB, err := json.Marshal(decoded)
+			require.NoError(t, err)
+			assert.JSONEq(t, string(legacy), string(decodedB))
+		})
+	}
+	t.Run("unsupported format", func(t *testing.T) {
+		_, err := Marshal(msg, "xml")
+		assert.Error(t, err)
+	})
+	t.Run("unsupported binary version", func(t *testing.T) {
+		var decoded Msg
+		assert.Error(t, Unmarshal([]byte("\x00hb\x09"), &decoded))
+	})
+}
@@ -13,6 +13,7 @@ type (
 	Msg struct {
 		Kind     string                     `json:"kind"`
 		Compat   uint64                     `json:"compat"`
+		Encoding uint64                     `json:"encoding,omitempty"`
 		Gen      map[string]uint64          `json:"gen"`
 		Updated  time.Time                  `json:"updated"`
 		Ping     node.Monitor               `json:"monitor"`
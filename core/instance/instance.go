@@ -28,6 +28,8 @@ type (
 	// timestamp of last change and the nodes it should be installed on.
 	Config struct {
 		Checksum        string                    `json:"csum"`
+		Failback        string                    `json:"failback,omitempty"`
+		FailbackDelay   time.Duration             `json:"failback_delay,omitempty"`
 		FlexMax         int                       `json:"flex_max,omitempty"`
 		FlexMin         int                       `json:"flex_min,omitempty"`
 		FlexTarget      int                       `json:"flex_target,omitempty"`
@@ -47,10 +49,17 @@ type (
 		RestartDelay *time.Duration
 		IsMonitored  bool
 		IsDisabled   bool
+		ReloadPolicy ReloadPolicy
+		SecretsEnv   []string
+		ConfigsEnv   []string
 	}
 
 	MonitorAction string
 
+	// ReloadPolicy is the action the daemon takes when a sec or cfg key
+	// consumed by a resource changes.
+	ReloadPolicy string
+
 	// Status describes the instance status.
 	Status struct {
 		App         string                   `json:"app,omitempty"`
@@ -93,6 +102,11 @@ var (
 	MonitorActionFreezeStop MonitorAction = "freeze_stop"
 	MonitorActionReboot     MonitorAction = "reboot"
 	MonitorActionSwitch     MonitorAction = "switch"
+
+	ReloadPolicyNone           ReloadPolicy = "none"
+	ReloadPolicySignal         ReloadPolicy = "signal"
+	ReloadPolicyCommand        ReloadPolicy = "command"
+	ReloadPolicyRollingRestart ReloadPolicy = "rolling_restart"
 )
 
 // Has is true if the rid is found running in the Instance Monitor data sent by the daemon.
@@ -21,6 +21,7 @@ type (
 		State                   MonitorState        `json:"state"`
 		StateUpdated            time.Time           `json:"state_updated"`
 		MonitorActionExecutedAt time.Time           `json:"monitor_action_executed_at"`
+		FailbackScheduled       time.Time           `json:"failback_scheduled,omitempty"`
 		Resources               ResourceMonitorMap  `json:"resources,omitempty"`
 	}
 
@@ -36,10 +37,11 @@ type (
 		State               *MonitorState        `json:"state"`
 	}
 
-	// ResourceMonitor describes the restart states maintained by the daemon
-	// for an object instance.
+	// ResourceMonitor describes the restart and reload states maintained by
+	// the daemon for an object instance.
 	ResourceMonitor struct {
 		Restart ResourceMonitorRestart `json:"restart"`
+		Reload  ResourceMonitorReload  `json:"reload"`
 	}
 	ResourceMonitorRestart struct {
 		Remaining int         `json:"remaining"`
@@ -47,6 +49,14 @@ type (
 		Timer     *time.Timer `json:"-"`
 	}
 
+	// ResourceMonitorReload describes the reload state of a resource with
+	// a reload policy. Csum is the checksum of the sec and cfg key values
+	// the resource was last started or reloaded with. Peers use it to
+	// serialize the rolling restarts.
+	ResourceMonitorReload struct {
+		Csum string `json:"csum,omitempty"`
+	}
+
 	MonitorState        int
 	MonitorLocalExpect  int
 	MonitorGlobalExpect int
@@ -69,6 +79,7 @@ const (
 	MonitorStateProvisionFailed
 	MonitorStatePurgeFailed
 	MonitorStateReady
+	MonitorStateReloading
 	MonitorStateShutting
 	MonitorStateStarted
 	MonitorStateStartFailed
@@ -82,10 +93,16 @@ const (
 	MonitorStateUnprovisioned
 	MonitorStateUnprovisionFailed
 	MonitorStateUnprovisioning
+	MonitorStateWaitChildren
 	MonitorStateWaitLeader
 	MonitorStateWaitNonLeader
+	MonitorStateWaitParents
 )
 
+// CompatMonitorStateWaitRelations is the minimal node compat version able
+// to decode the wait parents and wait children monitor states.
+const CompatMonitorStateWaitRelations uint64 = 13
+
 const (
 	MonitorLocalExpectUnset MonitorLocalExpect = iota
 	MonitorLocalExpectStarted
@@ -120,6 +137,7 @@ var (
 		MonitorStateProvisionFailed:   "provision failed",
 		MonitorStatePurgeFailed:       "purge failed",
 		MonitorStateReady:             "ready",
+		MonitorStateReloading:         "reloading",
 		MonitorStateShutting:          "shutting",
 		MonitorStateStarted:           "started",
 		MonitorStateStartFailed:       "start failed",
@@ -133,8 +151,10 @@ var (
 		MonitorStateUnprovisioned:     "unprovisioned",
 		MonitorStateUnprovisionFailed: "unprovision failed",
 		MonitorStateUnprovisioning:    "unprovisioning",
+		MonitorStateWaitChildren:      "wait children",
 		MonitorStateWaitLeader:        "wait leader",
 		MonitorStateWaitNonLeader:     "wait non-leader",
+		MonitorStateWaitParents:       "wait parents",
 	}
 
 	MonitorStateValues = map[string]MonitorState{
@@ -150,6 +170,7 @@ var (
 		"provision failed":   MonitorStateProvisionFailed,
 		"purge failed":       MonitorStatePurgeFailed,
 		"ready":              MonitorStateReady,
+		"reloading":          MonitorStateReloading,
 		"shutting":           MonitorStateShutting,
 		"started":            MonitorStateStarted,
 		"start failed":       MonitorStateStartFailed,
@@ -163,8 +184,10 @@ var (
 		"unprovisioned":      MonitorStateUnprovisioned,
 		"unprovision failed": MonitorStateUnprovisionFailed,
 		"unprovisioning":     MonitorStateUnprovisioning,
+		"wait children":      MonitorStateWaitChildren,
 		"wait leader":        MonitorStateWaitLeader,
 		"wait non-leader":    MonitorStateWaitNonLeader,
+		"wait parents":       MonitorStateWaitParents,
 	}
 
 	MonitorLocalExpectStrings = map[MonitorLocalExpect]string{
@@ -318,6 +341,14 @@ func (m ResourceMonitorMap) DecRestartRemaining(rid string) {
 	}
 }
 
+func (m ResourceMonitorMap) GetReloadCsum(rid string) (string, bool) {
+	if rmon, ok := m[rid]; ok {
+		return rmon.Reload.Csum, true
+	} else {
+		return "", false
+	}
+}
+
 func (m ResourceMonitorMap) GetRestartRemaining(rid string) (int, bool) {
 	if rmon, ok := m[rid]; ok {
 		return rmon.Restart.Remaining, true
@@ -342,6 +373,13 @@ func (m ResourceMonitorMap) HasRestartTimer(rid string) bool {
 	}
 }
 
+func (m ResourceMonitorMap) SetReloadCsum(rid string, v string) {
+	if rmon, ok := m[rid]; ok {
+		rmon.Reload.Csum = v
+		m[rid] = rmon
+	}
+}
+
 func (m ResourceMonitorMap) SetRestartLastAt(rid string, v time.Time) {
 	if rmon, ok := m[rid]; ok {
 		rmon.Restart.LastAt = v
@@ -0,0 +1,202 @@
+package instance
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"time"
+
+	"opensvc.com/opensvc/core/path"
+)
+
+type (
+	// MonitorTransition is a change of the state, local expect or global
+	// expect of an instance or node monitor.
+	MonitorTransition struct {
+		At   time.Time `json:"at"`
+		Node string    `json:"node"`
+
+		// Field is the changed instance monitor field: state,
+		// local_expect or global_expect.
+		Field string `json:"field"`
+
+		From string `json:"from"`
+		To   string `json:"to"`
+
+		// Trigger describes the event or the request causing the
+		// transition.
+		Trigger string `json:"trigger"`
+	}
+
+	// MonitorHistory is the list of the instance monitor transitions of an
+	// object on a node, the oldest first.
+	MonitorHistory []MonitorTransition
+
+	// MonitorHistoryWriter appends monitor transitions to a json lines
+	// file, and trims the file periodically.
+	MonitorHistoryWriter struct {
+		filename string
+
+		// lines is the number of lines written since the last trim,
+		// plus the lines kept by the last trim.
+		lines int
+
+		// trimmed is the time of the last trim. The first Append trims
+		// the file, which counts the lines of the existing file.
+		trimmed time.Time
+	}
+)
+
+const (
+	MonitorTransitionFieldState        = "state"
+	MonitorTransitionFieldLocalExpect  = "local_expect"
+	MonitorTransitionFieldGlobalExpect = "global_expect"
+)
+
+var (
+	// MonitorHistoryMaxEntries is the maximum number of transitions kept
+	// in a monitor history.
+	MonitorHistoryMaxEntries = 1000
+
+	// MonitorHistoryMaxAge is the maximum age of the transitions kept in a
+	// monitor history.
+	MonitorHistoryMaxAge = 30 * 24 * time.Hour
+
+	// MonitorHistoryTrimInterval is the maximum delay between two trims
+	// of a monitor history file.
+	MonitorHistoryTrimInterval = 24 * time.Hour
+)
+
+// MonitorHistoryFile returns the path of the file recording the instance
+// monitor transitions of the object on the local node.
+func MonitorHistoryFile(p path.T) string {
+	return filepath.Join(p.VarDir(), "monitor_history.jsonl")
+}
+
+// LoadMonitorHistory returns the instance monitor transitions of the object
+// persisted on the local node.
+func LoadMonitorHistory(p path.T) (MonitorHistory, error) {
+	return LoadMonitorHistoryFile(MonitorHistoryFile(p))
+}
+
+// LoadMonitorHistoryFile returns the transitions, within the retention
+// bounds, recorded in the json lines file filename. The corrupted lines are
+// skipped.
+func LoadMonitorHistoryFile(filename string) (MonitorHistory, error) {
+	l := make(MonitorHistory, 0)
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return l, nil
+	} else if err != nil {
+		return l, err
+	}
+	defer func() { _ = f.Close() }()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var e MonitorTransition
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		l = append(l, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return l, err
+	}
+	return l.trim(time.Now()), nil
+}
+
+// NewMonitorHistoryWriter returns a writer appending the transitions to the
+// json lines file filename.
+func NewMonitorHistoryWriter(filename string) *MonitorHistoryWriter {
+	return &MonitorHistoryWriter{filename: filename}
+}
+
+// Append appends the transitions to the history file. The file is rewritten
+// without the transitions out of the retention bounds when its line count
+// exceeds twice MonitorHistoryMaxEntries, or when the last trim is older
+// than MonitorHistoryTrimInterval.
+func (t *MonitorHistoryWriter) Append(transitions ...MonitorTransition) error {
+	if len(transitions) == 0 {
+		return nil
+	}
+	var b []byte
+	for _, e := range transitions {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b = append(append(b, line...), '\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(t.filename), os.ModePerm); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(t.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	t.lines += len(transitions)
+	if t.lines > 2*MonitorHistoryMaxEntries || time.Since(t.trimmed) > MonitorHistoryTrimInterval {
+		return t.trim()
+	}
+	return nil
+}
+
+// trim rewrites the history file without the transitions out of the
+// retention bounds.
+func (t *MonitorHistoryWriter) trim() error {
+	l, err := LoadMonitorHistoryFile(t.filename)
+	if err != nil {
+		return err
+	}
+	var b []byte
+	for _, e := range l {
+		line, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		b = append(append(b, line...), '\n')
+	}
+	tmp := t.filename + ".tmp"
+	if err := os.WriteFile(tmp, b, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, t.filename); err != nil {
+		return err
+	}
+	t.lines = len(l)
+	t.trimmed = time.Now()
+	return nil
+}
+
+// trim returns the transitions within the retention bounds.
+func (t MonitorHistory) trim(now time.Time) MonitorHistory {
+	minAt := now.Add(-MonitorHistoryMaxAge)
+	i := 0
+	for i < len(t) && t[i].At.Before(minAt) {
+		i++
+	}
+	if n := len(t) - i; n > MonitorHistoryMaxEntries {
+		i += n - MonitorHistoryMaxEntries
+	}
+	return t[i:]
+}
+
+// Since returns the transitions at or after tm.
+func (t MonitorHistory) Since(tm time.Time) MonitorHistory {
+	l := make(MonitorHistory, 0)
+	for _, e := range t {
+		if !e.At.Before(tm) {
+			l = append(l, e)
+		}
+	}
+	return l
+}
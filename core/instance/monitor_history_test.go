@@ -0,0 +1,102 @@
+package instance
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+)
+
+func Test_MonitorHistory_Append(t *testing.T) {
+	rawconfig.Paths.Var = t.TempDir()
+	p, err := path.Parse("svc1")
+	require.Nil(t, err)
+
+	l, err := LoadMonitorHistory(p)
+	require.Nil(t, err)
+	require.Len(t, l, 0)
+
+	w := NewMonitorHistoryWriter(MonitorHistoryFile(p))
+	now := time.Now()
+	require.Nil(t, w.Append(MonitorTransition{
+		At:      now.Add(-time.Minute),
+		Node:    "node1",
+		Field:   MonitorTransitionFieldGlobalExpect,
+		From:    "unset",
+		To:      "started",
+		Trigger: "set instance monitor request from api user root",
+	}))
+	require.Nil(t, w.Append(MonitorTransition{
+		At:      now,
+		Node:    "node1",
+		Field:   MonitorTransitionFieldState,
+		From:    "idle",
+		To:      "starting",
+		Trigger: "object status update from node node1",
+	}))
+	l, err = LoadMonitorHistory(p)
+	require.Nil(t, err)
+	require.Len(t, l, 2)
+	assert.Equal(t, "started", l[0].To)
+	assert.Equal(t, "starting", l[1].To)
+	assert.Len(t, l.Since(now), 1)
+
+	l, err = LoadMonitorHistoryFile(filepath.Join(rawconfig.Paths.Var, "notexist"))
+	require.Nil(t, err)
+	require.Len(t, l, 0)
+}
+
+func Test_MonitorHistoryWriter_Trim(t *testing.T) {
+	maxEntries := MonitorHistoryMaxEntries
+	defer func() { MonitorHistoryMaxEntries = maxEntries }()
+	MonitorHistoryMaxEntries = 2
+
+	filename := filepath.Join(t.TempDir(), "monitor_history.jsonl")
+	countLines := func() int {
+		b, err := os.ReadFile(filename)
+		require.Nil(t, err)
+		return strings.Count(string(b), "\n")
+	}
+	w := NewMonitorHistoryWriter(filename)
+	now := time.Now()
+	for i := 0; i < 4; i++ {
+		require.Nil(t, w.Append(MonitorTransition{At: now, To: fmt.Sprint(i)}))
+	}
+	assert.Equal(t, 4, countLines(), "the file must grow up to twice the max entries")
+
+	require.Nil(t, w.Append(MonitorTransition{At: now, To: "4"}))
+	assert.Equal(t, 2, countLines(), "the file must be trimmed when exceeding twice the max entries")
+
+	l, err := LoadMonitorHistoryFile(filename)
+	require.Nil(t, err)
+	require.Len(t, l, 2)
+	assert.Equal(t, "3", l[0].To)
+	assert.Equal(t, "4", l[1].To)
+}
+
+func Test_MonitorHistory_Trim(t *testing.T) {
+	maxEntries := MonitorHistoryMaxEntries
+	defer func() { MonitorHistoryMaxEntries = maxEntries }()
+	MonitorHistoryMaxEntries = 3
+
+	now := time.Now()
+	l := MonitorHistory{
+		{At: now.Add(-MonitorHistoryMaxAge - time.Hour), To: "expired"},
+		{At: now.Add(-4 * time.Minute), To: "a"},
+		{At: now.Add(-3 * time.Minute), To: "b"},
+		{At: now.Add(-2 * time.Minute), To: "c"},
+		{At: now.Add(-1 * time.Minute), To: "d"},
+	}
+	l = l.trim(now)
+	require.Len(t, l, 3)
+	assert.Equal(t, "b", l[0].To)
+	assert.Equal(t, "d", l[2].To)
+}
@@ -0,0 +1,158 @@
+package keywords
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+type (
+	// Schema is a JSON Schema document, or a subschema.
+	Schema map[string]any
+)
+
+const (
+	// SchemaDialect is the JSON Schema dialect of the generated schemas.
+	SchemaDialect = "https://json-schema.org/draft/2020-12/schema"
+)
+
+// Schema returns the JSON Schema of the keyword value. All values are
+// strings in the ini formatted configuration files, so the keyword type is
+// exposed as the "x-converter" annotation.
+func (t Keyword) Schema() Schema {
+	s := Schema{
+		"type": "string",
+	}
+	if t.Text != "" {
+		s["description"] = t.Text
+	}
+	if t.Default != "" {
+		s["default"] = t.Default
+	}
+	if len(t.Candidates) > 0 {
+		s["enum"] = t.Candidates
+	}
+	if t.Example != "" {
+		s["examples"] = []string{t.Example}
+	}
+	if t.Deprecated != "" || t.ReplacedBy != "" {
+		s["deprecated"] = true
+	}
+	if t.Converter != nil {
+		s["x-converter"] = fmt.Sprint(t.Converter)
+	}
+	if t.Scopable {
+		s["x-scopable"] = true
+	}
+	if t.Provisioning {
+		s["x-provisioning"] = true
+	}
+	return s
+}
+
+// SectionSchema returns the JSON Schema of a configuration section
+// accepting the keywords of the store. The scopable keywords also accept
+// the <option>@<scope> form. If typ is not empty, the section "type"
+// keyword must be set to typ, unless optionalType is true.
+func (t Store) SectionSchema(typ string, optionalType bool) Schema {
+	properties := Schema{}
+	patternProperties := Schema{}
+	required := make([]string, 0)
+	add := func(option string, kw Keyword) {
+		kwSchema := kw.Schema()
+		properties[option] = kwSchema
+		if kw.Scopable {
+			patternProperties["^"+regexp.QuoteMeta(option)+"@.+$"] = kwSchema
+		}
+	}
+	for _, kw := range t {
+		add(kw.Option, kw)
+		for _, alias := range kw.Aliases {
+			aliasKw := kw
+			aliasKw.ReplacedBy = kw.Option
+			add(alias, aliasKw)
+		}
+		if kw.Required && !kw.Scopable {
+			required = append(required, kw.Option)
+		}
+	}
+	if typ != "" {
+		typeSchema := Schema{"const": typ}
+		if kw, ok := properties["type"]; ok {
+			if desc, ok := kw.(Schema)["description"]; ok {
+				typeSchema["description"] = desc
+			}
+		}
+		properties["type"] = typeSchema
+		if !optionalType {
+			required = append(required, "type")
+		}
+	}
+	s := Schema{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+	if len(patternProperties) > 0 {
+		s["patternProperties"] = patternProperties
+	}
+	if len(required) > 0 {
+		sort.Strings(required)
+		s["required"] = required
+	}
+	return s
+}
+
+// SectionTypes returns the sorted list of section types the keywords of
+// the store are limited to.
+func (t Store) SectionTypes() []string {
+	m := make(map[string]any)
+	for _, kw := range t {
+		for _, typ := range kw.Types {
+			m[typ] = nil
+		}
+	}
+	l := make([]string, 0, len(m))
+	for typ := range m {
+		l = append(l, typ)
+	}
+	sort.Strings(l)
+	return l
+}
+
+// WithSection returns the keywords of the section, and the keywords
+// applying to all sections.
+func (t Store) WithSection(section string) Store {
+	l := make(Store, 0)
+	for _, kw := range t {
+		if kw.Section == section || kw.Section == "" {
+			l = append(l, kw)
+		}
+	}
+	return l
+}
+
+// WithType returns the keywords accepted in a section of type typ: the
+// keywords not limited to section types, and the keywords limited to typ.
+func (t Store) WithType(typ string) Store {
+	l := make(Store, 0)
+	for _, kw := range t {
+		if len(kw.Types) == 0 {
+			l = append(l, kw)
+			continue
+		}
+		for _, kwType := range kw.Types {
+			if kwType == typ {
+				l = append(l, kw)
+				break
+			}
+		}
+	}
+	return l
+}
+
+// IndexedSectionPattern returns the regular expression matching the
+// <name>#<index> section names.
+func IndexedSectionPattern(name string) string {
+	return "^" + regexp.QuoteMeta(name) + "#.+$"
+}
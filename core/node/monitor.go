@@ -16,8 +16,42 @@ type (
 		StateUpdated        time.Time           `json:"state_updated"`
 		GlobalExpectUpdated time.Time           `json:"global_expect_updated"`
 		LocalExpectUpdated  time.Time           `json:"local_expect_updated"`
+
+		// Evacuation is the progress of the switches of the local failover
+		// object instances, indexed by object path, during and after a
+		// node evacuation.
+		Evacuation map[string]EvacuationItem `json:"evacuation,omitempty"`
+
+		// Upgraded is the global expect update time of the last rolling
+		// upgrade orchestration completed by the node.
+		Upgraded time.Time `json:"upgraded"`
+
+		// Maintenance is the maintenance declared by the operator on the
+		// node. The peer nodes don't take over the node objects while the
+		// maintenance is active.
+		Maintenance Maintenance `json:"maintenance"`
+	}
+
+	// Maintenance describes an operator-declared node maintenance. The
+	// maintenance is active from Since until Expire, or until left if
+	// Expire is zero.
+	Maintenance struct {
+		Since  time.Time `json:"since"`
+		Expire time.Time `json:"expire"`
+	}
+
+	// EvacuationItem describes the progress of the switch of a failover
+	// object away from the evacuated node.
+	EvacuationItem struct {
+		Destination string          `json:"destination,omitempty"`
+		State       EvacuationState `json:"state"`
+		Error       string          `json:"error,omitempty"`
+		Updated     time.Time       `json:"updated"`
 	}
 
+	// EvacuationState is the state of an EvacuationItem.
+	EvacuationState string
+
 	// MonitorUpdate is embedded in the SetNodeMonitor message to
 	// change some Monitor values. A nil value does not change the
 	// current value.
@@ -25,6 +59,9 @@ type (
 		State        *MonitorState        `json:"state"`
 		LocalExpect  *MonitorLocalExpect  `json:"local_expect"`
 		GlobalExpect *MonitorGlobalExpect `json:"global_expect"`
+
+		// Maintenance enters the maintenance, or leaves it if zero.
+		Maintenance *Maintenance `json:"maintenance"`
 	}
 
 	MonitorState        int
@@ -46,11 +83,36 @@ const (
 	MonitorStateMaintenance
 	MonitorStateUpgrade
 	MonitorStateRejoin
+	MonitorStateEvacuating
+	MonitorStateEvacuated
+	MonitorStateUpgradeFailed
+	MonitorStateRollingBack
 )
 
 const (
 	MonitorLocalExpectUnset MonitorLocalExpect = iota
 	MonitorLocalExpectDrained
+	MonitorLocalExpectEvacuated
+)
+
+const (
+	// EvacuationStatePending is the state of the switches not started yet.
+	EvacuationStatePending EvacuationState = "pending"
+
+	// EvacuationStateSwitching is the state of the running switches.
+	EvacuationStateSwitching EvacuationState = "switching"
+
+	// EvacuationStateSwitched is the state of the objects running on their
+	// destination node.
+	EvacuationStateSwitched EvacuationState = "switched"
+
+	// EvacuationStateFailed is the state of the switches not completed in
+	// time or failed. The local instance is shut down by the drain.
+	EvacuationStateFailed EvacuationState = "failed"
+
+	// EvacuationStateSkipped is the state of the objects without eligible
+	// destination node. The local instance is shut down by the drain.
+	EvacuationStateSkipped EvacuationState = "skipped"
 )
 
 const (
@@ -58,23 +120,28 @@ const (
 	MonitorGlobalExpectAborted
 	MonitorGlobalExpectFrozen
 	MonitorGlobalExpectThawed
+	MonitorGlobalExpectUpgraded
 )
 
 var (
 	MonitorStateStrings = map[MonitorState]string{
-		MonitorStateDraining:     "draining",
-		MonitorStateDrainFailed:  "drain failed",
-		MonitorStateIdle:         "idle",
-		MonitorStateThawedFailed: "unfreeze failed",
-		MonitorStateFreezeFailed: "freeze failed",
-		MonitorStateFreezing:     "freezing",
-		MonitorStateFrozen:       "frozen",
-		MonitorStateThawing:      "thawing",
-		MonitorStateShutting:     "shutting",
-		MonitorStateMaintenance:  "maintenance",
-		MonitorStateInit:         "init",
-		MonitorStateUpgrade:      "upgrade",
-		MonitorStateRejoin:       "rejoin",
+		MonitorStateDraining:      "draining",
+		MonitorStateDrainFailed:   "drain failed",
+		MonitorStateIdle:          "idle",
+		MonitorStateThawedFailed:  "unfreeze failed",
+		MonitorStateFreezeFailed:  "freeze failed",
+		MonitorStateFreezing:      "freezing",
+		MonitorStateFrozen:        "frozen",
+		MonitorStateThawing:       "thawing",
+		MonitorStateShutting:      "shutting",
+		MonitorStateMaintenance:   "maintenance",
+		MonitorStateInit:          "init",
+		MonitorStateUpgrade:       "upgrade",
+		MonitorStateRejoin:        "rejoin",
+		MonitorStateEvacuating:    "evacuating",
+		MonitorStateEvacuated:     "evacuated",
+		MonitorStateUpgradeFailed: "upgrade failed",
+		MonitorStateRollingBack:   "rolling back",
 	}
 
 	MonitorStateValues = map[string]MonitorState{
@@ -91,39 +158,49 @@ var (
 		"init":            MonitorStateInit,
 		"upgrade":         MonitorStateUpgrade,
 		"rejoin":          MonitorStateRejoin,
+		"evacuating":      MonitorStateEvacuating,
+		"evacuated":       MonitorStateEvacuated,
+		"upgrade failed":  MonitorStateUpgradeFailed,
+		"rolling back":    MonitorStateRollingBack,
 	}
 
 	MonitorLocalExpectStrings = map[MonitorLocalExpect]string{
-		MonitorLocalExpectUnset:   "unset",
-		MonitorLocalExpectDrained: "drained",
+		MonitorLocalExpectUnset:     "unset",
+		MonitorLocalExpectDrained:   "drained",
+		MonitorLocalExpectEvacuated: "evacuated",
 	}
 
 	MonitorLocalExpectValues = map[string]MonitorLocalExpect{
-		"unset":   MonitorLocalExpectUnset,
-		"drained": MonitorLocalExpectDrained,
+		"unset":     MonitorLocalExpectUnset,
+		"drained":   MonitorLocalExpectDrained,
+		"evacuated": MonitorLocalExpectEvacuated,
 	}
 
 	MonitorGlobalExpectStrings = map[MonitorGlobalExpect]string{
-		MonitorGlobalExpectAborted: "aborted",
-		MonitorGlobalExpectFrozen:  "frozen",
-		MonitorGlobalExpectThawed:  "thawed",
-		MonitorGlobalExpectUnset:   "unset",
+		MonitorGlobalExpectAborted:  "aborted",
+		MonitorGlobalExpectFrozen:   "frozen",
+		MonitorGlobalExpectThawed:   "thawed",
+		MonitorGlobalExpectUnset:    "unset",
+		MonitorGlobalExpectUpgraded: "upgraded",
 	}
 
 	MonitorGlobalExpectValues = map[string]MonitorGlobalExpect{
-		"aborted": MonitorGlobalExpectAborted,
-		"frozen":  MonitorGlobalExpectFrozen,
-		"thawed":  MonitorGlobalExpectThawed,
-		"unset":   MonitorGlobalExpectUnset,
+		"aborted":  MonitorGlobalExpectAborted,
+		"frozen":   MonitorGlobalExpectFrozen,
+		"thawed":   MonitorGlobalExpectThawed,
+		"unset":    MonitorGlobalExpectUnset,
+		"upgraded": MonitorGlobalExpectUpgraded,
 	}
 
 	// the node monitor states evicting a node from ranking algorithms
 	MonitorStateUnrankable = map[MonitorState]any{
-		MonitorStateMaintenance: nil,
-		MonitorStateUpgrade:     nil,
-		MonitorStateInit:        nil,
-		MonitorStateShutting:    nil,
-		MonitorStateRejoin:      nil,
+		MonitorStateMaintenance:   nil,
+		MonitorStateUpgrade:       nil,
+		MonitorStateUpgradeFailed: nil,
+		MonitorStateRollingBack:   nil,
+		MonitorStateInit:          nil,
+		MonitorStateShutting:      nil,
+		MonitorStateRejoin:        nil,
 	}
 )
 
@@ -136,9 +213,34 @@ func (t MonitorState) IsRankable() bool {
 	return !ok
 }
 
+// IsZero returns true if no maintenance is declared.
+func (t Maintenance) IsZero() bool {
+	return t.Since.IsZero()
+}
+
+// IsActive returns true if the maintenance is declared and not expired at tm.
+func (t Maintenance) IsActive(tm time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	return t.Expire.IsZero() || tm.Before(t.Expire)
+}
+
+// IsInMaintenance returns true if the node is in maintenance at tm, either
+// declared by the operator or announced by a stopping daemon.
+func (n Monitor) IsInMaintenance(tm time.Time) bool {
+	return n.State == MonitorStateMaintenance || n.Maintenance.IsActive(tm)
+}
+
 func (n *Monitor) DeepCopy() *Monitor {
 	var d Monitor
 	d = *n
+	if n.Evacuation != nil {
+		d.Evacuation = make(map[string]EvacuationItem, len(n.Evacuation))
+		for k, v := range n.Evacuation {
+			d.Evacuation[k] = v
+		}
+	}
 	return &d
 }
 
@@ -0,0 +1,40 @@
+package node
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMonitorIsInMaintenance(t *testing.T) {
+	now := time.Now()
+	cases := map[string]struct {
+		monitor  Monitor
+		expected bool
+	}{
+		"no maintenance": {
+			monitor: Monitor{State: MonitorStateIdle},
+		},
+		"declared without expire": {
+			monitor:  Monitor{State: MonitorStateIdle, Maintenance: Maintenance{Since: now.Add(-time.Hour)}},
+			expected: true,
+		},
+		"declared not expired": {
+			monitor:  Monitor{Maintenance: Maintenance{Since: now.Add(-time.Hour), Expire: now.Add(time.Minute)}},
+			expected: true,
+		},
+		"declared expired": {
+			monitor: Monitor{Maintenance: Maintenance{Since: now.Add(-time.Hour), Expire: now.Add(-time.Minute)}},
+		},
+		"announced by a stopping daemon": {
+			monitor:  Monitor{State: MonitorStateMaintenance},
+			expected: true,
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.expected, c.monitor.IsInMaintenance(now))
+		})
+	}
+}
@@ -202,9 +202,10 @@ func (t T) DoAsync() error {
 		return err
 	}
 	req := c.NewPostNodeMonitor()
-	if t.Target == "drained" {
+	switch t.Target {
+	case "drained", "evacuated":
 		req.LocalExpect = t.Target
-	} else {
+	default:
 		req.GlobalExpect = t.Target
 	}
 	b, err := req.Do()
@@ -55,6 +55,7 @@ type (
 		ConfigureResources()
 		IsDisabled() bool
 
+		Reload(context.Context) error
 		Restart(context.Context) error
 		Run(context.Context) error
 		Shutdown(context.Context) error
@@ -66,6 +67,7 @@ type (
 		Unprovision(context.Context) error
 		SetProvisioned(context.Context) error
 		SetUnprovisioned(context.Context) error
+		Snooze(context.Context, time.Duration) error
 		SyncResync(context.Context) error
 		Enter(context.Context, string) error
 
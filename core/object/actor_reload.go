@@ -0,0 +1,45 @@
+package object
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/resource"
+)
+
+// Reload applies the reload policy of the selected resources, so their
+// processes pick up the changed sec and cfg keys they consume.
+func (t *actor) Reload(ctx context.Context) error {
+	ctx = actioncontext.WithProps(ctx, actioncontext.Reload)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv("reload", false)
+	unlock, err := t.lockAction(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return t.lockedReload(ctx)
+}
+
+func (t *actor) lockedReload(ctx context.Context) error {
+	if err := t.masterReload(ctx); err != nil {
+		return err
+	}
+	if err := t.slaveReload(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *actor) masterReload(ctx context.Context) error {
+	return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+		t.log.Debug().Str("rid", r.RID()).Msg("reload resource")
+		return resource.Reload(ctx, r)
+	})
+}
+
+func (t *actor) slaveReload(ctx context.Context) error {
+	return nil
+}
@@ -0,0 +1,31 @@
+package object
+
+import (
+	"context"
+	"time"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/resource"
+)
+
+// Snooze silences the status alert of the selected resources for the
+// duration d. A zero duration ends the snooze.
+func (t *actor) Snooze(ctx context.Context, d time.Duration) error {
+	ctx = actioncontext.WithProps(ctx, actioncontext.Snooze)
+	if err := t.validateAction(); err != nil {
+		return err
+	}
+	t.setenv("snooze", false)
+	unlock, err := t.lockAction(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	return t.action(ctx, func(ctx context.Context, r resource.Driver) error {
+		i, ok := r.(resource.AlertSnoozer)
+		if !ok {
+			return nil
+		}
+		return i.SnoozeAlert(d)
+	})
+}
@@ -46,6 +46,7 @@ type (
 
 	compliancer interface {
 		NewCompliance() (*compliance.T, error)
+		NewComplianceFrom(string) (*compliance.T, error)
 	}
 
 	volatiler interface {
@@ -2,17 +2,21 @@ package object
 
 import "opensvc.com/opensvc/util/compliance"
 
-func (t core) NewCompliance() (*compliance.T, error) {
+func (t *core) NewCompliance() (*compliance.T, error) {
+	return t.NewComplianceFrom("")
+}
+
+// NewComplianceFrom returns a compliance handle using the collector or the
+// local rulesets and modulesets definitions, depending on source.
+func (t *core) NewComplianceFrom(source string) (*compliance.T, error) {
 	n, err := t.Node()
 	if err != nil {
 		return nil, err
 	}
-	client, err := n.CollectorComplianceClient()
+	comp, err := newCompliance(n, source)
 	if err != nil {
 		return nil, err
 	}
-	comp := compliance.New()
-	comp.SetCollectorClient(client)
 	comp.SetObjectPath(t.path)
 	return comp, nil
 }
@@ -338,7 +338,11 @@ func (t core) Dereference(ref string) (string, error) {
 	}
 	switch {
 	case strings.HasPrefix(ref, "safe://"):
-		return ref, fmt.Errorf("TODO")
+		b, err := resolveSecretRef(ref)
+		if err != nil {
+			return ref, err
+		}
+		return string(b), nil
 	case strings.Contains(ref, ".exposed_devs"):
 		return t.dereferenceExposedDevices(ref)
 	}
@@ -0,0 +1,50 @@
+package object
+
+import (
+	"context"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+)
+
+// ConfigRevisions returns the configuration revision history of the object.
+func (t *core) ConfigRevisions() (xconfig.Revisions, error) {
+	return xconfig.ListRevisions(t.path)
+}
+
+// ConfigRevision returns the configuration of the revision id.
+func (t *core) ConfigRevision(id int) (rawconfig.T, error) {
+	b, err := xconfig.ReadRevision(t.path, id)
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	cf, err := xconfig.NewObject("", b)
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	return cf.Raw(), nil
+}
+
+// DiffConfigRevisions returns the unified diff between the configuration
+// revisions a and b.
+func (t *core) DiffConfigRevisions(a, b int) (string, error) {
+	return xconfig.DiffRevisions(t.path, a, b)
+}
+
+// RollbackConfig commits the configuration of the revision id. The commit
+// is validated and recorded as a new revision, and the daemon propagates
+// the change to the peers like any other configuration change.
+func (t *core) RollbackConfig(ctx context.Context, id int) error {
+	ctx = actioncontext.WithProps(ctx, actioncontext.RollbackConfig)
+	unlock, err := t.lockAction(ctx)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+	c, err := t.ConfigRevision(id)
+	if err != nil {
+		return err
+	}
+	return t.config.CommitData(c)
+}
@@ -359,6 +359,25 @@ var keywordStore = keywords.Store{
 		Candidates: []string{"no", "ha", "start"},
 		Text:       "If set to ``no``, disable service orchestration by the OpenSVC daemon monitor, including service start on boot. If set to ``start`` failover services won't failover automatically, though the service instance on the natural placement leader is started if another instance is not already up. Flex services won't restart the :kw:`flex_target` number of up instances. Resource restart is still active whatever the :kw:`orchestrate` value.",
 	},
+	{
+		Section: "DEFAULT",
+		Option:  "failback",
+		Inherit: keywords.InheritHead,
+		Kind:    kind.Or(kind.Svc),
+		Depends: keyop.ParseList("topology=failover"),
+		Text:    "The schedule allowing the daemon monitor to place a failover service back on its preferred node, the first healthy node in the placement order, when it runs on another node. The failback is planned at the first allowed time after the preferred node has been healthy for :kw:`failback_delay`, and executed like a ``giveback``. No automatic failback if not set. See ``usr/share/doc/schedule`` for the schedule syntax.",
+		Example: "02:00-04:00 sat-sun",
+	},
+	{
+		Section:   "DEFAULT",
+		Option:    "failback_delay",
+		Inherit:   keywords.InheritHead,
+		Kind:      kind.Or(kind.Svc),
+		Converter: converters.Duration,
+		Default:   "10m",
+		Depends:   keyop.ParseList("topology=failover"),
+		Text:      "The duration the preferred node must stay healthy before a :kw:`failback` is planned.",
+	},
 	{
 		Section:   "DEFAULT",
 		Option:    "priority",
@@ -652,7 +671,7 @@ func driverIDFromRID(t Configurer, section string) (driver.ID, error) {
 
 func keywordLookup(store keywords.Store, k key.T, kd kind.T, sectionType string) keywords.Keyword {
 	switch k.Section {
-	case "data", "env":
+	case "data", "env", versionsSectionName:
 		return keywords.Keyword{
 			Option:   "*", // trick IsZero()
 			Scopable: true,
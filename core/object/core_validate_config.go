@@ -15,5 +15,11 @@ func (t *core) ValidateConfig(ctx context.Context) (xconfig.ValidateAlerts, erro
 		return xconfig.ValidateAlerts{}, err
 	}
 	defer unlock()
-	return t.config.Validate()
+	alerts, err := t.config.Validate()
+	semanticAlerts, semanticErr := t.config.ValidateSemantic()
+	alerts = append(alerts, semanticAlerts...)
+	if err != nil {
+		return alerts, err
+	}
+	return alerts, semanticErr
 }
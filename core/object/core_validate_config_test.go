@@ -0,0 +1,79 @@
+package object_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/testhelper"
+)
+
+func TestValidateConfigSemantic(t *testing.T) {
+	testhelper.Setup(t)
+	conf := []byte(`
+[DEFAULT]
+topology = flex
+nodes = n1 n2
+flex_target = 3
+
+[fs#1]
+type = ext4
+dev = /dev/sda1
+mnt = /srv/data
+subset = g1
+
+[fs#2]
+type = ext4
+dev = /dev/sda2
+mnt = /srv/data/
+
+[app#1]
+start_requires = fs#1 fs#9(up)
+
+[ip#1]
+type = netns
+netns = container#1
+
+[subset#fs:g1]
+parallel = true
+
+[subset#g1]
+parallel = false
+
+[subset#app:g2]
+parallel = true
+`)
+	p, err := path.Parse("semantic")
+	require.NoError(t, err)
+	s, err := object.NewSvc(p, object.WithConfigData(conf))
+	require.NoError(t, err)
+	alerts, err := s.ValidateConfig(context.Background())
+	assert.Error(t, err, "error level semantic alerts fail the validation")
+
+	found := make(map[string][]string)
+	for _, alert := range alerts {
+		if alert.Check == "" {
+			continue
+		}
+		found[alert.Check] = append(found[alert.Check], alert.Key.String())
+	}
+	assert.Equal(t, []string{"fs#2.mnt"}, found["fs-mnt-unique"])
+	assert.Equal(t, []string{"app#1.start_requires"}, found["requires-rid"])
+	assert.Equal(t, []string{"ip#1.netns"}, found["ip-netns-container"])
+	assert.ElementsMatch(t, []string{"subset#app:g2", "subset#fs:g1.parallel"}, found["subset-parallel"])
+	assert.Equal(t, []string{"flex_target"}, found["flex-target"])
+
+	for _, alert := range alerts {
+		switch alert.Check {
+		case "flex-target", "subset-parallel":
+			assert.Equal(t, xconfig.ValidateAlertLevelWarn, alert.Level)
+		case "fs-mnt-unique", "requires-rid", "ip-netns-container":
+			assert.Equal(t, xconfig.ValidateAlertLevelError, alert.Level)
+		}
+	}
+}
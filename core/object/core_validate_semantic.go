@@ -0,0 +1,135 @@
+package object
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/resourcereqs"
+	"opensvc.com/opensvc/core/resourceset"
+	"opensvc.com/opensvc/core/topology"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func init() {
+	xconfig.RegisterSemanticCheck(xconfig.SemanticCheck{
+		Name:  "requires-rid",
+		Level: xconfig.ValidateAlertLevelError,
+		Text:  "The resources referenced by the <action>_requires keywords exist.",
+		Func:  checkRequiresRID,
+	})
+	xconfig.RegisterSemanticCheck(xconfig.SemanticCheck{
+		Name:  "subset-parallel",
+		Level: xconfig.ValidateAlertLevelWarn,
+		Text:  "The subset sections have member resources, and the parallel flags of the driver group subset sections are the same as their generic subset section.",
+		Func:  checkSubsetParallel,
+	})
+	xconfig.RegisterSemanticCheck(xconfig.SemanticCheck{
+		Name:  "flex-target",
+		Level: xconfig.ValidateAlertLevelWarn,
+		Text:  "The flex_target of flex objects is not greater than the number of nodes.",
+		Func:  checkFlexTarget,
+	})
+}
+
+// checkRequiresRID verifies the resource ids in the <action>_requires
+// keywords, including the scoped ones, are defined in the configuration.
+func checkRequiresRID(t *xconfig.T) []xconfig.SemanticFinding {
+	l := make([]xconfig.SemanticFinding, 0)
+	for _, section := range t.ResourceSections(driver.GroupUnknown) {
+		for _, option := range t.Keys(section) {
+			k := key.New(section, option)
+			if !strings.HasSuffix(k.BaseOption(), "_requires") {
+				continue
+			}
+			for rid := range resourcereqs.New(t.Get(k)).Requirements() {
+				if t.HasSectionString(rid) {
+					continue
+				}
+				l = append(l, xconfig.SemanticFinding{
+					Key:     k,
+					Comment: fmt.Sprintf("requires the undefined resource %s", rid),
+				})
+			}
+		}
+	}
+	return l
+}
+
+// checkSubsetParallel verifies the subset sections have member resources,
+// and the parallel flag of the [subset#<group>:<name>] sections, which
+// override the [subset#<name>] sections, do not silently contradict them.
+func checkSubsetParallel(t *xconfig.T) []xconfig.SemanticFinding {
+	l := make([]xconfig.SemanticFinding, 0)
+	members := make(map[string]int)
+	for _, section := range t.ResourceSections(driver.GroupUnknown) {
+		name := t.Get(key.New(section, "subset"))
+		if name == "" {
+			continue
+		}
+		group := strings.SplitN(section, "#", 2)[0]
+		members[resourceset.FormatSectionName(group, name)]++
+		members["subset#"+name]++
+	}
+	for _, section := range t.SectionStrings() {
+		if !strings.HasPrefix(section, "subset#") {
+			continue
+		}
+		if members[section] == 0 {
+			l = append(l, xconfig.SemanticFinding{
+				Key:     key.T{Section: section},
+				Comment: "no resource is member of this subset",
+			})
+			continue
+		}
+		name := strings.TrimPrefix(section, "subset#")
+		i := strings.Index(name, ":")
+		if i < 0 {
+			continue
+		}
+		generic := "subset#" + name[i+1:]
+		if !t.HasSectionString(generic) {
+			continue
+		}
+		parallel := t.GetBool(key.New(section, "parallel"))
+		genericParallel := t.GetBool(key.New(generic, "parallel"))
+		if parallel != genericParallel {
+			l = append(l, xconfig.SemanticFinding{
+				Key:     key.New(section, "parallel"),
+				Comment: fmt.Sprintf("parallel=%t overrides parallel=%t in %s", parallel, genericParallel, generic),
+			})
+		}
+	}
+	return l
+}
+
+// checkFlexTarget verifies the flex_target of a flex object does not
+// exceed its number of nodes, in which case the daemon caps the target.
+func checkFlexTarget(t *xconfig.T) []xconfig.SemanticFinding {
+	k := key.Parse("flex_target")
+	if !t.HasKey(k) {
+		return nil
+	}
+	if topology.New(t.GetString(key.Parse("topology"))) != topology.Flex {
+		return nil
+	}
+	target, err := t.GetIntStrict(k)
+	if err != nil {
+		return nil
+	}
+	i, err := xconfig.NodesConverter.Convert(t.Get(key.Parse("nodes")))
+	if err != nil {
+		return nil
+	}
+	nodes := i.([]string)
+	if target <= len(nodes) {
+		return nil
+	}
+	return []xconfig.SemanticFinding{
+		{
+			Key:     k,
+			Comment: fmt.Sprintf("flex_target %d is greater than the %d nodes", target, len(nodes)),
+		},
+	}
+}
@@ -19,6 +19,10 @@ type (
 		RecoverAndEditConfig() error
 		DiscardAndEditConfig() error
 		PrintConfig() (rawconfig.T, error)
+		ConfigRevisions() (xconfig.Revisions, error)
+		ConfigRevision(int) (rawconfig.T, error)
+		DiffConfigRevisions(int, int) (string, error)
+		RollbackConfig(context.Context, int) error
 		EvalConfig() (rawconfig.T, error)
 		EvalConfigAs(string) (rawconfig.T, error)
 		Eval(key.T) (interface{}, error)
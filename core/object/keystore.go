@@ -4,6 +4,7 @@ import (
 	"io/ioutil"
 	"os"
 	"os/user"
+	"time"
 
 	"opensvc.com/opensvc/util/key"
 )
@@ -38,6 +39,19 @@ type (
 		EditKey(name string) error
 		InstallKey(name string) error
 		InstallKeyTo(string, string, *os.FileMode, *os.FileMode, *user.User, *user.Group) error
+		KeyVersions(pattern string) (KeyVersions, error)
+		RotateKey(name string, b []byte, tm time.Time) error
+		RotateKeyFrom(name string, from string, tm time.Time) error
+		PruneKeyVersions(pattern string) (KeyVersions, error)
+		NextKeyVersionActivation() time.Time
+		ActivateKeyVersions() error
+	}
+
+	// RefKeystore is implemented by the Keystore object kinds accepting keys
+	// referencing a secret in an external backend (sec).
+	RefKeystore interface {
+		AddKeyRef(name string, ref string) error
+		ChangeKeyRef(name string, ref string) error
 	}
 
 	// SecureKeystore is implemented by encrypting Keystore object kinds (usr, sec).
@@ -3,6 +3,7 @@ package object
 import (
 	"fmt"
 	"os"
+	"time"
 
 	"opensvc.com/opensvc/core/keyop"
 	"opensvc.com/opensvc/util/file"
@@ -20,7 +21,9 @@ func (t *keystore) AddKey(name string, b []byte) error {
 	return t.config.Commit()
 }
 
-// ChangeKey changes the value of a existing key and commits immediately
+// ChangeKey changes the value of a existing key and commits immediately.
+// For a rotated key, the new value is a version activated now, and the
+// versions pending activation are canceled.
 func (t *keystore) ChangeKey(name string, b []byte) error {
 	if !t.HasKey(name) {
 		return fmt.Errorf("key does not exist: %s. use the add action.", name)
@@ -77,6 +80,26 @@ func (t *keystore) alterFrom(name string, from string) error {
 	return err
 }
 
+// readFrom returns the value read from the uri or regular file source.
+func (t *keystore) readFrom(from string) ([]byte, error) {
+	u := uri.New(from)
+	switch {
+	case from == "":
+		return nil, fmt.Errorf("empty value source")
+	case u.IsValid():
+		fName, err := u.Fetch()
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(fName)
+		return os.ReadFile(fName)
+	case file.ExistsAndRegular(from):
+		return os.ReadFile(from)
+	default:
+		return nil, fmt.Errorf("unexpected value source: %s", from)
+	}
+}
+
 func (t *keystore) fromRegular(name string, p string) error {
 	b, err := os.ReadFile(p)
 	if err != nil {
@@ -111,6 +134,22 @@ func (t *keystore) addKey(name string, b []byte) error {
 	if err != nil {
 		return err
 	}
+	return t.setKeyValue(name, s)
+}
+
+// setKeyValue sets the encoded value of the key.
+//
+// Note: setKeyValue does not commit.
+func (t *keystore) setKeyValue(name string, s string) error {
+	if name == "" {
+		return fmt.Errorf("key name can not be empty")
+	}
+	if len(t.allKeyVersions()[name]) > 0 {
+		// a rotated key value change is a new version activated now,
+		// which must not be overridden by a planned version
+		t.cancelPendingKeyVersions(name, time.Now())
+		return t.addKeyVersion(name, s, time.Time{})
+	}
 	op := keyop.T{
 		Key:   keyFromName(name),
 		Op:    keyop.Set,
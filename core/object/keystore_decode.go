@@ -16,14 +16,18 @@ func (t *keystore) decode(keyname string) ([]byte, error) {
 	if !t.HasKey(keyname) {
 		return []byte{}, fmt.Errorf("key does not exist: %s", keyname)
 	}
-	k := keyFromName(keyname)
-	if s, err = t.config.GetStrict(k); err != nil {
+	if s, _, err = t.activeKeyValue(keyname); err != nil {
 		return []byte{}, err
 	}
+	if isSecretRef(s) {
+		return resolveSecretRef(s)
+	}
 	return t.customDecode(s)
 }
 
-// DecodeKey returns the decoded bytes of the key value
+// DecodeKey returns the decoded bytes of the key value. For a rotated key,
+// it is the value of the version active now. For a key referencing a
+// secret in the external backend, it is the resolved secret value.
 func (t *keystore) DecodeKey(keyname string) ([]byte, error) {
 	return t.decode(keyname)
 }
@@ -206,7 +206,7 @@ func (t keystore) chown(p string, usr *user.User, grp *user.Group) error {
 // writeKey reads the r Reader and writes the byte stream to the file at dst.
 // This function return false if the dst content didn't change.
 func (t keystore) writeKey(vk vKey, dst string, b []byte, mode *os.FileMode, usr *user.User, grp *user.Group) (bool, error) {
-	mtime := t.configModTime()
+	mtime := t.keyModTime(vk.Key)
 	if file.Exists(dst) {
 		if err := t.chmod(dst, mode); err != nil {
 			return false, err
@@ -214,7 +214,7 @@ func (t keystore) writeKey(vk vKey, dst string, b []byte, mode *os.FileMode, usr
 		if err := t.chown(dst, usr, grp); err != nil {
 			return false, err
 		}
-		if mtime == file.ModTime(dst) {
+		if mtime == file.ModTime(dst) && !t.isKeyRef(vk.Key) {
 			return false, nil
 		}
 		targetMD5 := md5.New().Sum(b)
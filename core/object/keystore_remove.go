@@ -4,8 +4,12 @@ import (
 	"opensvc.com/opensvc/util/key"
 )
 
-// Remove gets a keyword value
+// RemoveKey removes the key, and all its versions if rotated.
 func (t *keystore) RemoveKey(keyname string) error {
 	k := key.New(dataSectionName, keyname)
-	return unsetKeys(t.config, k)
+	if t.unsetKeyVersions(keyname) == 0 {
+		return unsetKeys(t.config, k)
+	}
+	t.config.Unset(k)
+	return t.commitVersions()
 }
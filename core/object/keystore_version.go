@@ -0,0 +1,389 @@
+package object
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/danwakefield/fnmatch"
+
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/util/key"
+)
+
+const (
+	// versionsSectionName is the name of the section hosting the versions
+	// of the rotated keys. The value of the version <n> of the key <name>
+	// is stored in the <name>.v<n> option, and its activation time in the
+	// <name>.v<n>.activate option.
+	//
+	// The data section value of a rotated key is a copy of its active
+	// version value at the time of the last commit, for the consumers
+	// not aware of versions.
+	versionsSectionName = "versions"
+)
+
+type (
+	// KeyVersion describes a version of a keystore key value.
+	KeyVersion struct {
+		Key      string    `json:"key"`
+		Version  int       `json:"version"`
+		Activate time.Time `json:"activate"`
+		Active   bool      `json:"active"`
+	}
+
+	// KeyVersions is a list of KeyVersion, sorted by key name then version.
+	KeyVersions []KeyVersion
+
+	keyVersion struct {
+		KeyVersion
+		value string
+	}
+)
+
+var (
+	reKeyVersionOption = regexp.MustCompile(`^(.+)\.v([0-9]+)(\.activate)?$`)
+)
+
+// Render returns the human representation of the key versions list.
+func (t KeyVersions) Render() string {
+	s := ""
+	for _, v := range t {
+		activate := "-"
+		if !v.Activate.IsZero() {
+			activate = v.Activate.Format(time.RFC3339)
+		}
+		active := ""
+		if v.Active {
+			active = "active"
+		}
+		s += fmt.Sprintf("%-24s v%-4d %-25s %s\n", v.Key, v.Version, activate, active)
+	}
+	return s
+}
+
+func keyVersionValueKey(name string, version int) key.T {
+	return key.New(versionsSectionName, fmt.Sprintf("%s.v%d", name, version))
+}
+
+func keyVersionActivateKey(name string, version int) key.T {
+	return key.New(versionsSectionName, fmt.Sprintf("%s.v%d.activate", name, version))
+}
+
+// allKeyVersions returns the stored versions of the rotated keys, indexed
+// by key name and sorted by version.
+func (t *keystore) allKeyVersions() map[string][]keyVersion {
+	if !t.config.HasSectionString(versionsSectionName) {
+		return map[string][]keyVersion{}
+	}
+	m := make(map[string]map[int]*keyVersion)
+	for _, option := range t.config.Keys(versionsSectionName) {
+		l := reKeyVersionOption.FindStringSubmatch(option)
+		if l == nil {
+			continue
+		}
+		name := l[1]
+		version, err := strconv.Atoi(l[2])
+		if err != nil {
+			continue
+		}
+		if _, ok := m[name]; !ok {
+			m[name] = make(map[int]*keyVersion)
+		}
+		v, ok := m[name][version]
+		if !ok {
+			v = &keyVersion{KeyVersion: KeyVersion{Key: name, Version: version}}
+			m[name][version] = v
+		}
+		s := t.config.Get(key.New(versionsSectionName, option))
+		if l[3] == "" {
+			v.value = s
+		} else if tm, err := time.Parse(time.RFC3339, s); err == nil {
+			v.Activate = tm
+		} else {
+			t.log.Warn().Str("key", name).Int("version", version).Msgf("invalid activation time %s: %s", s, err)
+		}
+	}
+	data := make(map[string][]keyVersion)
+	for name, versions := range m {
+		l := make([]keyVersion, 0, len(versions))
+		for _, v := range versions {
+			l = append(l, *v)
+		}
+		sort.Slice(l, func(i, j int) bool { return l[i].Version < l[j].Version })
+		data[name] = l
+	}
+	return data
+}
+
+// keyVersions returns the stored versions of the key, with the Active
+// flag set on the version active at the time tm.
+func (t *keystore) keyVersions(name string, tm time.Time) []keyVersion {
+	l := t.allKeyVersions()[name]
+	if i := activeKeyVersionIndex(l, tm); i >= 0 {
+		l[i].Active = true
+	}
+	return l
+}
+
+// activeKeyVersionIndex returns the index of the version active at the time
+// tm: the version with the most recent activation time before tm, or the
+// greatest version number amongst the versions activated at the same time.
+// It returns -1 if no version is active yet.
+func activeKeyVersionIndex(l []keyVersion, tm time.Time) int {
+	idx := -1
+	for i, v := range l {
+		if v.Activate.After(tm) {
+			continue
+		}
+		if idx < 0 || !v.Activate.Before(l[idx].Activate) {
+			idx = i
+		}
+	}
+	return idx
+}
+
+// activeKeyValue returns the encoded value of the key version active now,
+// and its activation time. If the key is not rotated, it returns the data
+// section value and a zero time.
+func (t *keystore) activeKeyValue(name string) (string, time.Time, error) {
+	for _, v := range t.keyVersions(name, time.Now()) {
+		if v.Active {
+			return v.value, v.Activate, nil
+		}
+	}
+	s, err := t.config.GetStrict(keyFromName(name))
+	return s, time.Time{}, err
+}
+
+// keyModTime returns the reference modification time of the installed
+// files of the key. It is the config file modification time, or the
+// activation time of the active key version if more recent, so installed
+// files are refreshed when a planned version activates.
+func (t *keystore) keyModTime(name string) time.Time {
+	mtime := t.configModTime()
+	if _, activate, err := t.activeKeyValue(name); err == nil && activate.After(mtime) {
+		return activate
+	}
+	return mtime
+}
+
+// KeyVersions returns the versions of the keys matching the pattern. A
+// key not rotated yet has a single active version 1.
+func (t *keystore) KeyVersions(pattern string) (KeyVersions, error) {
+	names, err := t.MatchingKeys(pattern)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	data := make(KeyVersions, 0)
+	for _, name := range names {
+		l := t.keyVersions(name, now)
+		if len(l) == 0 {
+			data = append(data, KeyVersion{Key: name, Version: 1, Active: true})
+			continue
+		}
+		for _, v := range l {
+			data = append(data, v.KeyVersion)
+		}
+	}
+	sort.SliceStable(data, func(i, j int) bool { return data[i].Key < data[j].Key })
+	return data, nil
+}
+
+// RotateKey adds a new version of an existing key, activated at the time
+// tm, or immediately if tm is zero. The version in use before the
+// activation is kept until pruned.
+func (t *keystore) RotateKey(name string, b []byte, tm time.Time) error {
+	if !t.HasKey(name) {
+		return fmt.Errorf("key does not exist: %s. use the add action.", name)
+	}
+	if b == nil {
+		b = []byte{}
+	}
+	s, err := t.customEncode(b)
+	if err != nil {
+		return err
+	}
+	if err := t.addKeyVersion(name, s, tm); err != nil {
+		return err
+	}
+	return t.config.Commit()
+}
+
+// RotateKeyFrom is like RotateKey, with the new version value read from
+// a uri or a file.
+func (t *keystore) RotateKeyFrom(name string, from string, tm time.Time) error {
+	if !t.HasKey(name) {
+		return fmt.Errorf("key does not exist: %s. use the add action.", name)
+	}
+	b, err := t.readFrom(from)
+	if err != nil {
+		return err
+	}
+	return t.RotateKey(name, b, tm)
+}
+
+// addKeyVersion stores the encoded value s as a new version of the key,
+// activated at the time tm. The current data section value becomes the
+// version 1 of a key not rotated yet.
+//
+// Note: addKeyVersion does not commit.
+func (t *keystore) addKeyVersion(name string, s string, tm time.Time) error {
+	if tm.IsZero() {
+		tm = time.Now()
+	}
+	versions := t.keyVersions(name, time.Now())
+	if len(versions) == 0 {
+		current, err := t.config.GetStrict(keyFromName(name))
+		if err != nil {
+			return err
+		}
+		if err := t.config.Set(keyop.T{Key: keyVersionValueKey(name, 1), Op: keyop.Set, Value: current}); err != nil {
+			return err
+		}
+		versions = append(versions, keyVersion{KeyVersion: KeyVersion{Key: name, Version: 1}})
+	}
+	version := versions[len(versions)-1].Version + 1
+	ops := []keyop.T{
+		{Key: keyVersionValueKey(name, version), Op: keyop.Set, Value: s},
+		{Key: keyVersionActivateKey(name, version), Op: keyop.Set, Value: tm.Format(time.RFC3339)},
+	}
+	for _, op := range ops {
+		if err := t.config.Set(op); err != nil {
+			return err
+		}
+	}
+	t.log.Info().Str("key", name).Int("version", version).Time("activate", tm).Msg("key version added")
+	return t.syncKeyData(name)
+}
+
+// cancelPendingKeyVersions removes the versions of the key planned for
+// activation after the time tm, and returns the number of removed versions.
+//
+// Note: cancelPendingKeyVersions does not commit.
+func (t *keystore) cancelPendingKeyVersions(name string, tm time.Time) int {
+	n := 0
+	for _, v := range t.allKeyVersions()[name] {
+		if !v.Activate.After(tm) {
+			continue
+		}
+		t.config.Unset(keyVersionValueKey(name, v.Version), keyVersionActivateKey(name, v.Version))
+		t.log.Info().Str("key", name).Int("version", v.Version).Time("activate", v.Activate).Msg("key version planned activation canceled")
+		n++
+	}
+	return n
+}
+
+// syncKeyData copies the active version value of a rotated key to the data
+// section.
+func (t *keystore) syncKeyData(name string) error {
+	s, _, err := t.activeKeyValue(name)
+	if err != nil {
+		return err
+	}
+	return t.config.Set(keyop.T{Key: keyFromName(name), Op: keyop.Set, Value: s})
+}
+
+// unsetKeyVersions removes all the stored versions of the key, and
+// returns the number of removed options.
+//
+// Note: unsetKeyVersions does not commit.
+func (t *keystore) unsetKeyVersions(name string) int {
+	ks := make([]key.T, 0)
+	for _, v := range t.allKeyVersions()[name] {
+		ks = append(ks, keyVersionValueKey(name, v.Version), keyVersionActivateKey(name, v.Version))
+	}
+	return t.config.Unset(ks...)
+}
+
+// PruneKeyVersions removes the versions of the keys matching the pattern
+// that can no longer be activated: the versions activated before the
+// active version. The versions pending activation are kept. A key left
+// with only its active version is no longer rotated. The removed versions
+// are returned.
+func (t *keystore) PruneKeyVersions(pattern string) (KeyVersions, error) {
+	now := time.Now()
+	pruned := make(KeyVersions, 0)
+	f := fnmatch.FNM_PATHNAME | fnmatch.FNM_LEADING_DIR
+	for name := range t.allKeyVersions() {
+		if pattern != "" && !fnmatch.Match(pattern, name, f) {
+			continue
+		}
+		l := t.keyVersions(name, now)
+		i := activeKeyVersionIndex(l, now)
+		if i < 0 {
+			continue
+		}
+		active := l[i]
+		kept := 0
+		for _, v := range l {
+			if v.Active || v.Activate.After(now) {
+				kept++
+				continue
+			}
+			t.config.Unset(keyVersionValueKey(name, v.Version), keyVersionActivateKey(name, v.Version))
+			pruned = append(pruned, v.KeyVersion)
+		}
+		if kept == 1 {
+			t.unsetKeyVersions(name)
+			if err := t.config.Set(keyop.T{Key: keyFromName(name), Op: keyop.Set, Value: active.value}); err != nil {
+				return pruned, err
+			}
+		} else if err := t.syncKeyData(name); err != nil {
+			return pruned, err
+		}
+	}
+	sort.SliceStable(pruned, func(i, j int) bool { return pruned[i].Key < pruned[j].Key })
+	return pruned, t.commitVersions()
+}
+
+// commitVersions commits the config changes, dropping the versions section
+// if empty.
+func (t *keystore) commitVersions() error {
+	if t.config.HasSectionString(versionsSectionName) && len(t.config.Keys(versionsSectionName)) == 0 {
+		// DeleteSections commits
+		return t.config.DeleteSections([]string{versionsSectionName})
+	}
+	if !t.config.Changed() {
+		return nil
+	}
+	return t.config.Commit()
+}
+
+// NextKeyVersionActivation returns the earliest activation time of the
+// key versions pending activation, or a zero time if none is pending.
+func (t *keystore) NextKeyVersionActivation() time.Time {
+	var next time.Time
+	now := time.Now()
+	for _, l := range t.allKeyVersions() {
+		for _, v := range l {
+			if !v.Activate.After(now) {
+				continue
+			}
+			if next.IsZero() || v.Activate.Before(next) {
+				next = v.Activate
+			}
+		}
+	}
+	return next
+}
+
+// ActivateKeyVersions copies the values of the key versions active now to
+// the data section, for the consumers not aware of versions, and installs
+// the keys in the volumes. The daemon calls it when a planned version
+// activates.
+func (t *keystore) ActivateKeyVersions() error {
+	for name := range t.allKeyVersions() {
+		if err := t.syncKeyData(name); err != nil {
+			return err
+		}
+	}
+	if !t.config.Changed() {
+		return t.postInstall("")
+	}
+	// the commit installs the keys in the volumes
+	return t.config.Commit()
+}
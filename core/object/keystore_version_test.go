@@ -0,0 +1,105 @@
+package object_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/testhelper"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestKeystoreVersions(t *testing.T) {
+	testhelper.Setup(t)
+	p, err := path.Parse("test/cfg/db")
+	require.NoError(t, err)
+	o, err := object.NewCfg(p)
+	require.NoError(t, err)
+
+	decode := func() string {
+		b, err := o.DecodeKey("password")
+		require.NoError(t, err)
+		return string(b)
+	}
+	data := func() string {
+		return o.Config().GetString(key.New("data", "password"))
+	}
+	versions := func() map[int]bool {
+		l, err := o.KeyVersions("password")
+		require.NoError(t, err)
+		m := make(map[int]bool)
+		for _, v := range l {
+			m[v.Version] = v.Active
+		}
+		return m
+	}
+
+	require.NoError(t, o.AddKey("password", []byte("v1")))
+	assert.Equal(t, map[int]bool{1: true}, versions(), "a key not rotated has an active version 1")
+
+	t.Run("a planned version is not active before its activation time", func(t *testing.T) {
+		require.NoError(t, o.RotateKey("password", []byte("v2"), time.Now().Add(time.Hour)))
+		assert.Equal(t, "v1", decode())
+		assert.Equal(t, map[int]bool{1: true, 2: false}, versions())
+	})
+
+	t.Run("the most recently activated version is active", func(t *testing.T) {
+		require.NoError(t, o.RotateKey("password", []byte("v3"), time.Now().Add(-time.Minute)))
+		assert.Equal(t, "v3", decode())
+		assert.Equal(t, map[int]bool{1: false, 2: false, 3: true}, versions())
+	})
+
+	t.Run("change adds a version activated now and cancels the pending versions", func(t *testing.T) {
+		require.NoError(t, o.ChangeKey("password", []byte("v4")))
+		assert.Equal(t, "v4", decode())
+		assert.Equal(t, map[int]bool{1: false, 3: false, 4: true}, versions())
+	})
+
+	t.Run("the installed file follows the active version", func(t *testing.T) {
+		dst := t.TempDir() + "/password"
+		mode := os.FileMode(0600)
+		require.NoError(t, o.InstallKeyTo("password", dst, &mode, &mode, nil, nil))
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		assert.Equal(t, "v4", string(b))
+	})
+
+	t.Run("prune keeps the active and pending versions", func(t *testing.T) {
+		activate := time.Now().Add(time.Hour).Truncate(time.Second)
+		require.NoError(t, o.RotateKey("password", []byte("v5"), activate))
+		assert.True(t, activate.Equal(o.NextKeyVersionActivation()))
+		pruned, err := o.PruneKeyVersions("")
+		require.NoError(t, err)
+		assert.Len(t, pruned, 2)
+		assert.Equal(t, map[int]bool{4: true, 5: false}, versions())
+		assert.Equal(t, "v4", decode())
+	})
+
+	t.Run("remove drops the versions", func(t *testing.T) {
+		require.NoError(t, o.RemoveKey("password"))
+		assert.False(t, o.HasKey("password"))
+		require.NoError(t, o.AddKey("password", []byte("v5")))
+		assert.Equal(t, map[int]bool{1: true}, versions())
+		assert.Equal(t, "v5", decode())
+	})
+
+	t.Run("activate syncs the data section with the activated version", func(t *testing.T) {
+		require.NoError(t, o.RotateKey("password", []byte("v6"), time.Now().Add(time.Second)))
+		next := o.NextKeyVersionActivation()
+		require.False(t, next.IsZero())
+		assert.Equal(t, "literal:v5", data())
+		time.Sleep(time.Until(next) + 10*time.Millisecond)
+		assert.True(t, o.NextKeyVersionActivation().IsZero())
+		require.NoError(t, o.ActivateKeyVersions())
+		assert.Equal(t, "literal:v6", data())
+	})
+
+	t.Run("rotate requires an existing key", func(t *testing.T) {
+		assert.Error(t, o.RotateKey("foo", []byte("bar"), time.Time{}))
+	})
+}
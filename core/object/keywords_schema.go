@@ -0,0 +1,163 @@
+package object
+
+import (
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/resource"
+)
+
+var (
+	// nodeIndexedSections are the node and cluster configuration sections
+	// named <section>#<index>. The other sections are singletons.
+	nodeIndexedSections = []string{"arbitrator", "array", "backup", "hb", "hook", "network", "pool", "stonith", "switch"}
+
+	// ErrSchemaKind is returned by KeywordSchema for unsupported kinds.
+	ErrSchemaKind = errors.New("no keyword schema for this kind")
+)
+
+// SchemaKinds returns the names accepted by KeywordSchema.
+func SchemaKinds() []string {
+	return []string{"svc", "vol", "cfg", "sec", "usr", "node", "cluster"}
+}
+
+// KeywordSchema returns the JSON Schema of the configuration files of
+// the objects of kind <name>, of the node.conf file if name is "node", or
+// of the cluster.conf file if name is "cluster".
+func KeywordSchema(name string) (keywords.Schema, error) {
+	switch name {
+	case "node":
+		return nodeConfigSchema("node", nodeKeywordStore), nil
+	case "cluster", "ccfg":
+		return nodeConfigSchema("cluster", ccfgKeywordStore), nil
+	}
+	switch k := kind.New(name); k {
+	case kind.Svc, kind.Vol, kind.Cfg, kind.Sec, kind.Usr:
+		return objectConfigSchema(k), nil
+	default:
+		return nil, errors.Wrap(ErrSchemaKind, name)
+	}
+}
+
+func newConfigSchema(name string) keywords.Schema {
+	return keywords.Schema{
+		"$schema":              keywords.SchemaDialect,
+		"$id":                  "urn:opensvc:schema:" + name,
+		"title":                name + " configuration",
+		"description":          "The " + name + " configuration, as a map of ini sections to key-value maps.",
+		"type":                 "object",
+		"additionalProperties": false,
+	}
+}
+
+// freeSectionSchema is the schema of the sections accepting any key, like
+// env and data.
+var freeSectionSchema = keywords.Schema{
+	"type": "object",
+	"additionalProperties": keywords.Schema{
+		"type": "string",
+	},
+}
+
+func objectConfigSchema(k kind.T) keywords.Schema {
+	store := make(keywords.Store, 0)
+	for _, kw := range keywordStore {
+		if kw.Kind.Has(k) {
+			store = append(store, kw)
+		}
+	}
+	properties := keywords.Schema{
+		"DEFAULT": store.WithSection("DEFAULT").SectionSchema("", false),
+		"env":     freeSectionSchema,
+	}
+	patternProperties := keywords.Schema{
+		keywords.IndexedSectionPattern("subset"): store.WithSection("subset").SectionSchema("", false),
+	}
+	switch k {
+	case kind.Cfg, kind.Sec, kind.Usr:
+		properties["data"] = freeSectionSchema
+		properties[versionsSectionName] = freeSectionSchema
+	case kind.Svc, kind.Vol:
+		for group, variants := range resourceSectionSchemas(k) {
+			patternProperties[keywords.IndexedSectionPattern(group)] = oneOf(variants)
+		}
+	}
+	s := newConfigSchema(k.String())
+	s["properties"] = properties
+	s["patternProperties"] = patternProperties
+	return s
+}
+
+// resourceSectionSchemas returns the section schemas of the resource
+// drivers supporting the kind, indexed by driver group name.
+func resourceSectionSchemas(k kind.T) map[string][]keywords.Schema {
+	m := make(map[string][]keywords.Schema)
+	ids := driver.List()
+	sort.Sort(ids)
+	for _, did := range ids {
+		if !did.Group.IsResource() {
+			continue
+		}
+		allocator, ok := driver.Get(did).(func() resource.Driver)
+		if !ok {
+			continue
+		}
+		store := make(keywords.Store, 0)
+		for _, kw := range allocator().Manifest().Keywords {
+			if kw.Kind.Has(k) {
+				store = append(store, kw)
+			}
+		}
+		group := did.Group.String()
+		// the "type" keyword is optional for the group default driver
+		optionalType := driver.DefaultDriver[did.Group] == did.Name
+		m[group] = append(m[group], store.SectionSchema(did.Name, optionalType))
+	}
+	return m
+}
+
+func nodeConfigSchema(name string, store keywords.Store) keywords.Schema {
+	properties := keywords.Schema{}
+	patternProperties := keywords.Schema{}
+	sections := make(map[string]any)
+	for _, kw := range store {
+		if kw.Section != "" {
+			sections[kw.Section] = nil
+		}
+	}
+	indexed := make(map[string]any)
+	for _, section := range nodeIndexedSections {
+		indexed[section] = nil
+	}
+	for section := range sections {
+		sectionStore := store.WithSection(section)
+		variants := make([]keywords.Schema, 0)
+		if types := sectionStore.SectionTypes(); len(types) > 0 {
+			for _, typ := range types {
+				variants = append(variants, sectionStore.WithType(typ).SectionSchema(typ, false))
+			}
+		} else {
+			variants = append(variants, sectionStore.SectionSchema("", false))
+		}
+		if _, ok := indexed[section]; ok {
+			patternProperties[keywords.IndexedSectionPattern(section)] = oneOf(variants)
+		} else {
+			properties[section] = oneOf(variants)
+		}
+	}
+	s := newConfigSchema(name)
+	s["properties"] = properties
+	s["patternProperties"] = patternProperties
+	return s
+}
+
+func oneOf(l []keywords.Schema) keywords.Schema {
+	if len(l) == 1 {
+		return l[0]
+	}
+	return keywords.Schema{"oneOf": l}
+}
@@ -0,0 +1,221 @@
+package object_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	_ "opensvc.com/opensvc/core/driverdb"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+)
+
+// variantTypes returns the "type" const values of the section schema
+// variants.
+func variantTypes(t *testing.T, s keywords.Schema) []string {
+	l := make([]string, 0)
+	variants := []keywords.Schema{s}
+	if i, ok := s["oneOf"]; ok {
+		variants = i.([]keywords.Schema)
+	}
+	for _, variant := range variants {
+		properties := variant["properties"].(keywords.Schema)
+		if typ, ok := properties["type"].(keywords.Schema)["const"]; ok {
+			l = append(l, typ.(string))
+		}
+	}
+	return l
+}
+
+func TestKeywordSchema(t *testing.T) {
+	for _, name := range object.SchemaKinds() {
+		t.Run(name+" schema is json serializable", func(t *testing.T) {
+			s, err := object.KeywordSchema(name)
+			require.NoError(t, err)
+			_, err = json.Marshal(s)
+			require.NoError(t, err)
+			assert.Equal(t, keywords.SchemaDialect, s["$schema"])
+		})
+	}
+
+	t.Run("svc", func(t *testing.T) {
+		s, err := object.KeywordSchema("svc")
+		require.NoError(t, err)
+		defaultSection := s["properties"].(keywords.Schema)["DEFAULT"].(keywords.Schema)
+		defaultProperties := defaultSection["properties"].(keywords.Schema)
+		assert.Contains(t, defaultProperties, "nodes")
+		assert.Contains(t, defaultProperties, "orchestrate")
+		assert.Equal(t, "string", defaultProperties["nodes"].(keywords.Schema)["type"])
+		assert.Contains(t, defaultSection["patternProperties"], "^nodes@.+$", "scoped keyword")
+
+		patternProperties := s["patternProperties"].(keywords.Schema)
+		require.Contains(t, patternProperties, "^fs#.+$")
+		assert.Contains(t, variantTypes(t, patternProperties["^fs#.+$"].(keywords.Schema)), "flag")
+		require.Contains(t, patternProperties, "^app#.+$")
+		assert.Contains(t, variantTypes(t, patternProperties["^app#.+$"].(keywords.Schema)), "forking")
+	})
+
+	t.Run("sec has a free data section and no resource", func(t *testing.T) {
+		s, err := object.KeywordSchema("sec")
+		require.NoError(t, err)
+		assert.Contains(t, s["properties"], "data")
+		assert.NotContains(t, s["patternProperties"], "^fs#.+$")
+	})
+
+	t.Run("node has typed hb sections", func(t *testing.T) {
+		s, err := object.KeywordSchema("node")
+		require.NoError(t, err)
+		assert.Contains(t, s["properties"], "node")
+		patternProperties := s["patternProperties"].(keywords.Schema)
+		require.Contains(t, patternProperties, "^hb#.+$")
+		assert.Contains(t, variantTypes(t, patternProperties["^hb#.+$"].(keywords.Schema)), "unicast")
+	})
+
+	t.Run("unsupported kind", func(t *testing.T) {
+		_, err := object.KeywordSchema("foo")
+		assert.ErrorIs(t, err, object.ErrSchemaKind)
+	})
+}
+
+// validateSchema returns an error if the json decoded document v does not
+// validate against the schema s. It implements the subset of the JSON
+// Schema vocabulary used by the generated keyword schemas.
+func validateSchema(s keywords.Schema, v any) error {
+	if c, ok := s["const"]; ok && v != c {
+		return fmt.Errorf("%v is not %v", v, c)
+	}
+	if l, ok := s["enum"].([]string); ok {
+		found := false
+		for _, e := range l {
+			if v == e {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("%v is not in %v", v, l)
+		}
+	}
+	if l, ok := s["oneOf"].([]keywords.Schema); ok {
+		n := 0
+		for _, variant := range l {
+			if validateSchema(variant, v) == nil {
+				n++
+			}
+		}
+		if n != 1 {
+			return fmt.Errorf("%v matches %d oneOf variants", v, n)
+		}
+	}
+	switch s["type"] {
+	case "string":
+		if _, ok := v.(string); !ok {
+			return fmt.Errorf("%v is not a string", v)
+		}
+	case "object":
+		m, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%v is not an object", v)
+		}
+		if l, ok := s["required"].([]string); ok {
+			for _, k := range l {
+				if _, ok := m[k]; !ok {
+					return fmt.Errorf("%s is required", k)
+				}
+			}
+		}
+		for k, kv := range m {
+			if err := validateSchemaProperty(s, k, kv); err != nil {
+				return fmt.Errorf("%s: %w", k, err)
+			}
+		}
+	}
+	return nil
+}
+
+func validateSchemaProperty(s keywords.Schema, k string, v any) error {
+	matched := false
+	if properties, ok := s["properties"].(keywords.Schema); ok {
+		if ps, ok := properties[k]; ok {
+			matched = true
+			if err := validateSchema(ps.(keywords.Schema), v); err != nil {
+				return err
+			}
+		}
+	}
+	if patternProperties, ok := s["patternProperties"].(keywords.Schema); ok {
+		for pattern, ps := range patternProperties {
+			if !regexp.MustCompile(pattern).MatchString(k) {
+				continue
+			}
+			matched = true
+			if err := validateSchema(ps.(keywords.Schema), v); err != nil {
+				return err
+			}
+		}
+	}
+	if !matched {
+		switch additional := s["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				return fmt.Errorf("additional property is not allowed")
+			}
+		case keywords.Schema:
+			return validateSchema(additional, v)
+		}
+	}
+	return nil
+}
+
+func TestKeywordSchemaValidatesConfig(t *testing.T) {
+	s, err := object.KeywordSchema("svc")
+	require.NoError(t, err)
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	// configDocument returns the svc configuration as the json document
+	// validated by the schema.
+	configDocument := func(t *testing.T, text string) any {
+		o, err := object.NewSvc(p, object.WithVolatile(true), object.WithConfigData([]byte(text)))
+		require.NoError(t, err)
+		b, err := json.Marshal(o.Config().Raw().Data)
+		require.NoError(t, err)
+		var v any
+		require.NoError(t, json.Unmarshal(b, &v))
+		return v
+	}
+
+	const sample = `[DEFAULT]
+nodes = n1 n2
+orchestrate = ha
+
+[env]
+foo = bar
+
+[fs#1]
+type = flag
+
+[app#1]
+type = forking
+start = /bin/true
+start@n1 = /bin/false
+`
+
+	t.Run("accepts a valid config", func(t *testing.T) {
+		assert.NoError(t, validateSchema(s, configDocument(t, sample)))
+	})
+
+	t.Run("rejects an unknown keyword", func(t *testing.T) {
+		assert.ErrorContains(t, validateSchema(s, configDocument(t, sample+"foo = bar\n")), "app#1: ")
+		assert.ErrorContains(t, validateSchema(s, configDocument(t, "[DEFAULT]\nfoo = bar\n")), "foo: additional property is not allowed")
+	})
+
+	t.Run("rejects an invalid candidate", func(t *testing.T) {
+		assert.Error(t, validateSchema(s, configDocument(t, "[DEFAULT]\norchestrate = foo\n")))
+	})
+}
@@ -1,13 +1,78 @@
 package object
 
-import "opensvc.com/opensvc/util/compliance"
+import (
+	"strings"
+
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/compliance"
+)
+
+// ComplianceNamespace is the namespace of the cfg objects hosting the
+// rulesets/<name> and modulesets/<name> keys used by the local compliance
+// source.
+const ComplianceNamespace = "compliance"
 
 func (t Node) NewCompliance() (*compliance.T, error) {
-	client, err := t.CollectorComplianceClient()
+	return t.NewComplianceFrom("")
+}
+
+// NewComplianceFrom returns a compliance handle using the collector or the
+// local rulesets and modulesets definitions, depending on source.
+func (t Node) NewComplianceFrom(source string) (*compliance.T, error) {
+	return newCompliance(&t, source)
+}
+
+func newCompliance(n *Node, source string) (*compliance.T, error) {
+	src, err := compliance.ParseSource(source)
 	if err != nil {
 		return nil, err
 	}
 	comp := compliance.New()
-	comp.SetCollectorClient(client)
+	comp.SetSource(src)
+	switch src {
+	case compliance.SourceLocal:
+		comp.SetDefinitionsFunc(complianceCfgDefinitions)
+	default:
+		client, err := n.CollectorComplianceClient()
+		if err != nil {
+			return nil, err
+		}
+		comp.SetCollectorClient(client)
+	}
 	return comp, nil
 }
+
+// complianceCfgDefinitions returns the rulesets/<name> and modulesets/<name>
+// keys of the cfg objects in the compliance namespace.
+func complianceCfgDefinitions() (map[string][]byte, error) {
+	m := make(map[string][]byte)
+	paths, err := path.List()
+	if err != nil {
+		return m, err
+	}
+	for _, p := range paths {
+		if p.Namespace != ComplianceNamespace || p.Kind != kind.Cfg {
+			continue
+		}
+		o, err := NewCfg(p, WithVolatile(true))
+		if err != nil {
+			return m, err
+		}
+		keys, err := o.AllKeys()
+		if err != nil {
+			return m, err
+		}
+		for _, k := range keys {
+			if !strings.HasPrefix(k, "rulesets/") && !strings.HasPrefix(k, "modulesets/") {
+				continue
+			}
+			b, err := o.DecodeKey(k)
+			if err != nil {
+				return m, err
+			}
+			m[k] = b
+		}
+	}
+	return m, nil
+}
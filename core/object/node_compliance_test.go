@@ -0,0 +1,52 @@
+package object_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/testhelper"
+)
+
+func TestNodeComplianceLocalSource(t *testing.T) {
+	testhelper.Setup(t)
+	addKeys := func(s string, m map[string]string) {
+		p, err := path.Parse(s)
+		require.NoError(t, err)
+		o, err := object.NewCfg(p)
+		require.NoError(t, err)
+		for k, v := range m {
+			require.NoError(t, o.AddKey(k, []byte(v)))
+		}
+	}
+	addKeys("compliance/cfg/base", map[string]string{
+		"rulesets/base":   `{"vars": [["ntp_servers", "ntp1 ntp2", "raw"]]}`,
+		"modulesets/base": `{"modules": [["ntp", true]], "rulesets": ["base"]}`,
+		"ignored":         `garbage`,
+	})
+	addKeys("other/cfg/base", map[string]string{
+		"rulesets/other": `{"vars": []}`,
+	})
+
+	n, err := object.NewNode(object.WithVolatile(true))
+	require.NoError(t, err)
+	comp, err := n.NewComplianceFrom("local")
+	require.NoError(t, err)
+
+	l, err := comp.ListRulesets("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base"}, l)
+
+	l, err = comp.ListModulesets("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base"}, l)
+
+	require.NoError(t, comp.AttachModuleset("base"))
+	data, err := comp.GetData([]string{})
+	require.NoError(t, err)
+	require.Contains(t, data.Rsets, "base")
+	assert.Equal(t, "OSVC_COMP_NTP_SERVERS=ntp1 ntp2", data.Rsets["base"].Vars[0].String())
+}
@@ -108,7 +108,11 @@ func (t Node) Dereference(ref string) (string, error) {
 	}
 	switch {
 	case strings.HasPrefix(ref, "safe://"):
-		return ref, fmt.Errorf("TODO")
+		b, err := resolveSecretRef(ref)
+		if err != nil {
+			return ref, err
+		}
+		return string(b), nil
 	}
 	return ref, fmt.Errorf("unknown reference: %s", ref)
 }
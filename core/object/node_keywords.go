@@ -440,6 +440,46 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Default:   "5s",
 		Text:      "A duration expression, like ``10s``, defining how long the daemon monitor waits before starting a service instance in ``ready`` state. A peer node can preempt the start during this period. Usually set to allow at least a couple of heartbeats to be received.",
 	},
+	{
+		Section:   "node",
+		Option:    "evacuation_concurrency",
+		Converter: converters.Int,
+		Default:   "2",
+		Text:      "The maximum number of failover object switches the daemon runs in parallel when evacuating the node with :cmd:`om node drain --evacuate`.",
+	},
+	{
+		Section:   "node",
+		Option:    "evacuation_timeout",
+		Converter: converters.Duration,
+		Default:   "10m",
+		Text:      "A duration expression, like ``5m``, defining how long the daemon waits for a failover object switch to complete when evacuating the node. An object not switched in time is shut down with the remaining local instances.",
+	},
+	{
+		Section: "node",
+		Option:  "upgrade_command",
+		Example: "yum -y update opensvc",
+		Text:    "The command installing the new agent version on the node, executed by :cmd:`om node upgrade --local` and by the rolling upgrade orchestration. The daemon is restarted by the orchestration after the command succeeds.",
+	},
+	{
+		Section: "node",
+		Option:  "upgrade_rollback_command",
+		Example: "yum -y downgrade opensvc",
+		Text:    "The command reinstalling the previous agent version on the node, executed by the rolling upgrade orchestration when the :kw:`node.upgrade_command` fails. The node returns to the idle state if the command succeeds. If not set, or if the command fails, the node stays in the ``upgrade failed`` state until :cmd:`om node clear`.",
+	},
+	{
+		Section:   "node",
+		Option:    "upgrade_evacuate",
+		Converter: converters.Bool,
+		Default:   "false",
+		Text:      "If set to ``true``, the rolling upgrade orchestration switches the failover objects running on the node to their peer nodes before running the :kw:`node.upgrade_command`.",
+	},
+	{
+		Section:   "node",
+		Option:    "upgrade_timeout",
+		Converter: converters.Duration,
+		Default:   "10m",
+		Text:      "A duration expression, like ``5m``, defining how long the rolling upgrade orchestration waits for the daemon restart after the :kw:`node.upgrade_command`, and for an upgraded node to rejoin and announce its compat version. The orchestration is aborted on timeout.",
+	},
 	{
 		Section: "dequeue_actions",
 		Option:  "schedule",
@@ -683,6 +723,20 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Default:   "false",
 		Text:      "Should a split segment of the cluster commit suicide. Default is false. If set to ``true``, please set at least 2 arbitrators so you can rolling upgrade the opensvc daemons.",
 	},
+	{
+		Section:    "cluster",
+		Option:     "hb_format",
+		Candidates: []string{"json", "binary"},
+		Default:    "json",
+		Text:       "The format of the heartbeat messages. The ``binary`` format is a compact encoding of the json messages, reducing the size of the full messages of the clusters with many object instances. The ``json`` format is used until all the cluster nodes announce in their heartbeat messages an agent version supporting the ``binary`` format.",
+	},
+	{
+		Section:    "cluster",
+		Option:     "hb_compression",
+		Candidates: []string{"zlib", "gzip", "none"},
+		Default:    "zlib",
+		Text:       "The compression algorithm of the heartbeat messages. The ``zlib`` compression is used until all the cluster nodes announce in their heartbeat messages an agent version supporting the other algorithms.",
+	},
 	{
 		Section:    "node",
 		Option:     "split_action",
@@ -856,11 +910,47 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Text:    "The directory hosting the CNI network configuration files.",
 		Example: "/var/lib/opensvc/cni/net.d",
 	},
+	{
+		Section: "vault",
+		Option:  "url",
+		Example: "https://vault.example.com:8200",
+		Text:    "The base url of the Vault compatible secret store api, used to resolve the sec keys referencing a secret with the ``vault://<path>#<field>`` format, and the ``{safe://<path>#<field>}`` configuration references. The path is the api path relative to ``/v1/``, for example ``secret/data/db`` for a key-value version 2 secret.",
+	},
+	{
+		Section: "vault",
+		Option:  "secret",
+		Default: "system/sec/vault",
+		Text:    "The name of a node-local sec object containing either a ``token`` key, or ``role_id`` and ``secret_id`` keys for the AppRole authentication.",
+	},
+	{
+		Section: "vault",
+		Option:  "approle_mount",
+		Default: "approle",
+		Text:    "The mount path of the AppRole authentication method.",
+	},
+	{
+		Section: "vault",
+		Option:  "namespace",
+		Text:    "The Vault enterprise namespace, sent as the X-Vault-Namespace header.",
+	},
+	{
+		Section:   "vault",
+		Option:    "ttl",
+		Converter: converters.Duration,
+		Default:   "5m",
+		Text:      "A duration expression, like ``1m``, defining how long the resolved secrets are cached in memory. Set to ``0`` to disable the cache.",
+	},
+	{
+		Section:   "vault",
+		Option:    "insecure",
+		Converter: converters.Bool,
+		Text:      "Set to true to disable the secret store x509 certificate verification. This should only be done for testing.",
+	},
 	{
 		Section:    "pool",
 		Option:     "type",
 		Default:    "directory",
-		Candidates: []string{"directory", "loop", "vg", "zpool", "freenas", "share", "shm", "symmetrix", "virtual", "dorado", "hcs", "drbd"},
+		Candidates: []string{"directory", "loop", "vg", "zpool", "freenas", "share", "shm", "symmetrix", "virtual", "dorado", "hcs", "drbd", "btrfs"},
 		Text:       "The pool type.",
 	},
 	{
@@ -993,6 +1083,21 @@ The default ruser is root for all nodes. ruser accepts a list of user[@node] ...
 		Required: true,
 		Text:     "The name of the zpool to allocate the pool volumes zvol or datasets into.",
 	},
+	{
+		Section:  "pool",
+		Types:    []string{"btrfs"},
+		Option:   "dev",
+		Required: true,
+		Example:  "LABEL=pool1",
+		Text:     "The device, label or uuid of the btrfs filesystem to allocate the pool volumes subvolumes into. The filesystem is formatted on first volume provision if needed.",
+	},
+	{
+		Section: "pool",
+		Types:   []string{"btrfs"},
+		Option:  "subvol",
+		Default: "volumes",
+		Text:    "The path, relative to the btrfs filesystem top-level, of the directory hosting the pool volumes subvolumes.",
+	},
 	{
 		Section: "pool",
 		Types:   []string{"drbd"},
@@ -0,0 +1,50 @@
+package object
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+
+	"opensvc.com/opensvc/core/node"
+)
+
+// maintenanceFile is the path of the file recording the maintenance declared
+// on the node, so it survives the daemon restarts.
+func (t *Node) maintenanceFile() string {
+	return filepath.Join(t.VarDir(), "maintenance")
+}
+
+// Maintenance returns the maintenance declared on the node. The returned
+// value is zero if no maintenance is declared.
+func (t *Node) Maintenance() node.Maintenance {
+	var m node.Maintenance
+	b, err := os.ReadFile(t.maintenanceFile())
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(b, &m); err != nil {
+		return node.Maintenance{}
+	}
+	return m
+}
+
+// SetMaintenance records the maintenance declared on the node. A zero
+// maintenance removes the record.
+func (t *Node) SetMaintenance(m node.Maintenance) error {
+	p := t.maintenanceFile()
+	if m.IsZero() {
+		if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	b, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0644)
+}
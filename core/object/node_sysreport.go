@@ -1,12 +1,19 @@
 package object
 
-import "opensvc.com/opensvc/util/sysreport"
+import (
+	"strings"
+	"time"
+
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/sysreport"
+)
 
 // Sysreport sends an archive of modified files the agent is configured
 // to track, and the list of files deleted since the last call.
 //
 // The collector is in charge of versioning this information and of
-// reporting on changes.
+// reporting on changes. A local snapshot of the collected files is also
+// kept, and the push is skipped if no collector is configured.
 func (t Node) Sysreport() error {
 	sr, err := t.newSysreport()
 	if err != nil {
@@ -24,12 +31,40 @@ func (t Node) ForceSysreport() error {
 	return sr.Do()
 }
 
+// SysreportDiff returns the changes of the collected files between the
+// local snapshot taken at or before <since>, a date or a duration, and the
+// last local snapshot. The empty since selects the snapshot before the last.
+func (t *Node) SysreportDiff(since string) (sysreport.Diff, error) {
+	tm, err := sysreport.ParseTime(since, time.Now())
+	if err != nil {
+		return sysreport.Diff{}, err
+	}
+	return sysreport.New().HistoryDiff(tm)
+}
+
+// SysreportShow returns the content of a collected file or command output,
+// from the local snapshot selected by the <path>[@<date>] specifier.
+func (t *Node) SysreportShow(spec string) ([]byte, error) {
+	path, date := spec, ""
+	if i := strings.LastIndex(spec, "@"); i >= 0 {
+		path, date = spec[:i], spec[i+1:]
+	}
+	tm, err := sysreport.ParseTime(date, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return sysreport.New().HistoryShow(path, tm)
+}
+
 func (t Node) newSysreport() (*sysreport.T, error) {
+	sr := sysreport.New()
+	if t.mergedConfig.GetString(key.Parse("node.dbopensvc")) == "" {
+		return sr, nil
+	}
 	client, err := t.CollectorFeedClient()
 	if err != nil {
 		return nil, err
 	}
-	sr := sysreport.New()
 	sr.SetCollectorClient(client)
 	return sr, nil
 }
@@ -0,0 +1,78 @@
+package object
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/key"
+)
+
+// upgradedFile is the path of the file recording the last rolling upgrade
+// completed by the node.
+func (t *Node) upgradedFile() string {
+	return filepath.Join(t.VarDir(), "upgraded")
+}
+
+// Upgrade runs the node.upgrade_command, installing the new agent version.
+// The daemon is not restarted.
+func (t *Node) Upgrade() error {
+	return t.runUpgradeCommand("upgrade_command")
+}
+
+// UpgradeRollback runs the node.upgrade_rollback_command, reinstalling the
+// previous agent version after a failed upgrade.
+func (t *Node) UpgradeRollback() error {
+	return t.runUpgradeCommand("upgrade_rollback_command")
+}
+
+func (t *Node) runUpgradeCommand(option string) error {
+	s := t.MergedConfig().GetString(key.New("node", option))
+	if s == "" {
+		return fmt.Errorf("node.%s is not set", option)
+	}
+	cmdArgs, err := command.CmdArgsFromString(s)
+	if err != nil {
+		return err
+	}
+	if len(cmdArgs) == 0 {
+		return nil
+	}
+	cmd := command.New(
+		command.WithName(cmdArgs[0]),
+		command.WithVarArgs(cmdArgs[1:]...),
+		command.WithLogger(t.Log()),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+// Upgraded returns the identifier of the last rolling upgrade completed by
+// the node, which is the global expect update time of the orchestration.
+func (t *Node) Upgraded() time.Time {
+	b, err := os.ReadFile(t.upgradedFile())
+	if err != nil {
+		return time.Time{}
+	}
+	tm, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}
+	}
+	return tm
+}
+
+// SetUpgraded records the identifier of the last rolling upgrade completed
+// by the node, so the restarted daemon does not upgrade again.
+func (t *Node) SetUpgraded(tm time.Time) error {
+	p := t.upgradedFile()
+	if err := os.MkdirAll(filepath.Dir(p), os.ModePerm); err != nil {
+		return err
+	}
+	return os.WriteFile(p, []byte(tm.Format(time.RFC3339Nano)+"\n"), 0644)
+}
@@ -30,9 +30,13 @@ type (
 	// A Signal can be sent to consumer processes upon exposed key value
 	// changes.
 	//
+	// A Key can reference a secret stored in an external Vault compatible
+	// backend, resolved when decoded or installed.
+	//
 	Sec interface {
 		Keystore
 		SecureKeystore
+		RefKeystore
 	}
 )
 
@@ -0,0 +1,200 @@
+package object
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/vault"
+)
+
+type (
+	// secretBackendConfig is the node configuration of the external
+	// secret backend. A change invalidates the cached client.
+	secretBackendConfig struct {
+		url          string
+		namespace    string
+		secret       string
+		appRoleMount string
+		ttl          time.Duration
+		insecure     bool
+
+		// secretMtime is the modification time of the credentials sec
+		// object configuration file.
+		secretMtime time.Time
+	}
+)
+
+var (
+	// ErrNoSecretBackend is returned when resolving a secret reference
+	// on a node without vault.url.
+	ErrNoSecretBackend = errors.New("no external secret backend configured: set vault.url in the node configuration")
+
+	secretBackendMu     sync.Mutex
+	secretBackendClient *vault.Client
+	secretBackendCfg    secretBackendConfig
+)
+
+// isSecretRef returns true if the stored key value references a secret in
+// the external backend instead of embedding the encrypted value.
+func isSecretRef(s string) bool {
+	return vault.IsRef(s)
+}
+
+// isKeyRef returns true if the active value of the key references a secret
+// in the external backend. The installed files of such keys can not be
+// considered up to date based on the config file modification time.
+func (t *keystore) isKeyRef(name string) bool {
+	s, _, err := t.activeKeyValue(name)
+	return err == nil && isSecretRef(s)
+}
+
+// resolveSecretRef returns the value of the secret referenced by ref
+// in the external backend configured in the node vault section. The
+// references use the <scheme>://<api path>[#<field>] format: vault://
+// for the sec key values, and safe:// for the {safe://...} configuration
+// references.
+func resolveSecretRef(ref string) ([]byte, error) {
+	c, err := getSecretBackendClient()
+	if err != nil {
+		return nil, err
+	}
+	b, err := c.Resolve(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolve %s", ref)
+	}
+	return b, nil
+}
+
+func getSecretBackendClient() (*vault.Client, error) {
+	n, err := NewNode(WithVolatile(true))
+	if err != nil {
+		return nil, err
+	}
+	config := n.MergedConfig()
+	cfg := secretBackendConfig{
+		url:          config.GetString(key.Parse("vault.url")),
+		namespace:    config.GetString(key.Parse("vault.namespace")),
+		secret:       config.GetString(key.Parse("vault.secret")),
+		appRoleMount: config.GetString(key.Parse("vault.approle_mount")),
+		insecure:     config.GetBool(key.Parse("vault.insecure")),
+	}
+	if d := config.GetDuration(key.Parse("vault.ttl")); d != nil {
+		cfg.ttl = *d
+	}
+	if cfg.url == "" {
+		return nil, ErrNoSecretBackend
+	}
+	if p, err := path.Parse(cfg.secret); err == nil {
+		cfg.secretMtime = file.ModTime(p.ConfigFile())
+	}
+	secretBackendMu.Lock()
+	defer secretBackendMu.Unlock()
+	if secretBackendClient != nil && secretBackendCfg == cfg {
+		return secretBackendClient, nil
+	}
+	c := &vault.Client{
+		URL:          cfg.url,
+		Namespace:    cfg.namespace,
+		AppRoleMount: cfg.appRoleMount,
+		TTL:          cfg.ttl,
+		HTTPClient: &http.Client{
+			Timeout: 5 * time.Second,
+			Transport: &http.Transport{
+				TLSClientConfig: &tls.Config{
+					InsecureSkipVerify: cfg.insecure,
+				},
+			},
+		},
+	}
+	if err := loadSecretBackendAuth(c, cfg.secret); err != nil {
+		return nil, err
+	}
+	secretBackendClient = c
+	secretBackendCfg = cfg
+	return c, nil
+}
+
+// loadSecretBackendAuth sets the client credentials from the keys of the
+// node-local sec object: token, or role_id and secret_id.
+func loadSecretBackendAuth(c *vault.Client, secret string) error {
+	p, err := path.Parse(secret)
+	if err != nil {
+		return errors.Wrapf(err, "vault.secret %s", secret)
+	}
+	if !p.Exists() {
+		return fmt.Errorf("vault.secret %s does not exist", p)
+	}
+	s, err := NewSec(p, WithVolatile(true))
+	if err != nil {
+		return err
+	}
+	// decode the credentials without resolving references, which would
+	// need the credentials.
+	decode := func(name string) (string, error) {
+		v, _, err := s.activeKeyValue(name)
+		if err != nil {
+			return "", err
+		}
+		if isSecretRef(v) {
+			return "", fmt.Errorf("%s key %s: the secret backend credentials can not be references", p, name)
+		}
+		b, err := s.customDecode(v)
+		return strings.TrimSpace(string(b)), err
+	}
+	switch {
+	case s.HasKey("token"):
+		c.Token, err = decode("token")
+		return err
+	case s.HasKey("role_id"):
+		if c.RoleID, err = decode("role_id"); err != nil {
+			return err
+		}
+		if !s.HasKey("secret_id") {
+			return nil
+		}
+		c.SecretID, err = decode("secret_id")
+		return err
+	default:
+		return fmt.Errorf("%s has no token nor role_id key", p)
+	}
+}
+
+// AddKeyRef adds a key referencing a secret in the external backend. The
+// reference is resolved when the key is decoded or installed.
+func (t *sec) AddKeyRef(name string, ref string) error {
+	if t.HasKey(name) {
+		return fmt.Errorf("key already exist: %s. use the change action.", name)
+	}
+	return t.setKeyRef(name, ref)
+}
+
+// ChangeKeyRef changes an existing key to a reference to a secret in the
+// external backend.
+func (t *sec) ChangeKeyRef(name string, ref string) error {
+	if !t.HasKey(name) {
+		return fmt.Errorf("key does not exist: %s. use the add action.", name)
+	}
+	return t.setKeyRef(name, ref)
+}
+
+func (t *sec) setKeyRef(name string, ref string) error {
+	if !isSecretRef(ref) {
+		return fmt.Errorf("invalid secret reference %s: expect %s<path>[#<field>]", ref, vault.RefScheme)
+	}
+	if _, _, err := vault.ParseRef(ref); err != nil {
+		return err
+	}
+	if err := t.setKeyValue(name, ref); err != nil {
+		return err
+	}
+	return t.config.Commit()
+}
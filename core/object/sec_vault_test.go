@@ -0,0 +1,95 @@
+package object_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/testhelper"
+	"opensvc.com/opensvc/util/key"
+)
+
+func TestSecVaultRef(t *testing.T) {
+	testhelper.Setup(t)
+	clusterConf := "[cluster]\nname = cluster1\nsecret = 0123456789abcdef0123456789abcdef\n"
+	require.NoError(t, os.WriteFile(rawconfig.ClusterConfigFile(), []byte(clusterConf), 0600))
+	rawconfig.LoadSections()
+	validToken := "test-token"
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != validToken {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/db":
+			_, _ = w.Write([]byte(`{"data":{"data":{"password":"s3cr3t"},"metadata":{"version":1}}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer srv.Close()
+
+	p, err := path.Parse("test/sec/app")
+	require.NoError(t, err)
+	o, err := object.NewSec(p)
+	require.NoError(t, err)
+
+	t.Run("a reference requires a backend", func(t *testing.T) {
+		require.NoError(t, o.AddKeyRef("password", "vault://secret/data/db#password"))
+		_, err := o.DecodeKey("password")
+		assert.ErrorIs(t, err, object.ErrNoSecretBackend)
+	})
+
+	nodeConf := "[vault]\nurl = " + srv.URL + "\nttl = 0\n"
+	require.NoError(t, os.WriteFile(rawconfig.NodeConfigFile(), []byte(nodeConf), 0600))
+	authPath, err := path.Parse("system/sec/vault")
+	require.NoError(t, err)
+	auth, err := object.NewSec(authPath)
+	require.NoError(t, err)
+	require.NoError(t, auth.AddKey("token", []byte("test-token")))
+
+	t.Run("decode resolves the reference", func(t *testing.T) {
+		b, err := o.DecodeKey("password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", string(b))
+	})
+
+	t.Run("install resolves the reference", func(t *testing.T) {
+		dst := t.TempDir() + "/password"
+		mode := os.FileMode(0600)
+		require.NoError(t, o.InstallKeyTo("password", dst, &mode, &mode, nil, nil))
+		b, err := os.ReadFile(dst)
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", string(b))
+	})
+
+	t.Run("safe reference in a configuration", func(t *testing.T) {
+		svcPath, err := path.Parse("test/svc/app")
+		require.NoError(t, err)
+		svc, err := object.NewSvc(svcPath, object.WithConfigData([]byte("[env]\npassword = {safe://secret/data/db#password}\n")))
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", svc.Config().GetString(key.New("env", "password")))
+	})
+
+	t.Run("a credentials change renews the client", func(t *testing.T) {
+		validToken = "new-token"
+		require.NoError(t, auth.ChangeKey("token", []byte("new-token")))
+		b, err := o.DecodeKey("password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", string(b))
+	})
+
+	t.Run("invalid references are refused", func(t *testing.T) {
+		assert.Error(t, o.AddKeyRef("other", "secret/data/db#password"))
+		assert.Error(t, o.ChangeKeyRef("missing", "vault://secret/data/db#password"))
+	})
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"opensvc.com/opensvc/core/colorstatus"
 	"opensvc.com/opensvc/core/instance"
@@ -34,20 +35,21 @@ type (
 	// Status contains the object states obtained via
 	// aggregation of all instances states. It exists when a instance config exists somewhere
 	Status struct {
-		Avail            status.T         `json:"avail"`
-		FlexTarget       int              `json:"flex_target,omitempty"`
-		FlexMin          int              `json:"flex_min,omitempty"`
-		FlexMax          int              `json:"flex_max,omitempty"`
-		Frozen           string           `json:"frozen"`
-		Orchestrate      string           `json:"orchestrate"`
-		Overall          status.T         `json:"overall"`
-		PlacementPolicy  placement.Policy `json:"placement_policy"`
-		PlacementState   placement.State  `json:"placement_state"`
-		Priority         priority.T       `json:"priority"`
-		Provisioned      provisioned.T    `json:"provisioned"`
-		Scope            []string         `json:"scope"`
-		Topology         topology.T       `json:"topology"`
-		UpInstancesCount int              `json:"up_instances_count"`
+		Avail             status.T         `json:"avail"`
+		FailbackScheduled time.Time        `json:"failback_scheduled,omitempty"`
+		FlexTarget        int              `json:"flex_target,omitempty"`
+		FlexMin           int              `json:"flex_min,omitempty"`
+		FlexMax           int              `json:"flex_max,omitempty"`
+		Frozen            string           `json:"frozen"`
+		Orchestrate       string           `json:"orchestrate"`
+		Overall           status.T         `json:"overall"`
+		PlacementPolicy   placement.Policy `json:"placement_policy"`
+		PlacementState    placement.State  `json:"placement_state"`
+		Priority          priority.T       `json:"priority"`
+		Provisioned       provisioned.T    `json:"provisioned"`
+		Scope             []string         `json:"scope"`
+		Topology          topology.T       `json:"topology"`
+		UpInstancesCount  int              `json:"up_instances_count"`
 	}
 )
 
@@ -141,7 +143,11 @@ func (t Digest) descString() string {
 	switch t.Object.PlacementState {
 	case placement.Optimal, placement.NotApplicable:
 	default:
-		l = append(l, rawconfig.Colorize.Warning(fmt.Sprintf("%s placement", t.Object.PlacementState)))
+		if t.Object.FailbackScheduled.IsZero() {
+			l = append(l, rawconfig.Colorize.Warning(fmt.Sprintf("%s placement", t.Object.PlacementState)))
+		} else {
+			l = append(l, rawconfig.Colorize.Warning(fmt.Sprintf("%s, failback scheduled at %s", t.Object.PlacementState, t.Object.FailbackScheduled.Format(time.RFC3339))))
+		}
 	}
 
 	// Agent compatibility
@@ -164,19 +170,20 @@ func NewStatus() *Digest {
 
 func (s *Status) DeepCopy() *Status {
 	return &Status{
-		Avail:            s.Avail,
-		Overall:          s.Overall,
-		Frozen:           s.Frozen,
-		Orchestrate:      s.Orchestrate,
-		PlacementState:   s.PlacementState,
-		PlacementPolicy:  s.PlacementPolicy,
-		Provisioned:      s.Provisioned,
-		Priority:         s.Priority,
-		Topology:         s.Topology,
-		FlexTarget:       s.FlexTarget,
-		FlexMin:          s.FlexMin,
-		FlexMax:          s.FlexMax,
-		UpInstancesCount: s.UpInstancesCount,
-		Scope:            append([]string{}, s.Scope...),
+		Avail:             s.Avail,
+		FailbackScheduled: s.FailbackScheduled,
+		Overall:           s.Overall,
+		Frozen:            s.Frozen,
+		Orchestrate:       s.Orchestrate,
+		PlacementState:    s.PlacementState,
+		PlacementPolicy:   s.PlacementPolicy,
+		Provisioned:       s.Provisioned,
+		Priority:          s.Priority,
+		Topology:          s.Topology,
+		FlexTarget:        s.FlexTarget,
+		FlexMin:           s.FlexMin,
+		FlexMax:           s.FlexMax,
+		UpInstancesCount:  s.UpInstancesCount,
+		Scope:             append([]string{}, s.Scope...),
 	}
 }
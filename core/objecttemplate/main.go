@@ -0,0 +1,371 @@
+// Package objecttemplate implements the local catalog of object
+// configuration templates used by "create --template <name> --param k=v".
+//
+// Templates are read from two sources:
+//
+//   - the <etc>/templates/<name>.conf files, local to the node
+//   - the "template" key of the cfg objects in the "templates" namespace,
+//     named after the template, replicated cluster-wide like any cfg
+//
+// A template is an ini formatted object configuration, where the values
+// can embed parameters using the {{.<param>}} text/template syntax. The
+// parameters are declared in the [template] section, either with their
+// default value, possibly empty, or in the list of required parameters:
+//
+//	[template]
+//	description = a web frontend
+//	required = image
+//	param.port = 8080
+//	param.env =
+//
+//	[container#1]
+//	type = docker
+//	image = {{.image}}
+//	environment = PORT={{.port}} {{.env}}
+//
+// A required parameter has no default value and must be set on create.
+// The [template] section is removed from the rendered configuration.
+package objecttemplate
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/cvaroqui/ini"
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
+)
+
+type (
+	// T is a object configuration template.
+	T struct {
+		Name        string  `json:"name"`
+		Source      string  `json:"source"`
+		Description string  `json:"description"`
+		Params      []Param `json:"params"`
+		Text        string  `json:"text"`
+	}
+
+	// L is a list of templates.
+	L []T
+
+	// Param is a template parameter declaration.
+	Param struct {
+		Name     string `json:"name"`
+		Default  string `json:"default"`
+		Required bool   `json:"required"`
+	}
+)
+
+const (
+	// Namespace is the namespace hosting the template cfg objects.
+	Namespace = "templates"
+
+	// KeyName is the cfg key holding the template text.
+	KeyName = "template"
+
+	sectionName = "template"
+	paramPrefix = "param."
+	requiredKey = "required"
+)
+
+var (
+	ErrNotFound = errors.New("template not found")
+)
+
+// Dir returns the directory hosting the node-local templates.
+func Dir() string {
+	return filepath.Join(rawconfig.Paths.Etc, "templates")
+}
+
+// List returns the templates of the catalog, sorted by name. A node-local
+// template hides the cfg object template of the same name. An invalid
+// template, from either source, aborts the listing.
+func List() (L, error) {
+	m := make(map[string]T)
+	l, err := listCfg()
+	if err != nil {
+		return nil, err
+	}
+	for _, t := range l {
+		m[t.Name] = t
+	}
+	if l, err = listDir(); err != nil {
+		return nil, err
+	}
+	for _, t := range l {
+		m[t.Name] = t
+	}
+	result := make(L, 0, len(m))
+	for _, t := range m {
+		result = append(result, t)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result, nil
+}
+
+// validateName returns an error if <name> can not be a template file
+// basename in the catalog directory.
+func validateName(name string) error {
+	switch {
+	case name == "", name == ".", name == "..", strings.ContainsAny(name, "/\\"):
+		return errors.Errorf("invalid template name '%s'", name)
+	}
+	return nil
+}
+
+// Get returns the template named <name>.
+func Get(name string) (T, error) {
+	if err := validateName(name); err != nil {
+		return T{}, err
+	}
+	if t, err := fromFile(filepath.Join(Dir(), name+".conf")); err == nil {
+		return t, nil
+	} else if !os.IsNotExist(errors.Cause(err)) {
+		return T{}, err
+	}
+	p, err := path.New(name, Namespace, kind.Cfg.String())
+	if err != nil {
+		return T{}, errors.Wrapf(ErrNotFound, "%s", name)
+	}
+	if !p.Exists() {
+		return T{}, errors.Wrapf(ErrNotFound, "%s", name)
+	}
+	return fromCfg(p)
+}
+
+func listDir() (L, error) {
+	l := make(L, 0)
+	matches, err := filepath.Glob(filepath.Join(Dir(), "*.conf"))
+	if err != nil {
+		return l, err
+	}
+	for _, p := range matches {
+		t, err := fromFile(p)
+		if err != nil {
+			return l, err
+		}
+		l = append(l, t)
+	}
+	return l, nil
+}
+
+func listCfg() (L, error) {
+	l := make(L, 0)
+	paths, err := path.List()
+	if err != nil {
+		return l, err
+	}
+	for _, p := range paths {
+		if p.Namespace != Namespace || p.Kind != kind.Cfg {
+			continue
+		}
+		t, err := fromCfg(p)
+		if err != nil {
+			return l, err
+		}
+		l = append(l, t)
+	}
+	return l, nil
+}
+
+func fromFile(p string) (T, error) {
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return T{}, errors.Wrapf(err, "read template %s", p)
+	}
+	name := strings.TrimSuffix(filepath.Base(p), ".conf")
+	return New(name, p, string(b))
+}
+
+func fromCfg(p path.T) (T, error) {
+	o, err := object.NewCfg(p, object.WithVolatile(true))
+	if err != nil {
+		return T{}, err
+	}
+	if !o.HasKey(KeyName) {
+		return T{}, errors.Errorf("template %s: key %s not found", p, KeyName)
+	}
+	b, err := o.DecodeKey(KeyName)
+	if err != nil {
+		return T{}, err
+	}
+	return New(p.Name, p.String(), string(b))
+}
+
+// New parses the template text and returns the template with its declared
+// parameters.
+func New(name, source, text string) (T, error) {
+	t := T{
+		Name:   name,
+		Source: source,
+		Text:   text,
+		Params: make([]Param, 0),
+	}
+	loadOptions := ini.LoadOptions{
+		Loose:                      true,
+		AllowPythonMultilineValues: true,
+		SpaceBeforeInlineComment:   true,
+		SkipUnrecognizableLines:    true,
+	}
+	f, err := ini.LoadSources(loadOptions, []byte(text))
+	if err != nil {
+		return t, errors.Wrapf(err, "template %s", name)
+	}
+	section, err := f.GetSection(sectionName)
+	if err != nil {
+		return t, nil
+	}
+	for _, k := range section.Keys() {
+		option := k.Name()
+		switch {
+		case option == "description":
+			t.Description = k.Value()
+		case option == requiredKey:
+			for _, name := range strings.Fields(k.Value()) {
+				t.Params = append(t.Params, Param{
+					Name:     name,
+					Required: true,
+				})
+			}
+		case strings.HasPrefix(option, paramPrefix):
+			t.Params = append(t.Params, Param{
+				Name:    option[len(paramPrefix):],
+				Default: k.Value(),
+			})
+		}
+	}
+	declared := make(map[string]any)
+	for _, p := range t.Params {
+		if _, ok := declared[p.Name]; ok {
+			return t, errors.Errorf("template %s: parameter %s is declared more than once, or both required and with a default", name, p.Name)
+		}
+		declared[p.Name] = nil
+	}
+	return t, nil
+}
+
+// Values merges the parameter values with the declared defaults. It returns
+// an error if a value is set for an undeclared parameter, if a value
+// contains a line break, or if a required parameter has no value.
+func (t T) Values(params map[string]string) (map[string]string, error) {
+	values := make(map[string]string)
+	declared := make(map[string]any)
+	for _, p := range t.Params {
+		declared[p.Name] = nil
+		if v, ok := params[p.Name]; ok {
+			if strings.ContainsAny(v, "\r\n") {
+				// a line break would inject sections or keywords in
+				// the rendered configuration
+				return nil, errors.Errorf("template %s: parameter %s value contains a line break", t.Name, p.Name)
+			}
+			values[p.Name] = v
+		} else if p.Required {
+			return nil, errors.Errorf("template %s: parameter %s is required", t.Name, p.Name)
+		} else {
+			values[p.Name] = p.Default
+		}
+	}
+	for name := range params {
+		if _, ok := declared[name]; !ok {
+			return nil, errors.Errorf("template %s: parameter %s is not declared", t.Name, name)
+		}
+	}
+	return values, nil
+}
+
+// Execute renders the template text with the parameter values, and
+// returns the object configuration, without the [template] section.
+func (t T) Execute(params map[string]string) (rawconfig.T, error) {
+	values, err := t.Values(params)
+	if err != nil {
+		return rawconfig.T{}, err
+	}
+	tmpl, err := template.New(t.Name).Option("missingkey=error").Parse(t.Text)
+	if err != nil {
+		return rawconfig.T{}, errors.Wrapf(err, "template %s", t.Name)
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, values); err != nil {
+		return rawconfig.T{}, errors.Wrapf(err, "template %s", t.Name)
+	}
+	cf, err := xconfig.NewObject("", b.Bytes())
+	if err != nil {
+		return rawconfig.T{}, errors.Wrapf(err, "template %s", t.Name)
+	}
+	c := cf.Raw()
+	c.Data.Delete(sectionName)
+	return c, nil
+}
+
+// Instantiate renders the template with the parameter values, and validates
+// the resulting configuration of the object p against the keyword store.
+func (t T) Instantiate(p path.T, params map[string]string) (rawconfig.T, error) {
+	c, err := t.Execute(params)
+	if err != nil {
+		return c, err
+	}
+	o, err := object.New(p, object.WithVolatile(true))
+	if err != nil {
+		return c, err
+	}
+	oc, ok := o.(object.Configurer)
+	if !ok {
+		return c, errors.Errorf("%s is not configurable", p)
+	}
+	if err := oc.Config().LoadRaw(c); err != nil {
+		return c, err
+	}
+	if alerts, err := oc.Config().Validate(); alerts.HasError() {
+		return c, errors.Errorf("template %s rendered an invalid %s configuration:\n%s", t.Name, p, alerts.Render())
+	} else if err != nil {
+		return c, err
+	}
+	return c, nil
+}
+
+// Render returns the human representation of the template.
+func (t T) Render() string {
+	s := fmt.Sprintf("name: %s\nsource: %s\n", t.Name, t.Source)
+	if t.Description != "" {
+		s += fmt.Sprintf("description: %s\n", t.Description)
+	}
+	if len(t.Params) > 0 {
+		s += "params:\n"
+		for _, p := range t.Params {
+			if p.Required {
+				s += fmt.Sprintf("  %s (required)\n", p.Name)
+			} else {
+				s += fmt.Sprintf("  %s = %s\n", p.Name, p.Default)
+			}
+		}
+	}
+	s += "\n" + t.Text
+	if !strings.HasSuffix(t.Text, "\n") {
+		s += "\n"
+	}
+	return s
+}
+
+// Render returns the human representation of the template list.
+func (t L) Render() string {
+	s := ""
+	for _, tmpl := range t {
+		l := make([]string, len(tmpl.Params))
+		for i, p := range tmpl.Params {
+			l[i] = p.Name
+		}
+		s += fmt.Sprintf("%-20s %-40s %s\n", tmpl.Name, tmpl.Description, strings.Join(l, " "))
+	}
+	return s
+}
@@ -0,0 +1,113 @@
+package objecttemplate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/iancoleman/orderedmap"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/testhelper"
+)
+
+const webTemplate = `[template]
+description = a web frontend
+required = nodes
+param.orchestrate = ha
+param.app =
+
+[DEFAULT]
+nodes = {{.nodes}}
+orchestrate = {{.orchestrate}}
+app = {{.app}}
+`
+
+func installTemplate(t *testing.T, name, text string) {
+	require.NoError(t, os.MkdirAll(Dir(), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(Dir(), name+".conf"), []byte(text), 0600))
+}
+
+func TestNew(t *testing.T) {
+	tmpl, err := New("web", "test", webTemplate)
+	require.NoError(t, err)
+	assert.Equal(t, "a web frontend", tmpl.Description)
+	assert.Equal(t, []Param{
+		{Name: "nodes", Required: true},
+		{Name: "orchestrate", Default: "ha"},
+		{Name: "app"},
+	}, tmpl.Params)
+
+	_, err = New("web", "test", "[template]\nrequired = nodes\nparam.nodes = n1\n")
+	assert.ErrorContains(t, err, "parameter nodes is declared more than once")
+}
+
+func TestValues(t *testing.T) {
+	tmpl, err := New("web", "test", webTemplate)
+	require.NoError(t, err)
+
+	_, err = tmpl.Values(map[string]string{})
+	assert.ErrorContains(t, err, "parameter nodes is required")
+
+	_, err = tmpl.Values(map[string]string{"nodes": "n1", "foo": "bar"})
+	assert.ErrorContains(t, err, "parameter foo is not declared")
+
+	_, err = tmpl.Values(map[string]string{"nodes": "n1\n[task#1]\ncommand = /bin/true"})
+	assert.ErrorContains(t, err, "parameter nodes value contains a line break")
+
+	values, err := tmpl.Values(map[string]string{"nodes": "n1"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"nodes": "n1", "orchestrate": "ha", "app": ""}, values)
+}
+
+func TestGetInvalidName(t *testing.T) {
+	testhelper.Setup(t)
+	for _, name := range []string{"", "..", "../web", "sub/web"} {
+		_, err := Get(name)
+		assert.ErrorContains(t, err, "invalid template name", name)
+	}
+}
+
+func TestInstantiate(t *testing.T) {
+	testhelper.Setup(t)
+	installTemplate(t, "web", webTemplate)
+	p, err := path.Parse("web1")
+	require.NoError(t, err)
+
+	l, err := List()
+	require.NoError(t, err)
+	require.Len(t, l, 1)
+	assert.Equal(t, "web", l[0].Name)
+
+	installTemplate(t, "broken", "[template\n")
+	_, err = List()
+	assert.ErrorContains(t, err, "template broken")
+	require.NoError(t, os.Remove(filepath.Join(Dir(), "broken.conf")))
+
+	_, err = Get("nonexistent")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	tmpl, err := Get("web")
+	require.NoError(t, err)
+
+	t.Run("renders with defaults", func(t *testing.T) {
+		c, err := tmpl.Instantiate(p, map[string]string{"nodes": "n1 n2"})
+		require.NoError(t, err)
+		_, ok := c.Data.Get("template")
+		assert.False(t, ok, "template section must be removed")
+		i, ok := c.Data.Get("DEFAULT")
+		require.True(t, ok)
+		section := i.(orderedmap.OrderedMap)
+		nodes, _ := section.Get("nodes")
+		assert.Equal(t, "n1 n2", nodes)
+		orchestrate, _ := section.Get("orchestrate")
+		assert.Equal(t, "ha", orchestrate)
+	})
+
+	t.Run("rejects invalid keyword values", func(t *testing.T) {
+		_, err := tmpl.Instantiate(p, map[string]string{"nodes": "n1", "orchestrate": "foo"})
+		assert.ErrorContains(t, err, "invalid")
+	})
+}
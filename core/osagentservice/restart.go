@@ -0,0 +1,20 @@
+package osagentservice
+
+import (
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// restartDetached starts the "<cmdPath> daemon restart" command without
+// waiting for it, so the command survives the exit of the daemon it
+// restarts.
+func restartDetached(cmdPath string, env []string, attr *syscall.SysProcAttr) error {
+	cmd := exec.Command(cmdPath, "daemon", "restart")
+	cmd.Env = append(os.Environ(), env...)
+	cmd.SysProcAttr = attr
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package osagentservice
+
+// Restart starts a detached "<cmdPath> daemon restart" command, so the
+// daemon can request its own restart.
+func Restart(cmdPath string, env ...string) error {
+	return restartDetached(cmdPath, env, nil)
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package osagentservice
+
+import (
+	"os/exec"
+	"syscall"
+
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/systemd"
+)
+
+// Restart asks systemd to restart the opensvc agent service, without
+// waiting for the restart, so the daemon can request its own restart.
+// Without the systemd capability, a detached "<cmdPath> daemon restart"
+// command, in its own session, restarts the daemon.
+func Restart(cmdPath string, env ...string) error {
+	if capabilities.Has(systemd.NodeCapability) {
+		return exec.Command("systemctl", "restart", "--no-block", agentServiceName).Run()
+	}
+	return restartDetached(cmdPath, env, &syscall.SysProcAttr{Setsid: true})
+}
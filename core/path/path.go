@@ -298,21 +298,20 @@ func (t Relation) Split() (T, string, error) {
 	return p, t.Node(), err
 }
 
+// Node returns the node part of a path@node relation, or an empty string
+// if the relation is not scoped to a node.
 func (t Relation) Node() string {
-	var s string
-	if strings.Contains(string(t), "@") {
-		s = strings.SplitN(string(t), "@", 1)[1]
+	l := strings.SplitN(string(t), "@", 2)
+	if len(l) == 2 {
+		return l[1]
 	}
-	return s
+	return ""
 }
 
+// Path returns the object path of the relation.
 func (t Relation) Path() (T, error) {
-	var s string
-	if strings.Contains(string(t), "@") {
-		s = strings.SplitN(string(t), "@", 1)[0]
-	}
-	s = string(t)
-	return Parse(s)
+	l := strings.SplitN(string(t), "@", 2)
+	return Parse(l[0])
 }
 
 func (t L) String() string {
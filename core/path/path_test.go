@@ -468,3 +468,30 @@ func TestString(t *testing.T) {
 	p := T{}
 	assert.Equal(t, "", p.String())
 }
+
+func TestRelation(t *testing.T) {
+	tests := map[string]struct {
+		relation string
+		path     string
+		node     string
+	}{
+		"object": {
+			relation: "ns1/svc/db",
+			path:     "ns1/svc/db",
+			node:     "",
+		},
+		"instance": {
+			relation: "ns1/svc/db@node1",
+			path:     "ns1/svc/db",
+			node:     "node1",
+		},
+	}
+	for testName, test := range tests {
+		t.Run(testName, func(t *testing.T) {
+			p, node, err := Relation(test.relation).Split()
+			require.NoError(t, err)
+			assert.Equal(t, test.path, p.String())
+			assert.Equal(t, test.node, node)
+		})
+	}
+}
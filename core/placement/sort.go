@@ -0,0 +1,89 @@
+package placement
+
+import (
+	"bytes"
+	"crypto/md5"
+	"sort"
+)
+
+type (
+	// Sorter holds the object and cluster data needed to sort the
+	// candidate nodes of an object instance placement.
+	Sorter struct {
+		// Key is the object path, salting the spread policy hashes.
+		Key string
+
+		// ScalerSliceIndex is the scaler slice index of the object, used
+		// by the shift policy.
+		ScalerSliceIndex int
+
+		// Nodes is the object nodes list, in configuration order.
+		Nodes []string
+
+		// Scores is the score of the cluster nodes, indexed by node name.
+		Scores map[string]uint64
+	}
+)
+
+// Sort returns the candidates sorted by decreasing priority according to
+// the placement policy. The policies not implemented return an empty list.
+func (t Sorter) Sort(policy Policy, candidates []string) []string {
+	switch policy {
+	case NodesOrder:
+		return t.sortNodesOrder(candidates)
+	case Spread:
+		return t.sortSpread(candidates)
+	case Score:
+		return t.sortScore(candidates)
+	case Shift:
+		return t.sortShift(candidates)
+	default:
+		return []string{}
+	}
+}
+
+func (t Sorter) sortSpread(candidates []string) []string {
+	l := append([]string{}, candidates...)
+	sum := func(s string) []byte {
+		b := append([]byte(t.Key), []byte(s)...)
+		return md5.New().Sum(b)
+	}
+	sort.SliceStable(l, func(i, j int) bool {
+		return bytes.Compare(sum(l[i]), sum(l[j])) < 0
+	})
+	return l
+}
+
+// sortScore sorts candidates by descending node score.
+func (t Sorter) sortScore(candidates []string) []string {
+	l := append([]string{}, candidates...)
+	sort.SliceStable(l, func(i, j int) bool {
+		return t.Scores[l[i]] > t.Scores[l[j]]
+	})
+	return l
+}
+
+func (t Sorter) sortShift(candidates []string) []string {
+	var i int
+	l := t.sortNodesOrder(candidates)
+	n := len(l)
+	l = append(l, l...)
+	if n > 0 && t.ScalerSliceIndex > n {
+		i = t.ScalerSliceIndex % n
+	}
+	return l[i : i+n]
+}
+
+func (t Sorter) sortNodesOrder(candidates []string) []string {
+	m := make(map[string]any)
+	for _, node := range candidates {
+		m[node] = nil
+	}
+	l := make([]string, 0)
+	for _, node := range t.Nodes {
+		if _, ok := m[node]; ok {
+			l = append(l, node)
+		}
+	}
+	return l
+}
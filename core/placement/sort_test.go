@@ -0,0 +1,37 @@
+package placement
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSorterSort(t *testing.T) {
+	sorter := Sorter{
+		Key:    "svc1",
+		Nodes:  []string{"n1", "n2", "n3"},
+		Scores: map[string]uint64{"n1": 10, "n2": 30, "n3": 20},
+	}
+	candidates := []string{"n3", "n2", "n1"}
+	t.Run("nodes order", func(t *testing.T) {
+		assert.Equal(t, []string{"n1", "n2", "n3"}, sorter.Sort(NodesOrder, candidates))
+		assert.Equal(t, []string{"n1", "n3"}, sorter.Sort(NodesOrder, []string{"n3", "n1"}))
+	})
+	t.Run("score", func(t *testing.T) {
+		assert.Equal(t, []string{"n2", "n3", "n1"}, sorter.Sort(Score, candidates))
+	})
+	t.Run("shift", func(t *testing.T) {
+		shifted := sorter
+		shifted.ScalerSliceIndex = 4
+		assert.Equal(t, []string{"n2", "n3", "n1"}, shifted.Sort(Shift, candidates))
+		assert.Equal(t, []string{"n1", "n2", "n3"}, sorter.Sort(Shift, candidates))
+	})
+	t.Run("spread is stable", func(t *testing.T) {
+		l := sorter.Sort(Spread, candidates)
+		assert.ElementsMatch(t, candidates, l)
+		assert.Equal(t, l, sorter.Sort(Spread, []string{"n1", "n3", "n2"}))
+	})
+	t.Run("not implemented", func(t *testing.T) {
+		assert.Empty(t, sorter.Sort(LoadAvg, candidates))
+	})
+}
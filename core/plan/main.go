@@ -0,0 +1,380 @@
+// Package plan implements the multi-object orchestrated actions.
+//
+// A plan applies an action to a selection of objects. The objects are
+// ordered in waves, computed from their parents and children relations and
+// their priority. The objects of a wave are orchestrated in parallel by the
+// daemons, through the object monitor api, and a wave starts only when the
+// objects of the previous wave reached the action target state.
+//
+// The plan progress is saved in the var directory of the node running the
+// plan command, so a plan can be followed, aborted and resumed from another
+// command on the same node. The daemons don't know the plans: a plan is
+// not visible from the other cluster nodes, and is lost with its node. A
+// running plan records its runner process and heartbeat, so a plan whose
+// runner died can be resumed or aborted by another command.
+package plan
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/priority"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/status"
+)
+
+type (
+	// T is a multi-object orchestrated action.
+	T struct {
+		ID          string    `json:"id"`
+		Action      Action    `json:"action"`
+		Selector    string    `json:"selector"`
+		Destination []string  `json:"destination,omitempty"`
+		Status      Status    `json:"status"`
+		Error       string    `json:"error,omitempty"`
+		Created     time.Time `json:"created"`
+		Updated     time.Time `json:"updated"`
+		Runner      *Runner   `json:"runner,omitempty"`
+		Waves       []Wave    `json:"waves"`
+	}
+
+	// Runner is the process running a plan.
+	Runner struct {
+		Host string `json:"host"`
+		PID  int    `json:"pid"`
+
+		// Heartbeat is refreshed by the runner while the plan is
+		// running.
+		Heartbeat time.Time `json:"heartbeat"`
+	}
+
+	// Wave is a set of objects orchestrated in parallel.
+	Wave struct {
+		Items []Item `json:"items"`
+	}
+
+	// Item is the progress of the action on an object.
+	Item struct {
+		Path   path.T `json:"path"`
+		Status Status `json:"status"`
+		Error  string `json:"error,omitempty"`
+
+		// Orchestrated is true when a daemon was seen orchestrating
+		// the action target.
+		Orchestrated bool `json:"orchestrated,omitempty"`
+
+		Updated time.Time `json:"updated"`
+	}
+
+	// Object is an object to include in a plan, with the relations and
+	// priority used to order the waves.
+	Object struct {
+		Path     path.T
+		Priority priority.T
+		Parents  []path.T
+		Children []path.T
+	}
+
+	// Action is the action applied to the plan objects.
+	Action string
+
+	// Status is the status of a plan or a plan item.
+	Status string
+)
+
+const (
+	ActionProvision Action = "provision"
+	ActionStart     Action = "start"
+	ActionStop      Action = "stop"
+	ActionSwitch    Action = "switch"
+)
+
+const (
+	StatusPending Status = "pending"
+	StatusRunning Status = "running"
+	StatusDone    Status = "done"
+	StatusFailed  Status = "failed"
+	StatusAborted Status = "aborted"
+)
+
+const (
+	// orchestrationGracePeriod is the delay after the submit of an item
+	// for a daemon to start its orchestration.
+	orchestrationGracePeriod = 30 * time.Second
+)
+
+var (
+	// actionGlobalExpect is the object monitor global expect submitted
+	// for each action.
+	actionGlobalExpect = map[Action]instance.MonitorGlobalExpect{
+		ActionProvision: instance.MonitorGlobalExpectProvisioned,
+		ActionStart:     instance.MonitorGlobalExpectStarted,
+		ActionStop:      instance.MonitorGlobalExpectStopped,
+		ActionSwitch:    instance.MonitorGlobalExpectPlacedAt,
+	}
+)
+
+// ParseAction returns the Action named s.
+func ParseAction(s string) (Action, error) {
+	action := Action(s)
+	if _, ok := actionGlobalExpect[action]; !ok {
+		return action, fmt.Errorf("unsupported plan action %s (supported: provision, start, stop, switch)", s)
+	}
+	return action, nil
+}
+
+// GlobalExpect returns the object monitor global expect the action submits.
+func (t Action) GlobalExpect() instance.MonitorGlobalExpect {
+	return actionGlobalExpect[t]
+}
+
+// isReverse returns true if the action applies to the children before their
+// parents.
+func (t Action) isReverse() bool {
+	return t == ActionStop
+}
+
+// New returns a plan applying action to objects, with the objects ordered
+// in waves.
+//
+// The parents are orchestrated in a wave before their children, or after
+// their children for the stop action. The relations with objects not in
+// the plan are ignored. Amongst the objects free to go, the objects with the
+// smaller priority go first, or last for the stop action.
+func New(action Action, selector string, objects []Object) (*T, error) {
+	if _, ok := actionGlobalExpect[action]; !ok {
+		return nil, fmt.Errorf("unsupported plan action %s", action)
+	}
+	waves, err := newWaves(action, objects)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	t := &T{
+		ID:       uuid.New().String(),
+		Action:   action,
+		Selector: selector,
+		Status:   StatusPending,
+		Created:  now,
+		Updated:  now,
+		Waves:    make([]Wave, len(waves)),
+	}
+	for i, paths := range waves {
+		items := make([]Item, len(paths))
+		for j, p := range paths {
+			items[j] = Item{Path: p, Status: StatusPending, Updated: now}
+		}
+		t.Waves[i] = Wave{Items: items}
+	}
+	return t, nil
+}
+
+// newWaves returns the object paths grouped in waves, in the order of the
+// action.
+func newWaves(action Action, objects []Object) ([][]path.T, error) {
+	byPath := make(map[string]Object)
+	for _, o := range objects {
+		byPath[o.Path.String()] = o
+	}
+
+	// deps indexes the paths an object must wait for
+	deps := make(map[string]map[string]any)
+	addDep := func(before, after path.T) {
+		b, a := before.String(), after.String()
+		if _, ok := byPath[b]; !ok {
+			return
+		}
+		if _, ok := byPath[a]; !ok || a == b {
+			return
+		}
+		if action.isReverse() {
+			a, b = b, a
+		}
+		if deps[a] == nil {
+			deps[a] = make(map[string]any)
+		}
+		deps[a][b] = nil
+	}
+	for _, o := range objects {
+		for _, parent := range o.Parents {
+			addDep(parent, o.Path)
+		}
+		for _, child := range o.Children {
+			addDep(o.Path, child)
+		}
+	}
+
+	waves := make([][]path.T, 0)
+	done := make(map[string]any)
+	for len(done) < len(byPath) {
+		ready := make([]Object, 0)
+		for ps, o := range byPath {
+			if _, ok := done[ps]; ok {
+				continue
+			}
+			isReady := true
+			for dep := range deps[ps] {
+				if _, ok := done[dep]; !ok {
+					isReady = false
+					break
+				}
+			}
+			if isReady {
+				ready = append(ready, o)
+			}
+		}
+		if len(ready) == 0 {
+			l := make([]string, 0)
+			for ps := range byPath {
+				if _, ok := done[ps]; !ok {
+					l = append(l, ps)
+				}
+			}
+			sort.Strings(l)
+			return nil, fmt.Errorf("relations cycle between %s", strings.Join(l, ", "))
+		}
+		sort.Slice(ready, func(i, j int) bool {
+			if ready[i].Priority != ready[j].Priority {
+				if action.isReverse() {
+					return ready[i].Priority > ready[j].Priority
+				}
+				return ready[i].Priority < ready[j].Priority
+			}
+			return ready[i].Path.String() < ready[j].Path.String()
+		})
+		// split the ready objects in one wave per priority
+		var wave []path.T
+		for i, o := range ready {
+			if i > 0 && o.Priority != ready[i-1].Priority {
+				waves = append(waves, wave)
+				wave = nil
+			}
+			wave = append(wave, o.Path)
+		}
+		waves = append(waves, wave)
+		for _, o := range ready {
+			done[o.Path.String()] = nil
+		}
+	}
+	return waves, nil
+}
+
+// isReached returns true if the object reached the target state of the
+// action.
+func (t Action) isReached(digest object.Digest, orchestrated bool) bool {
+	switch t {
+	case ActionProvision:
+		return digest.Object.Provisioned == provisioned.True
+	case ActionStart:
+		return digest.Object.Avail == status.Up
+	case ActionStop:
+		switch digest.Object.Avail {
+		case status.Up, status.Warn:
+			return false
+		default:
+			return true
+		}
+	case ActionSwitch:
+		return orchestrated && !isOrchestrated(digest) && digest.Object.Avail == status.Up
+	default:
+		return false
+	}
+}
+
+// isOrchestrated returns true if an instance of the object has a global
+// expect set.
+func isOrchestrated(digest object.Digest) bool {
+	for _, instanceStates := range digest.Instances {
+		switch instanceStates.Monitor.GlobalExpect {
+		case instance.MonitorGlobalExpectEmpty, instance.MonitorGlobalExpectUnset:
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// failedStates returns the failed instance monitor states of the object,
+// formatted like "node1: start failed".
+func failedStates(digest object.Digest) []string {
+	l := make([]string, 0)
+	for nodename, instanceStates := range digest.Instances {
+		if s := instanceStates.Monitor.State.String(); strings.HasSuffix(s, "failed") {
+			l = append(l, nodename+": "+s)
+		}
+	}
+	sort.Strings(l)
+	return l
+}
+
+// Evaluate updates the running item from the object digest. The item is
+// done when the object reached the action target state, and failed when the
+// daemons ended the orchestration without reaching the target. An item not
+// orchestrated yet fails if an instance monitor state is failed, or if no
+// orchestration is seen within the orchestration grace period after the
+// submit.
+func (t T) Evaluate(item Item, digest object.Digest) Item {
+	if item.Status != StatusRunning {
+		return item
+	}
+	orchestrated := isOrchestrated(digest)
+	if orchestrated {
+		item.Orchestrated = true
+	}
+	switch {
+	case t.Action.isReached(digest, item.Orchestrated):
+		item.Status = StatusDone
+	case item.Orchestrated && !orchestrated:
+		item.Status = StatusFailed
+		item.Error = "orchestration ended before reaching the target"
+		if l := failedStates(digest); len(l) > 0 {
+			item.Error += ": " + strings.Join(l, ", ")
+		}
+	case !item.Orchestrated && len(failedStates(digest)) > 0:
+		item.Status = StatusFailed
+		item.Error = "orchestration failed: " + strings.Join(failedStates(digest), ", ")
+	case !item.Orchestrated && time.Since(item.Updated) >= orchestrationGracePeriod:
+		item.Status = StatusFailed
+		item.Error = fmt.Sprintf("no orchestration seen within %s", orchestrationGracePeriod)
+	default:
+		return item
+	}
+	item.Updated = time.Now()
+	return item
+}
+
+// Reset sets the items not done back to pending, so a run resumes the plan
+// from the first wave not done.
+func (t *T) Reset() {
+	for i, wave := range t.Waves {
+		for j, item := range wave.Items {
+			if item.Status == StatusDone {
+				continue
+			}
+			item.Status = StatusPending
+			item.Error = ""
+			item.Orchestrated = false
+			t.Waves[i].Items[j] = item
+		}
+	}
+	t.Status = StatusPending
+	t.Error = ""
+}
+
+// Paths returns the paths of the plan objects.
+func (t T) Paths() []path.T {
+	l := make([]path.T, 0)
+	for _, wave := range t.Waves {
+		for _, item := range wave.Items {
+			l = append(l, item.Path)
+		}
+	}
+	return l
+}
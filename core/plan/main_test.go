@@ -0,0 +1,304 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+type fakeBackend struct {
+	clusterStatus cluster.Status
+	submitted     []string
+	aborted       []string
+
+	// unreachable indexes the submitted objects never reaching the
+	// target state
+	unreachable map[string]bool
+}
+
+func newFakeBackend(unreachable ...string) *fakeBackend {
+	t := &fakeBackend{
+		clusterStatus: cluster.Status{
+			Cluster: cluster.Cluster{
+				Object: make(map[string]object.Status),
+				Node: map[string]node.Node{
+					"node1": {Instance: make(map[string]instance.Instance)},
+				},
+			},
+		},
+		unreachable: make(map[string]bool),
+	}
+	for _, s := range unreachable {
+		t.unreachable[s] = true
+	}
+	return t
+}
+
+func (t *fakeBackend) Status() (cluster.Status, error) {
+	return t.clusterStatus, nil
+}
+
+func (t *fakeBackend) Submit(action Action, p path.T, destination []string) error {
+	t.submitted = append(t.submitted, p.String())
+	if !t.unreachable[p.String()] {
+		t.clusterStatus.Cluster.Object[p.String()] = object.Status{Avail: status.Up}
+	}
+	return nil
+}
+
+func (t *fakeBackend) Abort(p path.T) error {
+	t.aborted = append(t.aborted, p.String())
+	return nil
+}
+
+func mustParse(t *testing.T, s string) path.T {
+	p, err := path.Parse(s)
+	require.NoError(t, err)
+	return p
+}
+
+func wavesStrings(waves [][]path.T) [][]string {
+	l := make([][]string, len(waves))
+	for i, wave := range waves {
+		for _, p := range wave {
+			l[i] = append(l[i], p.String())
+		}
+	}
+	return l
+}
+
+func TestNewWaves(t *testing.T) {
+	db := mustParse(t, "db")
+	app := mustParse(t, "app")
+	web := mustParse(t, "web")
+	batch := mustParse(t, "batch")
+	objects := []Object{
+		{Path: db, Priority: 50, Children: []path.T{app}},
+		{Path: app, Priority: 50, Parents: []path.T{db, mustParse(t, "notselected")}},
+		{Path: web, Priority: 50, Parents: []path.T{app}},
+		{Path: batch, Priority: 10},
+	}
+
+	t.Run("start", func(t *testing.T) {
+		waves, err := newWaves(ActionStart, objects)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"batch"}, {"db"}, {"app"}, {"web"}}, wavesStrings(waves))
+	})
+
+	t.Run("stop", func(t *testing.T) {
+		waves, err := newWaves(ActionStop, objects)
+		require.NoError(t, err)
+		assert.Equal(t, [][]string{{"web"}, {"batch"}, {"app"}, {"db"}}, wavesStrings(waves))
+	})
+
+	t.Run("cycle", func(t *testing.T) {
+		_, err := newWaves(ActionStart, []Object{
+			{Path: db, Parents: []path.T{app}},
+			{Path: app, Parents: []path.T{db}},
+			{Path: batch},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "app, db")
+	})
+}
+
+func TestEvaluate(t *testing.T) {
+	p := mustParse(t, "app")
+	plan := T{Action: ActionStart}
+	item := Item{Path: p, Status: StatusRunning}
+	digest := object.Digest{
+		Path: p,
+		Instances: map[string]instance.States{
+			"node1": {Monitor: instance.Monitor{GlobalExpect: instance.MonitorGlobalExpectStarted}},
+		},
+	}
+	item = plan.Evaluate(item, digest)
+	assert.Equal(t, StatusRunning, item.Status)
+	assert.True(t, item.Orchestrated)
+
+	digest.Instances["node1"] = instance.States{Monitor: instance.Monitor{
+		GlobalExpect: instance.MonitorGlobalExpectEmpty,
+		State:        instance.MonitorStateStartFailed,
+	}}
+	item = plan.Evaluate(item, digest)
+	assert.Equal(t, StatusFailed, item.Status)
+	assert.Contains(t, item.Error, "node1: start failed")
+
+	item = Item{Path: p, Status: StatusRunning}
+	digest.Object.Avail = status.Up
+	item = plan.Evaluate(item, digest)
+	assert.Equal(t, StatusDone, item.Status)
+
+	t.Run("failed state without orchestration seen", func(t *testing.T) {
+		item := Item{Path: p, Status: StatusRunning, Updated: time.Now()}
+		digest := object.Digest{
+			Path: p,
+			Instances: map[string]instance.States{
+				"node1": {Monitor: instance.Monitor{State: instance.MonitorStateStartFailed}},
+			},
+		}
+		item = plan.Evaluate(item, digest)
+		assert.Equal(t, StatusFailed, item.Status)
+		assert.Contains(t, item.Error, "node1: start failed")
+	})
+
+	t.Run("no orchestration seen", func(t *testing.T) {
+		digest := object.Digest{
+			Path: p,
+			Instances: map[string]instance.States{
+				"node1": {Monitor: instance.Monitor{State: instance.MonitorStateIdle}},
+			},
+		}
+		item := Item{Path: p, Status: StatusRunning, Updated: time.Now()}
+		item = plan.Evaluate(item, digest)
+		assert.Equal(t, StatusRunning, item.Status, "wait the daemons within the grace period")
+
+		item.Updated = time.Now().Add(-orchestrationGracePeriod)
+		item = plan.Evaluate(item, digest)
+		assert.Equal(t, StatusFailed, item.Status)
+		assert.Contains(t, item.Error, "no orchestration seen")
+	})
+}
+
+func TestRun(t *testing.T) {
+	rawconfig.Paths.Var = t.TempDir()
+	plan, err := New(ActionStart, "*", []Object{
+		{Path: mustParse(t, "db"), Children: []path.T{mustParse(t, "app")}},
+		{Path: mustParse(t, "app")},
+	})
+	require.NoError(t, err)
+	backend := newFakeBackend()
+	err = plan.Run(context.Background(), backend, RunOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"db", "app"}, backend.submitted)
+	assert.Equal(t, StatusDone, plan.Status)
+
+	loaded, err := Load(plan.ID)
+	require.NoError(t, err)
+	assert.Equal(t, StatusDone, loaded.Status)
+	assert.Equal(t, StatusDone, loaded.Waves[1].Items[0].Status)
+
+	_, err = Load("unknown")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestRunAbortAndResume(t *testing.T) {
+	rawconfig.Paths.Var = t.TempDir()
+	plan, err := New(ActionStart, "*", []Object{
+		{Path: mustParse(t, "db"), Priority: 10},
+		{Path: mustParse(t, "app"), Priority: 20},
+	})
+	require.NoError(t, err)
+
+	backend := newFakeBackend("app")
+	opts := RunOptions{
+		Interval: time.Millisecond,
+		OnUpdate: func(t *T) {
+			if t.Status == StatusRunning && t.Waves[1].Items[0].Status == StatusRunning {
+				_ = RequestAbort(t.ID, backend)
+			}
+		},
+	}
+	err = plan.Run(context.Background(), backend, opts)
+	assert.True(t, errors.Is(err, ErrAborted), "unexpected error: %v", err)
+	assert.Equal(t, StatusAborted, plan.Status)
+	assert.Equal(t, []string{"app"}, backend.aborted)
+	assert.Equal(t, StatusDone, plan.Waves[0].Items[0].Status)
+	assert.Equal(t, StatusAborted, plan.Waves[1].Items[0].Status)
+
+	resumed, err := Load(plan.ID)
+	require.NoError(t, err)
+	backend = newFakeBackend()
+	err = resumed.Run(context.Background(), backend, RunOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app"}, backend.submitted, "resume must skip the done objects")
+	assert.Equal(t, StatusDone, resumed.Status)
+}
+
+func TestRunWaveTimeout(t *testing.T) {
+	rawconfig.Paths.Var = t.TempDir()
+	plan, err := New(ActionStart, "*", []Object{{Path: mustParse(t, "app")}})
+	require.NoError(t, err)
+	err = plan.Run(context.Background(), newFakeBackend("app"), RunOptions{
+		Interval:    time.Millisecond,
+		WaveTimeout: 20 * time.Millisecond,
+	})
+	require.Error(t, err)
+	assert.Equal(t, StatusFailed, plan.Status)
+	assert.Equal(t, StatusFailed, plan.Waves[0].Items[0].Status)
+	assert.Equal(t, "wave timeout", plan.Waves[0].Items[0].Error)
+}
+
+func TestRunnerTakeOver(t *testing.T) {
+	rawconfig.Paths.Var = t.TempDir()
+	cmd := exec.Command("sh", "-c", "exit 0")
+	require.NoError(t, cmd.Run())
+	deadPID := cmd.Process.Pid
+
+	newRunningPlan := func(t *testing.T, runner Runner) *T {
+		plan, err := New(ActionStart, "*", []Object{{Path: mustParse(t, "app")}})
+		require.NoError(t, err)
+		plan.Status = StatusRunning
+		plan.Runner = &runner
+		plan.Waves[0].Items[0].Status = StatusRunning
+		require.NoError(t, plan.Save())
+		return plan
+	}
+
+	t.Run("live runner", func(t *testing.T) {
+		plan := newRunningPlan(t, Runner{Host: hostname.Hostname(), PID: os.Getpid(), Heartbeat: time.Now()})
+		assert.True(t, plan.IsRunnerAlive())
+		err := plan.Run(context.Background(), newFakeBackend(), RunOptions{Interval: time.Millisecond})
+		assert.ErrorIs(t, err, ErrRunning)
+
+		backend := newFakeBackend()
+		require.NoError(t, RequestAbort(plan.ID, backend))
+		assert.True(t, plan.isAbortRequested(), "a live runner must be requested to abort")
+		assert.Empty(t, backend.aborted)
+	})
+
+	t.Run("stale heartbeat", func(t *testing.T) {
+		plan := newRunningPlan(t, Runner{Host: "node2", PID: 1, Heartbeat: time.Now().Add(-time.Hour)})
+		assert.False(t, plan.IsRunnerAlive())
+	})
+
+	t.Run("resume takes over", func(t *testing.T) {
+		plan := newRunningPlan(t, Runner{Host: hostname.Hostname(), PID: deadPID, Heartbeat: time.Now()})
+		assert.False(t, plan.IsRunnerAlive())
+		loaded, err := Load(plan.ID)
+		require.NoError(t, err)
+		backend := newFakeBackend()
+		err = loaded.Run(context.Background(), backend, RunOptions{Interval: time.Millisecond})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"app"}, backend.submitted)
+		assert.Equal(t, StatusDone, loaded.Status)
+		assert.Equal(t, os.Getpid(), loaded.Runner.PID)
+	})
+
+	t.Run("abort takes over", func(t *testing.T) {
+		plan := newRunningPlan(t, Runner{Host: hostname.Hostname(), PID: deadPID, Heartbeat: time.Now()})
+		backend := newFakeBackend()
+		require.NoError(t, RequestAbort(plan.ID, backend))
+		assert.Equal(t, []string{"app"}, backend.aborted)
+		assert.False(t, plan.isAbortRequested())
+		loaded, err := Load(plan.ID)
+		require.NoError(t, err)
+		assert.Equal(t, StatusAborted, loaded.Status)
+		assert.Equal(t, StatusAborted, loaded.Waves[0].Items[0].Status)
+	})
+}
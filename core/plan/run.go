@@ -0,0 +1,257 @@
+package plan
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/hostname"
+)
+
+type (
+	// Backend is the interface to the daemons a plan run submits the
+	// orchestrations to.
+	Backend interface {
+		// Status returns the cluster status used to follow the
+		// orchestrations.
+		Status() (cluster.Status, error)
+
+		// Submit asks the daemons to orchestrate the action on the
+		// object.
+		Submit(action Action, p path.T, destination []string) error
+
+		// Abort asks the daemons to abort the orchestration of the
+		// object.
+		Abort(p path.T) error
+	}
+
+	// RunOptions are the options of a plan run.
+	RunOptions struct {
+		// Interval is the delay between two evaluations of the
+		// orchestrations progress.
+		Interval time.Duration
+
+		// WaveTimeout is the maximum duration of a wave. Zero means no
+		// timeout.
+		WaveTimeout time.Duration
+
+		// OnUpdate, if set, is called after each save of the plan
+		// progress.
+		OnUpdate func(*T)
+	}
+)
+
+const (
+	defaultInterval = time.Second
+
+	// heartbeatInterval is the delay between two refreshes of the
+	// runner heartbeat.
+	heartbeatInterval = 10 * time.Second
+
+	// runnerTimeout is the heartbeat age after which the runner of a
+	// running plan is considered dead.
+	runnerTimeout = 3 * heartbeatInterval
+
+	lockTimeout = 5 * time.Second
+)
+
+var (
+	// ErrAborted is returned by Run when the plan was aborted.
+	ErrAborted = fmt.Errorf("plan aborted")
+
+	// ErrRunning is returned by Run when the plan is run by another
+	// live runner.
+	ErrRunning = fmt.Errorf("plan running")
+)
+
+// Run orchestrates the plan waves in order, and returns when all objects
+// reached the action target state, on the first wave with a failed object,
+// or on abort. The objects already done by a previous run are skipped, so
+// Run also resumes an interrupted, failed or aborted plan, or a running plan
+// whose runner is dead.
+func (t *T) Run(ctx context.Context, backend Backend, opts RunOptions) error {
+	if t.Status == StatusDone {
+		return nil
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = defaultInterval
+	}
+	if err := t.claim(opts); err != nil {
+		return err
+	}
+	for i := range t.Waves {
+		if err := t.runWave(ctx, backend, opts, i); err != nil {
+			return err
+		}
+	}
+	t.Status = StatusDone
+	return t.save(opts)
+}
+
+// claim records the current process as the plan runner, and resets the
+// items not done. It fails if the saved plan is run by another live runner.
+func (t *T) claim(opts RunOptions) error {
+	return withLock(t.ID, "plan run", func() error {
+		saved, err := Load(t.ID)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return err
+		case saved.IsRunnerAlive():
+			return fmt.Errorf("%w: %s on %s pid %d", ErrRunning, t.ID, saved.Runner.Host, saved.Runner.PID)
+		}
+		t.clearAbortRequest()
+		t.Reset()
+		t.Status = StatusRunning
+		t.Runner = &Runner{
+			Host: hostname.Hostname(),
+			PID:  os.Getpid(),
+		}
+		return t.save(opts)
+	})
+}
+
+// save saves the plan progress and refreshes the runner heartbeat.
+func (t *T) save(opts RunOptions) error {
+	if t.Runner != nil {
+		t.Runner.Heartbeat = time.Now()
+	}
+	if err := t.Save(); err != nil {
+		return err
+	}
+	if opts.OnUpdate != nil {
+		opts.OnUpdate(t)
+	}
+	return nil
+}
+
+func (t *T) runWave(ctx context.Context, backend Backend, opts RunOptions, i int) error {
+	wave := &t.Waves[i]
+	for j, item := range wave.Items {
+		if item.Status != StatusPending {
+			continue
+		}
+		item.Updated = time.Now()
+		if err := backend.Submit(t.Action, item.Path, t.Destination); err != nil {
+			item.Status = StatusFailed
+			item.Error = err.Error()
+		} else {
+			item.Status = StatusRunning
+		}
+		wave.Items[j] = item
+	}
+	if err := t.save(opts); err != nil {
+		return err
+	}
+
+	var timeout <-chan time.Time
+	if opts.WaveTimeout > 0 {
+		timer := time.NewTimer(opts.WaveTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+	ticker := time.NewTicker(opts.Interval)
+	defer ticker.Stop()
+
+	for {
+		if !wave.hasStatus(StatusRunning) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return t.fail(opts, fmt.Sprintf("wave %d interrupted: %s", i+1, ctx.Err()))
+		case <-timeout:
+			wave.setRunning(StatusFailed, "wave timeout")
+			return t.fail(opts, fmt.Sprintf("wave %d timeout", i+1))
+		case <-ticker.C:
+			if t.isAbortRequested() {
+				return t.abort(backend, opts)
+			}
+			clusterStatus, err := backend.Status()
+			if err != nil {
+				// transient api errors are retried on next tick
+				continue
+			}
+			changed := false
+			for j, item := range wave.Items {
+				updated := t.Evaluate(item, clusterStatus.GetObjectStatus(item.Path))
+				if updated.Status != item.Status || updated.Orchestrated != item.Orchestrated {
+					wave.Items[j] = updated
+					changed = true
+				}
+			}
+			if changed || time.Since(t.Runner.Heartbeat) >= heartbeatInterval {
+				if err := t.save(opts); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	if wave.hasStatus(StatusFailed) {
+		return t.fail(opts, fmt.Sprintf("wave %d has failed objects", i+1))
+	}
+	return nil
+}
+
+func (t *T) fail(opts RunOptions, reason string) error {
+	t.Status = StatusFailed
+	t.Error = reason
+	if err := t.save(opts); err != nil {
+		return err
+	}
+	return fmt.Errorf("plan %s failed: %s", t.ID, reason)
+}
+
+func (t *T) abort(backend Backend, opts RunOptions) error {
+	t.clearAbortRequest()
+	t.abortRunning(backend)
+	t.Status = StatusAborted
+	if err := t.save(opts); err != nil {
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrAborted, t.ID)
+}
+
+// abortRunning asks the daemons to abort the orchestrations of the running
+// items, and marks these items aborted.
+func (t *T) abortRunning(backend Backend) {
+	for i := range t.Waves {
+		wave := &t.Waves[i]
+		for j, item := range wave.Items {
+			if item.Status != StatusRunning {
+				continue
+			}
+			if err := backend.Abort(item.Path); err != nil {
+				item.Error = err.Error()
+			}
+			item.Status = StatusAborted
+			item.Updated = time.Now()
+			wave.Items[j] = item
+		}
+	}
+}
+
+func (t Wave) hasStatus(s Status) bool {
+	for _, item := range t.Items {
+		if item.Status == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *Wave) setRunning(s Status, reason string) {
+	for j, item := range t.Items {
+		if item.Status != StatusRunning {
+			continue
+		}
+		item.Status = s
+		item.Error = reason
+		item.Updated = time.Now()
+		t.Items[j] = item
+	}
+}
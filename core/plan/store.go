@@ -0,0 +1,158 @@
+package plan
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"syscall"
+	"time"
+
+	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/lock"
+)
+
+// ErrNotFound is returned when loading an unknown plan id.
+var ErrNotFound = errors.New("plan not found")
+
+func dir() string {
+	return filepath.Join(rawconfig.Paths.Var, "plan")
+}
+
+func file(id string) string {
+	return filepath.Join(dir(), id+".json")
+}
+
+func abortFile(id string) string {
+	return filepath.Join(dir(), id+".abort")
+}
+
+func lockFile(id string) string {
+	return filepath.Join(dir(), id+".lock")
+}
+
+// withLock calls f with the plan lock held, so the commands claiming or
+// aborting the plan don't race.
+func withLock(id string, intent string, f func() error) error {
+	if err := os.MkdirAll(dir(), 0700); err != nil {
+		return err
+	}
+	return lock.Func(lockFile(id), lockTimeout, intent, f)
+}
+
+// Save writes the plan progress to its file in the node var directory.
+func (t *T) Save() error {
+	if err := os.MkdirAll(dir(), 0700); err != nil {
+		return err
+	}
+	t.Updated = time.Now()
+	b, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := file(t.ID)
+	tmp := filepath.Join(dir(), "."+filepath.Base(p)+".swp")
+	if err := os.WriteFile(tmp, b, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, p)
+}
+
+// Load returns the plan identified by id.
+func Load(id string) (*T, error) {
+	if id == "" || strings.ContainsAny(id, "/\\") {
+		return nil, fmt.Errorf("%w: invalid id %s", ErrNotFound, id)
+	}
+	b, err := os.ReadFile(file(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, id)
+	} else if err != nil {
+		return nil, err
+	}
+	t := &T{}
+	if err := json.Unmarshal(b, t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// List returns the saved plans, the most recent first.
+func List() ([]*T, error) {
+	l := make([]*T, 0)
+	matches, err := filepath.Glob(filepath.Join(dir(), "*.json"))
+	if err != nil {
+		return l, err
+	}
+	for _, m := range matches {
+		t, err := Load(strings.TrimSuffix(filepath.Base(m), ".json"))
+		if err != nil {
+			continue
+		}
+		l = append(l, t)
+	}
+	sort.Slice(l, func(i, j int) bool {
+		return l[i].Created.After(l[j].Created)
+	})
+	return l, nil
+}
+
+// RequestAbort asks the command running the plan to abort the
+// orchestrations in progress and stop. A running plan whose runner is dead
+// has its orchestrations in progress aborted through backend. A plan not
+// running is marked aborted immediately.
+func RequestAbort(id string, backend Backend) error {
+	return withLock(id, "plan abort", func() error {
+		t, err := Load(id)
+		if err != nil {
+			return err
+		}
+		switch {
+		case t.Status == StatusRunning && t.IsRunnerAlive():
+			return os.WriteFile(abortFile(id), []byte{}, 0600)
+		case t.Status == StatusRunning:
+			t.abortRunning(backend)
+			t.Status = StatusAborted
+			return t.Save()
+		case t.Status == StatusDone:
+			return fmt.Errorf("plan %s is already done", id)
+		default:
+			t.Status = StatusAborted
+			return t.Save()
+		}
+	})
+}
+
+// IsRunnerAlive returns true if the runner of the running plan is alive.
+// The runner is dead if its heartbeat is older than runnerTimeout, or if
+// its process is gone from the local node.
+func (t T) IsRunnerAlive() bool {
+	if t.Status != StatusRunning || t.Runner == nil {
+		return false
+	}
+	if time.Since(t.Runner.Heartbeat) > runnerTimeout {
+		return false
+	}
+	if t.Runner.Host != hostname.Hostname() {
+		return true
+	}
+	p, err := os.FindProcess(t.Runner.PID)
+	if err != nil {
+		return false
+	}
+	return !errors.Is(p.Signal(syscall.Signal(0)), os.ErrProcessDone)
+}
+
+// isAbortRequested returns true if an abort was requested for the plan.
+func (t T) isAbortRequested() bool {
+	_, err := os.Stat(abortFile(t.ID))
+	return err == nil
+}
+
+// clearAbortRequest removes the abort request of the plan, if any.
+func (t T) clearAbortRequest() {
+	_ = os.Remove(abortFile(t.ID))
+}
@@ -50,6 +50,9 @@ type (
 		Secret     string `mapstructure:"secret"`
 		CASecPaths string `mapstructure:"ca"`
 		Nodes      string `mapstructure:"nodes"`
+
+		HbFormat      string `mapstructure:"hb_format"`
+		HbCompression string `mapstructure:"hb_compression"`
 	}
 
 	nodeSection struct {
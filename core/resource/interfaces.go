@@ -2,6 +2,7 @@ package resource
 
 import (
 	"context"
+	"time"
 
 	"opensvc.com/opensvc/core/actionresdeps"
 	"opensvc.com/opensvc/core/schedule"
@@ -59,6 +60,13 @@ type (
 		StatusInfo() map[string]interface{}
 	}
 
+	// AlertSnoozer is implemented by the resources whose status alert can
+	// be silenced by the operator, like the tasks. A zero duration ends
+	// the snooze.
+	AlertSnoozer interface {
+		SnoozeAlert(time.Duration) error
+	}
+
 	// NetNSPather exposes a NetNSPath method a resource can call to
 	// get the string identifying the network namespace for libs like
 	// netlink.
@@ -77,7 +85,25 @@ type (
 		PID() int
 	}
 
+	// Snapshoter exposes the Snapshot and DeleteSnapshot methods sync
+	// drivers can call to get a consistent point-in-time copy of the
+	// resource data as a source for their transfers.
+	// Snapshot() returns the path of the created snapshot.
+	Snapshoter interface {
+		Snapshot(ctx context.Context, name string, readonly bool) (string, error)
+		DeleteSnapshot(ctx context.Context, name string) error
+	}
+
 	resyncer interface {
 		Resync(context.Context) error
 	}
+
+	//
+	// Reloader implements the Reload func, which the core calls on the
+	// "reload" action, when sec or cfg keys consumed by the resource
+	// changed.
+	//
+	Reloader interface {
+		Reload(context.Context) error
+	}
 )
@@ -821,6 +821,24 @@ func Resync(ctx context.Context, r Driver) error {
 	return nil
 }
 
+// Reload applies the reload policy of a resource interfacer
+func Reload(ctx context.Context, r Driver) error {
+	var i any = r
+	s, ok := i.(Reloader)
+	if !ok {
+		return nil
+	}
+	defer Status(ctx, r)
+	if r.IsDisabled() {
+		return nil
+	}
+	Setenv(r)
+	if err := s.Reload(ctx); err != nil {
+		return errors.Wrapf(err, "reload")
+	}
+	return nil
+}
+
 // Shutdown deactivates a resource interfacer even if standby is true
 func Shutdown(ctx context.Context, r Driver) error {
 	defer Status(ctx, r)
@@ -1005,10 +1023,12 @@ func (t *T) SetLoggerForTest(l zerolog.Logger) {
 	t.log = l
 }
 
+// DoWithLock calls f with the <intent> lock of the resource held. The lock is
+// not acquired if <disable> is set (--nolock), but f is still called.
 func (t *T) DoWithLock(disable bool, timeout time.Duration, intent string, f func() error) error {
 	if disable {
 		// --nolock
-		return nil
+		return f()
 	}
 	p := filepath.Join(t.VarDir(), intent)
 	lock := flock.New(p, xsession.ID, fcntllock.New)
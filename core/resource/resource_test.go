@@ -0,0 +1,18 @@
+package resource
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDoWithLockDisabled(t *testing.T) {
+	var r T
+	called := false
+	err := r.DoWithLock(true, 0, "run", func() error {
+		called = true
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.True(t, called, "f must be called with the lock disabled")
+}
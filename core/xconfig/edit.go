@@ -80,8 +80,17 @@ func Edit(src string, mode EditMode, ref Referrer) error {
 		fmt.Println("unchanged")
 	} else if err := ValidateFile(dst, ref); err != nil {
 		return ErrEditValidate
-	} else if err := file.Copy(dst, src); err != nil {
-		return err
+	} else {
+		cf := ref.Config()
+		if cf != nil {
+			cf.recordPriorRevision()
+		}
+		if err := file.Copy(dst, src); err != nil {
+			return err
+		}
+		if cf != nil {
+			cf.recordRevision()
+		}
 	}
 	if err := os.Remove(dst); err != nil {
 		return err
@@ -1113,9 +1113,11 @@ func (t *T) rawCommit(configData rawconfig.T, configPath string, validate bool)
 		}
 	}
 	if !t.Referrer.IsVolatile() {
+		t.recordPriorRevision()
 		if err := t.write(); err != nil {
 			return err
 		}
+		t.recordRevision()
 	}
 	//t.clearRefCache()
 	if t.postCommit != nil {
@@ -0,0 +1,293 @@
+package xconfig
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+	"github.com/opensvc/fcntllock"
+	"github.com/opensvc/flock"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/xsession"
+)
+
+type (
+	// Revision describes a committed object configuration.
+	Revision struct {
+		ID      int       `json:"id"`
+		Time    time.Time `json:"time"`
+		Author  string    `json:"author"`
+		Command string    `json:"command"`
+	}
+
+	// Revisions is a list of object configuration revisions, sorted by id.
+	Revisions []Revision
+
+	// RevisionDiff is the unified diff between two configuration revisions.
+	RevisionDiff string
+)
+
+const (
+	revisionLockTimeout = 5 * time.Second
+)
+
+var (
+	// MaxRevisions is the number of configuration revisions kept per object.
+	MaxRevisions = 20
+
+	ErrRevisionNotFound = errors.New("configuration revision not found")
+
+	// revisionMu serializes the revision history updates of this process,
+	// as the fcntl lock does not exclude the goroutines of its holder.
+	revisionMu sync.Mutex
+)
+
+// RevisionDir returns the directory hosting the configuration revisions
+// of the object p.
+func RevisionDir(p path.T) string {
+	return filepath.Join(p.VarDir(), "config_revisions")
+}
+
+func revisionFile(p path.T, id int) string {
+	return filepath.Join(RevisionDir(p), fmt.Sprintf("%d.conf", id))
+}
+
+func revisionMetaFile(p path.T, id int) string {
+	return filepath.Join(RevisionDir(p), fmt.Sprintf("%d.json", id))
+}
+
+// revisionAuthor returns the <user>@<node> string identifying the author of
+// a local commit.
+func revisionAuthor() string {
+	username := os.Getenv("USER")
+	if u, err := user.Current(); err == nil {
+		username = u.Username
+	}
+	return username + "@" + hostname.Hostname()
+}
+
+// revisionLockPath returns the path of the object lock serializing the
+// updates of the revision history. It is not the action lock, which the
+// committing process may already hold.
+func revisionLockPath(p path.T) string {
+	return filepath.Join(p.VarDir(), "lock", "revisions")
+}
+
+func withRevisionLock(p path.T, fn func() error) error {
+	revisionMu.Lock()
+	defer revisionMu.Unlock()
+	lockPath := revisionLockPath(p)
+	if err := os.MkdirAll(filepath.Dir(lockPath), 0700); err != nil {
+		return err
+	}
+	lock := flock.New(lockPath, xsession.ID, fcntllock.New)
+	if err := lock.Lock(revisionLockTimeout, "record config revision"); err != nil {
+		return err
+	}
+	defer func() { _ = lock.UnLock() }()
+	return fn()
+}
+
+// hasRevisionHistory returns true if the configuration file revisions
+// are recorded for this config.
+func (t *T) hasRevisionHistory() bool {
+	return !t.Path.IsZero() && t.ConfigFilePath == t.Path.ConfigFile()
+}
+
+// recordPriorRevision adds the installed configuration file content to the
+// object revision history, if the history is empty, so the first recorded
+// commit can be diffed against and rolled back. It must be called before
+// the installed file is replaced. Errors are logged but not returned.
+func (t *T) recordPriorRevision() {
+	if !t.hasRevisionHistory() {
+		return
+	}
+	if err := RecordPriorRevision(t.Path, t.ConfigFilePath); err != nil {
+		log.Warn().Err(err).Msgf("%s: record prior config revision", t.Path)
+	}
+}
+
+// recordRevision adds the installed configuration file content to the
+// object revision history. Errors are logged but not returned, so the
+// history never blocks a commit.
+func (t *T) recordRevision() {
+	if !t.hasRevisionHistory() {
+		return
+	}
+	b, err := os.ReadFile(t.ConfigFilePath)
+	if err != nil {
+		log.Warn().Err(err).Msgf("%s: record config revision", t.Path)
+		return
+	}
+	command := strings.Join(os.Args, " ")
+	if _, err := RecordRevision(t.Path, b, revisionAuthor(), command); err != nil {
+		log.Warn().Err(err).Msgf("%s: record config revision", t.Path)
+	}
+}
+
+// RecordPriorRevision adds the content of the configuration file
+// filename, about to be replaced, as the first revision of the object p,
+// if the history is empty. The revision time is the file modification
+// time, and its author is unknown.
+func RecordPriorRevision(p path.T, filename string) error {
+	return withRevisionLock(p, func() error {
+		revs, err := ListRevisions(p)
+		if err != nil {
+			return err
+		}
+		if len(revs) > 0 {
+			return nil
+		}
+		info, err := os.Stat(filename)
+		if os.IsNotExist(err) {
+			return nil
+		} else if err != nil {
+			return err
+		}
+		b, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+		_, err = addRevision(p, revs, b, Revision{
+			Time:    info.ModTime(),
+			Author:  "unknown",
+			Command: "prior content",
+		})
+		return err
+	})
+}
+
+// RecordRevision adds b to the configuration revision history of the object
+// p, unless b is the content of the last revision, and drops the revisions
+// exceeding MaxRevisions. The history update is serialized by an object
+// lock.
+func RecordRevision(p path.T, b []byte, author, command string) (rev Revision, err error) {
+	err = withRevisionLock(p, func() error {
+		revs, err := ListRevisions(p)
+		if err != nil {
+			return err
+		}
+		rev, err = addRevision(p, revs, b, Revision{
+			Time:    time.Now(),
+			Author:  author,
+			Command: command,
+		})
+		return err
+	})
+	return
+}
+
+// addRevision adds b with the rev metadata to the revs history of the
+// object p. The caller must hold the revision lock.
+func addRevision(p path.T, revs Revisions, b []byte, rev Revision) (Revision, error) {
+	rev.ID = 1
+	if n := len(revs); n > 0 {
+		last := revs[n-1]
+		if lastB, err := os.ReadFile(revisionFile(p, last.ID)); err == nil && bytes.Equal(lastB, b) {
+			return last, nil
+		}
+		rev.ID = last.ID + 1
+	}
+	if err := os.MkdirAll(RevisionDir(p), 0700); err != nil {
+		return rev, err
+	}
+	if err := os.WriteFile(revisionFile(p, rev.ID), b, 0600); err != nil {
+		return rev, err
+	}
+	meta, err := json.Marshal(rev)
+	if err != nil {
+		return rev, err
+	}
+	if err := os.WriteFile(revisionMetaFile(p, rev.ID), meta, 0600); err != nil {
+		return rev, err
+	}
+	revs = append(revs, rev)
+	for len(revs) > MaxRevisions {
+		_ = os.Remove(revisionFile(p, revs[0].ID))
+		_ = os.Remove(revisionMetaFile(p, revs[0].ID))
+		revs = revs[1:]
+	}
+	return rev, nil
+}
+
+// ListRevisions returns the configuration revisions of the object p.
+func ListRevisions(p path.T) (Revisions, error) {
+	revs := make(Revisions, 0)
+	matches, err := filepath.Glob(filepath.Join(RevisionDir(p), "*.json"))
+	if err != nil {
+		return revs, err
+	}
+	for _, match := range matches {
+		if _, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(match), ".json")); err != nil {
+			continue
+		}
+		b, err := os.ReadFile(match)
+		if err != nil {
+			return revs, err
+		}
+		var rev Revision
+		if err := json.Unmarshal(b, &rev); err != nil {
+			log.Warn().Err(err).Msgf("%s: invalid config revision %s", p, match)
+			continue
+		}
+		revs = append(revs, rev)
+	}
+	sort.Slice(revs, func(i, j int) bool { return revs[i].ID < revs[j].ID })
+	return revs, nil
+}
+
+// ReadRevision returns the configuration file content of the revision id
+// of the object p.
+func ReadRevision(p path.T, id int) ([]byte, error) {
+	b, err := os.ReadFile(revisionFile(p, id))
+	if os.IsNotExist(err) {
+		return nil, errors.Wrapf(ErrRevisionNotFound, "%s revision %d", p, id)
+	}
+	return b, err
+}
+
+// DiffRevisions returns the unified diff between the configuration
+// revisions a and b of the object p.
+func DiffRevisions(p path.T, a, b int) (string, error) {
+	ab, err := ReadRevision(p, a)
+	if err != nil {
+		return "", err
+	}
+	bb, err := ReadRevision(p, b)
+	if err != nil {
+		return "", err
+	}
+	aName := fmt.Sprintf("%s@%d", p, a)
+	bName := fmt.Sprintf("%s@%d", p, b)
+	edits := myers.ComputeEdits(span.URIFromPath(aName), string(ab), string(bb))
+	return fmt.Sprint(gotextdiff.ToUnified(aName, bName, string(ab), edits)), nil
+}
+
+// Render returns the diff, as is.
+func (t RevisionDiff) Render() string {
+	return string(t)
+}
+
+// Render returns the human representation of the revision list.
+func (t Revisions) Render() string {
+	s := ""
+	for _, rev := range t {
+		s += fmt.Sprintf("%-4d %s  %-24s %s\n", rev.ID, rev.Time.Format(time.RFC3339), rev.Author, rev.Command)
+	}
+	return s
+}
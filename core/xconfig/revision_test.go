@@ -0,0 +1,118 @@
+package xconfig
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/testhelper"
+)
+
+func TestRevisions(t *testing.T) {
+	testhelper.Setup(t)
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	saved := MaxRevisions
+	MaxRevisions = 3
+	defer func() { MaxRevisions = saved }()
+
+	rev, err := RecordRevision(p, []byte("[DEFAULT]\nnodes = a\n"), "root@n1", "set")
+	require.NoError(t, err)
+	assert.Equal(t, 1, rev.ID)
+
+	t.Run("same content is not recorded twice", func(t *testing.T) {
+		rev, err := RecordRevision(p, []byte("[DEFAULT]\nnodes = a\n"), "root@n1", "set")
+		require.NoError(t, err)
+		assert.Equal(t, 1, rev.ID)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		rev, err := RecordRevision(p, []byte("[DEFAULT]\nnodes = b\n"), "n2", "install fetched config from n2")
+		require.NoError(t, err)
+		assert.Equal(t, 2, rev.ID)
+		s, err := DiffRevisions(p, 1, 2)
+		require.NoError(t, err)
+		assert.Contains(t, s, "-nodes = a")
+		assert.Contains(t, s, "+nodes = b")
+	})
+
+	t.Run("history is bounded", func(t *testing.T) {
+		for _, s := range []string{"c", "d"} {
+			_, err := RecordRevision(p, []byte("[DEFAULT]\nnodes = "+s+"\n"), "root@n1", "set")
+			require.NoError(t, err)
+		}
+		revs, err := ListRevisions(p)
+		require.NoError(t, err)
+		require.Len(t, revs, 3)
+		assert.Equal(t, 2, revs[0].ID)
+		assert.Equal(t, 4, revs[2].ID)
+		_, err = ReadRevision(p, 1)
+		assert.ErrorIs(t, err, ErrRevisionNotFound)
+	})
+}
+
+func TestRecordPriorRevision(t *testing.T) {
+	testhelper.Setup(t)
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+	cf := p.ConfigFile()
+
+	t.Run("no installed file", func(t *testing.T) {
+		require.NoError(t, RecordPriorRevision(p, cf))
+		revs, err := ListRevisions(p)
+		require.NoError(t, err)
+		assert.Empty(t, revs)
+	})
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(cf), 0700))
+	require.NoError(t, os.WriteFile(cf, []byte("[DEFAULT]\nnodes = a\n"), 0600))
+
+	t.Run("empty history records the prior content", func(t *testing.T) {
+		require.NoError(t, RecordPriorRevision(p, cf))
+		revs, err := ListRevisions(p)
+		require.NoError(t, err)
+		require.Len(t, revs, 1)
+		assert.Equal(t, "unknown", revs[0].Author)
+		b, err := ReadRevision(p, 1)
+		require.NoError(t, err)
+		assert.Equal(t, "[DEFAULT]\nnodes = a\n", string(b))
+	})
+
+	t.Run("non empty history is left as is", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(cf, []byte("[DEFAULT]\nnodes = b\n"), 0600))
+		require.NoError(t, RecordPriorRevision(p, cf))
+		revs, err := ListRevisions(p)
+		require.NoError(t, err)
+		require.Len(t, revs, 1)
+	})
+}
+
+func TestRecordRevisionConcurrent(t *testing.T) {
+	testhelper.Setup(t)
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			_, err := RecordRevision(p, []byte(fmt.Sprintf("[DEFAULT]\nnodes = n%d\n", i)), "root@n1", "set")
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+	revs, err := ListRevisions(p)
+	require.NoError(t, err)
+	require.Len(t, revs, 5, "concurrent records must not share a revision id")
+	for i, rev := range revs {
+		assert.Equal(t, i+1, rev.ID)
+	}
+}
@@ -0,0 +1,119 @@
+package xconfig
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/resourceid"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	// SemanticCheck is a configuration check looking at the relations
+	// between keywords and sections, where the keyword-level validation
+	// only looks at one keyword at a time.
+	SemanticCheck struct {
+		// Name identifies the check in the alerts. ex: fs-mnt-unique
+		Name string
+
+		// Level is the level of the alerts raised by the check.
+		Level ValidateAlertLevel
+
+		// Text describes what the check verifies.
+		Text string
+
+		// Func returns the findings of the check in the configuration.
+		Func SemanticCheckFunc
+	}
+
+	// SemanticCheckFunc is the function of a SemanticCheck.
+	SemanticCheckFunc func(t *T) []SemanticFinding
+
+	// SemanticFinding is a configuration problem found by a SemanticCheck.
+	SemanticFinding struct {
+		Key     key.T
+		Driver  driver.ID
+		Comment string
+	}
+
+	semanticChecks struct {
+		sync.RWMutex
+		m map[string]SemanticCheck
+	}
+)
+
+var (
+	semanticCheckRegistry = semanticChecks{m: make(map[string]SemanticCheck)}
+)
+
+// RegisterSemanticCheck adds a check to the registry used by
+// ValidateSemantic. The core and the drivers register their checks from
+// their package init. A check registered with the name of an already
+// registered check replaces it.
+func RegisterSemanticCheck(c SemanticCheck) {
+	semanticCheckRegistry.Lock()
+	defer semanticCheckRegistry.Unlock()
+	semanticCheckRegistry.m[c.Name] = c
+}
+
+// SemanticChecks returns the registered checks, sorted by name.
+func SemanticChecks() []SemanticCheck {
+	semanticCheckRegistry.RLock()
+	defer semanticCheckRegistry.RUnlock()
+	l := make([]SemanticCheck, 0, len(semanticCheckRegistry.m))
+	for _, c := range semanticCheckRegistry.m {
+		l = append(l, c)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Name < l[j].Name })
+	return l
+}
+
+// NewValidateAlertSemantic returns the alert of a finding of the semantic
+// check c.
+func (t T) NewValidateAlertSemantic(c SemanticCheck, f SemanticFinding) ValidateAlert {
+	return ValidateAlert{
+		Path:    t.Path,
+		Kind:    validateAlertKindSemantic,
+		Level:   c.Level,
+		Key:     f.Key,
+		Driver:  f.Driver,
+		Comment: f.Comment,
+		Check:   c.Name,
+	}
+}
+
+// ValidateSemantic runs the registered semantic checks on the
+// configuration, and returns the alerts of their findings. Like Validate,
+// it returns an error if an alert has the error level.
+func (t *T) ValidateSemantic() (ValidateAlerts, error) {
+	alerts := make(ValidateAlerts, 0)
+	for _, c := range SemanticChecks() {
+		for _, f := range c.Func(t) {
+			alerts = append(alerts, t.NewValidateAlertSemantic(c, f))
+		}
+	}
+	if alerts.HasError() {
+		return alerts, errors.New("")
+	}
+	return alerts, nil
+}
+
+// ResourceSections returns the configuration sections with a resource id
+// name of the driver group g, or of all driver groups if g is invalid.
+func (t T) ResourceSections(g driver.Group) []string {
+	l := make([]string, 0)
+	for _, section := range t.SectionStrings() {
+		rid, err := resourceid.Parse(section)
+		if err != nil {
+			continue
+		}
+		if g.IsValid() && rid.DriverGroup() != g {
+			continue
+		}
+		l = append(l, section)
+	}
+	return l
+}
@@ -25,14 +25,17 @@ type (
 		Key     key.T              `json:"key"`
 		Driver  driver.ID          `json:"driver"`
 		Comment string             `json:"comment"`
+
+		// Check is the name of the semantic check raising the alert.
+		Check string `json:"check,omitempty"`
 	}
 	ValidateAlertKind  int
 	ValidateAlertLevel int
 )
 
 const (
-	validateAlertLevelWarn ValidateAlertLevel = iota
-	validateAlertLevelError
+	ValidateAlertLevelWarn ValidateAlertLevel = iota
+	ValidateAlertLevelError
 
 	validateAlertKindScoping ValidateAlertKind = iota
 	validateAlertKindUnknown
@@ -41,18 +44,19 @@ const (
 	validateAlertKindCandidates
 	validateAlertKindDeprecated
 	validateAlertKindCapabilities
+	validateAlertKindSemantic
 )
 
 var (
 	validateAlertLevelWarnStr  = "warning"
 	validateAlertLevelErrorStr = "error"
 	validateAlertLevelNames    = map[ValidateAlertLevel]string{
-		validateAlertLevelWarn:  validateAlertLevelWarnStr,
-		validateAlertLevelError: validateAlertLevelErrorStr,
+		ValidateAlertLevelWarn:  validateAlertLevelWarnStr,
+		ValidateAlertLevelError: validateAlertLevelErrorStr,
 	}
 	validateAlertLevelFromNames = map[string]ValidateAlertLevel{
-		validateAlertLevelWarnStr:  validateAlertLevelWarn,
-		validateAlertLevelErrorStr: validateAlertLevelError,
+		validateAlertLevelWarnStr:  ValidateAlertLevelWarn,
+		validateAlertLevelErrorStr: ValidateAlertLevelError,
 	}
 	validateAlertKindUnknownDriverStr = "driver does not exist"
 	validateAlertKindScopingStr       = "keyword does not support scoping"
@@ -61,6 +65,7 @@ var (
 	validateAlertKindCandidatesStr    = "keyword value is not in allowed candidates"
 	validateAlertKindDeprecatedStr    = "keyword is deprecated"
 	validateAlertKindCapabilitiesStr  = "driver is not in node capabilities"
+	validateAlertKindSemanticStr      = "semantic check"
 	validateAlertKindNames            = map[ValidateAlertKind]string{
 		validateAlertKindScoping:       validateAlertKindScopingStr,
 		validateAlertKindUnknown:       validateAlertKindUnknownStr,
@@ -69,6 +74,7 @@ var (
 		validateAlertKindCandidates:    validateAlertKindCandidatesStr,
 		validateAlertKindDeprecated:    validateAlertKindDeprecatedStr,
 		validateAlertKindCapabilities:  validateAlertKindCapabilitiesStr,
+		validateAlertKindSemantic:      validateAlertKindSemanticStr,
 	}
 	validateAlertKindFromNames = map[string]ValidateAlertKind{
 		validateAlertKindScopingStr:       validateAlertKindScoping,
@@ -78,6 +84,7 @@ var (
 		validateAlertKindCandidatesStr:    validateAlertKindCandidates,
 		validateAlertKindDeprecatedStr:    validateAlertKindDeprecated,
 		validateAlertKindCapabilitiesStr:  validateAlertKindCapabilities,
+		validateAlertKindSemanticStr:      validateAlertKindSemantic,
 	}
 )
 
@@ -85,7 +92,7 @@ func (t T) NewValidateAlertScoping(k key.T, did driver.ID) ValidateAlert {
 	return ValidateAlert{
 		Path:   t.Path,
 		Kind:   validateAlertKindScoping,
-		Level:  validateAlertLevelError,
+		Level:  ValidateAlertLevelError,
 		Key:    k,
 		Driver: did,
 	}
@@ -95,7 +102,7 @@ func (t T) NewValidateAlertUnknownDriver(k key.T, did driver.ID) ValidateAlert {
 	return ValidateAlert{
 		Path:   t.Path,
 		Kind:   validateAlertKindUnknownDriver,
-		Level:  validateAlertLevelWarn,
+		Level:  ValidateAlertLevelWarn,
 		Key:    k,
 		Driver: did,
 	}
@@ -105,7 +112,7 @@ func (t T) NewValidateAlertUnknown(k key.T, did driver.ID) ValidateAlert {
 	return ValidateAlert{
 		Path:   t.Path,
 		Kind:   validateAlertKindUnknown,
-		Level:  validateAlertLevelWarn,
+		Level:  ValidateAlertLevelWarn,
 		Key:    k,
 		Driver: did,
 	}
@@ -115,7 +122,7 @@ func (t T) NewValidateAlertCandidates(k key.T, did driver.ID) ValidateAlert {
 	return ValidateAlert{
 		Path:   t.Path,
 		Kind:   validateAlertKindCandidates,
-		Level:  validateAlertLevelError,
+		Level:  ValidateAlertLevelError,
 		Key:    k,
 		Driver: did,
 	}
@@ -125,7 +132,7 @@ func (t T) NewValidateAlertEval(k key.T, did driver.ID, comment string) Validate
 	return ValidateAlert{
 		Path:    t.Path,
 		Kind:    validateAlertKindEval,
-		Level:   validateAlertLevelError,
+		Level:   ValidateAlertLevelError,
 		Key:     k,
 		Driver:  did,
 		Comment: comment,
@@ -140,7 +147,7 @@ func (t T) NewValidateAlertDeprecated(k key.T, did driver.ID, release, replacedB
 	return ValidateAlert{
 		Path:    t.Path,
 		Kind:    validateAlertKindDeprecated,
-		Level:   validateAlertLevelWarn,
+		Level:   ValidateAlertLevelWarn,
 		Key:     k,
 		Driver:  did,
 		Comment: comment,
@@ -151,7 +158,7 @@ func (t T) NewValidateAlertCapabilities(k key.T, did driver.ID) ValidateAlert {
 	return ValidateAlert{
 		Path:   t.Path,
 		Kind:   validateAlertKindCapabilities,
-		Level:  validateAlertLevelWarn,
+		Level:  ValidateAlertLevelWarn,
 		Key:    k,
 		Driver: did,
 	}
@@ -212,11 +219,11 @@ func (t *ValidateAlertKind) UnmarshalJSON(b []byte) error {
 }
 
 func (t ValidateAlerts) HasError() bool {
-	return t.has(validateAlertLevelError)
+	return t.has(ValidateAlertLevelError)
 }
 
 func (t ValidateAlerts) HasWarn() bool {
-	return t.has(validateAlertLevelWarn)
+	return t.has(ValidateAlertLevelWarn)
 }
 
 func (t ValidateAlerts) has(lvl ValidateAlertLevel) bool {
@@ -247,13 +254,17 @@ func (t ValidateAlerts) Tree() *tree.Tree {
 	for _, alert := range t {
 		n := tr.AddNode()
 		color := rawconfig.Color.Warning
-		if alert.Level == validateAlertLevelError {
+		if alert.Level == ValidateAlertLevelError {
 			color = rawconfig.Color.Error
 		}
 		driver := alert.Driver.String()
 		if driver == "" {
 			driver = "-"
 		}
+		kind := alert.Kind.String()
+		if alert.Check != "" {
+			kind += ": " + alert.Check
+		}
 		comment := alert.Comment
 		if comment == "" {
 			comment = "-"
@@ -261,7 +272,7 @@ func (t ValidateAlerts) Tree() *tree.Tree {
 		n.AddColumn().AddText(alert.Level.String()).SetColor(color)
 		n.AddColumn().AddText(alert.Key.String())
 		n.AddColumn().AddText(driver)
-		n.AddColumn().AddText(alert.Kind.String())
+		n.AddColumn().AddText(kind)
 		n.AddColumn().AddText(comment)
 	}
 	return tr
@@ -46,6 +46,9 @@ type ServerInterface interface {
 	// (POST /node/clear)
 	PostNodeClear(w http.ResponseWriter, r *http.Request)
 
+	// (POST /node/maintenance)
+	PostNodeMaintenance(w http.ResponseWriter, r *http.Request)
+
 	// (POST /node/monitor)
 	PostNodeMonitor(w http.ResponseWriter, r *http.Request)
 
@@ -67,12 +70,18 @@ type ServerInterface interface {
 	// (POST /object/monitor)
 	PostObjectMonitor(w http.ResponseWriter, r *http.Request)
 
+	// (GET /object/monitor/history)
+	GetObjectMonitorHistory(w http.ResponseWriter, r *http.Request, params GetObjectMonitorHistoryParams)
+
 	// (POST /object/progress)
 	PostObjectProgress(w http.ResponseWriter, r *http.Request)
 
 	// (GET /object/selector)
 	GetObjectSelector(w http.ResponseWriter, r *http.Request, params GetObjectSelectorParams)
 
+	// (POST /object/simulate)
+	PostObjectSimulate(w http.ResponseWriter, r *http.Request)
+
 	// (POST /object/status)
 	PostObjectStatus(w http.ResponseWriter, r *http.Request)
 
@@ -82,6 +91,9 @@ type ServerInterface interface {
 	// (GET /public/openapi)
 	GetSwagger(w http.ResponseWriter, r *http.Request)
 
+	// (GET /public/schema/{kind})
+	GetPublicSchema(w http.ResponseWriter, r *http.Request, kind GetPublicSchemaParamsKind)
+
 	// (GET /relay/message)
 	GetRelayMessage(w http.ResponseWriter, r *http.Request, params GetRelayMessageParams)
 
@@ -353,6 +365,25 @@ func (siw *ServerInterfaceWrapper) PostNodeClear(w http.ResponseWriter, r *http.
 	handler(w, r.WithContext(ctx))
 }
 
+// PostNodeMaintenance operation middleware
+func (siw *ServerInterfaceWrapper) PostNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BasicAuthScopes, []string{""})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostNodeMaintenance(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // PostNodeMonitor operation middleware
 func (siw *ServerInterfaceWrapper) PostNodeMonitor(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -546,6 +577,55 @@ func (siw *ServerInterfaceWrapper) PostObjectMonitor(w http.ResponseWriter, r *h
 	handler(w, r.WithContext(ctx))
 }
 
+// GetObjectMonitorHistory operation middleware
+func (siw *ServerInterfaceWrapper) GetObjectMonitorHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	ctx = context.WithValue(ctx, BasicAuthScopes, []string{""})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetObjectMonitorHistoryParams
+
+	// ------------- Required query parameter "path" -------------
+	if paramValue := r.URL.Query().Get("path"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "path"})
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "path", r.URL.Query(), &params.Path)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "path", Err: err})
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+	if paramValue := r.URL.Query().Get("since"); paramValue != "" {
+
+	}
+
+	err = runtime.BindQueryParameter("form", true, false, "since", r.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "since", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetObjectMonitorHistory(w, r, params)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // PostObjectProgress operation middleware
 func (siw *ServerInterfaceWrapper) PostObjectProgress(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -603,6 +683,25 @@ func (siw *ServerInterfaceWrapper) GetObjectSelector(w http.ResponseWriter, r *h
 	handler(w, r.WithContext(ctx))
 }
 
+// PostObjectSimulate operation middleware
+func (siw *ServerInterfaceWrapper) PostObjectSimulate(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	ctx = context.WithValue(ctx, BasicAuthScopes, []string{""})
+
+	ctx = context.WithValue(ctx, BearerAuthScopes, []string{""})
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.PostObjectSimulate(w, r)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // PostObjectStatus operation middleware
 func (siw *ServerInterfaceWrapper) PostObjectStatus(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -656,6 +755,32 @@ func (siw *ServerInterfaceWrapper) GetSwagger(w http.ResponseWriter, r *http.Req
 	handler(w, r.WithContext(ctx))
 }
 
+// GetPublicSchema operation middleware
+func (siw *ServerInterfaceWrapper) GetPublicSchema(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	var err error
+
+	// ------------- Path parameter "kind" -------------
+	var kind GetPublicSchemaParamsKind
+
+	err = runtime.BindStyledParameter("simple", false, "kind", chi.URLParam(r, "kind"), &kind)
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "kind", Err: err})
+		return
+	}
+
+	var handler = func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetPublicSchema(w, r, kind)
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler(w, r.WithContext(ctx))
+}
+
 // GetRelayMessage operation middleware
 func (siw *ServerInterfaceWrapper) GetRelayMessage(w http.ResponseWriter, r *http.Request) {
 	ctx := r.Context()
@@ -858,6 +983,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/node/clear", wrapper.PostNodeClear)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/node/maintenance", wrapper.PostNodeMaintenance)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/node/monitor", wrapper.PostNodeMonitor)
 	})
@@ -879,12 +1007,18 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/object/monitor", wrapper.PostObjectMonitor)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/object/monitor/history", wrapper.GetObjectMonitorHistory)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/object/progress", wrapper.PostObjectProgress)
 	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/object/selector", wrapper.GetObjectSelector)
 	})
+	r.Group(func(r chi.Router) {
+		r.Post(options.BaseURL+"/object/simulate", wrapper.PostObjectSimulate)
+	})
 	r.Group(func(r chi.Router) {
 		r.Post(options.BaseURL+"/object/status", wrapper.PostObjectStatus)
 	})
@@ -894,6 +1028,9 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/public/openapi", wrapper.GetSwagger)
 	})
+	r.Group(func(r chi.Router) {
+		r.Get(options.BaseURL+"/public/schema/{kind}", wrapper.GetPublicSchema)
+	})
 	r.Group(func(r chi.Router) {
 		r.Get(options.BaseURL+"/relay/message", wrapper.GetRelayMessage)
 	})
@@ -907,71 +1044,88 @@ func HandlerWithOptions(si ServerInterface, options ChiServerOptions) http.Handl
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+w8/W/cNrL/CqE+4LUPyq6djwLPQIFL0/QuhzQJ6hzuB8cwuNKslg1FKiS19l7h//3A",
-	"L4mSyF3ZyRqHXH5pY5EcDud7hsP9Myt43XAGTMns7M+swQLXoECYvz61IHa/tAIrwpn+UIIsBGnsn1mN",
-	"b1DpR/OM6G9mSZZnDNeQnWXBsCw2UGMNBW5w3VA9/ExmeaZ2jf63VIKwKru9zS2Ql1tg6ldCFYjp1pRI",
-	"hfgagZ6E1nZWHIVusEeAKKjlFKidieCmESAl4ewMXXwkrLy8yCleAf1pi2kLl//3QR+nP8Tb1R9QqHOF",
-	"VSv/0ZRYQZk3WG1+WnM+PV73AQuBd/1xX5OaqNhBa6KQQRgVvGUqcUozL07l0zxbc1FjlZ1lhKkfn/ZI",
-	"EaagAtFj8QbXIBtcwFuDAKZTjJifksAkHO+xSTDZ0u4dVpvpRtyMIU3KxFZuSMCnlggoszMlWpi96zlQ",
-	"KBQXyZ2lnxDfPRi+Mwa/A8WKbEGm6Sz8lMT24fhkvxXnFDAbbrh7QVupQLwqp7upDaDCDiNSos4qaCXT",
-	"Y5JypQc4M3/qzXcJxByYK1JmMymxe8NLsKtjeDE3+llYeSCzcOIUZNro4IYgwWlKAdxQxNz8j4B1dpZ9",
-	"t+yN7tJOk0uzKmkdvKymxWW+sKaPf+sHDba4aSKTcs9f4y4Eb0AoYqlVcLYm1aGDuuUv7OTb3HBm5iIt",
-	"J3qJVdCZi6y262XS2OiZy6xBNyzo1fvCH9Kh3aHSAb/sWMi7fYdH7mk6mfHGkSI1/rY7d2rGeXfEyYwS",
-	"Q23d+JBtFRe8VYRBuKzzDHkm29UhkukpY0IFYC2MGGVACB6VpDJiC17qyaiwdA9d2pPHEZeWZzVIiask",
-	"ID8ci0CGHDcb+umXt1rDpMKsgJ7aQ/yd6uwjmZ5ym2d4iwk9SF4ninlWbAgtBbBDK7RntD6GM7OOM6kE",
-	"Ji7MG3uJPCtkW0e1vRRNfAWwbXTBmsLNVY1v4sJkRwnbM6qwqEAlJgj+L3v6jv864HqkSA2xWEuHb4do",
-	"ZeZooxLY1nnc4KLYgKarOmjAwql65RYEpnfYqsHCx+h34XtDcQE1sIO2sp+oVwmQILbg4oQ1bqnKztaY",
-	"SshHquSnIiKRjn0Q0Z6ZSGRRRxssEeMKrQAYam10jMoWkOIIow9sA1ioFWCFSn7NNBtRoYkDJVrtEEa1",
-	"lllgWtlQA4LwcvGBXW/AOvzpKAJWytwGBxYDueEtLdEKUMuKDWYVlDn6wDArUYf8NaFUz5CgNGLmpAsT",
-	"5k/lvhGEC6J2Bynq55k1fEt0RgHl4WX9VGOIJG9FYc3KvEDCrXh503AJ5XknQsPIIs9Ey5hWkxDwgWRF",
-	"xweYQsJPULyFO0uo5dJVJXgbDzdku5KgZCxAdqRBgfHN+7MMTbLObCkFGhPpKZMFKePxYegYOpB2fsy/",
-	"jcmneMMprw4KTzfvNs+c1sw1eiMkrYPpLKczib0FGgpnv1vsNN6aTnikY6FXbM2nZDeJcyyWNt+N1dgA",
-	"8pG1hoPs0CJk5T5S6TWv9ZIYvVkyseiSCoeC+bdLKwwa1xsQYLGzuBqLgdVGIixMLkJYhdaC14uY5zEz",
-	"p9taALFjK46k4gJXgAz6SGJm95tNComZSaVjaUQoE44peZgUWXxjXO8JPOFugrQBWc1WhrhRKpmKyhSC",
-	"+TwEYT4tDoq7O42FmzqN9LI6W8DMgoh8Wbh9YD8kT4kVjobitVHd+Qo9AWD/9Suxtji+awd7tVPR4Oiu",
-	"WIR0Npt4EJdJDH1lZ7I3n5RgZvEigBrjxjAeG5t5YDrGvcg2OMvdJ6mwUAH+Q/3t/FS6ZhgUqRAXCDPk",
-	"cwP77Xv9379oEfrhcC1wFK91+GeMM82S+NZ+CWo4JYXO+f1BKcclwlufrUrERWlKoQ6eLLgw/28EYFOt",
-	"2ZB1ghxcql9MAvmaV/IFZ0rwiEGgsB252Ixo1elxKmHVVqZIYT5fY2GqqSYPzLM1Vtj4JMxI4RG9PCSM",
-	"dteYFPZon7er54Vn5ihb6757JK1YaPHgTZQcOiiZCoPNsIMylTbwofHui8ab1XenC3Ezqz48cOeFL6dr",
-	"DFJHfsNL+I0zomLJdUX5CtMruGmGtYQeA8qL/RN00AbxACmKj61bPF9xoWKRWdRGTIIttUme18J/QQGL",
-	"I8I/JkWblKHcQ+o7ov9O8EqAjMTGRF41WChic99IypNEzt6TXJHys3EfAPNL9x8oVX3ZS8vD9b9RcSeF",
-	"bbrcF2B4TVSxeR+Ji0uQirCpd5k6acJe2cHTiLuYL9r5YMsU2qYa/1tfNxuV5frK/p7C8JWPCqdnkVUy",
-	"fUgsGtfiwruFwX4WegAresQgd+881LOTsWfVklC2VMf3foWp+zPkfG7n4zlDGBElkasGT7PiUeo/LtqL",
-	"LSkgDwAK5PNaFCxFVh86N+rCl5rcmKSNLXGWK9FCzFeJvTzFZSn2cvMBmf3Zua4+S34XIdmf74aUe61d",
-	"+fwaTEDyWOklGI8YsDoYmbuNwW9MkA5Q/HSxQtHUUhGJVzSSpG0IU9LduzmJJRXjAiTClFqJRUpgJole",
-	"gWzoIqNFNWAFbqZbEFaSAivQ22A12kuiDWYltZVCPWSAyJaaGiOuNKl8pc8iViIHZLNrtOZJLpCJHROl",
-	"PuLSxCFSH2H3yCaoDSZCWjUttbHQai+MldX/tgKsT644KjjVqQ76oKkBj65JCQiveKtstdSfKkSk5xT1",
-	"2XckrhjW7xLR+FQ5e3MwJ7QObj9mFLnqPk4aXeYCpVZiXKRM1ogoX6FVglQVCISRA+AkBnXl3g8s5D7j",
-	"CrVNgnU8eVEaUNuXiHFVCaiM2BCmOHpra2PGKgMute1/vsWE9mbaLlx8YOYqSSLCkN+xh15y9r8K6RwC",
-	"4ZQ6JIvMswvGfrt3fklf8dWyiEXiKsWVOOeAflW66ImVq126EOsZiek13klTcW9y06KD8FoZzhpi3I0U",
-	"84K2/qbE1osTnQRBkc/OG6qfFissJam0y1Xx7h1cybuVzO3fBxXN6LhlS3dot3if9X4Vd88pqZj6mrsU",
-	"gYKQfvYdxuic6cBegGw4k+Dy9QS+QffBjEv84b33vgVuViLgzDow+zA3LWs+UJjoyHCKEbWuAizl4E57",
-	"RRg23Rsxvho4r9iap0jk3VbkimbcHZAQRlec2ZPjeDx+a29+5rFKkK9CJlzTqDQ6CAXKhhOmDmPpapDd",
-	"gjm+CZgSuxT8CIHCDrno3h24WeR6x6V63qrNe/4RIpUo5T9PjYoeuYKbhgi4wuqeAbKFP4W2D+X3cBOn",
-	"lelYCopmuKyJBr6iuPioBdt/qFowZbDurlebOc4NyT61WGn9ihbZ3HVGRMCJItiFGDMuRF51840B9+0F",
-	"M1a+t5On+uEBdvBiJJxsHwlw3ZC/7NhwqZDU0aG//kFevhf2rm729QtG11zQ0oSaLSOfWhjCQ6QEpsia",
-	"gFgMGlrJJ7Z4fHLy9NHpyaLg9aJdtUy1ZyenZ/DjqnyKn6yePXuarlxOHO+u6e5yur31x9GuspBk3u3H",
-	"kDnTDc13v+XoUu0/grT//+j01JCWN8DktlhIsT0rYfuYnS4cvgt7isXp3QmNvySpYQu+YnLYWg4K5FO9",
-	"7QzA/G4G2a7+1q+KVcenKLer5xRileZ01jM86F6E/LxErp0FoC7j2P2MZawQo3G+E2HsKSNOzvYotmJ+",
-	"ISXPCgF3qbzk2efVfN1pB7j2SBjo+4rAoVi8g7DtcUhUPe56XKaRBsXyvo7Uw3VADqHoGEzfrrOzi4N8",
-	"NfJxm89XjIACt5ejLpP+Im6NCeVbG8vGLhK7Vf1lXbBkTeEmfhMnoWi1uJ9rzBzZsSSFjnP0HwZjQ3r9",
-	"tSftRinTArkCLED42favXz1L/v7P975l2YAwo2MYt0GtRhFlbJyzrLYOhHCj7d0WhLRHfrL4cfHk1BYK",
-	"gOlR/e1kcZIFnRtL3KrNsovJGm7FRcuXqevo1CsbRnT54AVNgtX9lGXQbq4ZPn1fY3bvXtnkqMY3pG5r",
-	"2w6BHj/d3O/hzelJHZHxyz7qMwR4fHLi+rqVu5LGTUNJYSAv/5A2q+rhH6glRCJgw7pRTbwtCpByIFqG",
-	"lIFQXVxqaoWCc3GpsbfJ+UWmGZddaggur1uah0L2si4WMuiMTNPZlqXsZFOPGDL7r+Buk19acPdid/eg",
-	"KqXi4wX2adDc2eG7qRkcVXCjLHUeSSUA13dnaZ/2HomdPvUOGUp5JZdF0IeQ1M5p24K15SDVz7zcfTEJ",
-	"j7dIxEgCyksa5RXyJdbhI6LbB9BFk9o9IM+CjlOnhQn1+t1NfAAa+ALGA5KhD5v3U+HcFwHvYWSmD/nm",
-	"2o/p07S5KyePlB7EoQxo9aBc5M0cw3Ou532Fyizb1bLvnDpIha796tjGt98pQgx3o8Q7Vy/bFQqeQH/V",
-	"VpjxEpZF1yTFY3Vq00MlEdhrre/XXCAXNeZIZwNQ/oAI67uk/VWdSdUWk4DpnWtGs61ZcWImTp33qcsX",
-	"IrhtMoxQumW2MwtK5Ofcn+SmEyQgeHAbmlaSsGHveAoS7hIhQz1A4GGVILjP2KcKX4lQyKVPV1MRwJuu",
-	"Tf2IxO974Y9kfoJj2yLDEnc9oEldCJtFj6cL4S6R05sB1DeUa69RtEIAU3SHXCBr27/s02rjVtauQcw8",
-	"2ZilRF+ZnLvy14DlE4+TYnnvJI7JcrtLhBD7XJ/poxg7wIHvk6ZD8JswHBKG7qlMyvK9DZ/U3Cv3eRs+",
-	"EhlTFbaYtraXMlY3C4b3/ajG5B6vbkBIzkxjywaQA2OaW7rm0Nh+wcK9P81wzExq8IjpSJ4gJgtr93xp",
-	"vySYR06fLQfHp5/B8wGpNyuuHD5cOLZp/XKx5X+BJWzC5xgHGNg93Tg2B7uNYv7RPJ435q3rgxy4QKQ4",
-	"ErA2fa56lj+h8Yy2+Tds/ixJaXo4jW+F8puX7GVDBu8i91vH8/53fe5tITsYD2Al+70ezlL29dZDetZV",
-	"XI+rZelEV8/xj0xkiMw3rVBLGb6mOsRJP/fovPQbfXN6+1jYtCtKimV32562a+fXuKpAfG7dY9SPsd/a",
-	"eJQtlg5l87pmGfQMpTAePJq7XwfA4Efw7nJTE/ym35FvW8IXS8euVud79HtE7WNp92CblKXGtgheYoUl",
-	"KPNjIAjbnyFEg27Xz1D/L1L9N0DE1stkK6hrnpFny6V5nLzhUp2dPj59lt1e3v47AAD//+wG4zoXVgAA",
+	"H4sIAAAAAAAC/+w97W7cOJKvQmgPuN07pdvOJAOcgQUuM5u5zWImCdY53I/YMNhSdTfXFKmQVNt9A7/7",
+	"gV8SJZEttZ02Dtn8mUmLZFWxqlgsVhXp37OCVzVnwJTMLn7PaixwBQqE+fWlAbH/SyOwIpzpDyXIQpDa",
+	"/swqfI9K35pnRH8zQ7I8Y7iC7CILmmWxhQprKHCPq5rq5tcyyzO1r/W/pRKEbbKHh9wCebsDpn4hVIEY",
+	"o6ZEKsTXCHQntLa94iS0jR0BREElx0BtTwT3tQApCWcX6PMtYeX155ziFdA/7zBt4PrfrvR0ukl8WP0D",
+	"CnWpsGrkf9clVlDmNVbbP685H0+v/YCFwPtuur+SiqjYRCuikCEYFbxhKjFL0y/O5fM8W3NRYZVdZISp",
+	"H191RBGmYAOio+I9rkDWuIAPhgBMxxQx3yVBSdjeUZMQsuXdR6y2Y0TctCHNygQq1yTgS0MElNmFEg3M",
+	"xnoJFArFRRKz9B3i2IPmoyn4O1CsyA5kms/Cd0mgD9tH+FacU8Csj3D/M22kAvGuHGNTW0CFbUakRK1V",
+	"0ItMt0nKlW7gzPzUyPcJwhyYG1JmMzmxf89LsKNjdDHX+iSqPJBZNHEKMm10cE2Q4DS1AFxTxNz8i4B1",
+	"dpH9YdkZ3aXtJpdmVNI6eF1Nq8t8ZU1P/8E3GmpxXUc65V6+uq0WvAahiOVWwdmabKYm6ob/bDs/5EYy",
+	"MwdpPdFD7AKdOciudj1MGhs9c5g16EYE3fL+7CfpyG5JaYFftyLkLd7+lC9+T/Z471iRav/QzjvV47Kd",
+	"4qhHiaHibCy2DRe8UYRBOKzdGfJMNqsplukuQ0YFYC2MGGdACB7VpDJiC97qzqiwfA+3tB9eRra0PKtA",
+	"SrxJAvLNMQ+kL3GD0He/ftArTCrMCui43affLZ1DLNNdHvIM7zChk+x1qphnxZbQUgCbGqF3RrvHcGbG",
+	"cSaVwMS5ecNdIs8K2VTR1V6KOj4C2C46YE3h/qbC93Flsq2EHWhVWGxAJToI/r929q38tcP1QpEKYr6W",
+	"dt+meGX6aKMS2NZ50uCi2ILmq5o0YGFXPXIHAtMjUNVYeB/9GLnXFBdQAZu0lV1HPUqABLED5yescUNV",
+	"drHGVEI+WEq+KyISKdEAInpnJhJZ0tEWS8S4QisAhhrrHaOyAaQ4wuiKbQELtQKsUMnvmBYjKjRzoESr",
+	"PcKo0joLTC82VIMgvFxcsbst2A1/3IqAlTI3jY4CueUNLdEKUMOKLWYbKHN0xTArUUv8HaFU95CgNGFm",
+	"posr1mlUoPe1IFwQtZ/kqO9nxvAdkYQzKKeHdV2NIZK8EQXI+Y6EG/H2vuYSystWhfqeRZ6JhjG9TELA",
+	"E4eVPJMFppDYJyjewdEaaqV0sxG8ibsbsllJUDLmIDvWoMD45t1c+iZZn2wpBRpT6bGQBSnj/mG4MbQg",
+	"bf/Y/jZkn+I1p3wzqTxtv4c8c6tmrtEbEGk3mNZyOpPYWaC+cnbYYrPx1nQkI8ZLeMfWfMx2c3CO+dLm",
+	"u7EaW0Des9ZwkG1ahKI8xCo95lc9JMZvljxY+BZPgvm3O1YYMu62IMBSZ2k1FgOrrURYmLMIYRu0Frxa",
+	"xHYe03OM1gKITVtxJBUXeAPIkI8kZhbfbFZIzMxROnaMCHXCCSUPD0WW3pjUOwaPpJtgbcBWg8owN8ol",
+	"E1EZQzCf+yDMp8WkurvZWLip2Uivq7MVzAyI6JeF2zn2ffaUWOGoK16ZpTt/QY8A2H/9QiiksbawV3sV",
+	"dY6OpSLks0HiQVwnKfyNM6K4+CvRqr0f06oEZpJoqc/f4HqQP7UAJrU+xDVJcQB37N2r+Q7omgBNhVus",
+	"M4L8YQJVFjUyY3Ljv0COKC8wvYH7GgqFuEAbylfthyhKwauklY42KB7/LMhmAyJOvY28cuECL18akAoV",
+	"uJHaKupvHbun9yjVnactw9wsDGkdIWmp+QjiaA58FOo7QsfixrQFSqqG4oSKFI/R6Q7imyKu0sGBcSwS",
+	"CwSZHnhFKFF77wTjtQIrqu2+5moLihSYOhWMKVGBWUm0ZsuE8vp2s3k5j7sWsAahffc1EVKF29akY0kB",
+	"lyAS2LYY2XaL7SjAdVw1Rr6cCSV7b8lT02NE3oo1rYgjESZUIz5P22Z4ObIKd+YYIxqW61XHOIvKjcib",
+	"Lb6x5MfP7UQebE4aCQFYcjbNSbeOOzQDojwXsxbkHG5GPHrZb3zUOpvcNEIkUTr7IYD2ZJExnuUZMB1V",
+	"+ZxtsTFwNkooVACp7zC2B6N0kirIimg9wKzTFPPtj/q//6mF8Kfp5NMgQBBSb9QrjtoPQTWnpNgH86Qc",
+	"lwjvfHhUIi6syB08WXBh/l8LwCY9sCXrBDu4VH8xEctf+Ub+zJkSPOKBUtgNznQZ0b5aR1MJq2aT5f7z",
+	"HRYs84HHPFtjhc0hCDNSeEKvpzYsizWmDx3Zl81q2gR4Iq1aaPXgdZQd+hQ8VgYb0g3yIvpEEVrHLku5",
+	"Xf3hfCHuZyUke3uzX6yGgtSUdeD6ty4YM54ysGge13wehXJ0DEk02vcBvINYszm4R8MzcF8TkUgkmfDS",
+	"CJpEuFG8wmZLpHtEYa3CAPN8p9hO8iCPrDGPBOF7Xl3M/oZ+YLSD8Rfj9jlKj00mvFlxoWLhktm75vVB",
+	"+D9TwOKE8E/J0TrlVR5g9ZHkfxR8I0BGtjcib2osFLEB6UgcMkmcLV64IeWTae8B80MPT8jtrRETMAju",
+	"j5en7oBsByRAbzP+LGFaLDYZdoumfczOc6PDyel0skQFZ5KUJtBM+ZG+qsXQ5SJm4GjDb/OxDLbmIYrh",
+	"NjxgmedWZLeOaEyqEiKEpDhy3hDkCFOq7TDjCklQ84IWgZIk8mYHF9x05naQlkupdDpRG1B4R1Sx/RSJ",
+	"aJYgFWFjN20cXiHsnW08f+KpJESZItvUUfzWZTz7RAc1GQdS+jc+njeei9wkQwqJQYOp9KpCevgs9ABW",
+	"dIpB1qV19V6fDV1UrQllQ/Ui8CNMxQbzh+PWWeYMYUSURO68MrYjg6TNABGIHSkgDwAK5DMSKBhqwzid",
+	"P+pSDhW5N+F2tsRZrkQDMadPHJQpLktxUJrPKOwnZyn0XPJjlORwpiLk3K9EqiOyZ93AaNIsaI8YsCpo",
+	"mYvG0DdkSAsoPrtYim9sqYjEKxrxhbeEKekCd05jyYZxAdLYdaOxQfwO+bBH1N9mBa7HKAgrSYEVaDRY",
+	"DXDptDArqc3x6iYDRJqtpUR4o1nlc7SWsBI5INt9rVee5AKZQ1giSUtcgL9P1C3sX9jUQo2JkHaZltpY",
+	"6GUvjJXV/7YKrGeuOCo41bsjutLcgBd3pASEV7xRNs/tZxUS0kmK+rxJxPnsZ14Tx9rx4uzMwZwzajdg",
+	"Tnqy6pzpgR8AlFqNcUdOskZE+dy6i8/qdL0F4ANZbaL+ioXS115DUydEx5MlbgG327jmZiNgY9SGMMXR",
+	"B5vVNFYZcKlt/xsd0uvMtB24uGKmCEgiwpDH2EEvOftXhaTiNcKp5ZAsD5id6vfoPvohXa5egI0IRBPt",
+	"gswG/a503hMrV/t0Ct0LEtM7vJemVqLOTYjfRY6xZcZxrJjntHU1LjbTn3Cog/Ss7ddffuYYLyXZ6C1X",
+	"xeuu8UYeV+xgf08uNLPGrVjaSbvBh6z3uzIRZ41rxXivOSZ9F5z7ZlefDOaZPv0JkDVnElzgK0FvUDc6",
+	"o/yyX7F4aIDrlXA4sxbMIcrNZQPvKIzWSL+LUbU2dy9lrxpxRRgW0UOWh6NTySkW+W1rvOblsK4zoYwu",
+	"ynngjOPp+K25/4nHQqo+f5zYmgZJ7Z4rUNacMDVNpcsetwPm7E3AlNin4EcYFN5tiOJuwc1i10cu1ZtG",
+	"bT/xW4iEdJX/HEmu3gK7sWHJm/mp4wHVFv4Y2iGSP8F9nFem1jyIPuOyIhr4iuLiViu2/7BpwIRG2iq9",
+	"TA/m+n/yS4NVP9wZCMMVokQUnCiCnYsxo5TlXdvfGHAfO5ox8pPtPF4fHmALL8bCEfqIg+uafJnKlkuF",
+	"pPYOfeEO8vq9yPIBHw4XzmB0xwUtjavZMPKlgT48REpgiqwJiEXvKhL5whYvz85evTg/WxS8WjSrhqnm",
+	"4uz8An5cla/wD6vXr1+lUwCjjXdft1U4LW79cYBVFpLMCwH1hTNGaL57lINyqP8XrP2PF+fnhrW8BiZ3",
+	"xUKK3UUJu5fsfOHoXdhZLM6PZzT+mqyGHfiIybS17GWaxuu2NQDzU62yWf21GxVLM41JblZvKMTSEelT",
+	"T3+iBwny/RJn7SwAdR2n7icsY4EYTfNRjLGzjGxy9nZJI+YHUvKsEHBM5CXPnpYYcLPt0doRYaAfyhSE",
+	"avGOKRA7TH9qiltQsWsgTSr6bZq0/0UcDIkovwOhw3DwpcFUH911x5WBjZq6BoFWvDHFry2fmtSFyDyj",
+	"iSRiFGC3Qs9lNp09znI3tykOfQR7pSfqK7qJP2pFDlgfK8jBUt0cU+pmBmxSGZENMLB3gf0xTvf3l3CQ",
+	"gALIziZKKtNcg3He54iqIlJCOVtRgOJaQonuiNrypqVhJjJPaY8xB/oPShwPUKjlogNQ9pRta7PkLKoG",
+	"CtaS2LKmT0Ygq07OeaBRc9UydoZxs4gfETSy446sR2l1t1yGLPFUORI87KmJOvtOP6yzi8+TdJjt4SE/",
+	"nt5GZg/Xg+sBXUHLGhPKd/YoGyvIaUd1RS/BEJ0ujVe0SCgavdtdasqc8LAkhT7m6B+GYiNA/bUT0FYp",
+	"c3dtBViA8L3tr1+8YP/2P5/8XVMDwrQOYTwEoVpFlDGgzrGyYWB911aXc4OQdso/LH5c/HBu44TAdKv+",
+	"drY4y4KS+yVu1HbZHslqbpWO184C6chL1j/Q5b2nDxKi7rosg3vCWuDjhxEM9vZ5hBxV+J5UTWXr2NHL",
+	"V9vHvZhwflZF9pfr7tBnGPDy7MxdyFUuf4zrmuqAPOFs+Q9Xx9fBnwglRg7ARnT9WcumKEDKnmoZVgZK",
+	"9flacytUnM/XD9c+Nvc504LLrjUEF9ZZmjpjW9AROzHogIzms+lsi5KlCUf2hf1f4Kqy3lpwjxJ3+xLG",
+	"Qz5vgH3TYW7v8MGLGRJVcK8sd15IJQBXx4u0i3qdSJw+8hYKlPKNXBZBPV9ydY7L/6xNB6l+4uX+q2l4",
+	"vNQwxhJQXtMo3yCfYem//vDwDGvRRHaeUWbBVcENRKTVLq+/u47PwAMfv3xGNnSn5sNcuPQ5gEcYmfEL",
+	"LHPtx/hNkbkjR69LPMuG0uPVs0qR13MMz6Xu9w0uZtmsll0F8iQX2jLmUxvfDlOEGS6hzNutXjarru5Z",
+	"fttWmPESlkVbSMtjaSpTZysR2Kz2H9dcIOc15kifBqD8k851t9dbfabeHDYXWR5RAF2wbMAmVkFi1nl3",
+	"dPlKDDeTinG6YbZ6F0rk+zye5ZotIcOrQT17lO1vmQIhkSlH0dffAwZ3wxfokwtouMJQW1+g8C0gfUrr",
+	"FWzqcjULgbAekKSMwsL70y3SIaaIOKoRIc+7IIPU6qFl+c0oaFD6nrTi4a2DEyuHwxJTjB4B35XidEoh",
+	"lz6eknJR37cX4E/I/O6W/Yn2x2Da1nAucXuRJbkWwhsvp1sLIZbI7E0D6u4NaremaIQApugeuZOWLU+2",
+	"dfkuZO72h8XcRfSN6bmdfl/kI5coJfLOizmlyC2WCCMO+Wamzm/oofWcM+cSfFeGCWVoH+FIWb4P4WMd",
+	"jzqcfwifBRhyFXRo19b6xwK7QfOh5zqHUElVg5CcmcJL8+6CAWOKL9vLCzF8wcCDjz6e8qjfex7lRDtB",
+	"TBfW7mGUw5pgnk95sh6cnn+Gzmfk3iy/sn/78tSm9ev5lv8EltBxZLntXt05vBAGr/R8fdPImXZtQDWC",
+	"Dd6GkfpiBxftAyVEojJtQyVhg3elZ5VSnn6JDjj4LR40YppWh7eXJ0xFe9P51LaiRRTzxExRRfyBFXe5",
+	"gSMBa3PjR5knbSww44PZa1DhNZiSlPYOrMJCQfndH+t0I7xUfNj8tK8zPcHwtDCeYbF3uJ5vT5bhtfqJ",
+	"ldZewT/1SmsRRRiRuDluQ529wy9f5+ZtxfTrVM8XNBo/O/TPYsq73Oakevns5omVKxmz0338fW4ZEvPd",
+	"7KqlDB8umJKk73tyWXpE3/33QyKsmxUlxbKtbEtvnJd32LyI+ERTOKh9PLydeZItlX2SLcjl7/qF4Idk",
+	"sZbOgv3t8sN7ZCoOsS8EdkvZ15Nbw+sbNUTz4p39fcUYL2Gh+5pNw93ysx/0sV/mvtQ4LCa+hf0dF6W5",
+	"UgLS3izX30tBdP6twoysQSq5iNeOfTSztFSPvZTkS4wt7SadxwXq7iQS1v39Gne4cQ8zp/+WTPtY2K7Q",
+	"NZGmHqpYbzKjfVmeNVJ072h6VNdPPQ5Z/P/+FOXJs1dnr06/MBlHtg/SEVbDzqTemgcYlsG1ktRK672r",
+	"8rgq0d5fuDmmmif4gz0nrsjp5nj6Upz8wL404PapdqUempSH4bLw+r6oBGVtCbZ/Ywj1LkQ+Ydv6Gvy0",
+	"QMTO62QjqCuwlhfLpXnkbMulujh/ef5al5n/3wC06g7f9G0AAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
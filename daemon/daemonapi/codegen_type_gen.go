@@ -197,12 +197,61 @@ type ObjectFile struct {
 	Mtime time.Time `json:"mtime"`
 }
 
+// ObjectMonitorHistory defines model for objectMonitorHistory.
+type ObjectMonitorHistory struct {
+	Transitions []ObjectMonitorTransition `json:"transitions"`
+}
+
+// ObjectMonitorTransition defines model for objectMonitorTransition.
+type ObjectMonitorTransition struct {
+	At time.Time `json:"at"`
+
+	// the changed instance monitor field, state, local_expect or global_expect
+	Field string `json:"field"`
+	From  string `json:"from"`
+	Node  string `json:"node"`
+	To    string `json:"to"`
+
+	// the event or the request causing the transition
+	Trigger string `json:"trigger"`
+}
+
 // ObjectPath defines model for objectPath.
 type ObjectPath = string
 
 // ObjectSelector defines model for objectSelector.
 type ObjectSelector = []ObjectPath
 
+// ObjectSimulation defines model for objectSimulation.
+type ObjectSimulation struct {
+	Actions []ObjectSimulationAction `json:"actions"`
+
+	// the object availability status after the hypothetical change
+	Avail string `json:"avail"`
+
+	// the candidate nodes, the preferred first
+	Candidates []string `json:"candidates"`
+
+	// the ha leader nodes
+	Leaders []string `json:"leaders"`
+	Path    string   `json:"path"`
+}
+
+// ObjectSimulationAction defines model for objectSimulationAction.
+type ObjectSimulationAction struct {
+	// the action the instance monitor would run, or none
+	Action     string `json:"action"`
+	IsHaLeader bool   `json:"is_ha_leader"`
+	IsLeader   bool   `json:"is_leader"`
+	Node       string `json:"node"`
+	Reason     string `json:"reason"`
+}
+
+// ObjectSimulations defines model for objectSimulations.
+type ObjectSimulations struct {
+	Simulations []ObjectSimulation `json:"simulations"`
+}
+
 // Orchestrate defines model for orchestrate.
 type Orchestrate string
 
@@ -231,6 +280,15 @@ type PostDaemonSubAction struct {
 // PostDaemonSubActionAction defines model for PostDaemonSubAction.Action.
 type PostDaemonSubActionAction string
 
+// PostNodeMaintenance defines model for postNodeMaintenance.
+type PostNodeMaintenance struct {
+	// enter the maintenance if true, leave the maintenance if false
+	Enter bool `json:"enter"`
+
+	// the time the maintenance is automatically left
+	Expire *time.Time `json:"expire,omitempty"`
+}
+
 // PostNodeMonitor defines model for postNodeMonitor.
 type PostNodeMonitor struct {
 	GlobalExpect *string `json:"global_expect,omitempty"`
@@ -264,6 +322,24 @@ type PostObjectProgress struct {
 	State     string `json:"state"`
 }
 
+// PostObjectSimulate defines model for postObjectSimulate.
+type PostObjectSimulate struct {
+	// the flex target replacing the flex objects flex target
+	FlexTarget *int `json:"flex_target,omitempty"`
+
+	// the nodes considered lost
+	NodesDown *[]string `json:"nodes_down,omitempty"`
+
+	// the nodes considered frozen
+	NodesFrozen *[]string `json:"nodes_frozen,omitempty"`
+
+	// the placement policy replacing the objects placement policy
+	Placement *string `json:"placement,omitempty"`
+
+	// the objects to simulate, all if not set
+	Selector *string `json:"selector,omitempty"`
+}
+
 // PostObjectStatus defines model for postObjectStatus.
 type PostObjectStatus struct {
 	Path   string         `json:"path"`
@@ -448,21 +524,48 @@ type SubBase struct {
 	State      string     `json:"state"`
 }
 
+// SubHeartbeatIntervalBucket defines model for subHeartbeatIntervalBucket.
+type SubHeartbeatIntervalBucket struct {
+	// the count of intervals lower or equal to the bucket upper bound
+	Count uint64 `json:"count"`
+
+	// the bucket upper bound
+	Le string `json:"le"`
+}
+
+// SubHeartbeatPeerStats defines model for subHeartbeatPeerStats.
+type SubHeartbeatPeerStats struct {
+	Intervals []SubHeartbeatIntervalBucket `json:"intervals"`
+	LastAt    time.Time                    `json:"last_at"`
+
+	// the generation of the last message received from the peer
+	LastGen uint64 `json:"last_gen"`
+
+	// the count of intervals elapsed without message
+	Missed   uint64 `json:"missed"`
+	Received uint64 `json:"received"`
+
+	// the count of beating state changes
+	Transitions uint64 `json:"transitions"`
+}
+
 // SubHeartbeatPeerStatus defines model for subHeartbeatPeerStatus.
 type SubHeartbeatPeerStatus struct {
-	Beating bool      `json:"beating"`
-	Last    time.Time `json:"last"`
+	Beating bool                  `json:"beating"`
+	Last    time.Time             `json:"last"`
+	Stats   SubHeartbeatPeerStats `json:"stats"`
 }
 
 // SubHeartbeats defines model for subHeartbeats.
 type SubHeartbeats struct {
-	Alerts     []SubAlert `json:"alerts"`
-	Beating    bool       `json:"beating"`
-	Configured time.Time  `json:"configured"`
-	Created    time.Time  `json:"created"`
-	Id         string     `json:"id"`
-	Last       time.Time  `json:"last"`
-	State      string     `json:"state"`
+	Alerts     []SubAlert            `json:"alerts"`
+	Beating    bool                  `json:"beating"`
+	Configured time.Time             `json:"configured"`
+	Created    time.Time             `json:"created"`
+	Id         string                `json:"id"`
+	Last       time.Time             `json:"last"`
+	State      string                `json:"state"`
+	Stats      SubHeartbeatPeerStats `json:"stats"`
 }
 
 // object topology
@@ -540,6 +643,9 @@ type GetDaemonStatusParams struct {
 // PostDaemonSubActionJSONBody defines parameters for PostDaemonSubAction.
 type PostDaemonSubActionJSONBody = PostDaemonSubAction
 
+// PostNodeMaintenanceJSONBody defines parameters for PostNodeMaintenance.
+type PostNodeMaintenanceJSONBody = PostNodeMaintenance
+
 // PostNodeMonitorJSONBody defines parameters for PostNodeMonitor.
 type PostNodeMonitorJSONBody = PostNodeMonitor
 
@@ -570,6 +676,15 @@ type GetObjectFileParams struct {
 // PostObjectMonitorJSONBody defines parameters for PostObjectMonitor.
 type PostObjectMonitorJSONBody = PostObjectMonitor
 
+// GetObjectMonitorHistoryParams defines parameters for GetObjectMonitorHistory.
+type GetObjectMonitorHistoryParams struct {
+	// object path
+	Path QueryObjectPath `form:"path" json:"path"`
+
+	// only return the transitions at or after this date
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+}
+
 // PostObjectProgressJSONBody defines parameters for PostObjectProgress.
 type PostObjectProgressJSONBody = PostObjectProgress
 
@@ -579,12 +694,18 @@ type GetObjectSelectorParams struct {
 	Selector QueryObjectSelector `form:"selector" json:"selector"`
 }
 
+// PostObjectSimulateJSONBody defines parameters for PostObjectSimulate.
+type PostObjectSimulateJSONBody = PostObjectSimulate
+
 // PostObjectStatusJSONBody defines parameters for PostObjectStatus.
 type PostObjectStatusJSONBody = PostObjectStatus
 
 // PostObjectSwitchToJSONBody defines parameters for PostObjectSwitchTo.
 type PostObjectSwitchToJSONBody = PostObjectSwitchTo
 
+// GetPublicSchemaParamsKind defines parameters for GetPublicSchema.
+type GetPublicSchemaParamsKind string
+
 // GetRelayMessageParams defines parameters for GetRelayMessage.
 type GetRelayMessageParams struct {
 	// the nodename component of the slot id on the relay
@@ -603,6 +724,9 @@ type PostDaemonLogsControlJSONRequestBody = PostDaemonLogsControlJSONBody
 // PostDaemonSubActionJSONRequestBody defines body for PostDaemonSubAction for application/json ContentType.
 type PostDaemonSubActionJSONRequestBody = PostDaemonSubActionJSONBody
 
+// PostNodeMaintenanceJSONRequestBody defines body for PostNodeMaintenance for application/json ContentType.
+type PostNodeMaintenanceJSONRequestBody = PostNodeMaintenanceJSONBody
+
 // PostNodeMonitorJSONRequestBody defines body for PostNodeMonitor for application/json ContentType.
 type PostNodeMonitorJSONRequestBody = PostNodeMonitorJSONBody
 
@@ -618,6 +742,9 @@ type PostObjectMonitorJSONRequestBody = PostObjectMonitorJSONBody
 // PostObjectProgressJSONRequestBody defines body for PostObjectProgress for application/json ContentType.
 type PostObjectProgressJSONRequestBody = PostObjectProgressJSONBody
 
+// PostObjectSimulateJSONRequestBody defines body for PostObjectSimulate for application/json ContentType.
+type PostObjectSimulateJSONRequestBody = PostObjectSimulateJSONBody
+
 // PostObjectStatusJSONRequestBody defines body for PostObjectStatus for application/json ContentType.
 type PostObjectStatusJSONRequestBody = PostObjectStatusJSONBody
 
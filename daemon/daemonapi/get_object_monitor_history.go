@@ -0,0 +1,55 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/daemon/handlers/handlerhelper"
+)
+
+// GetObjectMonitorHistory returns the instance monitor transitions of an
+// object on the local node.
+func (a *DaemonApi) GetObjectMonitorHistory(w http.ResponseWriter, r *http.Request, params GetObjectMonitorHistoryParams) {
+	write, log := handlerhelper.GetWriteAndLog(w, r, "objecthandler.GetObjectMonitorHistory")
+	log.Debug().Msg("starting")
+
+	p, err := path.Parse(params.Path)
+	if err != nil {
+		sendError(w, http.StatusBadRequest, "invalid path: "+params.Path)
+		return
+	}
+	history, err := instance.LoadMonitorHistory(p)
+	if err != nil {
+		log.Error().Err(err).Msgf("load monitor history of %s", p)
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	if params.Since != nil {
+		history = history.Since(*params.Since)
+	}
+	resp := ObjectMonitorHistory{
+		Transitions: make([]ObjectMonitorTransition, len(history)),
+	}
+	for i, e := range history {
+		resp.Transitions[i] = ObjectMonitorTransition{
+			At:      e.At,
+			Node:    e.Node,
+			Field:   e.Field,
+			From:    e.From,
+			To:      e.To,
+			Trigger: e.Trigger,
+		}
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		log.Error().Err(err).Msgf("marshal response error %s", p)
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, err := write(b); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
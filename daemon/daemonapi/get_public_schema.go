@@ -0,0 +1,31 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"opensvc.com/opensvc/core/object"
+)
+
+// GetPublicSchema serves the JSON Schema of the object, node or cluster
+// configurations, so editors and CI pipelines can validate config files
+// without running the agent.
+func (a *DaemonApi) GetPublicSchema(w http.ResponseWriter, r *http.Request, kind GetPublicSchemaParamsKind) {
+	log := getLogger(r, "GetPublicSchema")
+	log.Debug().Msgf("starting %s", kind)
+	schema, err := object.KeywordSchema(string(kind))
+	if errors.Is(err, object.ErrSchemaKind) {
+		sendErrorf(w, http.StatusNotFound, "%s", err)
+		return
+	} else if err != nil {
+		log.Error().Err(err).Msgf("keyword schema %s", kind)
+		sendError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/schema+json")
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(schema); err != nil {
+		log.Error().Err(err).Msg("encode keyword schema")
+	}
+}
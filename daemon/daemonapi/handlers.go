@@ -11,6 +11,7 @@ import (
 	"github.com/allenai/go-swaggerui"
 	"github.com/go-chi/chi/v5"
 	"github.com/rs/zerolog"
+	"github.com/shaj13/go-guardian/v2/auth"
 
 	"opensvc.com/opensvc/daemon/daemonlogctx"
 	"opensvc.com/opensvc/util/pubsub"
@@ -50,6 +51,14 @@ func sendErrorf(w http.ResponseWriter, code int, format string, a ...any) {
 	_ = json.NewEncoder(w).Encode(err)
 }
 
+// requestOrigin describes the requester, like "api user root".
+func requestOrigin(r *http.Request) string {
+	if user := auth.User(r); user != nil && user.GetUserName() != "" {
+		return "api user " + user.GetUserName()
+	}
+	return "api"
+}
+
 func getLogger(r *http.Request, name string) zerolog.Logger {
 	return daemonlogctx.Logger(r.Context()).With().Str("func", name).Logger()
 }
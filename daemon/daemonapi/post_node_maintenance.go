@@ -0,0 +1,51 @@
+package daemonapi
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/goccy/go-json"
+
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/daemon/msgbus"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/pubsub"
+)
+
+func (a *DaemonApi) PostNodeMaintenance(w http.ResponseWriter, r *http.Request) {
+	var (
+		payload     PostNodeMaintenance
+		maintenance node.Maintenance
+		status      string
+	)
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	if payload.Enter {
+		now := time.Now()
+		maintenance.Since = now
+		if payload.Expire != nil {
+			if !payload.Expire.After(now) {
+				sendError(w, http.StatusBadRequest, "expire is in the past")
+				return
+			}
+			maintenance.Expire = *payload.Expire
+		}
+		status = "enter maintenance"
+	} else {
+		status = "leave maintenance"
+	}
+	bus := pubsub.BusFromContext(r.Context())
+	bus.Pub(msgbus.SetNodeMonitor{
+		Node: hostname.Hostname(),
+		Value: node.MonitorUpdate{
+			Maintenance: &maintenance,
+		},
+	}, labelApi)
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(ResponseInfoStatus{
+		Info:   0,
+		Status: status,
+	})
+}
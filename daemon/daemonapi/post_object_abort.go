@@ -32,9 +32,10 @@ func (a *DaemonApi) PostObjectAbort(w http.ResponseWriter, r *http.Request) {
 	}
 	bus := pubsub.BusFromContext(r.Context())
 	msg := msgbus.SetInstanceMonitor{
-		Path:  p,
-		Node:  hostname.Hostname(),
-		Value: instMonitor,
+		Path:   p,
+		Node:   hostname.Hostname(),
+		Value:  instMonitor,
+		Origin: requestOrigin(r),
 	}
 	bus.Pub(msg, pubsub.Label{"path", p.String()}, labelApi)
 	w.WriteHeader(http.StatusOK)
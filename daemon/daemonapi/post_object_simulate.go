@@ -0,0 +1,105 @@
+package daemonapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+
+	"opensvc.com/opensvc/core/objectselector"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/daemon/daemondata"
+	"opensvc.com/opensvc/daemon/handlers/handlerhelper"
+	"opensvc.com/opensvc/daemon/monitor/imon"
+)
+
+// PostObjectSimulate returns the orchestration decisions the daemons would
+// take for the selected objects after a hypothetical change of the current
+// cluster data. Nothing is executed.
+func (a *DaemonApi) PostObjectSimulate(w http.ResponseWriter, r *http.Request) {
+	write, log := handlerhelper.GetWriteAndLog(w, r, "objecthandler.PostObjectSimulate")
+	log.Debug().Msg("starting")
+
+	var payload PostObjectSimulate
+	if err := json.NewDecoder(r.Body).Decode(&payload); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+	change := imon.SimulationChange{
+		FlexTarget: payload.FlexTarget,
+	}
+	if payload.NodesDown != nil {
+		change.NodesDown = *payload.NodesDown
+	}
+	if payload.NodesFrozen != nil {
+		change.NodesFrozen = *payload.NodesFrozen
+	}
+	if payload.Placement != nil {
+		change.Placement = *payload.Placement
+	}
+
+	// the status is a copy, so the change does not alter the daemon data
+	clusterStatus := daemondata.FromContext(r.Context()).GetStatus()
+	var paths path.L
+	if payload.Selector != nil && *payload.Selector != "" {
+		l, err := objectselector.NewSelection(
+			*payload.Selector,
+			objectselector.SelectionWithLocal(true),
+		).Expand()
+		if err != nil {
+			sendError(w, http.StatusBadRequest, err.Error())
+			return
+		}
+		for _, p := range l {
+			if _, ok := clusterStatus.Cluster.Object[p.String()]; ok {
+				paths = append(paths, p)
+			}
+		}
+	} else {
+		for ps := range clusterStatus.Cluster.Object {
+			if p, err := path.Parse(ps); err == nil {
+				paths = append(paths, p)
+			}
+		}
+	}
+	sort.Slice(paths, func(i, j int) bool {
+		return paths[i].String() < paths[j].String()
+	})
+	if err := change.Apply(clusterStatus); err != nil {
+		sendError(w, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	resp := ObjectSimulations{
+		Simulations: make([]ObjectSimulation, len(paths)),
+	}
+	for i, p := range paths {
+		simulation := imon.Simulate(clusterStatus, p)
+		actions := make([]ObjectSimulationAction, len(simulation.Actions))
+		for j, action := range simulation.Actions {
+			actions[j] = ObjectSimulationAction{
+				Node:       action.Node,
+				IsLeader:   action.IsLeader,
+				IsHaLeader: action.IsHALeader,
+				Action:     action.Action,
+				Reason:     action.Reason,
+			}
+		}
+		resp.Simulations[i] = ObjectSimulation{
+			Path:       p.String(),
+			Avail:      simulation.Avail.String(),
+			Leaders:    simulation.Leaders,
+			Candidates: simulation.Candidates,
+			Actions:    actions,
+		}
+	}
+	b, err := json.Marshal(resp)
+	if err != nil {
+		log.Error().Err(err).Msg("marshal response error")
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+	if _, err := write(b); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+}
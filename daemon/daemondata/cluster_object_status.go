@@ -17,6 +17,11 @@ type (
 		path path.T
 	}
 
+	opGetObjectStatus struct {
+		status chan<- object.Status
+		path   path.T
+	}
+
 	opSetObjectStatus struct {
 		err   chan<- error
 		path  path.T
@@ -38,6 +43,19 @@ func (t T) DelObjectStatus(p path.T) error {
 	return <-err
 }
 
+// GetObjectStatus
+//
+// cluster.object.*
+func (t T) GetObjectStatus(p path.T) object.Status {
+	status := make(chan object.Status)
+	op := opGetObjectStatus{
+		status: status,
+		path:   p,
+	}
+	t.cmdC <- op
+	return <-status
+}
+
 // SetObjectStatus
 //
 // cluster.object.*
@@ -91,6 +109,15 @@ func (o opDelObjectStatus) call(ctx context.Context, d *data) {
 	}
 }
 
+func (o opGetObjectStatus) call(ctx context.Context, d *data) {
+	d.counterCmd <- idGetObjectStatus
+	s := d.pending.Cluster.Object[o.path.String()]
+	select {
+	case <-ctx.Done():
+	case o.status <- s:
+	}
+}
+
 func (o opSetObjectStatus) call(ctx context.Context, d *data) {
 	d.counterCmd <- idSetObjectStatus
 	s := o.path.String()
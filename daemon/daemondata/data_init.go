@@ -77,7 +77,7 @@ func newNodeData(localNode string) node.Node {
 			Agent:           "3.0-0",
 			API:             8,
 			Arbitrators:     map[string]node.ArbitratorStatus{},
-			Compat:          12,
+			Compat:          13,
 			Env:             "",
 			Frozen:          frozen,
 			Gen:             map[string]uint64{localNode: 1},
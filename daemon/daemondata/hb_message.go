@@ -52,6 +52,7 @@ func (d *data) getHbMessage() (hbtype.Msg, error) {
 	var err error
 	msg := hbtype.Msg{
 		Compat:   d.pending.Cluster.Node[d.localNode].Status.Compat,
+		Encoding: hbtype.EncodingVersion,
 		Kind:     d.hbMessageType,
 		Nodename: d.localNode,
 		Gen:      d.deepCopyLocalGens(),
@@ -16,6 +16,12 @@ type (
 		path path.T
 	}
 
+	opGetInstanceMonitor struct {
+		monitor chan<- instance.Monitor
+		path    path.T
+		node    string
+	}
+
 	opSetInstanceMonitor struct {
 		err   chan<- error
 		path  path.T
@@ -36,6 +42,20 @@ func (t T) DelInstanceMonitor(p path.T) error {
 	return <-err
 }
 
+// GetInstanceMonitor
+//
+// cluster.node.<node>.instance.<path>.monitor
+func (t T) GetInstanceMonitor(p path.T, node string) instance.Monitor {
+	monitor := make(chan instance.Monitor)
+	op := opGetInstanceMonitor{
+		monitor: monitor,
+		path:    p,
+		node:    node,
+	}
+	t.cmdC <- op
+	return <-monitor
+}
+
 // SetInstanceMonitor
 //
 // cluster.node.<localhost>.instance.<path>.monitor
@@ -54,6 +74,20 @@ func (o opDelInstanceMonitor) setError(err error) {
 	o.err <- err
 }
 
+func (o opGetInstanceMonitor) call(ctx context.Context, d *data) {
+	d.counterCmd <- idGetInstanceMonitor
+	m := instance.Monitor{}
+	if nodeStatus, ok := d.pending.Cluster.Node[o.node]; ok {
+		if inst, ok := nodeStatus.Instance[o.path.String()]; ok && inst.Monitor != nil {
+			m = *inst.Monitor
+		}
+	}
+	select {
+	case <-ctx.Done():
+	case o.monitor <- m:
+	}
+}
+
 func (o opDelInstanceMonitor) call(ctx context.Context, d *data) {
 	d.counterCmd <- idDelInstanceMonitor
 	s := o.path.String()
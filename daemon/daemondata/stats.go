@@ -38,6 +38,7 @@ const (
 	idGetHbMessage
 	idGetHbMessageType
 	idGetInstanceConfig
+	idGetInstanceMonitor
 	idGetInstanceStatus
 	idGetNode
 	idGetNodeConfig
@@ -47,6 +48,7 @@ const (
 	idGetNodeStatusMap
 	idGetNodeStatsMap
 	idGetNodesInfo
+	idGetObjectStatus
 	idGetServiceNames
 	idGetStatus
 	idSetHeartbeatPing
@@ -76,12 +78,14 @@ var (
 		idDropPeerNode:       "drop-peer-node",
 		idGetHbMessage:       "get-hb-message",
 		idGetHbMessageType:   "get-hb-message-type",
+		idGetInstanceMonitor: "get-instance-monitor",
 		idGetInstanceStatus:  "get-instance-status",
 		idGetNode:            "get-node",
 		idGetNodeConfig:      "get-node-config",
 		idGetNodeStatus:      "get-node-status",
 		idGetNodeStatusMap:   "get-node-status-map",
 		idGetNodesInfo:       "get-nodes-info",
+		idGetObjectStatus:    "get-object-status",
 		idGetServiceNames:    "get-service-names",
 		idGetStatus:          "get-status",
 		idSetSubHb:           "set-sub-hb",
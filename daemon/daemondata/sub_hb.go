@@ -29,6 +29,7 @@ func (d *data) setSubHb() {
 	subHb := cluster.SubHb{
 		Heartbeats: hbcache.Heartbeats(),
 		Modes:      hbModes,
+		Encoding:   hbcache.Encoding(),
 	}
 	d.pending.Sub.Hb = subHb
 	// TODO Use a dedicated msg for heartbeats updates
@@ -11,6 +11,7 @@ import (
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/path"
 	"opensvc.com/opensvc/core/rawconfig"
+	"opensvc.com/opensvc/core/xconfig"
 	"opensvc.com/opensvc/daemon/daemondata"
 	"opensvc.com/opensvc/daemon/daemonenv"
 	"opensvc.com/opensvc/daemon/daemonlogctx"
@@ -199,9 +200,16 @@ func (d *discover) onRemoteConfigFetched(c msgbus.RemoteFileConfig) {
 		s := c.Path.String()
 		confFile := rawconfig.Paths.Etc + "/" + prefix + s + ".conf"
 		d.log.Info().Msgf("install fetched config %s from %s", s, c.Node)
+		if err := xconfig.RecordPriorRevision(c.Path, confFile); err != nil {
+			d.log.Warn().Err(err).Msgf("can't record prior config revision of %s", s)
+		}
 		err := os.Rename(c.Filename, confFile)
 		if err != nil {
 			d.log.Error().Err(err).Msgf("can't install fetched config to %s", confFile)
+		} else if b, err := os.ReadFile(confFile); err == nil {
+			if _, err := xconfig.RecordRevision(c.Path, b, c.Node, "install fetched config from "+c.Node); err != nil {
+				d.log.Warn().Err(err).Msgf("can't record config revision of %s", s)
+			}
 		}
 		c.Err <- err
 	}
@@ -97,6 +97,10 @@ type (
 		Nodename string
 		HbId     string
 		Success  bool
+
+		// Gen is the generation of the message received from the peer,
+		// zero for the tx heartbeats.
+		Gen uint64
 	}
 
 	// CmdSetPeerStatus is a command to set a hb peer HeartbeatPeerStatus for a node
@@ -112,6 +116,10 @@ type (
 		Nodename string
 		Ctx      context.Context
 		Timeout  time.Duration
+
+		// Interval is the expected duration between two messages, used
+		// to count the missed intervals in the peer stats.
+		Interval time.Duration
 	}
 
 	// CmdDelWatcher is a command to stop one instance of a hb watcher for a remote
@@ -176,6 +184,7 @@ func (c *ctrl) run(ctx context.Context) {
 	events := make(EventStats)
 	remotes := make(map[string]RemoteBeating)
 	heartbeat := make(map[string]cluster.HeartbeatThreadStatus)
+	intervals := make(map[string]time.Duration)
 	bus := pubsub.BusFromContext(c.ctx)
 	defer c.log.Info().Msgf("stopped: %v", events)
 	updateDaemonDataHeartbeatsTicker := time.NewTicker(time.Second)
@@ -235,12 +244,19 @@ func (c *ctrl) run(ctx context.Context) {
 					}
 					delete(heartbeat, o.Id)
 				}
+				delete(intervals, o.Id)
 			case CmdSetState:
 				if hbToChange, ok := heartbeat[o.Id]; ok {
 					hbToChange.State = o.State
 					heartbeat[o.Id] = hbToChange
 				}
 			case CmdSetPeerSuccess:
+				if hbStatus, ok := heartbeat[o.HbId]; ok && o.Success {
+					if peerStatus, ok := hbStatus.Peers[o.Nodename]; ok {
+						peerStatus.Stats = peerStatus.Stats.WithMessage(time.Now(), o.Gen, intervals[o.HbId])
+						hbStatus.Peers[o.Nodename] = peerStatus
+					}
+				}
 				if remote, ok := remotes[o.Nodename]; ok {
 					k := o.HbId
 					if beatC, found := remote.beatingChan[k]; found {
@@ -297,7 +313,14 @@ func (c *ctrl) run(ctx context.Context) {
 				hbId := o.HbId
 				peerNode := o.Nodename
 				if foundHeartbeat, ok := heartbeat[hbId]; ok {
-					foundHeartbeat.Peers[peerNode] = o.PeerStatus
+					peerStatus := o.PeerStatus
+					previous := foundHeartbeat.Peers[peerNode]
+					peerStatus.Stats = previous.Stats
+					if previous.Beating != peerStatus.Beating && !(previous.Last.IsZero() && peerStatus.Beating) {
+						// don't count the initial beating as a transition
+						peerStatus.Stats.Transitions++
+					}
+					foundHeartbeat.Peers[peerNode] = peerStatus
 					heartbeat[hbId] = foundHeartbeat
 				}
 			case CmdAddWatcher:
@@ -314,6 +337,9 @@ func (c *ctrl) run(ctx context.Context) {
 				}
 				if _, ok := heartbeat[hbId]; ok {
 					heartbeat[hbId].Peers[peerNode] = cluster.HeartbeatPeerStatus{}
+					if o.Interval > 0 {
+						intervals[hbId] = o.Interval
+					}
 				} else {
 					c.log.Warn().Msgf("CmdAddWatcher %s %s called before CmdRegister", hbId, peerNode)
 					continue
@@ -6,8 +6,10 @@ import (
 	"time"
 
 	"github.com/rs/zerolog/log"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"opensvc.com/opensvc/core/cluster"
 	"opensvc.com/opensvc/daemon/daemonctx"
 	"opensvc.com/opensvc/daemon/daemondata"
 	"opensvc.com/opensvc/daemon/hbcache"
@@ -236,3 +238,34 @@ func TestCmdSetPeerSuccessCreatesPublishHbNodePing(t *testing.T) {
 		})
 	}
 }
+
+func TestCmdSetPeerSuccessUpdatesPeerStats(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctx = bootstrapDaemon(t, ctx)
+
+	testCtrl := setupCtrl(ctx)
+
+	hbId := "hb#1.rx"
+	node := "node2"
+	testCtrl.cmd <- CmdRegister{Id: hbId}
+	testCtrl.cmd <- CmdAddWatcher{
+		HbId:     hbId,
+		Nodename: node,
+		Ctx:      ctx,
+		Timeout:  time.Second,
+		Interval: time.Second,
+	}
+	for gen := uint64(1); gen <= 3; gen++ {
+		testCtrl.cmd <- CmdSetPeerSuccess{Nodename: node, HbId: hbId, Success: true, Gen: gen}
+	}
+	testCtrl.cmd <- CmdSetPeerSuccess{Nodename: node, HbId: hbId, Success: false, Gen: 4}
+
+	// the missed intervals and the intervals histogram accounting is
+	// tested with explicit timestamps by the cluster package tests.
+	result := make(chan map[string]cluster.HeartbeatPeerStatus)
+	testCtrl.cmd <- GetPeerStatus{HbId: hbId, result: result}
+	peerStatus := (<-result)[node]
+	assert.Equal(t, uint64(3), peerStatus.Stats.Received, "a failure is not a received message")
+	assert.Equal(t, uint64(3), peerStatus.Stats.LastGen)
+}
@@ -52,27 +52,46 @@ func peerDropWorker(ctx context.Context) {
 		}
 	}
 
+	cancelDropPeer := func(peer string) {
+		if drop, ok := dropM[peer]; ok {
+			drop.cancel()
+		}
+		delete(dropM, peer)
+	}
+
+	armDropPeer := func(peer string, at time.Time, delay time.Duration, reason string) {
+		dropCtx, cancel := context.WithTimeout(ctx, delay)
+		dropM[peer] = dropCall{cancel: cancel, at: at}
+		go func(ctx context.Context, peer string) {
+			<-ctx.Done()
+			if ctx.Err() == context.Canceled {
+				return
+			}
+			log.Info().Msgf("all hb rx stale for %s and %s expired. drop peer data", peer, reason)
+			dropPeer(peer)
+		}(dropCtx, peer)
+	}
+
 	delayDropPeer := func(peer string) {
-		if databus.GetNodeMonitor(peer).State == node.MonitorStateMaintenance {
-			delay := maintenanceGracePeriod
-			if drop, ok := dropM[peer]; ok {
-				drop.cancel()
-				delay = drop.at.Add(maintenanceGracePeriod).Sub(time.Now())
-				log.Info().Msgf("maintenance grace period timer reset to %s for %s", delay, peer)
+		now := time.Now()
+		at := now
+		drop, armed := dropM[peer]
+		if armed {
+			at = drop.at
+		}
+		d := newPeerDrop(databus.GetNodeMonitor(peer), at, maintenanceGracePeriod, now)
+		cancelDropPeer(peer)
+		switch {
+		case d.preserve:
+			log.Info().Msgf("all hb rx stale for %s in maintenance. preserve peer data", peer)
+		case d.delay > 0:
+			if armed {
+				log.Info().Msgf("%s timer reset to %s for %s", d.reason, d.delay, peer)
 			} else {
-				log.Info().Msgf("maintenance grace period timer set to %s for %s", delay, peer)
+				log.Info().Msgf("%s timer set to %s for %s", d.reason, d.delay, peer)
 			}
-			dropCtx, cancel := context.WithTimeout(ctx, delay)
-			dropM[peer] = dropCall{cancel: cancel, at: time.Now()}
-			go func(ctx context.Context, peer string) {
-				<-ctx.Done()
-				if ctx.Err() == context.Canceled {
-					return
-				}
-				log.Info().Msgf("all hb rx stale for %s and maintenance grace period expired. drop peer data", peer)
-				dropPeer(peer)
-			}(dropCtx, peer)
-		} else {
+			armDropPeer(peer, at, d.delay, d.reason)
+		default:
 			log.Info().Msgf("all hb rx stale for %s. drop peer data", peer)
 			dropPeer(peer)
 		}
@@ -90,10 +109,7 @@ func peerDropWorker(ctx context.Context) {
 	onHbNodePing := func(c msgbus.HbNodePing) {
 		peer := c.Node
 		if c.Status {
-			if drop, ok := dropM[peer]; ok {
-				drop.cancel()
-			}
-			delete(dropM, peer)
+			cancelDropPeer(peer)
 		} else {
 			delayDropPeer(peer)
 		}
@@ -113,3 +129,31 @@ func peerDropWorker(ctx context.Context) {
 		}
 	}
 }
+
+// peerDrop describes when to drop the data of a peer with all hb rx stale.
+// The data is preserved until the peer beats again if preserve is true,
+// dropped after delay if delay is positive, or dropped now.
+type peerDrop struct {
+	preserve bool
+	delay    time.Duration
+	reason   string
+}
+
+// newPeerDrop returns when to drop the data of a peer with all hb rx stale,
+// from its last known monitor. A peer in a declared maintenance is
+// preserved until the maintenance expires. A peer announcing its
+// maintenance on daemon stop is preserved for the maintenance grace period
+// started at at.
+func newPeerDrop(nodeMonitor node.Monitor, at time.Time, gracePeriod time.Duration, now time.Time) peerDrop {
+	switch {
+	case nodeMonitor.Maintenance.IsActive(now):
+		if nodeMonitor.Maintenance.Expire.IsZero() {
+			return peerDrop{preserve: true}
+		}
+		return peerDrop{delay: nodeMonitor.Maintenance.Expire.Sub(now), reason: "maintenance"}
+	case nodeMonitor.State == node.MonitorStateMaintenance:
+		return peerDrop{delay: at.Add(gracePeriod).Sub(now), reason: "maintenance grace period"}
+	default:
+		return peerDrop{}
+	}
+}
@@ -0,0 +1,61 @@
+package hbctrl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"opensvc.com/opensvc/core/node"
+)
+
+func TestNewPeerDrop(t *testing.T) {
+	now := time.Now()
+	gracePeriod := time.Minute
+	since := now.Add(-time.Hour)
+	cases := map[string]struct {
+		monitor node.Monitor
+		at      time.Time
+		want    peerDrop
+	}{
+		"idle peer is dropped now": {
+			monitor: node.Monitor{State: node.MonitorStateIdle},
+			at:      now,
+		},
+		"peer in maintenance without expire is preserved": {
+			monitor: node.Monitor{Maintenance: node.Maintenance{Since: since}},
+			at:      now,
+			want:    peerDrop{preserve: true},
+		},
+		"peer in maintenance is dropped at the maintenance expire": {
+			monitor: node.Monitor{Maintenance: node.Maintenance{Since: since, Expire: now.Add(time.Hour)}},
+			at:      now,
+			want:    peerDrop{delay: time.Hour, reason: "maintenance"},
+		},
+		"peer with an expired maintenance is dropped now": {
+			monitor: node.Monitor{Maintenance: node.Maintenance{Since: since, Expire: now.Add(-time.Minute)}},
+			at:      now,
+		},
+		"stopping peer is preserved for the grace period": {
+			monitor: node.Monitor{State: node.MonitorStateMaintenance},
+			at:      now,
+			want:    peerDrop{delay: gracePeriod, reason: "maintenance grace period"},
+		},
+		"stopping peer grace period is not restarted": {
+			monitor: node.Monitor{State: node.MonitorStateMaintenance},
+			at:      now.Add(-20 * time.Second),
+			want:    peerDrop{delay: 40 * time.Second, reason: "maintenance grace period"},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, c.want, newPeerDrop(c.monitor, c.at, gracePeriod, now))
+		})
+	}
+
+	t.Run("stopping peer is dropped after the grace period", func(t *testing.T) {
+		d := newPeerDrop(node.Monitor{State: node.MonitorStateMaintenance}, now.Add(-2*time.Minute), gracePeriod, now)
+		assert.False(t, d.preserve)
+		assert.LessOrEqual(t, d.delay, time.Duration(0))
+	})
+}
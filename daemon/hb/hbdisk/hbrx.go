@@ -2,7 +2,6 @@ package hbdisk
 
 import (
 	"context"
-	"encoding/json"
 	"sync"
 	"time"
 
@@ -73,6 +72,7 @@ func (t *rx) Start(cmdC chan<- any, msgC chan<- *hbtype.Msg) error {
 			Nodename: node,
 			Ctx:      ctx,
 			Timeout:  t.timeout,
+			Interval: t.interval,
 		}
 	}
 
@@ -139,7 +139,7 @@ func (t *rx) recv(nodename string) {
 	}
 
 	msg := hbtype.Msg{}
-	if err := json.Unmarshal(b, &msg); err != nil {
+	if err := hbtype.Unmarshal(b, &msg); err != nil {
 		t.log.Warn().Err(err).Msgf("can't unmarshal msg from %s", nodename)
 		return
 	}
@@ -149,6 +149,7 @@ func (t *rx) recv(nodename string) {
 		Nodename: msg.Nodename,
 		HbId:     t.id,
 		Success:  true,
+		Gen:      msg.Gen[msg.Nodename],
 	}
 	t.msgC <- &msg
 	t.last = c.Updated
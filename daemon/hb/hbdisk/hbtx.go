@@ -73,6 +73,7 @@ func (t *tx) Start(cmdC chan<- interface{}, msgC <-chan []byte) error {
 				Nodename: node,
 				Ctx:      ctx,
 				Timeout:  t.timeout,
+				Interval: t.interval,
 			}
 		}
 		var b []byte
@@ -29,6 +29,7 @@ type (
 		udpAddr  *net.UDPAddr
 		intf     *net.Interface
 		timeout  time.Duration
+		interval time.Duration
 		assembly map[string]msgMap
 
 		name   string
@@ -80,6 +81,7 @@ func (t *rx) Start(cmdC chan<- interface{}, msgC chan<- *hbtype.Msg) error {
 				Nodename: node,
 				Ctx:      ctx,
 				Timeout:  t.timeout,
+				Interval: t.interval,
 			}
 		}
 		listener, err := net.ListenMulticastUDP("udp", t.intf, t.udpAddr)
@@ -191,7 +193,7 @@ func (t *rx) recv(src *net.UDPAddr, n int, b []byte) {
 	}
 
 	data := hbtype.Msg{}
-	if err := json.Unmarshal(b, &data); err != nil {
+	if err := hbtype.Unmarshal(b, &data); err != nil {
 		t.log.Warn().Err(err).Msgf("can't unmarshal msg from %s", s)
 		return
 	}
@@ -203,22 +205,24 @@ func (t *rx) recv(src *net.UDPAddr, n int, b []byte) {
 		Nodename: data.Nodename,
 		HbId:     t.id,
 		Success:  true,
+		Gen:      data.Gen[data.Nodename],
 	}
 	t.msgC <- &data
 	delete(msg, f.MsgID)
 	t.assembly[s] = msg
 }
 
-func newRx(ctx context.Context, name string, nodes []string, udpAddr *net.UDPAddr, intf *net.Interface, timeout time.Duration) *rx {
+func newRx(ctx context.Context, name string, nodes []string, udpAddr *net.UDPAddr, intf *net.Interface, timeout, interval time.Duration) *rx {
 	id := name + ".rx"
 	log := daemonlogctx.Logger(ctx).With().Str("id", id).Logger()
 	return &rx{
-		ctx:     ctx,
-		id:      id,
-		nodes:   nodes,
-		udpAddr: udpAddr,
-		intf:    intf,
-		timeout: timeout,
-		log:     log,
+		ctx:      ctx,
+		id:       id,
+		nodes:    nodes,
+		udpAddr:  udpAddr,
+		intf:     intf,
+		timeout:  timeout,
+		interval: interval,
+		log:      log,
 	}
 }
@@ -75,6 +75,7 @@ func (t *tx) Start(cmdC chan<- interface{}, msgC <-chan []byte) error {
 				Nodename: node,
 				Ctx:      ctx,
 				Timeout:  t.timeout,
+				Interval: t.interval,
 			}
 		}
 		started <- true
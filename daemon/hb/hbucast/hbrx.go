@@ -2,7 +2,6 @@ package hbucast
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"net"
 	"sync"
@@ -20,13 +19,14 @@ type (
 	// rx holds an hb unicast receiver
 	rx struct {
 		sync.WaitGroup
-		ctx     context.Context
-		id      string
-		nodes   []string
-		addr    string
-		port    string
-		intf    string
-		timeout time.Duration
+		ctx      context.Context
+		id       string
+		nodes    []string
+		addr     string
+		port     string
+		intf     string
+		timeout  time.Duration
+		interval time.Duration
 
 		name   string
 		log    zerolog.Logger
@@ -93,6 +93,7 @@ func (t *rx) Start(cmdC chan<- interface{}, msgC chan<- *hbtype.Msg) error {
 				Nodename: node,
 				Ctx:      ctx,
 				Timeout:  t.timeout,
+				Interval: t.interval,
 			}
 		}
 		t.Add(1)
@@ -149,7 +150,7 @@ func (t *rx) handle(conn encryptconn.ConnNoder) {
 		t.log.Warn().Msgf("ReadWithNode huge message from %s: %d", nodename, i)
 	}
 	msg := hbtype.Msg{}
-	if err := json.Unmarshal(data[:i], &msg); err != nil {
+	if err := hbtype.Unmarshal(data[:i], &msg); err != nil {
 		t.log.Warn().Err(err).Msgf("can't unmarshal msg from %s", nodename)
 		return
 	}
@@ -157,21 +158,23 @@ func (t *rx) handle(conn encryptconn.ConnNoder) {
 		Nodename: msg.Nodename,
 		HbId:     t.id,
 		Success:  true,
+		Gen:      msg.Gen[msg.Nodename],
 	}
 	t.msgC <- &msg
 }
 
-func newRx(ctx context.Context, name string, nodes []string, addr, port, intf string, timeout time.Duration) *rx {
+func newRx(ctx context.Context, name string, nodes []string, addr, port, intf string, timeout, interval time.Duration) *rx {
 	id := name + ".rx"
 	log := daemonlogctx.Logger(ctx).With().Str("id", id).Logger()
 	return &rx{
-		ctx:     ctx,
-		id:      id,
-		nodes:   nodes,
-		addr:    addr,
-		port:    port,
-		intf:    intf,
-		timeout: timeout,
-		log:     log,
+		ctx:      ctx,
+		id:       id,
+		nodes:    nodes,
+		addr:     addr,
+		port:     port,
+		intf:     intf,
+		timeout:  timeout,
+		interval: interval,
+		log:      log,
 	}
 }
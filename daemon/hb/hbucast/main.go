@@ -78,6 +78,6 @@ func (t *T) Configure(ctx context.Context) {
 	name := t.Name()
 	tx := newTx(ctx, name, oNodes, port, intf, timeout, interval)
 	t.SetTx(tx)
-	rx := newRx(ctx, name, oNodes, "", port, intf, timeout)
+	rx := newRx(ctx, name, oNodes, "", port, intf, timeout, interval)
 	t.SetRx(rx)
 }
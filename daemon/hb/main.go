@@ -5,21 +5,24 @@ import (
 	"fmt"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 
-	"github.com/goccy/go-json"
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
 	reqjsonrpc "opensvc.com/opensvc/core/client/requester/jsonrpc"
+	"opensvc.com/opensvc/core/cluster"
 	"opensvc.com/opensvc/core/clusterhb"
 	"opensvc.com/opensvc/core/hbcfg"
 	"opensvc.com/opensvc/core/hbtype"
 	"opensvc.com/opensvc/core/kind"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/rawconfig"
 	"opensvc.com/opensvc/daemon/daemonctx"
 	"opensvc.com/opensvc/daemon/hb/hbctrl"
+	"opensvc.com/opensvc/daemon/hbcache"
 	"opensvc.com/opensvc/daemon/msgbus"
 	"opensvc.com/opensvc/daemon/routinehelper"
 	"opensvc.com/opensvc/daemon/subdaemon"
@@ -47,6 +50,11 @@ type (
 
 		ridSignature map[string]string
 
+		// peerEncoding is the encoding version announced by the peer nodes
+		// in their hb messages, used to negotiate the hb messages encoding.
+		peerEncoding     map[string]uint64
+		peerEncodingLock sync.RWMutex
+
 		sub *pubsub.Subscription
 	}
 
@@ -79,6 +87,7 @@ func New(opts ...funcopt.O) *T {
 	t.rxs = make(map[string]hbtype.Receiver)
 	t.readMsgQueue = make(chan *hbtype.Msg)
 	t.ridSignature = make(map[string]string)
+	t.peerEncoding = make(map[string]uint64)
 	return t
 }
 
@@ -274,6 +283,7 @@ func (t *T) msgToTx(ctx context.Context) error {
 	t.registerTxC = make(chan registerTxQueue)
 	t.unregisterTxC = make(chan string)
 	go func() {
+		var encoding cluster.HbEncoding
 		registeredTxMsgQueue := make(map[string]chan []byte)
 		defer func() {
 			tC := time.After(100 * time.Millisecond)
@@ -299,17 +309,31 @@ func (t *T) msgToTx(ctx context.Context) error {
 				t.log.Debug().Msgf("remove %s from hb transmitters", txId)
 				delete(registeredTxMsgQueue, txId)
 			case msg := <-msgC:
-				var rMsg *reqjsonrpc.Message
-				if b, err := json.Marshal(msg); err != nil {
-					err = fmt.Errorf("marshal failure %s for msg %v", err, msg)
+				format, compression := t.msgEncoding()
+				if format != encoding.Format || compression != encoding.Compression {
+					if encoding.Format != "" {
+						t.log.Info().Msgf("hb message encoding change %s/%s -> %s/%s", encoding.Format, encoding.Compression, format, compression)
+					}
+					encoding = cluster.HbEncoding{
+						Format:      format,
+						Compression: compression,
+						Sizes:       make(map[string]cluster.HbMsgSizes),
+					}
+				}
+				b, err := hbtype.Marshal(msg, format)
+				if err != nil {
+					t.log.Error().Err(err).Msgf("marshal %s msg", msg.Kind)
 					continue
-				} else {
-					rMsg = reqjsonrpc.NewMessage(b)
 				}
-				b, err := rMsg.Encrypt()
+				encoded := len(b)
+				rMsg := reqjsonrpc.NewMessage(b)
+				rMsg.Compression = compression
+				b, err = rMsg.Encrypt()
 				if err != nil {
 					continue
 				}
+				encoding = encoding.WithMsgSize(msg.Kind, encoded, len(b))
+				hbcache.SetEncoding(encoding)
 				for _, txQueue := range registeredTxMsgQueue {
 					txQueue <- b
 				}
@@ -362,12 +386,63 @@ func (t *T) msgFromRx(ctx context.Context) {
 			}
 			t.log.Debug().Msgf("process msg type %s from %s gens: %v", msg.Kind, msg.Nodename, msg.Gen)
 			msgTimes[peer] = msg.Updated
+			t.setPeerEncoding(peer, msg.Encoding)
 			dataMsgRecvQ <- msg
 			count++
 		}
 	}
 }
 
+// setPeerEncoding records the encoding version announced by a peer node.
+func (t *T) setPeerEncoding(peer string, version uint64) {
+	t.peerEncodingLock.RLock()
+	current, ok := t.peerEncoding[peer]
+	t.peerEncodingLock.RUnlock()
+	if ok && current == version {
+		return
+	}
+	t.peerEncodingLock.Lock()
+	t.peerEncoding[peer] = version
+	t.peerEncodingLock.Unlock()
+}
+
+// msgEncoding returns the format and compression of the next hb message.
+//
+// The configured cluster.hb_format and cluster.hb_compression are used only
+// if all the cluster peer nodes announced an encoding version supporting
+// them, else the messages are sent in the legacy json format, zlib
+// compressed.
+func (t *T) msgEncoding() (string, string) {
+	section := rawconfig.ClusterSection()
+	t.peerEncodingLock.RLock()
+	defer t.peerEncodingLock.RUnlock()
+	return negotiateEncoding(section.HbFormat, section.HbCompression, hostname.Hostname(), strings.Fields(section.Nodes), t.peerEncoding)
+}
+
+// negotiateEncoding returns the configured format and compression if all
+// the peers of localhost in nodes announced an encoding version supporting
+// them, else the legacy json format and zlib compression.
+func negotiateEncoding(format, compression, localhost string, nodes []string, peerEncoding map[string]uint64) (string, string) {
+	if format == "" {
+		format = hbtype.FormatJSON
+	}
+	if compression == "" {
+		compression = reqjsonrpc.CompressionZlib
+	}
+	if format == hbtype.FormatJSON && compression == reqjsonrpc.CompressionZlib {
+		return format, compression
+	}
+	for _, peer := range nodes {
+		if peer == localhost {
+			continue
+		}
+		if peerEncoding[peer] < hbtype.EncodingVersion {
+			return hbtype.FormatJSON, reqjsonrpc.CompressionZlib
+		}
+	}
+	return format, compression
+}
+
 func (t *T) startSubscriptions(ctx context.Context) {
 	bus := pubsub.BusFromContext(ctx)
 	clusterPath := path.T{Name: "cluster", Kind: kind.Ccfg}
@@ -0,0 +1,41 @@
+package hb
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	reqjsonrpc "opensvc.com/opensvc/core/client/requester/jsonrpc"
+	"opensvc.com/opensvc/core/hbtype"
+)
+
+func TestMsgEncodingNegotiation(t *testing.T) {
+	nodes := []string{"node1", "node2", "node3"}
+	hb := &T{peerEncoding: make(map[string]uint64)}
+	negotiate := func() (string, string) {
+		return negotiateEncoding(hbtype.FormatBinary, reqjsonrpc.CompressionGzip, "node1", nodes, hb.peerEncoding)
+	}
+
+	format, compression := negotiate()
+	assert.Equal(t, hbtype.FormatJSON, format, "peers not heard from yet use the legacy encoding")
+	assert.Equal(t, reqjsonrpc.CompressionZlib, compression)
+
+	// node2 is a new peer, node3 an old peer not announcing an encoding version
+	hb.setPeerEncoding("node2", hbtype.EncodingVersion)
+	hb.setPeerEncoding("node3", 0)
+	format, compression = negotiate()
+	assert.Equal(t, hbtype.FormatJSON, format, "an old peer forces the legacy encoding")
+	assert.Equal(t, reqjsonrpc.CompressionZlib, compression)
+
+	hb.setPeerEncoding("node3", hbtype.EncodingVersion)
+	format, compression = negotiate()
+	assert.Equal(t, hbtype.FormatBinary, format, "all peers support the configured encoding")
+	assert.Equal(t, reqjsonrpc.CompressionGzip, compression)
+
+	t.Run("the legacy encoding needs no negotiation", func(t *testing.T) {
+		hb.setPeerEncoding("node3", 0)
+		format, compression := negotiateEncoding("", "", "node1", nodes, hb.peerEncoding)
+		assert.Equal(t, hbtype.FormatJSON, format)
+		assert.Equal(t, reqjsonrpc.CompressionZlib, compression)
+	})
+}
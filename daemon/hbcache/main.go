@@ -5,6 +5,9 @@
 //
 // # It provides the heartbeat for sub.hb.heartbeat
 //
+// It also provides the hb messages encoding for sub.hb.encoding, populated
+// from the hb messages dispatcher.
+//
 // The cache must be started with Start(ctx). It is stopped when ctx is done
 package hbcache
 
@@ -26,6 +29,7 @@ func Start(ctx context.Context) {
 func run(ctx context.Context) {
 	gens := make(map[string]map[string]uint64)
 	heartbeats := make([]cluster.HeartbeatThreadStatus, 0)
+	encoding := cluster.HbEncoding{}
 	log := daemonlogctx.Logger(ctx).With().Str("name", "hbcache").Logger()
 	log.Debug().Msg("started")
 	defer log.Debug().Msg("done")
@@ -51,10 +55,19 @@ func run(ctx context.Context) {
 					})
 				}
 				cmd.response <- result
+			case getEncoding:
+				result := encoding
+				result.Sizes = make(map[string]cluster.HbMsgSizes)
+				for kind, sizes := range encoding.Sizes {
+					result.Sizes[kind] = sizes
+				}
+				cmd.response <- result
 			case dropPeer:
 				delete(gens, string(cmd))
 			case setHeartbeats:
 				heartbeats = cmd
+			case setEncoding:
+				encoding = cluster.HbEncoding(cmd)
 			default:
 				log.Error().Interface("cmd", i).Msg("invalid command")
 			}
@@ -71,6 +84,15 @@ func Heartbeats() []cluster.HeartbeatThreadStatus {
 	return <-response
 }
 
+// Encoding returns the encoding and size stats of the hb messages sent by
+// the local node.
+func Encoding() cluster.HbEncoding {
+	response := make(chan cluster.HbEncoding)
+	var i interface{} = getEncoding{response: response}
+	cmdI <- i
+	return <-response
+}
+
 // Setters
 
 // DropPeer drop a node from cache
@@ -87,14 +109,26 @@ func SetHeartbeats(hbs []cluster.HeartbeatThreadStatus) {
 	cmdI <- i
 }
 
+// SetEncoding updates the hb messages encoding cache
+//
+// The sizes map must not be modified by the caller after the call.
+func SetEncoding(encoding cluster.HbEncoding) {
+	var i interface{} = setEncoding(encoding)
+	cmdI <- i
+}
+
 // commands
 type (
 	// getters
 	getHeartbeats struct {
 		response chan<- []cluster.HeartbeatThreadStatus
 	}
+	getEncoding struct {
+		response chan<- cluster.HbEncoding
+	}
 
 	// setters
 	dropPeer      string
 	setHeartbeats []cluster.HeartbeatThreadStatus
+	setEncoding   cluster.HbEncoding
 )
@@ -52,6 +52,16 @@ func (o *imon) crmProvisionLeader() error {
 	return o.crmAction("provision leader", o.path.String(), "provision", "--local", "--leader", "--disable-rollback")
 }
 
+func (o *imon) crmResourceReload(rids []string) error {
+	s := strings.Join(rids, ",")
+	return o.crmAction("reload", o.path.String(), "reload", "--local", "--rid", s)
+}
+
+func (o *imon) crmResourceRestart(rids []string) error {
+	s := strings.Join(rids, ",")
+	return o.crmAction("restart", o.path.String(), "restart", "--local", "--rid", s)
+}
+
 func (o *imon) crmResourceStart(rids []string) error {
 	s := strings.Join(rids, ",")
 	return o.crmAction("start", o.path.String(), "start", "--local", "--rid", s)
@@ -82,6 +92,9 @@ func (o *imon) crmUnprovisionLeader() error {
 }
 
 func (o *imon) crmAction(title string, cmdArgs ...string) error {
+	if o.actionHook != nil {
+		return o.actionHook(title, cmdArgs...)
+	}
 	runners <- struct{}{}
 	defer func() {
 		<-runners
@@ -29,6 +29,7 @@ import (
 	"opensvc.com/opensvc/core/node"
 	"opensvc.com/opensvc/core/object"
 	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
 	"opensvc.com/opensvc/daemon/daemondata"
 	"opensvc.com/opensvc/daemon/msgbus"
 	"opensvc.com/opensvc/util/hostname"
@@ -61,19 +62,74 @@ type (
 		scopeNodes    []string
 		readyDuration time.Duration
 
+		// reloadPending maps the rid of the resources waiting for a reload
+		// to the checksum of the sec and cfg key values to apply.
+		reloadPending map[string]string
+
+		// relObjStatus and relInstAvail cache the object status of the
+		// parents and children, and the avail status of their instances
+		// referenced by a <path>@<node> relation.
+		relObjStatus map[string]object.Status
+		relInstAvail map[string]status.T
+
+		// stoppedChildren are the children stopped before the local
+		// instance stop, started back after the local instance start.
+		stoppedChildren map[string]path.Relation
+
 		objStatus   object.Status
 		cancelReady context.CancelFunc
 		localhost   string
 		change      bool
 
+		// trigger describes the event being handled, recorded in the
+		// monitor history as the cause of the transitions.
+		trigger string
+
+		// publisher publishes the instance monitor changes, and history
+		// records their transitions. The tests and the simulations use
+		// discardMonitor.
+		publisher monitorPublisher
+		history   monitorRecorder
+
+		// actionHook, if set, replaces the execution of the crm
+		// actions, the daemon freeze and unfreeze actions, and the
+		// children stop and start requests. Used by the tests and the
+		// simulations.
+		actionHook func(title string, cmdArgs ...string) error
+
+		// isSimulation is true for the instance monitors of the
+		// orchestration simulations. Their resource restart and reload
+		// orchestrations are skipped, as they act on the daemon timers
+		// and bus.
+		isSimulation bool
+
+		// failbackTimer triggers the orchestration at the scheduled
+		// failback time.
+		failbackTimer *time.Timer
+
 		sub *pubsub.Subscription
 	}
 
+	monitorPublisher interface {
+		SetInstanceMonitor(path.T, instance.Monitor) error
+	}
+
+	monitorRecorder interface {
+		Append(...instance.MonitorTransition) error
+	}
+
+	// discardMonitor neither publishes nor records the instance monitor
+	// changes.
+	discardMonitor struct{}
+
 	// cmdOrchestrate can be used from post action go routines
 	cmdOrchestrate struct {
 		state    instance.MonitorState
 		newState instance.MonitorState
 	}
+
+	// cmdFailback is sent when the scheduled failback time is reached
+	cmdFailback struct{}
 )
 
 // Start launch goroutine imon worker for a local instance state
@@ -100,12 +156,18 @@ func Start(parent context.Context, p path.T, nodes []string) error {
 		cancel:        cancel,
 		cmdC:          make(chan any),
 		databus:       databus,
+		publisher:     databus,
 		log:           log.Logger.With().Str("func", "imon").Stringer("object", p).Logger(),
 		instStatus:    make(map[string]instance.Status),
 		instMonitor:   make(map[string]instance.Monitor),
+		reloadPending: make(map[string]string),
+		relObjStatus:  make(map[string]object.Status),
+		relInstAvail:  make(map[string]status.T),
 		localhost:     hostname.Hostname(),
 		scopeNodes:    nodes,
 		change:        true,
+		trigger:       "monitor start",
+		history:       instance.NewMonitorHistoryWriter(instance.MonitorHistoryFile(p)),
 		readyDuration: 5 * time.Second,
 	}
 
@@ -137,6 +199,7 @@ func (o *imon) startSubscriptions() {
 	sub.AddFilter(msgbus.SetInstanceMonitor{}, label)
 	sub.AddFilter(msgbus.InstanceMonitorUpdated{}, label)
 	sub.AddFilter(msgbus.InstanceMonitorDeleted{}, label)
+	sub.AddFilter(msgbus.ConfigUpdated{}, nodeLabel)
 	sub.AddFilter(msgbus.NodeConfigUpdated{}, nodeLabel)
 	sub.AddFilter(msgbus.NodeMonitorUpdated{})
 	sub.AddFilter(msgbus.NodeStatusUpdated{})
@@ -152,6 +215,7 @@ func (o *imon) worker(initialNodes []string) {
 	for _, node := range initialNodes {
 		o.instStatus[node] = o.databus.GetInstanceStatus(o.path, node)
 	}
+	o.watchRelations()
 	o.updateIfChange()
 	defer o.delete()
 
@@ -166,28 +230,47 @@ func (o *imon) worker(initialNodes []string) {
 		case i := <-o.sub.C:
 			switch c := i.(type) {
 			case msgbus.ObjectStatusUpdated:
+				o.trigger = "object status update from node " + c.Node
 				o.onObjectStatusUpdated(c)
 			case msgbus.ProgressInstanceMonitor:
+				o.trigger = "action progress"
 				o.onProgressInstanceMonitor(c)
 			case msgbus.SetInstanceMonitor:
+				o.trigger = "set instance monitor request"
+				if c.Origin != "" {
+					o.trigger += " from " + c.Origin
+				}
 				o.onSetInstanceMonitor(c)
 			case msgbus.InstanceMonitorUpdated:
+				o.trigger = "instance monitor update from node " + c.Node
 				o.onInstanceMonitorUpdated(c)
 			case msgbus.InstanceMonitorDeleted:
+				o.trigger = "instance monitor deleted on node " + c.Node
 				o.onInstanceMonitorDeleted(c)
+			case msgbus.ConfigUpdated:
+				o.trigger = "keystore config update"
+				o.onKeystoreConfigUpdated(c)
 			case msgbus.NodeConfigUpdated:
+				o.trigger = "node config update"
 				o.onNodeConfigUpdated(c)
 			case msgbus.NodeMonitorUpdated:
+				o.trigger = "node monitor update from node " + c.Node
 				o.onNodeMonitorUpdated(c)
 			case msgbus.NodeStatusUpdated:
+				o.trigger = "node status update from node " + c.Node
 				o.onNodeStatusUpdated(c)
 			case msgbus.NodeStatsUpdated:
+				o.trigger = "node stats update from node " + c.Node
 				o.onNodeStatsUpdated(c)
 			}
 		case i := <-o.cmdC:
 			switch c := i.(type) {
 			case cmdOrchestrate:
+				o.trigger = "action result"
 				o.needOrchestrate(c)
+			case cmdFailback:
+				o.trigger = "failback schedule"
+				o.orchestrate()
 			}
 		}
 	}
@@ -201,11 +284,19 @@ func (o *imon) delete() {
 
 func (o *imon) update() {
 	newValue := o.state
-	if err := o.databus.SetInstanceMonitor(o.path, newValue); err != nil {
+	if err := o.publisher.SetInstanceMonitor(o.path, newValue); err != nil {
 		o.log.Error().Err(err).Msg("SetInstanceMonitor")
 	}
 }
 
+func (discardMonitor) SetInstanceMonitor(path.T, instance.Monitor) error {
+	return nil
+}
+
+func (discardMonitor) Append(...instance.MonitorTransition) error {
+	return nil
+}
+
 func (o *imon) transitionTo(newState instance.MonitorState) {
 	o.change = true
 	o.state.State = newState
@@ -221,7 +312,19 @@ func (o *imon) updateIfChange() {
 	now := time.Now()
 	previousVal := o.previousState
 	newVal := o.state
+	transitions := make(instance.MonitorHistory, 0)
+	addTransition := func(field, from, to string) {
+		transitions = append(transitions, instance.MonitorTransition{
+			At:      now,
+			Node:    o.localhost,
+			Field:   field,
+			From:    from,
+			To:      to,
+			Trigger: o.trigger,
+		})
+	}
 	if newVal.GlobalExpect != previousVal.GlobalExpect {
+		addTransition(instance.MonitorTransitionFieldGlobalExpect, previousVal.GlobalExpect.String(), newVal.GlobalExpect.String())
 		// Don't update GlobalExpectUpdated here
 		// GlobalExpectUpdated is updated only during cmdSetInstanceMonitorClient and
 		// its value is used for convergeGlobalExpectFromRemote
@@ -229,10 +332,12 @@ func (o *imon) updateIfChange() {
 	}
 	if newVal.LocalExpect != previousVal.LocalExpect {
 		o.state.LocalExpectUpdated = now
+		addTransition(instance.MonitorTransitionFieldLocalExpect, previousVal.LocalExpect.String(), newVal.LocalExpect.String())
 		o.loggerWithState().Info().Msgf("change monitor local expect %s -> %s", previousVal.LocalExpect, newVal.LocalExpect)
 	}
 	if newVal.State != previousVal.State {
 		o.state.StateUpdated = now
+		addTransition(instance.MonitorTransitionFieldState, previousVal.State.String(), newVal.State.String())
 		o.loggerWithState().Info().Msgf("change monitor state %s -> %s", previousVal.State, newVal.State)
 	}
 	if newVal.IsLeader != previousVal.IsLeader {
@@ -243,6 +348,9 @@ func (o *imon) updateIfChange() {
 	}
 	o.previousState = o.state
 	o.update()
+	if err := o.history.Append(transitions...); err != nil {
+		o.log.Warn().Err(err).Msg("monitor history update")
+	}
 }
 
 func (o *imon) hasOtherNodeActing() bool {
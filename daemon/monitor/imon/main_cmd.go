@@ -1,10 +1,7 @@
 package imon
 
 import (
-	"bytes"
-	"crypto/md5"
 	"fmt"
-	"sort"
 	"strings"
 	"time"
 
@@ -87,6 +84,10 @@ func (o *imon) onConfigDeleted(srcNode string, srcCmd msgbus.ConfigDeleted) {
 
 // onObjectStatusUpdated updateIfChange state global expect from object status
 func (o *imon) onObjectStatusUpdated(c msgbus.ObjectStatusUpdated) {
+	if c.Path != o.path {
+		o.onRelationObjectStatusUpdated(c)
+		return
+	}
 	if c.SrcEv != nil {
 		switch srcCmd := c.SrcEv.(type) {
 		case msgbus.InstanceStatusUpdated:
@@ -98,6 +99,7 @@ func (o *imon) onObjectStatusUpdated(c msgbus.ObjectStatusUpdated) {
 		}
 	}
 	o.objStatus = c.Value
+	o.watchRelations()
 	o.updateIsLeader()
 	o.orchestrate()
 	o.updateIfChange()
@@ -431,73 +433,16 @@ func (o *imon) needOrchestrate(c cmdOrchestrate) {
 }
 
 func (o *imon) sortCandidates(candidates []string) []string {
-	switch o.objStatus.PlacementPolicy {
-	case placement.NodesOrder:
-		return o.sortWithNodesOrderPolicy(candidates)
-	case placement.Spread:
-		return o.sortWithSpreadPolicy(candidates)
-	case placement.Score:
-		return o.sortWithScorePolicy(candidates)
-	case placement.Shift:
-		return o.sortWithShiftPolicy(candidates)
-	default:
-		return []string{}
-	}
-}
-
-func (o *imon) sortWithSpreadPolicy(candidates []string) []string {
-	l := append([]string{}, candidates...)
-	sum := func(s string) []byte {
-		b := append([]byte(o.path.String()), []byte(s)...)
-		return md5.New().Sum(b)
-	}
-	sort.SliceStable(l, func(i, j int) bool {
-		return bytes.Compare(sum(l[i]), sum(l[j])) < 0
-	})
-	return l
-}
-
-// sortWithScorePolicy sorts candidates by descending cluster.NodeStats.Score
-func (o *imon) sortWithScorePolicy(candidates []string) []string {
-	l := append([]string{}, candidates...)
-	sort.SliceStable(l, func(i, j int) bool {
-		var si, sj uint64
-		if stats, ok := o.nodeStats[l[i]]; ok {
-			si = stats.Score
-		}
-		if stats, ok := o.nodeStats[l[j]]; ok {
-			sj = stats.Score
-		}
-		return si > sj
-	})
-	return l
-}
-
-func (o *imon) sortWithLoadAvgPolicy(candidates []string) []string {
-	o.log.Warn().Msg("TODO: sortWithLoadAvgPolicy")
-	return candidates
-}
-
-func (o *imon) sortWithShiftPolicy(candidates []string) []string {
-	var i int
-	l := o.sortWithNodesOrderPolicy(candidates)
-	l = append(l, l...)
-	n := len(candidates)
-	scalerSliceIndex := o.path.ScalerSliceIndex()
-	if n > 0 && scalerSliceIndex > n {
-		i = o.path.ScalerSliceIndex() % n
+	sorter := placement.Sorter{
+		Key:              o.path.String(),
+		ScalerSliceIndex: o.path.ScalerSliceIndex(),
+		Nodes:            o.scopeNodes,
+		Scores:           make(map[string]uint64),
 	}
-	return candidates[i : i+n]
-}
-
-func (o *imon) sortWithNodesOrderPolicy(candidates []string) []string {
-	var l []string
-	for _, node := range o.scopeNodes {
-		if stringslice.Has(node, candidates) {
-			l = append(l, node)
-		}
+	for node, stats := range o.nodeStats {
+		sorter.Scores[node] = stats.Score
 	}
-	return l
+	return sorter.Sort(o.objStatus.PlacementPolicy, candidates)
 }
 
 func (o *imon) nextPlacedAtCandidates(want []string) string {
@@ -549,10 +494,17 @@ func (o imon) IsNodeMonitorStatusRankable(node string) (bool, bool) {
 	if !ok {
 		return false, false
 	}
+	if nodeMonitor.IsInMaintenance(time.Now()) {
+		// the node objects are not taken over by peers, and the node
+		// does not take over the peer objects.
+		return false, true
+	}
 	return nodeMonitor.State.IsRankable(), true
 }
 
-func (o *imon) newIsHALeader() bool {
+// haCandidates returns the scope nodes eligible to run the object
+// instance, in the scope order.
+func (o *imon) haCandidates() []string {
 	var candidates []string
 
 	for _, node := range o.scopeNodes {
@@ -570,7 +522,11 @@ func (o *imon) newIsHALeader() bool {
 		}
 		candidates = append(candidates, node)
 	}
-	candidates = o.sortCandidates(candidates)
+	return candidates
+}
+
+func (o *imon) newIsHALeader() bool {
+	candidates := o.sortCandidates(o.haCandidates())
 
 	var maxLeaders int = 1
 	if o.objStatus.Topology == topology.Flex {
@@ -649,6 +605,7 @@ func (o *imon) doAction(action func() error, newState, successState, errorState
 }
 
 func (o *imon) initResourceMonitor() {
+	previous := o.state.Resources
 	m := make(map[string]instance.ResourceMonitor)
 	for rid, res := range o.instConfig.Resources {
 		m[rid] = instance.ResourceMonitor{
@@ -658,5 +615,6 @@ func (o *imon) initResourceMonitor() {
 		}
 	}
 	o.state.Resources = m
+	o.initResourceReload(previous)
 	o.change = true
 }
@@ -0,0 +1,75 @@
+package imon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+func TestIsNodeMonitorStatusRankable(t *testing.T) {
+	now := time.Now()
+	o := imon{nodeMonitor: map[string]node.Monitor{
+		"idle":     {State: node.MonitorStateIdle},
+		"stopping": {State: node.MonitorStateMaintenance},
+		"declared": {State: node.MonitorStateIdle, Maintenance: node.Maintenance{Since: now.Add(-time.Hour)}},
+		"expiring": {State: node.MonitorStateIdle, Maintenance: node.Maintenance{Since: now.Add(-time.Hour), Expire: now.Add(time.Hour)}},
+		"expired":  {State: node.MonitorStateIdle, Maintenance: node.Maintenance{Since: now.Add(-time.Hour), Expire: now.Add(-time.Minute)}},
+	}}
+	cases := map[string]struct {
+		rankable bool
+		found    bool
+	}{
+		"unknown":  {rankable: false, found: false},
+		"idle":     {rankable: true, found: true},
+		"stopping": {rankable: false, found: true},
+		"declared": {rankable: false, found: true},
+		"expiring": {rankable: false, found: true},
+		"expired":  {rankable: true, found: true},
+	}
+	for nodename, c := range cases {
+		t.Run(nodename, func(t *testing.T) {
+			rankable, found := o.IsNodeMonitorStatusRankable(nodename)
+			assert.Equal(t, c.found, found)
+			assert.Equal(t, c.rankable, rankable)
+		})
+	}
+}
+
+func TestMaintenanceIsNotTakenOver(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+	setMaintenance := func(clusterStatus map[string]node.Node, nodename string) {
+		n := clusterStatus[nodename]
+		n.Monitor.Maintenance = node.Maintenance{Since: time.Now().Add(-time.Minute)}
+		clusterStatus[nodename] = n
+	}
+
+	t.Run("peers do not take over the node in maintenance", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up, "node2": status.Down})
+		setMaintenance(clusterStatus.Cluster.Node, "node1")
+		result := Simulate(clusterStatus, p)
+		assert.Equal(t, []string{"node2"}, result.Candidates)
+		assert.Equal(t, map[string]string{"node1": "none", "node2": "none"}, simulationActions(result))
+	})
+
+	t.Run("the node in maintenance does not take over", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Down, "node2": status.Down})
+		obj := clusterStatus.Cluster.Object["svc1"]
+		obj.Avail = status.Down
+		obj.UpInstancesCount = 0
+		clusterStatus.Cluster.Object = map[string]object.Status{"svc1": obj}
+		setMaintenance(clusterStatus.Cluster.Node, "node1")
+		result := Simulate(clusterStatus, p)
+		assert.Equal(t, []string{"node2"}, result.Candidates)
+		assert.Equal(t, []string{"node2"}, result.Leaders)
+		assert.Equal(t, "none", simulationActions(result)["node1"])
+		assert.Equal(t, "start", simulationActions(result)["node2"])
+	})
+}
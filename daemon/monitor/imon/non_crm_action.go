@@ -9,6 +9,9 @@ import (
 )
 
 func (o *imon) freeze() error {
+	if o.actionHook != nil {
+		return o.actionHook("freeze")
+	}
 	p := filepath.Join(o.path.VarDir(), "frozen")
 	if file.Exists(p) {
 		return nil
@@ -38,6 +41,9 @@ func (o *imon) freeze() error {
 }
 
 func (o *imon) unfreeze() error {
+	if o.actionHook != nil {
+		return o.actionHook("unfreeze")
+	}
 	p := filepath.Join(o.path.VarDir(), "frozen")
 	if !file.Exists(p) {
 		return nil
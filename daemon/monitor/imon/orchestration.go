@@ -24,11 +24,26 @@ func (o *imon) orchestrate() {
 
 	if nodeMonitor, ok := o.nodeMonitor[o.localhost]; !ok {
 		return
-	} else if nodeMonitor.State != node.MonitorStateIdle {
-		return
+	} else {
+		switch nodeMonitor.State {
+		case node.MonitorStateIdle:
+		case node.MonitorStateEvacuating:
+			// the evacuation switches the local instances to peers,
+			// so only the placed@ orchestration is allowed.
+			if o.state.GlobalExpect == instance.MonitorGlobalExpectPlacedAt {
+				o.orchestratePlacedAt()
+				o.updateIfChange()
+			}
+			return
+		default:
+			return
+		}
 	}
 
-	o.orchestrateResourceRestart()
+	if !o.isSimulation {
+		o.orchestrateResourceRestart()
+		o.orchestrateResourceReload()
+	}
 
 	switch o.state.GlobalExpect {
 	case instance.MonitorGlobalExpectUnset:
@@ -0,0 +1,149 @@
+package imon
+
+import (
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+	"opensvc.com/opensvc/util/schedule"
+)
+
+// timeNow returns the current time of the failback orchestration. The tests
+// replace it with a fixed clock.
+var timeNow = time.Now
+
+// orchestrateFailback places a failover object back on its preferred node,
+// the local node, at the first time allowed by the failback schedule after
+// the local node has been the healthy preferred node for the failback delay.
+// A failback due outside the schedule allowed time, like a failback delayed
+// by another node acting, is rescheduled.
+//
+// The failback time is published in the instance monitor so the object
+// status can report it, and is cleared as soon as the failback is no longer
+// needed.
+func (o *imon) orchestrateFailback() {
+	if v, reason := o.isFailbackNeeded(); !v {
+		if !o.state.FailbackScheduled.IsZero() {
+			o.log.Info().Msgf("cancel failback scheduled at %s: %s", o.state.FailbackScheduled, reason)
+		}
+		o.clearFailback()
+		return
+	}
+	now := timeNow()
+	if o.state.FailbackScheduled.IsZero() {
+		o.scheduleFailback(now, now.Add(o.instConfig.FailbackDelay))
+		if o.state.FailbackScheduled.IsZero() {
+			return
+		}
+	}
+	if now.Before(o.state.FailbackScheduled) {
+		return
+	}
+	if !isFailbackAllowed(o.instConfig.Failback, now) {
+		o.log.Info().Msgf("failback scheduled at %s is no longer allowed by the failback schedule", o.state.FailbackScheduled)
+		o.clearFailback()
+		o.scheduleFailback(now, now)
+		return
+	}
+	if o.hasOtherNodeActing() {
+		o.log.Debug().Msg("failback delayed: another node is acting")
+		return
+	}
+	o.log.Info().Msg("failback to the preferred node")
+	o.clearFailback()
+	o.change = true
+	o.state.GlobalExpect = instance.MonitorGlobalExpectPlaced
+	o.state.GlobalExpectOptions = nil
+	// update GlobalExpectUpdated now
+	// This will allow remote nodes to pickup most recent value
+	o.state.GlobalExpectUpdated = now
+}
+
+// scheduleFailback schedules the failback at the first time allowed by the
+// failback schedule at or after tm, and arms the timer orchestrating it.
+func (o *imon) scheduleFailback(now, tm time.Time) {
+	at, err := failbackTime(o.instConfig.Failback, tm)
+	if err != nil {
+		// the expression is validated when the instance config is loaded
+		o.log.Debug().Err(err).Msg("failback schedule")
+		return
+	}
+	o.log.Info().Msgf("failback scheduled at %s", at)
+	o.change = true
+	o.state.FailbackScheduled = at
+	o.failbackTimer = time.AfterFunc(at.Sub(now), func() {
+		select {
+		case o.cmdC <- cmdFailback{}:
+		case <-o.ctx.Done():
+		}
+	})
+}
+
+// isFailbackNeeded returns true if the failover object runs on another node
+// while the local node is its healthy preferred node.
+func (o *imon) isFailbackNeeded() (bool, string) {
+	if o.instConfig.Failback == "" {
+		return false, "no failback schedule"
+	}
+	if o.objStatus.Topology != topology.Failover {
+		return false, "not a failover object"
+	}
+	if o.objStatus.Orchestrate != "ha" {
+		return false, "orchestrate is not ha"
+	}
+	if o.state.GlobalExpect != instance.MonitorGlobalExpectUnset {
+		return false, fmt.Sprintf("global expect is %s", o.state.GlobalExpect)
+	}
+	if !o.state.IsLeader || !o.state.IsHALeader {
+		return false, "local node is not the healthy preferred node"
+	}
+	if o.objStatus.Avail != status.Up {
+		return false, fmt.Sprintf("object is %s", o.objStatus.Avail)
+	}
+	if instStatus, ok := o.instStatus[o.localhost]; !ok || instStatus.Avail != status.Down {
+		return false, "local instance is not down"
+	}
+	return true, "object is up on a non-preferred node"
+}
+
+func (o *imon) clearFailback() {
+	if o.failbackTimer != nil {
+		o.failbackTimer.Stop()
+		o.failbackTimer = nil
+	}
+	if !o.state.FailbackScheduled.IsZero() {
+		o.change = true
+		o.state.FailbackScheduled = time.Time{}
+	}
+}
+
+// failbackTime returns the first time allowed by the failback schedule
+// expression at or after tm. The duration returned by the schedule Next is
+// the delay to wait in the allowed timerange, not the timerange length, so
+// the failback time is checked again with isFailbackAllowed when due.
+func failbackTime(expr string, tm time.Time) (time.Time, error) {
+	next, _, err := schedule.New(expr).Next(schedule.NextWithTime(tm))
+	if err != nil {
+		return next, err
+	}
+	if next.IsZero() {
+		return next, fmt.Errorf("no allowed time in the failback schedule '%s'", expr)
+	}
+	return next, nil
+}
+
+// ValidateFailback returns an error if the failback schedule expression
+// can not be parsed or has no allowed time.
+func ValidateFailback(expr string) error {
+	_, err := failbackTime(expr, timeNow())
+	return err
+}
+
+// isFailbackAllowed returns true if tm is allowed by the failback schedule
+// expression.
+func isFailbackAllowed(expr string, tm time.Time) bool {
+	_, err := schedule.New(expr).Test(tm)
+	return err == nil
+}
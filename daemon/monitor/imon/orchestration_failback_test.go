@@ -0,0 +1,132 @@
+package imon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+func TestFailbackTime(t *testing.T) {
+	tm := time.Date(2015, 2, 27, 10, 0, 0, 0, time.UTC)
+
+	t.Run("next allowed day", func(t *testing.T) {
+		at, err := failbackTime("09:00-09:20", tm)
+		require.NoError(t, err)
+		assert.Equal(t, time.Date(2015, 2, 28, 9, 0, 0, 0, time.UTC), at)
+	})
+
+	t.Run("no allowed time", func(t *testing.T) {
+		_, err := failbackTime("@0", tm)
+		assert.Error(t, err)
+	})
+}
+
+func TestValidateFailback(t *testing.T) {
+	assert.NoError(t, ValidateFailback("02:00-04:00 sat-sun"))
+	assert.Error(t, ValidateFailback("@0"))
+	assert.Error(t, ValidateFailback("02:00-04:00 foo"))
+}
+
+func TestIsFailbackAllowed(t *testing.T) {
+	assert.True(t, isFailbackAllowed("11:00-13:00", time.Date(2015, 2, 27, 12, 0, 0, 0, time.Local)))
+	assert.False(t, isFailbackAllowed("11:00-13:00", time.Date(2015, 2, 27, 13, 30, 0, 0, time.Local)))
+}
+
+func TestOrchestrateFailback(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	// run at noon, so the schedules are in their allowed window
+	now := time.Date(2015, 2, 27, 12, 0, 0, 0, time.Local)
+	defer func(f func() time.Time) { timeNow = f }(timeNow)
+	timeNow = func() time.Time { return now }
+
+	newFailbackImon := func(t *testing.T, localAvail status.T, failback string, delay time.Duration) *imon {
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		return &imon{
+			ctx:        ctx,
+			path:       p,
+			log:        zerolog.Nop(),
+			localhost:  "node1",
+			cmdC:       make(chan any),
+			instConfig: instance.Config{Failback: failback, FailbackDelay: delay},
+			objStatus: object.Status{
+				Avail:       status.Up,
+				Orchestrate: "ha",
+				Topology:    topology.Failover,
+			},
+			instStatus: map[string]instance.Status{
+				"node1": {Avail: localAvail},
+				"node2": {Avail: status.Up},
+			},
+			state: instance.Monitor{
+				GlobalExpect: instance.MonitorGlobalExpectUnset,
+				State:        instance.MonitorStateIdle,
+				IsLeader:     true,
+				IsHALeader:   true,
+			},
+		}
+	}
+
+	t.Run("failback is placed when allowed", func(t *testing.T) {
+		o := newFailbackImon(t, status.Down, "11:00-13:00@1", 0)
+		o.orchestrateFailback()
+		assert.Equal(t, instance.MonitorGlobalExpectPlaced, o.state.GlobalExpect)
+		assert.True(t, o.state.FailbackScheduled.IsZero())
+	})
+
+	t.Run("failback is scheduled after the delay", func(t *testing.T) {
+		o := newFailbackImon(t, status.Down, "11:00-13:00@1", time.Hour)
+		o.orchestrateFailback()
+		defer o.clearFailback()
+		assert.Equal(t, instance.MonitorGlobalExpectUnset, o.state.GlobalExpect)
+		assert.Equal(t, now.Add(time.Hour), o.state.FailbackScheduled)
+	})
+
+	t.Run("schedule is cancelled when the preferred node is up", func(t *testing.T) {
+		o := newFailbackImon(t, status.Down, "11:00-13:00@1", time.Hour)
+		o.orchestrateFailback()
+		require.False(t, o.state.FailbackScheduled.IsZero())
+		o.instStatus["node1"] = instance.Status{Avail: status.Up}
+		o.orchestrateFailback()
+		assert.True(t, o.state.FailbackScheduled.IsZero())
+		assert.Nil(t, o.failbackTimer)
+	})
+
+	t.Run("failback due outside the allowed time is rescheduled", func(t *testing.T) {
+		o := newFailbackImon(t, status.Down, "11:00-13:00@1", 0)
+		o.orchestrateFailback()
+		defer o.clearFailback()
+		require.Equal(t, instance.MonitorGlobalExpectPlaced, o.state.GlobalExpect)
+
+		// the failback was delayed past the end of the allowed time
+		o.state.GlobalExpect = instance.MonitorGlobalExpectUnset
+		o.state.FailbackScheduled = now
+		later := now.Add(90 * time.Minute)
+		timeNow = func() time.Time { return later }
+		defer func() { timeNow = func() time.Time { return now } }()
+
+		o.orchestrateFailback()
+		assert.Equal(t, instance.MonitorGlobalExpectUnset, o.state.GlobalExpect)
+		assert.Equal(t, time.Date(2015, 2, 28, 11, 0, 0, 0, time.Local), o.state.FailbackScheduled)
+		assert.NotNil(t, o.failbackTimer)
+	})
+
+	t.Run("no failback without schedule", func(t *testing.T) {
+		o := newFailbackImon(t, status.Down, "", 0)
+		o.orchestrateFailback()
+		assert.Equal(t, instance.MonitorGlobalExpectUnset, o.state.GlobalExpect)
+		assert.True(t, o.state.FailbackScheduled.IsZero())
+	})
+}
@@ -11,17 +11,23 @@ func (o *imon) orchestrateUnset() {
 	if o.objStatus.Orchestrate == "ha" {
 		o.orchestrateHAStart()
 		o.orchestrateHAStop()
+	} else {
+		o.clearWaitRelations()
 	}
+	o.orchestrateFailback()
 }
 
 func (o *imon) orchestrateHAStop() {
 	if o.objStatus.Topology != topology.Flex {
+		o.clearWaitState(instance.MonitorStateWaitChildren)
 		return
 	}
 	if v, _ := o.isExtraInstance(); !v {
+		o.clearWaitState(instance.MonitorStateWaitChildren)
 		return
 	}
-	o.stop()
+	// the other instances keep the object up, so the children stay up
+	o.stopInstance()
 }
 
 func (o *imon) orchestrateHAStart() {
@@ -30,6 +36,7 @@ func (o *imon) orchestrateHAStart() {
 		o.cancelReadyState()
 	}
 	if v, _ := o.isStartable(); !v {
+		o.clearWaitState(instance.MonitorStateWaitParents)
 		return
 	}
 	o.orchestrateStarted()
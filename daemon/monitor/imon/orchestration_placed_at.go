@@ -16,6 +16,10 @@ func (o *imon) orchestrateFailoverPlacedStart() {
 		o.orchestrateFailoverPlacedStartFromStarted()
 	case instance.MonitorStateStopped:
 		o.orchestrateFailoverPlacedStartFromStopped()
+	case instance.MonitorStateWaitParents:
+		o.orchestrateFailoverPlacedStartFromStopped()
+	case instance.MonitorStateWaitChildren:
+		o.clearWaitRelations()
 	case instance.MonitorStateStartFailed:
 		o.orchestratePlacedFromStartFailed()
 	case instance.MonitorStateThawing:
@@ -37,6 +41,10 @@ func (o *imon) orchestrateFlexPlacedStart() {
 		o.orchestrateFlexPlacedStartFromStarted()
 	case instance.MonitorStateStopped:
 		o.transitionTo(instance.MonitorStateIdle)
+	case instance.MonitorStateWaitParents:
+		o.placedStart()
+	case instance.MonitorStateWaitChildren:
+		o.clearWaitRelations()
 	case instance.MonitorStateStartFailed:
 		o.orchestratePlacedFromStartFailed()
 	case instance.MonitorStateThawing:
@@ -58,6 +66,10 @@ func (o *imon) orchestrateFailoverPlacedStop() {
 		o.clearStopFailedIfDown()
 	case instance.MonitorStateStopped:
 		o.clearStoppedIfObjectStatusAvailUp()
+	case instance.MonitorStateWaitChildren:
+		o.placedStop()
+	case instance.MonitorStateWaitParents:
+		o.clearWaitRelations()
 	case instance.MonitorStateReady:
 		o.transitionTo(instance.MonitorStateIdle)
 	case instance.MonitorStateStartFailed:
@@ -81,6 +93,10 @@ func (o *imon) orchestrateFlexPlacedStop() {
 		o.clearStopFailedIfDown()
 	case instance.MonitorStateStopped:
 		o.clearStoppedIfObjectStatusAvailUp()
+	case instance.MonitorStateWaitChildren:
+		o.placedStop()
+	case instance.MonitorStateWaitParents:
+		o.clearWaitRelations()
 	case instance.MonitorStateReady:
 		o.transitionTo(instance.MonitorStateIdle)
 	case instance.MonitorStateStartFailed:
@@ -117,8 +133,12 @@ func (o *imon) placedUnfreeze() {
 	}
 }
 
+// doPlacedStart starts the local instance, after its parents are up.
 func (o *imon) doPlacedStart() {
-	o.doAction(o.crmStart, instance.MonitorStateStarting, instance.MonitorStateStarted, instance.MonitorStateStartFailed)
+	if o.waitParents() {
+		return
+	}
+	o.doAction(o.startAction, instance.MonitorStateStarting, instance.MonitorStateStarted, instance.MonitorStateStartFailed)
 }
 
 func (o *imon) placedStart() {
@@ -145,7 +165,11 @@ func (o *imon) placedStop() {
 	}
 }
 
+// doPlacedStop stops the local instance, after its children are down.
 func (o *imon) doPlacedStop() {
+	if o.waitChildren() {
+		return
+	}
 	o.createPendingWithDuration(stopDuration)
 	o.doAction(o.crmStop, instance.MonitorStateStopping, instance.MonitorStateStopped, instance.MonitorStateStopFailed)
 }
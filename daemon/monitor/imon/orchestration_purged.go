@@ -16,6 +16,8 @@ func (o *imon) orchestratePurged() {
 		o.purgedFromUnprovisioned()
 	case instance.MonitorStateWaitNonLeader:
 		o.purgedFromWaitNonLeader()
+	case instance.MonitorStateWaitChildren:
+		o.purgedFromWaitChildren()
 	}
 }
 
@@ -32,6 +34,15 @@ func (o *imon) purgedFromIdle() {
 	return
 }
 
+func (o *imon) purgedFromWaitChildren() {
+	if o.instStatus[o.localhost].Avail == status.Up {
+		o.purgedFromIdleUp()
+		return
+	}
+	o.clearWaitRelations()
+	o.purgedFromIdle()
+}
+
 func (o *imon) purgedFromDeleted() {
 	o.change = true
 	o.state.GlobalExpect = instance.MonitorGlobalExpectUnset
@@ -43,7 +54,11 @@ func (o *imon) purgedFromUnprovisioned() {
 	o.doAction(o.crmDelete, instance.MonitorStateDeleting, instance.MonitorStateDeleted, instance.MonitorStatePurgeFailed)
 }
 
+// purgedFromIdleUp stops the local instance, after its children are down.
 func (o *imon) purgedFromIdleUp() {
+	if o.waitChildren() {
+		return
+	}
 	o.doAction(o.crmStop, instance.MonitorStateStopping, instance.MonitorStateIdle, instance.MonitorStateStopFailed)
 }
 
@@ -0,0 +1,298 @@
+package imon
+
+import (
+	"strings"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/daemon/msgbus"
+	"opensvc.com/opensvc/util/pubsub"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+// The parents and children relations are <path> or <path>@<node>. A <path>
+// relation is evaluated against the aggregated object status, cluster-wide.
+// A <path>@<node> relation is evaluated against the instance status of
+// <path> on <node>.
+
+// watchRelations subscribes to the object status updates of the parents
+// and children not watched yet, and caches their current status. The
+// relations no longer configured are unsubscribed and dropped from the
+// cache.
+func (o *imon) watchRelations() {
+	instStatus := o.instStatus[o.localhost]
+	relations := append(append([]path.Relation{}, instStatus.Parents...), instStatus.Children...)
+	paths := make(map[string]any)
+	instances := make(map[string]any)
+	for _, relation := range relations {
+		p, node, err := relation.Split()
+		if err != nil {
+			o.log.Warn().Err(err).Msgf("invalid relation %s", relation)
+			continue
+		}
+		s := p.String()
+		paths[s] = nil
+		if _, ok := o.relObjStatus[s]; !ok {
+			o.sub.AddFilter(msgbus.ObjectStatusUpdated{}, pubsub.Label{"path", s})
+			o.relObjStatus[s] = o.databus.GetObjectStatus(p)
+		}
+		if node == "" {
+			continue
+		}
+		k := relationInstanceKey(s, node)
+		instances[k] = nil
+		if _, ok := o.relInstAvail[k]; !ok {
+			o.relInstAvail[k] = o.databus.GetInstanceStatus(p, node).Avail
+		}
+	}
+	for s := range o.relObjStatus {
+		if _, ok := paths[s]; ok {
+			continue
+		}
+		o.log.Debug().Msgf("unwatch relation %s", s)
+		o.sub.DelFilter(msgbus.ObjectStatusUpdated{}, pubsub.Label{"path", s})
+		delete(o.relObjStatus, s)
+	}
+	for k := range o.relInstAvail {
+		if _, ok := instances[k]; !ok {
+			delete(o.relInstAvail, k)
+		}
+	}
+}
+
+// onRelationObjectStatusUpdated updates the cached status of a parent or
+// child object, and orchestrates if the local instance waits for its
+// relations.
+func (o *imon) onRelationObjectStatusUpdated(c msgbus.ObjectStatusUpdated) {
+	s := c.Path.String()
+	if _, ok := o.relObjStatus[s]; !ok {
+		// queued before the relation was unwatched
+		return
+	}
+	o.relObjStatus[s] = c.Value
+	if srcCmd, ok := c.SrcEv.(msgbus.InstanceStatusUpdated); ok {
+		o.relInstAvail[relationInstanceKey(s, srcCmd.Node)] = srcCmd.Value.Avail
+	}
+	switch o.state.State {
+	case instance.MonitorStateWaitParents, instance.MonitorStateWaitChildren:
+		o.orchestrate()
+		o.updateIfChange()
+	}
+}
+
+// relationAvail returns the cached avail status of a relation.
+func (o *imon) relationAvail(relation path.Relation) status.T {
+	p, node, err := relation.Split()
+	if err != nil {
+		return status.Undef
+	}
+	if node == "" {
+		return o.relObjStatus[p.String()].Avail
+	}
+	return o.relInstAvail[relationInstanceKey(p.String(), node)]
+}
+
+// parentsNotUp returns the parents not up yet.
+func (o *imon) parentsNotUp() []string {
+	l := make([]string, 0)
+	for _, relation := range o.instStatus[o.localhost].Parents {
+		switch o.relationAvail(relation) {
+		case status.Up, status.NotApplicable:
+		default:
+			l = append(l, relation.String())
+		}
+	}
+	return l
+}
+
+// childrenNotDown returns the children not down yet. A child with an
+// undefined status, like a child not deployed anywhere, does not block.
+func (o *imon) childrenNotDown() []string {
+	l := make([]string, 0)
+	for _, relation := range o.instStatus[o.localhost].Children {
+		switch o.relationAvail(relation) {
+		case status.Down, status.StandbyDown, status.NotApplicable, status.Undef:
+		default:
+			l = append(l, relation.String())
+		}
+	}
+	return l
+}
+
+// canWaitRelations returns true if all the nodes announce a compat version
+// able to decode the wait parents and wait children monitor states. During a
+// rolling upgrade, the relations are not waited for until the older nodes
+// are upgraded.
+func (o *imon) canWaitRelations() bool {
+	for nodename, nodeStatus := range o.nodeStatus {
+		if nodeStatus.Compat < instance.CompatMonitorStateWaitRelations {
+			o.log.Debug().Msgf("ignore the relations: node %s compat version %d is lower than %d", nodename, nodeStatus.Compat, instance.CompatMonitorStateWaitRelations)
+			return false
+		}
+	}
+	return true
+}
+
+// waitParents transitions to the wait parents state and returns true if
+// a parent is not up.
+func (o *imon) waitParents() bool {
+	l := o.parentsNotUp()
+	if len(l) == 0 {
+		return false
+	}
+	if !o.canWaitRelations() {
+		return false
+	}
+	if o.state.State != instance.MonitorStateWaitParents {
+		o.loggerWithState().Info().Msgf("wait parents up: %s", strings.Join(l, ", "))
+		o.transitionTo(instance.MonitorStateWaitParents)
+	}
+	return true
+}
+
+// waitChildren sets the stopped global expect on the children not down,
+// transitions to the wait children state and returns true if a child is
+// not down.
+//
+// A child without local instance can not be asked to stop from the local
+// node, so the stop fails immediately instead of waiting for a child stop
+// nobody requested.
+func (o *imon) waitChildren() bool {
+	l := o.childrenNotDown()
+	if len(l) == 0 {
+		return false
+	}
+	if !o.canWaitRelations() {
+		return false
+	}
+	if o.state.State != instance.MonitorStateWaitChildren {
+		if unreachable := o.stopChildren(l); len(unreachable) > 0 {
+			o.loggerWithState().Error().Msgf("children not down and without local instance to stop: %s", strings.Join(unreachable, ", "))
+			o.transitionTo(instance.MonitorStateStopFailed)
+			return true
+		}
+		o.loggerWithState().Info().Msgf("wait children down: %s", strings.Join(l, ", "))
+		o.transitionTo(instance.MonitorStateWaitChildren)
+	}
+	return true
+}
+
+// stopChildren stops the children relations and remembers them so the
+// local instance start can restore them. It returns the children without
+// local instance.
+//
+// A <path> child is stopped cluster-wide, through the stopped global expect
+// set on its local instance monitor. A <path>@<node> child is stopped only
+// on <node>, so only the local node can stop it.
+func (o *imon) stopChildren(relations []string) []string {
+	unreachable := make([]string, 0)
+	for _, s := range relations {
+		relation := path.Relation(s)
+		p, node, err := relation.Split()
+		switch {
+		case err != nil:
+			unreachable = append(unreachable, s)
+			continue
+		case node != "" && node != o.localhost:
+			unreachable = append(unreachable, s)
+			continue
+		case !stringslice.Has(o.localhost, o.relObjStatus[p.String()].Scope):
+			unreachable = append(unreachable, s)
+			continue
+		}
+		if o.stoppedChildren == nil {
+			o.stoppedChildren = make(map[string]path.Relation)
+		}
+		o.stoppedChildren[s] = relation
+		if node == "" {
+			o.setChildGlobalExpect(p, instance.MonitorGlobalExpectStopped, "stop")
+		} else {
+			o.doChildLocalAction(p, "stop")
+		}
+	}
+	return unreachable
+}
+
+// startStoppedChildren restores the children stopped by stopChildren. The
+// <path> children get the started global expect: the stopped orchestration
+// left them frozen, and the started orchestration thaws them and starts
+// them as soon as their parents are up. The <path>@<node> children have
+// their local instance started.
+func (o *imon) startStoppedChildren() {
+	for s, relation := range o.stoppedChildren {
+		p, node, _ := relation.Split()
+		if node == "" {
+			o.setChildGlobalExpect(p, instance.MonitorGlobalExpectStarted, "start")
+		} else {
+			o.doChildLocalAction(p, "start")
+		}
+		delete(o.stoppedChildren, s)
+	}
+}
+
+// doChildLocalAction runs the stop or start action on the local instance of
+// the child p, in the background. The wait children state is left when the
+// child instance status update reports it down.
+func (o *imon) doChildLocalAction(p path.T, action string) {
+	s := p.String()
+	o.log.Info().Msgf("%s the child %s local instance", action, s)
+	if o.actionHook != nil {
+		_ = o.actionHook(action+" child", s+"@"+o.localhost)
+		return
+	}
+	go func() {
+		if err := o.crmAction(action+" child", s, action, "--local"); err != nil {
+			o.log.Error().Err(err).Msgf("%s the child %s local instance", action, s)
+		}
+	}()
+}
+
+// setChildGlobalExpect sets the global expect of the child p through its
+// local instance monitor.
+func (o *imon) setChildGlobalExpect(p path.T, globalExpect instance.MonitorGlobalExpect, action string) {
+	s := p.String()
+	o.log.Info().Msgf("set the child %s global expect %s", s, globalExpect)
+	if o.actionHook != nil {
+		_ = o.actionHook(action+" child", s)
+		return
+	}
+	pubsub.BusFromContext(o.ctx).Pub(msgbus.SetInstanceMonitor{
+		Path:   p,
+		Node:   o.localhost,
+		Origin: "parent " + o.path.String() + " " + action,
+		Value: instance.MonitorUpdate{
+			GlobalExpect: &globalExpect,
+		},
+	}, pubsub.Label{"path", s})
+}
+
+// startAction starts the local instance, then restores the children its
+// stop has stopped.
+func (o *imon) startAction() error {
+	if err := o.crmStart(); err != nil {
+		return err
+	}
+	o.startStoppedChildren()
+	return nil
+}
+
+// clearWaitRelations leaves the wait parents or wait children state when
+// the orchestration that needed the relations is no longer active.
+func (o *imon) clearWaitRelations() {
+	o.clearWaitState(instance.MonitorStateWaitParents)
+	o.clearWaitState(instance.MonitorStateWaitChildren)
+}
+
+// clearWaitState transitions to idle if the monitor state is state.
+func (o *imon) clearWaitState(state instance.MonitorState) {
+	if o.state.State != state {
+		return
+	}
+	o.loggerWithState().Info().Msgf("leave %s state", state)
+	o.transitionTo(instance.MonitorStateIdle)
+}
+
+func relationInstanceKey(p, node string) string {
+	return p + "@" + node
+}
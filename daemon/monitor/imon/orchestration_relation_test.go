@@ -0,0 +1,234 @@
+package imon
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/daemon/msgbus"
+	"opensvc.com/opensvc/util/pubsub"
+)
+
+func newRelationImon(t *testing.T, children ...path.Relation) *imon {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	bus := pubsub.NewBus(t.Name())
+	bus.Start(ctx)
+	t.Cleanup(bus.Stop)
+	p, err := path.Parse("parent")
+	require.NoError(t, err)
+	return &imon{
+		ctx:       pubsub.ContextWithBus(ctx, bus),
+		path:      p,
+		log:       zerolog.Nop(),
+		localhost: "node1",
+		cmdC:      make(chan any, 10),
+		publisher: discardMonitor{},
+		history:   discardMonitor{},
+		state: instance.Monitor{
+			GlobalExpect: instance.MonitorGlobalExpectStopped,
+			State:        instance.MonitorStateIdle,
+		},
+		instStatus: map[string]instance.Status{
+			"node1": {Avail: status.Up, Children: children},
+		},
+		relObjStatus: map[string]object.Status{
+			"child1": {Avail: status.Up, Scope: []string{"node1", "node2"}},
+			"child2": {Avail: status.Up, Scope: []string{"node2"}},
+		},
+		relInstAvail: make(map[string]status.T),
+	}
+}
+
+func TestWaitChildren(t *testing.T) {
+	t.Run("the children with a local instance are asked to stop", func(t *testing.T) {
+		o := newRelationImon(t, "child1")
+		sub := pubsub.BusFromContext(o.ctx).Sub(t.Name())
+		sub.AddFilter(msgbus.SetInstanceMonitor{}, pubsub.Label{"path", "child1"})
+		sub.Start()
+		defer func() {
+			assert.NoError(t, sub.Stop())
+		}()
+
+		assert.True(t, o.waitChildren())
+		assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+		select {
+		case i := <-sub.C:
+			c, ok := i.(msgbus.SetInstanceMonitor)
+			require.True(t, ok)
+			require.NotNil(t, c.Value.GlobalExpect)
+			assert.Equal(t, instance.MonitorGlobalExpectStopped, *c.Value.GlobalExpect)
+		case <-time.After(time.Second):
+			require.Fail(t, "the child stop was not requested")
+		}
+
+		o.relObjStatus["child1"] = object.Status{Avail: status.Down}
+		assert.False(t, o.waitChildren(), "no more wait when the children are down")
+	})
+
+	t.Run("a child without local instance fails the stop", func(t *testing.T) {
+		o := newRelationImon(t, "child1", "child2")
+		assert.True(t, o.waitChildren())
+		assert.Equal(t, instance.MonitorStateStopFailed, o.state.State)
+	})
+}
+
+// recordActions sets an action hook recording the action titles and
+// arguments, and returns a func returning the recorded actions.
+func recordActions(o *imon) func() []string {
+	calls := make([]string, 0)
+	o.actionHook = func(title string, cmdArgs ...string) error {
+		calls = append(calls, strings.TrimSpace(title+" "+strings.Join(cmdArgs, " ")))
+		return nil
+	}
+	return func() []string { return calls }
+}
+
+func TestStartRestoresStoppedChildren(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	calls := recordActions(o)
+	assert.True(t, o.waitChildren())
+	assert.Equal(t, []string{"stop child child1"}, calls())
+
+	require.NoError(t, o.startAction())
+	assert.Equal(t, []string{"stop child child1", "start parent start --local", "start child child1"}, calls())
+
+	require.NoError(t, o.startAction())
+	assert.Equal(t, "start parent start --local", calls()[len(calls())-1], "the children are restored once")
+}
+
+func TestPlacedStopWaitsChildren(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	calls := recordActions(o)
+	o.state.GlobalExpect = instance.MonitorGlobalExpectPlacedAt
+	o.state.State = instance.MonitorStateThawed
+
+	o.orchestrateFailoverPlacedStop()
+	assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+	assert.Equal(t, []string{"stop child child1"}, calls())
+
+	o.orchestrateFailoverPlacedStop()
+	assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+	assert.Equal(t, []string{"stop child child1"}, calls(), "the children stop is requested once")
+
+	o.relObjStatus["child1"] = object.Status{Avail: status.Down}
+	o.orchestrateFailoverPlacedStop()
+	assert.Equal(t, instance.MonitorStateStopping, o.state.State)
+	assert.Equal(t, []string{"stop child child1", "stop parent stop --local"}, calls())
+}
+
+func TestPlacedStartWaitsParents(t *testing.T) {
+	o := newRelationImon(t)
+	calls := recordActions(o)
+	o.state.GlobalExpect = instance.MonitorGlobalExpectPlacedAt
+	o.state.State = instance.MonitorStateThawed
+	o.instStatus["node1"] = instance.Status{Avail: status.Down, Parents: []path.Relation{"parent1"}}
+	o.relObjStatus["parent1"] = object.Status{Avail: status.Down}
+
+	o.orchestrateFlexPlacedStart()
+	assert.Equal(t, instance.MonitorStateWaitParents, o.state.State)
+	assert.Empty(t, calls())
+
+	o.relObjStatus["parent1"] = object.Status{Avail: status.Up}
+	o.orchestrateFlexPlacedStart()
+	assert.Equal(t, instance.MonitorStateStarting, o.state.State)
+	assert.Equal(t, []string{"start parent start --local"}, calls())
+}
+
+func TestPurgedStopWaitsChildren(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	calls := recordActions(o)
+	o.state.GlobalExpect = instance.MonitorGlobalExpectPurged
+
+	o.orchestratePurged()
+	assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+	assert.Equal(t, []string{"stop child child1"}, calls())
+
+	o.relObjStatus["child1"] = object.Status{Avail: status.Down}
+	o.orchestratePurged()
+	assert.Equal(t, instance.MonitorStateStopping, o.state.State)
+	assert.Equal(t, []string{"stop child child1", "stop parent stop --local"}, calls())
+}
+
+func TestStopInstanceKeepsChildren(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	calls := recordActions(o)
+	o.state.GlobalExpect = instance.MonitorGlobalExpectUnset
+
+	o.stopInstance()
+	assert.Equal(t, []string{"stop parent stop --local"}, calls())
+}
+
+func TestWatchRelationsUnwatchStale(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	bus := pubsub.BusFromContext(o.ctx)
+	o.sub = bus.Sub(t.Name())
+	o.sub.AddFilter(msgbus.ObjectStatusUpdated{}, pubsub.Label{"path", "child1"})
+	o.sub.AddFilter(msgbus.ObjectStatusUpdated{}, pubsub.Label{"path", "child2"})
+	o.sub.Start()
+	defer func() {
+		assert.NoError(t, o.sub.Stop())
+	}()
+	o.relInstAvail["child2@node2"] = status.Up
+
+	o.watchRelations()
+	assert.Contains(t, o.relObjStatus, "child1")
+	assert.NotContains(t, o.relObjStatus, "child2")
+	assert.Empty(t, o.relInstAvail)
+
+	bus.Pub(msgbus.ObjectStatusUpdated{Path: path.T{Name: "child2"}}, pubsub.Label{"path", "child2"})
+	select {
+	case i := <-o.sub.C:
+		assert.Fail(t, "unexpected publication of an unwatched relation", "%v", i)
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestStopNodeScopedChildren(t *testing.T) {
+	o := newRelationImon(t, "child1@node1", "child1@node2")
+	o.relInstAvail["child1@node1"] = status.Up
+	o.relInstAvail["child1@node2"] = status.Down
+	calls := recordActions(o)
+
+	assert.True(t, o.waitChildren())
+	assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+	assert.Equal(t, []string{"stop child child1@node1"}, calls(), "only the local child instance is stopped")
+
+	require.NoError(t, o.startAction())
+	assert.Equal(t, []string{"stop child child1@node1", "start parent start --local", "start child child1@node1"}, calls())
+
+	t.Run("a child instance on a peer node fails the stop", func(t *testing.T) {
+		o := newRelationImon(t, "child1@node2")
+		o.relInstAvail["child1@node2"] = status.Up
+		calls := recordActions(o)
+		assert.True(t, o.waitChildren())
+		assert.Equal(t, instance.MonitorStateStopFailed, o.state.State)
+		assert.Empty(t, calls())
+	})
+}
+
+func TestWaitRelationsNeedsPeersCompat(t *testing.T) {
+	o := newRelationImon(t, "child1")
+	calls := recordActions(o)
+	o.nodeStatus = map[string]node.Status{
+		"node1": {Compat: instance.CompatMonitorStateWaitRelations},
+		"node2": {Compat: instance.CompatMonitorStateWaitRelations - 1},
+	}
+	assert.False(t, o.waitChildren(), "the children are not waited for while a node is not upgraded")
+	assert.Equal(t, instance.MonitorStateIdle, o.state.State)
+	assert.Empty(t, calls())
+
+	o.nodeStatus["node2"] = node.Status{Compat: instance.CompatMonitorStateWaitRelations}
+	assert.True(t, o.waitChildren())
+	assert.Equal(t, instance.MonitorStateWaitChildren, o.state.State)
+}
@@ -0,0 +1,252 @@
+package imon
+
+import (
+	"crypto/md5"
+	"fmt"
+	"sort"
+	"strings"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/daemon/msgbus"
+	"opensvc.com/opensvc/util/envprovider"
+)
+
+// initResourceReload sets the reload checksum of the resources with a
+// reload policy to the current sec and cfg key values, so only the
+// following key changes trigger a reload.
+//
+// The planned reloads of the resources still having a reload policy are
+// preserved, with the checksum of the values the resource runs with, found
+// in <previous>.
+func (o *imon) initResourceReload(previous instance.ResourceMonitorMap) {
+	pending := make(map[string]string)
+	for rid, rcfg := range o.instConfig.Resources {
+		if !hasReloadPolicy(rcfg) {
+			continue
+		}
+		if csum, ok := o.reloadPending[rid]; ok {
+			pending[rid] = csum
+			if current, ok := previous.GetReloadCsum(rid); ok {
+				o.state.Resources.SetReloadCsum(rid, current)
+			}
+			continue
+		}
+		csum, err := reloadCsum(o.path.Namespace, rcfg)
+		if err != nil {
+			o.log.Debug().Err(err).Msgf("resource %s reload checksum", rid)
+		}
+		o.state.Resources.SetReloadCsum(rid, csum)
+	}
+	o.reloadPending = pending
+}
+
+// onKeystoreConfigUpdated plans the reload of the resources consuming keys
+// of the updated sec or cfg object, if the consumed key values changed.
+func (o *imon) onKeystoreConfigUpdated(c msgbus.ConfigUpdated) {
+	switch c.Path.Kind {
+	case kind.Sec, kind.Cfg:
+	default:
+		return
+	}
+	if c.Path.Namespace != o.path.Namespace {
+		return
+	}
+	for rid, rcfg := range o.instConfig.Resources {
+		if !hasReloadPolicy(rcfg) {
+			continue
+		}
+		if !reloadReferences(rcfg, c.Path) {
+			continue
+		}
+		csum, err := reloadCsum(o.path.Namespace, rcfg)
+		if err != nil {
+			o.log.Warn().Err(err).Msgf("resource %s reload checksum", rid)
+			continue
+		}
+		if current, ok := o.state.Resources.GetReloadCsum(rid); !ok || current == csum {
+			continue
+		}
+		o.log.Info().Msgf("resource %s consumed keys changed in %s, plan %s", rid, c.Path, rcfg.ReloadPolicy)
+		o.reloadPending[rid] = csum
+	}
+	o.orchestrate()
+	o.updateIfChange()
+}
+
+func (o *imon) orchestrateResourceReload() {
+	if len(o.reloadPending) == 0 {
+		return
+	}
+
+	// discard all execpt svc and vol
+	switch o.path.Kind {
+	case kind.Svc, kind.Vol:
+	default:
+		return
+	}
+
+	// discard if the instance status does not exist
+	instStatus, ok := o.instStatus[o.localhost]
+	if !ok {
+		return
+	}
+
+	// wait for the running action to finish
+	if o.state.State != instance.MonitorStateIdle {
+		return
+	}
+
+	apply := func(rid string) {
+		o.state.Resources.SetReloadCsum(rid, o.reloadPending[rid])
+		delete(o.reloadPending, rid)
+		o.change = true
+	}
+
+	reloadRids := make([]string, 0)
+	restartRids := make([]string, 0)
+	for rid := range o.reloadPending {
+		rcfg, ok := o.instConfig.Resources[rid]
+		if !ok || rcfg.IsDisabled {
+			apply(rid)
+			continue
+		}
+		if !isResourceUp(instStatus, rid) {
+			// the resource will consume the new values on next start
+			o.log.Debug().Msgf("resource %s reload skip: not up", rid)
+			apply(rid)
+			continue
+		}
+		switch rcfg.ReloadPolicy {
+		case instance.ReloadPolicySignal, instance.ReloadPolicyCommand:
+			reloadRids = append(reloadRids, rid)
+		case instance.ReloadPolicyRollingRestart:
+			if o.isRollingRestartTurn(rid) {
+				restartRids = append(restartRids, rid)
+			}
+		default:
+			apply(rid)
+		}
+	}
+
+	switch {
+	case len(reloadRids) > 0:
+		sort.Strings(reloadRids)
+		action := func() error {
+			if err := o.crmResourceReload(reloadRids); err != nil {
+				// keep the reload pending, to retry
+				return err
+			}
+			for _, rid := range reloadRids {
+				apply(rid)
+			}
+			return nil
+		}
+		o.doAction(action, instance.MonitorStateReloading, instance.MonitorStateIdle, instance.MonitorStateIdle)
+	case len(restartRids) > 0:
+		if o.hasOtherNodeActing() {
+			return
+		}
+		sort.Strings(restartRids)
+		action := func() error {
+			if err := o.crmResourceRestart(restartRids); err != nil {
+				// keep the restart pending, to retry. The peers
+				// wait for this node in the rolling restart order.
+				return err
+			}
+			for _, rid := range restartRids {
+				apply(rid)
+			}
+			return nil
+		}
+		o.doAction(action, instance.MonitorStateReloading, instance.MonitorStateIdle, instance.MonitorStateIdle)
+	}
+}
+
+// isRollingRestartTurn returns true if all the scope nodes ordered before
+// the local node, and running the resource, have already restarted it with
+// the pending sec and cfg key values.
+func (o *imon) isRollingRestartTurn(rid string) bool {
+	csum := o.reloadPending[rid]
+	for _, node := range o.scopeNodes {
+		if node == o.localhost {
+			return true
+		}
+		if instStatus, ok := o.instStatus[node]; !ok || !isResourceUp(instStatus, rid) {
+			continue
+		}
+		instMonitor, ok := o.instMonitor[node]
+		if !ok {
+			continue
+		}
+		if peerCsum, _ := instMonitor.Resources.GetReloadCsum(rid); peerCsum != csum {
+			o.log.Debug().Msgf("resource %s rolling restart: wait for node %s", rid, node)
+			return false
+		}
+	}
+	return true
+}
+
+func isResourceUp(instStatus instance.Status, rid string) bool {
+	for _, r := range instStatus.Resources {
+		if r.Rid == rid {
+			return r.Status.Is(status.Up, status.StandbyUp)
+		}
+	}
+	return false
+}
+
+func hasReloadPolicy(rcfg instance.ResourceConfig) bool {
+	switch rcfg.ReloadPolicy {
+	case "", instance.ReloadPolicyNone:
+		return false
+	default:
+		return true
+	}
+}
+
+// reloadReferences returns true if the resource environment references
+// keys of the sec or cfg object p.
+func reloadReferences(rcfg instance.ResourceConfig, p path.T) bool {
+	var items []string
+	switch p.Kind {
+	case kind.Sec:
+		items = rcfg.SecretsEnv
+	case kind.Cfg:
+		items = rcfg.ConfigsEnv
+	}
+	for _, item := range items {
+		if i := strings.Index(item, "="); i >= 0 {
+			item = item[i+1:]
+		}
+		if name := strings.SplitN(item, "/", 2)[0]; name == p.Name {
+			return true
+		}
+	}
+	return false
+}
+
+// reloadCsum returns the checksum of the sec and cfg key values consumed
+// by the resource environment.
+func reloadCsum(namespace string, rcfg instance.ResourceConfig) (string, error) {
+	l := make([]string, 0)
+	if env, err := envprovider.From(rcfg.ConfigsEnv, namespace, "cfg"); err != nil {
+		return "", err
+	} else {
+		l = append(l, env...)
+	}
+	if env, err := envprovider.From(rcfg.SecretsEnv, namespace, "sec"); err != nil {
+		return "", err
+	} else {
+		l = append(l, env...)
+	}
+	sort.Strings(l)
+	h := md5.New()
+	for _, s := range l {
+		h.Write([]byte(s))
+		h.Write([]byte{0})
+	}
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
@@ -0,0 +1,105 @@
+package imon
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+)
+
+// recordingPublisher records the instance monitors published to the peers.
+type recordingPublisher struct {
+	published []instance.Monitor
+}
+
+func (t *recordingPublisher) SetInstanceMonitor(_ path.T, v instance.Monitor) error {
+	t.published = append(t.published, v)
+	return nil
+}
+
+// lastReloadCsum returns the reload checksum of rid in the last published
+// instance monitor.
+func (t *recordingPublisher) lastReloadCsum(rid string) string {
+	if len(t.published) == 0 {
+		return ""
+	}
+	csum, _ := t.published[len(t.published)-1].Resources.GetReloadCsum(rid)
+	return csum
+}
+
+func newReloadImon(t *testing.T, policy instance.ReloadPolicy, crmErr error) (*imon, *[]string) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+	calls := make([]string, 0)
+	o := &imon{
+		path:      p,
+		log:       zerolog.Nop(),
+		localhost: "node1",
+		cmdC:      make(chan any, 10),
+		publisher: &recordingPublisher{},
+		history:   discardMonitor{},
+		state: instance.Monitor{
+			State: instance.MonitorStateIdle,
+			Resources: instance.ResourceMonitorMap{
+				"app#1": {Reload: instance.ResourceMonitorReload{Csum: "old"}},
+			},
+		},
+		instConfig: instance.Config{
+			Resources: map[string]instance.ResourceConfig{
+				"app#1": {ReloadPolicy: policy},
+			},
+		},
+		instStatus: map[string]instance.Status{
+			"node1": {Resources: []resource.ExposedStatus{{Rid: "app#1", Status: status.Up}}},
+		},
+		instMonitor:   make(map[string]instance.Monitor),
+		scopeNodes:    []string{"node1"},
+		reloadPending: map[string]string{"app#1": "new"},
+		actionHook: func(title string, cmdArgs ...string) error {
+			calls = append(calls, title)
+			return crmErr
+		},
+	}
+	return o, &calls
+}
+
+func TestOrchestrateResourceReload(t *testing.T) {
+	for _, policy := range []instance.ReloadPolicy{instance.ReloadPolicySignal, instance.ReloadPolicyRollingRestart} {
+		t.Run(string(policy)+" success applies the checksum", func(t *testing.T) {
+			o, calls := newReloadImon(t, policy, nil)
+			o.orchestrateResourceReload()
+			assert.Len(t, *calls, 1)
+			csum, _ := o.state.Resources.GetReloadCsum("app#1")
+			assert.Equal(t, "new", csum)
+			assert.Equal(t, "new", o.publisher.(*recordingPublisher).lastReloadCsum("app#1"))
+			assert.Empty(t, o.reloadPending)
+		})
+
+		t.Run(string(policy)+" failure keeps the reload pending", func(t *testing.T) {
+			o, calls := newReloadImon(t, policy, errors.New("failed"))
+			o.orchestrateResourceReload()
+			assert.Len(t, *calls, 1)
+			csum, _ := o.state.Resources.GetReloadCsum("app#1")
+			assert.Equal(t, "old", csum, "peers must not consider the failed reload done")
+			assert.Equal(t, "old", o.publisher.(*recordingPublisher).lastReloadCsum("app#1"))
+			assert.Equal(t, map[string]string{"app#1": "new"}, o.reloadPending)
+		})
+	}
+}
+
+func TestInitResourceMonitorPreservesPendingReload(t *testing.T) {
+	o, _ := newReloadImon(t, instance.ReloadPolicySignal, nil)
+	o.instConfig.Resources["app#2"] = instance.ResourceConfig{ReloadPolicy: instance.ReloadPolicySignal}
+	o.reloadPending["gone#1"] = "new"
+	o.initResourceMonitor()
+	assert.Equal(t, map[string]string{"app#1": "new"}, o.reloadPending)
+	csum, _ := o.state.Resources.GetReloadCsum("app#1")
+	assert.Equal(t, "old", csum)
+}
@@ -19,6 +19,11 @@ func (o *imon) orchestrateStarted() {
 		o.startedFromThawed()
 	case instance.MonitorStateReady:
 		o.startedFromReady()
+	case instance.MonitorStateWaitParents:
+		o.startedFromWaitParents()
+	case instance.MonitorStateWaitChildren:
+		o.clearWaitRelations()
+		o.startedFromIdle()
 	case instance.MonitorStateStartFailed:
 		o.startedFromStartFailed()
 	case instance.MonitorStateStarting:
@@ -52,6 +57,7 @@ func (o *imon) startedFromIdle() {
 // local started => unset global expect, set local expect started
 // objectStatus.Avail Up => unset global expect, unset local expect
 // better candidate => no actions
+// parents not up => state -> wait parents
 // else => state -> ready, start ready routine
 func (o *imon) startedFromThawed() {
 	if o.startedClearIfReached() {
@@ -64,6 +70,9 @@ func (o *imon) startedFromThawed() {
 		o.log.Debug().Msg("another node acting")
 		return
 	}
+	if o.waitParents() {
+		return
+	}
 	o.transitionTo(instance.MonitorStateReady)
 	o.createPendingWithDuration(o.readyDuration)
 	go func(ctx context.Context) {
@@ -112,13 +121,29 @@ func (o *imon) startedFromReady() {
 			o.transitionTo(instance.MonitorStateIdle)
 			return
 		}
-		o.doAction(o.crmStart, instance.MonitorStateStarting, instance.MonitorStateIdle, instance.MonitorStateStartFailed)
+		if o.waitParents() {
+			return
+		}
+		o.doAction(o.startAction, instance.MonitorStateStarting, instance.MonitorStateIdle, instance.MonitorStateStartFailed)
 		return
 	default:
 		return
 	}
 }
 
+// startedFromWaitParents leaves the wait parents state to the ready state
+// when all parents are up.
+func (o *imon) startedFromWaitParents() {
+	if o.startedClearIfReached() {
+		return
+	}
+	if o.waitParents() {
+		return
+	}
+	o.transitionTo(instance.MonitorStateIdle)
+	o.startedFromThawed()
+}
+
 func (o *imon) startedFromAny() {
 	if o.pendingCancel == nil {
 		o.startedClearIfReached()
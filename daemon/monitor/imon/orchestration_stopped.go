@@ -23,6 +23,11 @@ func (o *imon) freezeStop() {
 		o.doStop()
 	case instance.MonitorStateReady:
 		o.stoppedFromReady()
+	case instance.MonitorStateWaitChildren:
+		o.doStop()
+	case instance.MonitorStateWaitParents:
+		o.clearWaitRelations()
+		o.doFreezeStop()
 	case instance.MonitorStateFreezing:
 		// wait for the freeze exec to end
 	case instance.MonitorStateStopping:
@@ -40,11 +45,27 @@ func (o *imon) freezeStop() {
 // This func must be called by orchestrations that know the ha auto-start will
 // not starts it back (ex: auto-stop), or that want the restart (ex: restart).
 func (o *imon) stop() {
+	o.stopWith(o.doStop)
+}
+
+// stopInstance stops the local instance but does not freeze, nor stop the
+// children. This func must be called by orchestrations stopping an instance
+// while the other instances keep the object up (ex: flex scale-down).
+func (o *imon) stopInstance() {
+	o.stopWith(o.doStopInstance)
+}
+
+func (o *imon) stopWith(doStop func()) {
 	switch o.state.State {
 	case instance.MonitorStateIdle:
-		o.doStop()
+		doStop()
 	case instance.MonitorStateReady:
 		o.stoppedFromReady()
+	case instance.MonitorStateWaitChildren:
+		doStop()
+	case instance.MonitorStateWaitParents:
+		o.clearWaitRelations()
+		doStop()
 	case instance.MonitorStateFrozen:
 		// honor the frozen state
 	case instance.MonitorStateFreezing:
@@ -84,7 +105,20 @@ func (o *imon) doFreeze() {
 	}
 }
 
+// doStop stops the local instance, after its children are down.
 func (o *imon) doStop() {
+	if o.stoppedClearIfReached() {
+		return
+	}
+	if o.waitChildren() {
+		return
+	}
+	o.createPendingWithDuration(stopDuration)
+	o.doAction(o.crmStop, instance.MonitorStateStopping, instance.MonitorStateIdle, instance.MonitorStateStopFailed)
+}
+
+// doStopInstance stops the local instance, without waiting for its children.
+func (o *imon) doStopInstance() {
 	if o.stoppedClearIfReached() {
 		return
 	}
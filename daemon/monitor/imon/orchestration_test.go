@@ -0,0 +1,53 @@
+package imon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/status"
+)
+
+func TestOrchestrateEvacuating(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	newEvacuatingImon := func(t *testing.T, localAvail, peerAvail status.T) (*imon, func() []string) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{
+			"node1": localAvail,
+			"node2": peerAvail,
+		})
+		o := newSimulatedImon(clusterStatus, p, "node1")
+		require.NotNil(t, o)
+		ctx, cancel := context.WithCancel(context.Background())
+		t.Cleanup(cancel)
+		o.ctx = ctx
+		o.nodeMonitor["node1"] = node.Monitor{State: node.MonitorStateEvacuating}
+		o.state.IsLeader = o.newIsLeader()
+		o.state.IsHALeader = o.newIsHALeader()
+		return o, recordActions(o)
+	}
+
+	t.Run("the ha start is not orchestrated", func(t *testing.T) {
+		o, calls := newEvacuatingImon(t, status.Down, status.Down)
+		o.objStatus.Avail = status.Down
+		require.True(t, o.state.IsHALeader)
+		o.orchestrate()
+		assert.Empty(t, calls())
+		assert.Equal(t, instance.MonitorStateIdle, o.state.State)
+	})
+
+	t.Run("the placed@ stop is orchestrated", func(t *testing.T) {
+		o, calls := newEvacuatingImon(t, status.Up, status.Down)
+		o.state.GlobalExpect = instance.MonitorGlobalExpectPlacedAt
+		o.state.GlobalExpectOptions = instance.MonitorGlobalExpectOptionsPlacedAt{Destination: []string{"node2"}}
+		o.state.State = instance.MonitorStateThawed
+		o.orchestrate()
+		assert.Equal(t, []string{"stop svc1 stop --local"}, calls())
+	})
+}
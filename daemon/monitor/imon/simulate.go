@@ -0,0 +1,325 @@
+package imon
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/placement"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+var (
+	// simulationTimeout is the maximum duration of the orchestration
+	// simulation of an instance.
+	simulationTimeout = 5 * time.Second
+
+	// simulationMaxSteps is the maximum number of orchestration runs
+	// of the simulation of an instance.
+	simulationMaxSteps = 10
+)
+
+type (
+	// SimulationChange is a hypothetical change of the cluster data applied
+	// before simulating the orchestration decisions.
+	SimulationChange struct {
+		// NodesDown are the nodes considered lost: their instances are
+		// down and they are not candidates.
+		NodesDown []string
+
+		// NodesFrozen are the nodes considered frozen.
+		NodesFrozen []string
+
+		// Placement, if set, replaces the placement policy of the
+		// simulated objects.
+		Placement string
+
+		// FlexTarget, if set, replaces the flex target of the simulated
+		// flex objects.
+		FlexTarget *int
+	}
+
+	// Simulation is the orchestration decisions the daemons would take
+	// for an object.
+	Simulation struct {
+		Path       path.T             `json:"path"`
+		Avail      status.T           `json:"avail"`
+		Leaders    []string           `json:"leaders"`
+		Candidates []string           `json:"candidates"`
+		Actions    []SimulationAction `json:"actions"`
+	}
+
+	// SimulationAction is the orchestration decision of an instance.
+	SimulationAction struct {
+		Node       string `json:"node"`
+		IsLeader   bool   `json:"is_leader"`
+		IsHALeader bool   `json:"is_ha_leader"`
+
+		// Action is the comma separated actions the instance monitor
+		// would run, "wait" if it would wait for its relations, or
+		// "none".
+		Action string `json:"action"`
+		Reason string `json:"reason"`
+	}
+)
+
+// Apply applies the hypothetical change to the cluster data.
+func (t SimulationChange) Apply(clusterStatus *cluster.Status) error {
+	var policy placement.Policy
+	if t.Placement != "" {
+		policy = placement.NewPolicy(t.Placement)
+		if policy == placement.Invalid {
+			return fmt.Errorf("invalid placement policy %s (supported: %s)", t.Placement, strings.Join(placement.PolicyNames(), ", "))
+		}
+	}
+	if t.FlexTarget != nil && *t.FlexTarget < 0 {
+		return fmt.Errorf("invalid flex target %d", *t.FlexTarget)
+	}
+	for _, nodename := range t.NodesFrozen {
+		nodeData, ok := clusterStatus.Cluster.Node[nodename]
+		if !ok {
+			return fmt.Errorf("unknown node %s", nodename)
+		}
+		nodeData.Status.Frozen = time.Now()
+		clusterStatus.Cluster.Node[nodename] = nodeData
+	}
+	down := make(map[string]any)
+	for _, nodename := range t.NodesDown {
+		if _, ok := clusterStatus.Cluster.Node[nodename]; !ok {
+			return fmt.Errorf("unknown node %s", nodename)
+		}
+		down[nodename] = nil
+	}
+	for ps, objStatus := range clusterStatus.Cluster.Object {
+		if policy != placement.Invalid {
+			objStatus.PlacementPolicy = policy
+		}
+		if t.FlexTarget != nil && objStatus.Topology == topology.Flex {
+			objStatus.FlexTarget = *t.FlexTarget
+		}
+		if len(down) > 0 {
+			objStatus = simulatedObjectStatus(clusterStatus, ps, objStatus, down)
+		}
+		clusterStatus.Cluster.Object[ps] = objStatus
+	}
+	for nodename := range down {
+		// a lost node has no status, so it is not a candidate
+		delete(clusterStatus.Cluster.Node, nodename)
+	}
+	return nil
+}
+
+// simulatedObjectStatus returns the object status aggregated without the
+// instances of the lost nodes.
+func simulatedObjectStatus(clusterStatus *cluster.Status, ps string, objStatus object.Status, down map[string]any) object.Status {
+	var up, warn, n int
+	for nodename, nodeData := range clusterStatus.Cluster.Node {
+		inst, ok := nodeData.Instance[ps]
+		if !ok || inst.Status == nil {
+			continue
+		}
+		n++
+		if _, ok := down[nodename]; ok {
+			continue
+		}
+		switch inst.Status.Avail {
+		case status.Up:
+			up++
+		case status.Warn:
+			warn++
+		}
+	}
+	objStatus.UpInstancesCount = up
+	switch {
+	case n == 0:
+	case warn > 0:
+		objStatus.Avail = status.Warn
+	case up == 0:
+		objStatus.Avail = status.Down
+	case objStatus.Topology == topology.Flex && (up < objStatus.FlexMin || up > objStatus.FlexMax):
+		objStatus.Avail = status.Warn
+	case objStatus.Topology == topology.Failover && up > 1:
+		objStatus.Avail = status.Warn
+	default:
+		objStatus.Avail = status.Up
+	}
+	return objStatus
+}
+
+// Simulate returns the orchestration decisions the instance monitors of the
+// object would take with the cluster data. The instance monitors run their
+// orchestration with the actions recorded instead of executed.
+func Simulate(clusterStatus *cluster.Status, p path.T) Simulation {
+	ps := p.String()
+	objStatus := clusterStatus.Cluster.Object[ps]
+	result := Simulation{
+		Path:       p,
+		Avail:      objStatus.Avail,
+		Leaders:    make([]string, 0),
+		Candidates: make([]string, 0),
+		Actions:    make([]SimulationAction, 0),
+	}
+	for _, nodename := range objStatus.Scope {
+		o := newSimulatedImon(clusterStatus, p, nodename)
+		if o == nil {
+			result.Actions = append(result.Actions, SimulationAction{
+				Node:   nodename,
+				Action: "none",
+				Reason: "node is down",
+			})
+			continue
+		}
+		if len(result.Candidates) == 0 {
+			result.Candidates = o.sortCandidates(o.haCandidates())
+		}
+		o.state.IsLeader = o.newIsLeader()
+		o.state.IsHALeader = o.newIsHALeader()
+		if o.state.IsHALeader {
+			result.Leaders = append(result.Leaders, nodename)
+		}
+		result.Actions = append(result.Actions, o.simulate())
+	}
+	return result
+}
+
+// simulate runs the orchestration of the simulated instance, with the
+// actions recorded by the action hook instead of executed, and returns the
+// orchestration decision. The orchestration runs again after each monitor
+// state transition, until an action is recorded or the state settles. The
+// ready period is zero, so a ready instance decides its start in the
+// simulation.
+func (o *imon) simulate() SimulationAction {
+	ctx, cancel := context.WithTimeout(context.Background(), simulationTimeout)
+	defer cancel()
+	o.ctx = ctx
+	actions := make([]string, 0)
+	o.actionHook = func(title string, cmdArgs ...string) error {
+		if strings.HasSuffix(title, " child") {
+			title += " " + strings.Join(cmdArgs, " ")
+		}
+		actions = append(actions, title)
+		return nil
+	}
+	initialState := o.state.State
+
+loop:
+	for step := 0; len(actions) == 0 && step < simulationMaxSteps; step++ {
+		previousState := o.state.State
+		if o.state.State == instance.MonitorStateReady && o.pendingCtx != nil {
+			// wait for the ready period expire
+			select {
+			case i := <-o.cmdC:
+				if c, ok := i.(cmdOrchestrate); ok {
+					o.needOrchestrate(c)
+				}
+			case <-ctx.Done():
+				break loop
+			}
+		} else {
+			o.orchestrate()
+		}
+		if o.state.State == previousState {
+			break
+		}
+	}
+	o.clearPending()
+	if o.failbackTimer != nil {
+		o.failbackTimer.Stop()
+	}
+
+	result := SimulationAction{
+		Node:       o.localhost,
+		IsLeader:   o.state.IsLeader,
+		IsHALeader: o.state.IsHALeader,
+		Action:     "none",
+	}
+	switch {
+	case len(actions) > 0:
+		result.Action = strings.Join(actions, ", ")
+	case o.state.State == instance.MonitorStateWaitParents, o.state.State == instance.MonitorStateWaitChildren:
+		result.Action = "wait"
+	}
+	switch {
+	case o.state.State == instance.MonitorStateWaitParents:
+		result.Reason = "parents not up: " + strings.Join(o.parentsNotUp(), ", ")
+	case o.state.State == instance.MonitorStateWaitChildren:
+		result.Reason = "children not down: " + strings.Join(o.childrenNotDown(), ", ")
+	case o.state.State != initialState:
+		result.Reason = fmt.Sprintf("monitor state %s -> %s", initialState, o.state.State)
+	default:
+		result.Reason = fmt.Sprintf("monitor state %s, global expect %s", o.state.State, o.state.GlobalExpect)
+	}
+	return result
+}
+
+// newSimulatedImon returns an instance monitor of the object on nodename,
+// loaded with the cluster data without the resource monitors, or nil if
+// nodename has no data.
+func newSimulatedImon(clusterStatus *cluster.Status, p path.T, nodename string) *imon {
+	ps := p.String()
+	if _, ok := clusterStatus.Cluster.Node[nodename]; !ok {
+		return nil
+	}
+	o := &imon{
+		path:         p,
+		cmdC:         make(chan any, 10),
+		publisher:    discardMonitor{},
+		history:      discardMonitor{},
+		log:          zerolog.Nop(),
+		localhost:    nodename,
+		objStatus:    clusterStatus.Cluster.Object[ps],
+		instStatus:   make(map[string]instance.Status),
+		instMonitor:  make(map[string]instance.Monitor),
+		nodeMonitor:  make(map[string]node.Monitor),
+		nodeStats:    make(map[string]node.Stats),
+		nodeStatus:   make(map[string]node.Status),
+		relObjStatus: clusterStatus.Cluster.Object,
+		relInstAvail: make(map[string]status.T),
+		isSimulation: true,
+		state: instance.Monitor{
+			GlobalExpect: instance.MonitorGlobalExpectUnset,
+			LocalExpect:  instance.MonitorLocalExpectUnset,
+			State:        instance.MonitorStateIdle,
+		},
+	}
+	o.scopeNodes = append([]string{}, o.objStatus.Scope...)
+	for peer, nodeData := range clusterStatus.Cluster.Node {
+		o.nodeMonitor[peer] = nodeData.Monitor
+		o.nodeStats[peer] = nodeData.Stats
+		o.nodeStatus[peer] = nodeData.Status
+		for relPath, inst := range nodeData.Instance {
+			if inst.Status != nil {
+				o.relInstAvail[relationInstanceKey(relPath, peer)] = inst.Status.Avail
+			}
+		}
+		inst, ok := nodeData.Instance[ps]
+		if !ok {
+			continue
+		}
+		if inst.Status != nil {
+			o.instStatus[peer] = *inst.Status
+		}
+		if inst.Monitor == nil {
+			continue
+		}
+		// the resource monitors share the restart timers of the daemon
+		// instance monitors, so they are not loaded.
+		mon := *inst.Monitor
+		mon.Resources = make(instance.ResourceMonitorMap)
+		if peer == nodename {
+			o.state = mon
+		} else {
+			o.instMonitor[peer] = mon
+		}
+	}
+	return o
+}
@@ -0,0 +1,135 @@
+package imon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/placement"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+func newSimulationClusterStatus(avails map[string]status.T) *cluster.Status {
+	nodes := make(map[string]node.Node)
+	for nodename, avail := range avails {
+		nodes[nodename] = node.Node{
+			Monitor: node.Monitor{State: node.MonitorStateIdle},
+			Instance: map[string]instance.Instance{
+				"svc1": {
+					Status: &instance.Status{Avail: avail, Provisioned: provisioned.True},
+					Monitor: &instance.Monitor{
+						GlobalExpect: instance.MonitorGlobalExpectUnset,
+						State:        instance.MonitorStateIdle,
+					},
+				},
+			},
+		}
+	}
+	return &cluster.Status{
+		Cluster: cluster.Cluster{
+			Node: nodes,
+			Object: map[string]object.Status{
+				"svc1": {
+					Avail:            status.Up,
+					Orchestrate:      "ha",
+					PlacementPolicy:  placement.NodesOrder,
+					Provisioned:      provisioned.True,
+					Scope:            []string{"node1", "node2"},
+					Topology:         topology.Failover,
+					UpInstancesCount: 1,
+				},
+			},
+		},
+	}
+}
+
+func simulationActions(result Simulation) map[string]string {
+	m := make(map[string]string)
+	for _, action := range result.Actions {
+		m[action.Node] = action.Action
+	}
+	return m
+}
+
+func TestSimulate(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+
+	t.Run("no change", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up, "node2": status.Down})
+		result := Simulate(clusterStatus, p)
+		assert.Equal(t, []string{"node1", "node2"}, result.Candidates)
+		assert.Equal(t, []string{"node1"}, result.Leaders)
+		assert.Equal(t, map[string]string{"node1": "none", "node2": "none"}, simulationActions(result))
+	})
+
+	t.Run("leader node down", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up, "node2": status.Down})
+		require.NoError(t, SimulationChange{NodesDown: []string{"node1"}}.Apply(clusterStatus))
+		result := Simulate(clusterStatus, p)
+		assert.Equal(t, status.Down, result.Avail)
+		assert.Equal(t, []string{"node2"}, result.Candidates)
+		assert.Equal(t, []string{"node2"}, result.Leaders)
+		assert.Equal(t, map[string]string{"node1": "none", "node2": "start"}, simulationActions(result))
+	})
+
+	t.Run("all nodes frozen", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Down, "node2": status.Down})
+		clusterStatus.Cluster.Object["svc1"] = func(o object.Status) object.Status {
+			o.Avail = status.Down
+			return o
+		}(clusterStatus.Cluster.Object["svc1"])
+		require.NoError(t, SimulationChange{NodesFrozen: []string{"node1", "node2"}}.Apply(clusterStatus))
+		result := Simulate(clusterStatus, p)
+		assert.Empty(t, result.Candidates)
+		assert.Empty(t, result.Leaders)
+		assert.Equal(t, map[string]string{"node1": "none", "node2": "none"}, simulationActions(result))
+	})
+
+	t.Run("invalid change", func(t *testing.T) {
+		clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up})
+		assert.Error(t, SimulationChange{NodesDown: []string{"node3"}}.Apply(clusterStatus))
+		assert.Error(t, SimulationChange{Placement: "foo"}.Apply(clusterStatus))
+	})
+}
+
+func TestSimulatePlacedAt(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+	clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up, "node2": status.Down})
+	for nodename, nodeData := range clusterStatus.Cluster.Node {
+		inst := nodeData.Instance["svc1"]
+		inst.Monitor.GlobalExpect = instance.MonitorGlobalExpectPlacedAt
+		inst.Monitor.GlobalExpectOptions = instance.MonitorGlobalExpectOptionsPlacedAt{Destination: []string{"node2"}}
+		nodeData.Instance["svc1"] = inst
+		clusterStatus.Cluster.Node[nodename] = nodeData
+	}
+	result := Simulate(clusterStatus, p)
+	assert.Equal(t, map[string]string{"node1": "stop", "node2": "none"}, simulationActions(result))
+}
+
+func TestSimulateKeepsRestartTimers(t *testing.T) {
+	p, err := path.Parse("svc1")
+	require.NoError(t, err)
+	clusterStatus := newSimulationClusterStatus(map[string]status.T{"node1": status.Up, "node2": status.Down})
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	nodeData := clusterStatus.Cluster.Node["node1"]
+	nodeData.Instance["svc1"].Status.Resources = []resource.ExposedStatus{{Rid: "app#1", Status: status.Down}}
+	nodeData.Instance["svc1"].Monitor.Resources = instance.ResourceMonitorMap{
+		"app#1": {Restart: instance.ResourceMonitorRestart{Remaining: 1, Timer: timer}},
+	}
+	require.NoError(t, SimulationChange{NodesFrozen: []string{"node1"}}.Apply(clusterStatus))
+	Simulate(clusterStatus, p)
+	assert.True(t, timer.Stop(), "the simulation stopped the daemon restart timer")
+}
@@ -58,7 +58,14 @@ type (
 		cmdC         chan any
 		databus      *daemondata.T
 		sub          *pubsub.Subscription
+
+		// keyActivationTimer fires at the next activation time of the
+		// keystore key versions pending activation.
+		keyActivationTimer *time.Timer
 	}
+
+	// cmdActivateKeyVersions is sent by the keyActivationTimer.
+	cmdActivateKeyVersions struct{}
 )
 
 var (
@@ -68,6 +75,8 @@ var (
 
 	configFileCheckError = errors.New("config file check")
 
+	keyFailback      = key.New("DEFAULT", "failback")
+	keyFailbackDelay = key.New("DEFAULT", "failback_delay")
 	keyFlexMax       = key.New("DEFAULT", "flex_max")
 	keyFlexMin       = key.New("DEFAULT", "flex_min")
 	keyFlexTarget    = key.New("DEFAULT", "flex_target")
@@ -156,6 +165,8 @@ func (o *T) worker(parent context.Context) {
 		return
 	}
 	defer o.delete()
+	defer o.stopKeyActivationTimer()
+	o.scheduleKeyActivation(parent)
 
 	imonCtx, cancelSmon := context.WithCancel(parent)
 	defer cancelSmon()
@@ -176,6 +187,7 @@ func (o *T) worker(parent context.Context) {
 					o.log.Error().Err(err).Msg("configFileCheck error")
 					return
 				}
+				o.scheduleKeyActivation(parent)
 				if !hasSmon {
 					o.log.Info().Msgf("imon not yet started, try start")
 					if hasSmon, err = o.startSmon(imonCtx); err != nil {
@@ -201,6 +213,9 @@ func (o *T) worker(parent context.Context) {
 			}
 		case i := <-o.cmdC:
 			switch i.(type) {
+			case cmdActivateKeyVersions:
+				o.onActivateKeyVersions()
+				o.scheduleKeyActivation(parent)
 			case msgbus.Exit:
 				log.Debug().Msg("eat poison pill")
 				return
@@ -294,6 +309,12 @@ func (o *T) configFileCheck() error {
 		cfg.FlexMin = o.getFlexMin(cf)
 		cfg.FlexMax = o.getFlexMax(cf)
 	}
+	if cfg.Topology == topology.Failover {
+		cfg.Failback = o.getFailback(cf)
+		cfg.FailbackDelay = o.getFailbackDelay(cf)
+	} else if cf.HasKey(keyFailback) {
+		o.log.Warn().Msgf("ignore failback: not supported with topology %s", cfg.Topology)
+	}
 
 	o.lastMtime = mtime
 	o.updateConfig(&cfg)
@@ -321,6 +342,29 @@ func (o *T) getScope(cf *xconfig.T) (scope []string, err error) {
 	return
 }
 
+// getFailback returns the failback schedule expression, or an empty string
+// disabling the failback if the expression is not valid. The config is
+// evaluated on change only, so the invalid expression is reported once
+// instead of on each failback orchestration.
+func (o *T) getFailback(cf *xconfig.T) string {
+	s := cf.GetString(keyFailback)
+	if s == "" {
+		return ""
+	}
+	if err := imon.ValidateFailback(s); err != nil {
+		o.log.Error().Err(err).Msg("disable failback")
+		return ""
+	}
+	return s
+}
+
+func (o *T) getFailbackDelay(cf *xconfig.T) time.Duration {
+	if d := cf.GetDuration(keyFailbackDelay); d != nil {
+		return *d
+	}
+	return 0
+}
+
 func (o *T) getMonitorAction(cf *xconfig.T) instance.MonitorAction {
 	s := cf.GetString(keyMonitorAction)
 	return instance.MonitorAction(s)
@@ -353,6 +397,9 @@ func (o *T) getResources(cf *xconfig.T) map[string]instance.ResourceConfig {
 			Restart:      cf.GetInt(key.New(section, "restart")),
 			IsDisabled:   cf.GetBool(key.New(section, "disable")),
 			IsMonitored:  cf.GetBool(key.New(section, "monitor")),
+			ReloadPolicy: instance.ReloadPolicy(cf.GetString(key.New(section, "reload_policy"))),
+			SecretsEnv:   cf.GetStrings(key.New(section, "secrets_environment")),
+			ConfigsEnv:   cf.GetStrings(key.New(section, "configs_environment")),
 		}
 	}
 	return m
@@ -394,6 +441,61 @@ func (o *T) getFlexMax(cf *xconfig.T) int {
 	}
 }
 
+// scheduleKeyActivation arms the timer firing at the next activation time
+// of the key versions pending activation of a keystore object.
+func (o *T) scheduleKeyActivation(ctx context.Context) {
+	o.stopKeyActivationTimer()
+	switch o.path.Kind {
+	case kind.Sec, kind.Cfg, kind.Usr:
+	default:
+		return
+	}
+	ks, err := object.NewKeystore(o.path, object.WithVolatile(true))
+	if err != nil {
+		o.log.Warn().Err(err).Msg("schedule key versions activation")
+		return
+	}
+	next := ks.NextKeyVersionActivation()
+	if next.IsZero() {
+		return
+	}
+	o.log.Info().Msgf("next key version activation at %s", next)
+	o.keyActivationTimer = time.AfterFunc(time.Until(next), func() {
+		select {
+		case o.cmdC <- cmdActivateKeyVersions{}:
+		case <-ctx.Done():
+		}
+	})
+}
+
+func (o *T) stopKeyActivationTimer() {
+	if o.keyActivationTimer != nil {
+		o.keyActivationTimer.Stop()
+		o.keyActivationTimer = nil
+	}
+}
+
+// onActivateKeyVersions installs the activated key versions in the
+// volumes. The first node of the scope also syncs the data section of the
+// keystore, and commits so the change is replicated to the peer nodes.
+func (o *T) onActivateKeyVersions() {
+	ks, err := object.NewKeystore(o.path)
+	if err != nil {
+		o.log.Error().Err(err).Msg("activate key versions")
+		return
+	}
+	if len(o.cfg.Scope) > 0 && o.cfg.Scope[0] == o.localhost {
+		o.log.Info().Msg("activate key versions")
+		err = ks.ActivateKeyVersions()
+	} else {
+		o.log.Info().Msg("install activated key versions")
+		err = ks.InstallKey("")
+	}
+	if err != nil {
+		o.log.Error().Err(err).Msg("activate key versions")
+	}
+}
+
 func (o *T) setConfigure() error {
 	configure, err := object.NewConfigurer(o.path)
 	if err != nil {
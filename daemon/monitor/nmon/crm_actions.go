@@ -4,6 +4,8 @@ import (
 	"os"
 
 	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/osagentservice"
 	"opensvc.com/opensvc/util/command"
 )
 
@@ -37,6 +39,29 @@ func (o *nmon) crmUnfreeze() error {
 	return o.crmAction("node", "unfreeze", "--local")
 }
 
+func (o *nmon) crmUpgrade() error {
+	return o.crmAction("node", "upgrade", "--local")
+}
+
+// crmDaemonRestart asks an external supervisor to restart the daemon, as
+// the daemon can not wait for its own restart.
+func (o *nmon) crmDaemonRestart() error {
+	o.log.Info().Msgf("-> request daemon restart")
+	if err := osagentservice.Restart(cmdPath, env.DaemonOriginSetenvArg()); err != nil {
+		o.log.Error().Err(err).Msg("request daemon restart")
+		return err
+	}
+	return nil
+}
+
+func (o *nmon) crmUpgradeRollback() error {
+	n, err := object.NewNode(object.WithVolatile(true))
+	if err != nil {
+		return err
+	}
+	return n.UpgradeRollback()
+}
+
 func (o *nmon) crmAction(cmdArgs ...string) error {
 	var cmdEnv []string
 	cmdEnv = append(
@@ -30,6 +30,7 @@ import (
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
 
+	"opensvc.com/opensvc/core/instance"
 	"opensvc.com/opensvc/core/node"
 	"opensvc.com/opensvc/core/nodesinfo"
 	"opensvc.com/opensvc/core/object"
@@ -56,7 +57,10 @@ type (
 		databus      *daemondata.T
 		log          zerolog.Logger
 		rejoinTicker *time.Ticker
-		startedAt    time.Time
+
+		// maintenanceTimer fires when the maintenance expires
+		maintenanceTimer *time.Timer
+		startedAt        time.Time
 
 		pendingCtx    context.Context
 		pendingCancel context.CancelFunc
@@ -68,9 +72,31 @@ type (
 		localhost   string
 		change      bool
 
+		// trigger describes the event being handled, recorded in the
+		// node monitor history.
+		trigger string
+
+		// history records the node monitor transitions.
+		history *instance.MonitorHistoryWriter
+
+		// upgradeWait is the current wait for the local node upgrade
+		// turn, started at upgradeWaitSince. upgradeWaitCancel cancels
+		// the timeout of a timed wait.
+		upgradeWait       upgradeWait
+		upgradeWaitSince  time.Time
+		upgradeWaitCancel context.CancelFunc
+
 		sub *pubsub.Subscription
 	}
 
+	// upgradeWait describes why the local node waits for its upgrade turn.
+	// A timed wait, for a node to rejoin after its upgrade, aborts the
+	// rolling upgrade when it lasts longer than node.upgrade_timeout.
+	upgradeWait struct {
+		reason string
+		timed  bool
+	}
+
 	// cmdOrchestrate can be used from post action go routines
 	cmdOrchestrate struct {
 		state    node.MonitorState
@@ -92,6 +118,8 @@ func Start(parent context.Context) error {
 		log:           log.Logger.With().Str("func", "nmon").Logger(),
 		localhost:     hostname.Hostname(),
 		change:        true,
+		trigger:       "monitor start",
+		history:       instance.NewMonitorHistoryWriter(MonitorHistoryFile()),
 		nodeMonitor:   make(map[string]node.Monitor),
 	}
 
@@ -99,6 +127,8 @@ func Start(parent context.Context) error {
 		return err
 	} else {
 		o.config = n.MergedConfig()
+		o.state.Upgraded = n.Upgraded()
+		o.state.Maintenance = n.Maintenance()
 	}
 
 	o.startSubscriptions()
@@ -167,6 +197,10 @@ func (o *nmon) worker() {
 
 	o.startRejoin()
 
+	o.maintenanceTimer = time.NewTimer(time.Second)
+	o.armMaintenanceTimer()
+	defer o.maintenanceTimer.Stop()
+
 	statsTicker := time.NewTicker(10 * time.Second)
 	defer statsTicker.Stop()
 
@@ -177,35 +211,54 @@ func (o *nmon) worker() {
 		case i := <-o.sub.C:
 			switch c := i.(type) {
 			case msgbus.ConfigFileUpdated:
+				o.trigger = "config file update"
 				o.onConfigFileUpdated(c)
 			case msgbus.NodeConfigUpdated:
+				o.trigger = "node config update"
 				o.onNodeConfigUpdated(c)
 			case msgbus.NodeMonitorUpdated:
+				o.trigger = "node monitor update from node " + c.Node
 				o.onNodeMonitorUpdated(c)
 			case msgbus.NodeMonitorDeleted:
+				o.trigger = "node monitor deleted on node " + c.Node
 				o.onNodeMonitorDeleted(c)
 			case msgbus.FrozenFileRemoved:
+				o.trigger = "frozen file removed"
 				o.onFrozenFileRemoved(c)
 			case msgbus.FrozenFileUpdated:
+				o.trigger = "frozen file update"
 				o.onFrozenFileUpdated(c)
 			case msgbus.HbMessageTypeUpdated:
+				o.trigger = "heartbeat message type update"
 				o.onHbMessageTypeUpdated(c)
 			case msgbus.SetNodeMonitor:
+				o.trigger = "set node monitor request"
 				o.onSetNodeMonitor(c)
 			case msgbus.NodeStatusLabelsUpdated:
+				o.trigger = "node labels update"
 				o.onNodeStatusLabelsUpdated(c)
 			case msgbus.NodeOsPathsUpdated:
+				o.trigger = "node os paths update"
 				o.onNodeOsPathsUpdated(c)
 			}
 		case i := <-o.cmdC:
 			switch c := i.(type) {
 			case cmdOrchestrate:
+				o.trigger = "action result"
 				o.onOrchestrate(c)
+			case cmdEvacuationProgress:
+				o.trigger = "evacuation progress"
+				o.onEvacuationProgress(c)
 			}
 		case <-statsTicker.C:
+			o.trigger = "stats update"
 			o.updateStats()
 		case <-o.rejoinTicker.C:
+			o.trigger = "rejoin grace period expire"
 			o.onRejoinGracePeriodExpire()
+		case <-o.maintenanceTimer.C:
+			o.trigger = "maintenance expire"
+			o.onMaintenanceExpire()
 		}
 	}
 }
@@ -253,20 +306,44 @@ func (o *nmon) updateIfChange() {
 		return
 	}
 	o.change = false
-	o.state.StateUpdated = time.Now()
+	now := time.Now()
+	o.state.StateUpdated = now
 	previousVal := o.previousState
 	newVal := o.state
+	transitions := make(instance.MonitorHistory, 0)
+	addTransition := func(field, from, to string) {
+		transitions = append(transitions, instance.MonitorTransition{
+			At:      now,
+			Node:    o.localhost,
+			Field:   field,
+			From:    from,
+			To:      to,
+			Trigger: o.trigger,
+		})
+	}
 	if newVal.State != previousVal.State {
+		addTransition(instance.MonitorTransitionFieldState, previousVal.State.String(), newVal.State.String())
 		o.log.Info().Msgf("change monitor state %s -> %s", previousVal.State, newVal.State)
 	}
 	if newVal.GlobalExpect != previousVal.GlobalExpect {
+		addTransition(instance.MonitorTransitionFieldGlobalExpect, previousVal.GlobalExpect.String(), newVal.GlobalExpect.String())
 		o.log.Info().Msgf("change monitor global expect %s -> %s", previousVal.GlobalExpect, newVal.GlobalExpect)
 	}
 	if newVal.LocalExpect != previousVal.LocalExpect {
+		addTransition(instance.MonitorTransitionFieldLocalExpect, previousVal.LocalExpect.String(), newVal.LocalExpect.String())
 		o.log.Info().Msgf("change monitor local expect %s -> %s", previousVal.LocalExpect, newVal.LocalExpect)
 	}
 	o.previousState = o.state
 	o.update()
+	if err := o.history.Append(transitions...); err != nil {
+		o.log.Warn().Err(err).Msg("monitor history update")
+	}
+}
+
+// MonitorHistoryFile returns the path of the file recording the node monitor
+// transitions of the local node.
+func MonitorHistoryFile() string {
+	return filepath.Join(rawconfig.NodeVarDir(), "monitor_history.jsonl")
 }
 
 func (o *nmon) hasOtherNodeActing() bool {
@@ -144,9 +144,17 @@ func (o *nmon) onSetNodeMonitor(c msgbus.SetNodeMonitor) {
 		}
 	}
 
+	doMaintenance := func() {
+		if c.Value.Maintenance == nil {
+			return
+		}
+		o.setMaintenance(*c.Value.Maintenance)
+	}
+
 	doState()
 	doLocalExpect()
 	doGlobalExpect()
+	doMaintenance()
 
 	if o.change {
 		o.updateIfChange()
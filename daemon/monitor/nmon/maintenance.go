@@ -0,0 +1,52 @@
+package nmon
+
+import (
+	"time"
+
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+)
+
+// setMaintenance enters the maintenance m, or leaves the maintenance if m
+// is zero. The maintenance is recorded in the node var dir so the restarted
+// daemon restores it, and the expire timer is armed.
+func (o *nmon) setMaintenance(m node.Maintenance) {
+	if m == o.state.Maintenance {
+		return
+	}
+	if n, err := object.NewNode(object.WithVolatile(true)); err != nil {
+		o.log.Error().Err(err).Msg("maintenance: record")
+	} else if err := n.SetMaintenance(m); err != nil {
+		o.log.Error().Err(err).Msg("maintenance: record")
+	}
+	switch {
+	case m.IsZero():
+		o.log.Info().Msg("leave maintenance")
+	case m.Expire.IsZero():
+		o.log.Info().Msg("enter maintenance")
+	default:
+		o.log.Info().Msgf("enter maintenance until %s", m.Expire)
+	}
+	o.state.Maintenance = m
+	o.change = true
+	o.armMaintenanceTimer()
+}
+
+// armMaintenanceTimer resets the maintenance expire timer to the local
+// maintenance expire time, or stops it if the maintenance has no expire.
+func (o *nmon) armMaintenanceTimer() {
+	o.maintenanceTimer.Stop()
+	if o.state.Maintenance.IsZero() || o.state.Maintenance.Expire.IsZero() {
+		return
+	}
+	o.maintenanceTimer.Reset(time.Until(o.state.Maintenance.Expire))
+}
+
+func (o *nmon) onMaintenanceExpire() {
+	if o.state.Maintenance.IsZero() || o.state.Maintenance.IsActive(time.Now()) {
+		return
+	}
+	o.log.Info().Msgf("maintenance expired at %s", o.state.Maintenance.Expire)
+	o.setMaintenance(node.Maintenance{})
+	o.updateIfChange()
+}
@@ -18,6 +18,8 @@ func (o *nmon) orchestrate() {
 	case node.MonitorLocalExpectUnset:
 	case node.MonitorLocalExpectDrained:
 		o.orchestrateDrained()
+	case node.MonitorLocalExpectEvacuated:
+		o.orchestrateEvacuated()
 	}
 
 	switch o.state.GlobalExpect {
@@ -28,6 +30,8 @@ func (o *nmon) orchestrate() {
 		o.orchestrateFrozen()
 	case node.MonitorGlobalExpectThawed:
 		o.orchestrateThawed()
+	case node.MonitorGlobalExpectUpgraded:
+		o.orchestrateUpgraded()
 	}
 	o.updateIfChange()
 }
@@ -0,0 +1,287 @@
+package nmon
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/kind"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/placement"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+	"opensvc.com/opensvc/daemon/msgbus"
+	"opensvc.com/opensvc/util/key"
+	"opensvc.com/opensvc/util/pubsub"
+)
+
+type (
+	// cmdEvacuationProgress is sent by the evacuation go routines to
+	// update the node monitor evacuation report.
+	cmdEvacuationProgress struct {
+		path string
+		item node.EvacuationItem
+	}
+)
+
+var (
+	// evacuationPollInterval is the interval between two checks of the
+	// progress of a switch.
+	evacuationPollInterval = time.Second
+)
+
+// orchestrateEvacuated switches the local failover object instances to
+// their peer nodes, then freezes the node and shuts down the remaining
+// local instances like the drain.
+//
+// idle -> evacuating -> evacuated -> freezing -> frozen -> draining -> idle
+func (o *nmon) orchestrateEvacuated() {
+	switch o.state.State {
+	case node.MonitorStateIdle:
+		o.evacuateFromIdle()
+	case node.MonitorStateEvacuated:
+		o.drainFreezeFromIdle()
+	case node.MonitorStateFrozen:
+		o.drainFromIdle()
+	}
+}
+
+func (o *nmon) evacuateFromIdle() {
+	clusterStatus := o.databus.GetStatus()
+	if o.evacuatedClearIfReached(clusterStatus) {
+		return
+	}
+	o.doEvacuate(clusterStatus)
+}
+
+// doEvacuate transitions to evacuating and starts the switches of the local
+// failover object instances. The monitor state is evacuated when the
+// switches are done.
+func (o *nmon) doEvacuate(clusterStatus *cluster.Status) {
+	plan := o.evacuationPlan(clusterStatus)
+	concurrency := o.config.GetInt(key.New("node", "evacuation_concurrency"))
+	timeout := 10 * time.Minute
+	if d := o.config.GetDuration(key.New("node", "evacuation_timeout")); d != nil {
+		timeout = *d
+	}
+	o.change = true
+	o.state.Evacuation = plan
+	o.state.State = node.MonitorStateEvacuating
+	o.updateIfChange()
+	go func() {
+		o.log.Info().Msgf("evacuate %d objects, %d in parallel", len(plan), concurrency)
+		o.evacuate(plan, concurrency, timeout)
+		o.orchestrateAfterAction(node.MonitorStateEvacuating, node.MonitorStateEvacuated)
+	}()
+}
+
+// evacuatedClearIfReached unsets the evacuated local expect when the node is
+// frozen and no local object instance is up.
+func (o *nmon) evacuatedClearIfReached(clusterStatus *cluster.Status) bool {
+	nodeData, ok := clusterStatus.Cluster.Node[o.localhost]
+	if !ok || nodeData.Status.Frozen.IsZero() {
+		return false
+	}
+	for _, inst := range nodeData.Instance {
+		if inst.Status != nil && inst.Status.Avail.Is(status.Up, status.Warn) {
+			return false
+		}
+	}
+	o.log.Info().Msg("node is frozen and its instances are down, unset local expect")
+	o.change = true
+	o.state.LocalExpect = node.MonitorLocalExpectUnset
+	o.updateIfChange()
+	return true
+}
+
+// evacuationPlan returns the evacuation items of the local failover object
+// instances that are up, with the destination node selected by the object
+// placement policy.
+func (o *nmon) evacuationPlan(clusterStatus *cluster.Status) map[string]node.EvacuationItem {
+	plan := make(map[string]node.EvacuationItem)
+	now := time.Now()
+	for s, inst := range clusterStatus.Cluster.Node[o.localhost].Instance {
+		if inst.Config == nil || inst.Status == nil {
+			continue
+		}
+		if inst.Config.Topology != topology.Failover {
+			continue
+		}
+		if !inst.Status.Avail.Is(status.Up, status.Warn) {
+			continue
+		}
+		p, err := path.Parse(s)
+		if err != nil || p.Kind != kind.Svc {
+			continue
+		}
+		item := node.EvacuationItem{
+			State:   node.EvacuationStatePending,
+			Updated: now,
+		}
+		if item.Destination = o.evacuationDestination(clusterStatus, p, *inst.Config); item.Destination == "" {
+			item.State = node.EvacuationStateSkipped
+			item.Error = "no eligible destination node"
+		}
+		plan[s] = item
+	}
+	return plan
+}
+
+// evacuationDestination returns the peer node with the highest placement
+// priority amongst the idle and thawed nodes with a provisioned instance of
+// the object.
+func (o *nmon) evacuationDestination(clusterStatus *cluster.Status, p path.T, cfg instance.Config) string {
+	candidates := make([]string, 0)
+	sorter := placement.Sorter{
+		Key:              p.String(),
+		ScalerSliceIndex: p.ScalerSliceIndex(),
+		Nodes:            cfg.Scope,
+		Scores:           make(map[string]uint64),
+	}
+	for _, nodename := range cfg.Scope {
+		if nodename == o.localhost {
+			continue
+		}
+		nodeData, ok := clusterStatus.Cluster.Node[nodename]
+		if !ok {
+			continue
+		}
+		if nodeData.Monitor.State != node.MonitorStateIdle || !nodeData.Status.Frozen.IsZero() {
+			continue
+		}
+		inst, ok := nodeData.Instance[p.String()]
+		if !ok || inst.Status == nil {
+			continue
+		}
+		if !inst.Status.Provisioned.IsOneOf(provisioned.True, provisioned.NotApplicable) {
+			continue
+		}
+		candidates = append(candidates, nodename)
+		sorter.Scores[nodename] = nodeData.Stats.Score
+	}
+	l := sorter.Sort(cfg.PlacementPolicy, candidates)
+	if len(l) == 0 {
+		// placement policy not implemented by the sorter
+		l = sorter.Sort(placement.NodesOrder, candidates)
+	}
+	if len(l) == 0 {
+		return ""
+	}
+	return l[0]
+}
+
+// evacuate runs the pending switches of the plan, with at most concurrency
+// switches in parallel, and returns when all switches are done.
+func (o *nmon) evacuate(plan map[string]node.EvacuationItem, concurrency int, timeout time.Duration) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	paths := make([]string, 0, len(plan))
+	for s, item := range plan {
+		if item.State == node.EvacuationStatePending {
+			paths = append(paths, s)
+		}
+	}
+	sort.Strings(paths)
+	sem := make(chan any, concurrency)
+	var wg sync.WaitGroup
+	for _, s := range paths {
+		select {
+		case <-o.ctx.Done():
+			return
+		case sem <- nil:
+		}
+		wg.Add(1)
+		go func(s string, item node.EvacuationItem) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			o.evacuateObject(s, item, timeout)
+		}(s, plan[s])
+	}
+	wg.Wait()
+}
+
+// evacuateObject sets the placed@<destination> global expect on the object,
+// and waits for its instance on the destination node to be up.
+func (o *nmon) evacuateObject(s string, item node.EvacuationItem, timeout time.Duration) {
+	p, err := path.Parse(s)
+	if err != nil {
+		return
+	}
+	item.State = node.EvacuationStateSwitching
+	o.evacuationProgress(s, item)
+
+	globalExpect := instance.MonitorGlobalExpectPlacedAt
+	bus := pubsub.BusFromContext(o.ctx)
+	bus.Pub(msgbus.SetInstanceMonitor{
+		Path:   p,
+		Node:   o.localhost,
+		Origin: "node drain",
+		Value: instance.MonitorUpdate{
+			GlobalExpect: &globalExpect,
+			GlobalExpectOptions: instance.MonitorGlobalExpectOptionsPlacedAt{
+				Destination: []string{item.Destination},
+			},
+		},
+	}, pubsub.Label{"path", s})
+
+	ctx, cancel := context.WithTimeout(o.ctx, timeout)
+	defer cancel()
+	ticker := time.NewTicker(evacuationPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			item.State = node.EvacuationStateFailed
+			item.Error = fmt.Sprintf("not up on %s after %s", item.Destination, timeout)
+			o.evacuationProgress(s, item)
+			return
+		case <-ticker.C:
+			if o.databus.GetInstanceStatus(p, item.Destination).Avail.Is(status.Up) {
+				item.State = node.EvacuationStateSwitched
+				o.evacuationProgress(s, item)
+				return
+			}
+			if o.databus.GetInstanceMonitor(p, item.Destination).State == instance.MonitorStateStartFailed {
+				item.State = node.EvacuationStateFailed
+				item.Error = fmt.Sprintf("start failed on %s", item.Destination)
+				o.evacuationProgress(s, item)
+				return
+			}
+		}
+	}
+}
+
+func (o *nmon) evacuationProgress(s string, item node.EvacuationItem) {
+	select {
+	case <-o.ctx.Done():
+	case o.cmdC <- cmdEvacuationProgress{path: s, item: item}:
+	}
+}
+
+// onEvacuationProgress updates the evacuation report of the node monitor.
+// The report map is replaced, not modified, because the previous value is
+// shared with the published node monitor.
+func (o *nmon) onEvacuationProgress(c cmdEvacuationProgress) {
+	c.item.Updated = time.Now()
+	m := make(map[string]node.EvacuationItem, len(o.state.Evacuation)+1)
+	for k, v := range o.state.Evacuation {
+		m[k] = v
+	}
+	m[c.path] = c.item
+	switch c.item.State {
+	case node.EvacuationStateFailed:
+		o.log.Warn().Msgf("evacuate %s to %s: %s", c.path, c.item.Destination, c.item.Error)
+	default:
+		o.log.Info().Msgf("evacuate %s to %s: %s", c.path, c.item.Destination, c.item.State)
+	}
+	o.change = true
+	o.state.Evacuation = m
+	o.updateIfChange()
+}
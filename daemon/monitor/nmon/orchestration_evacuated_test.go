@@ -0,0 +1,76 @@
+package nmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/placement"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/core/topology"
+)
+
+func TestEvacuationPlan(t *testing.T) {
+	newInstance := func(topo topology.T, avail status.T, scope ...string) instance.Instance {
+		return instance.Instance{
+			Config: &instance.Config{
+				Topology:        topo,
+				PlacementPolicy: placement.NodesOrder,
+				Scope:           scope,
+			},
+			Status: &instance.Status{
+				Avail:       avail,
+				Provisioned: provisioned.True,
+			},
+		}
+	}
+	newNode := func(state node.MonitorState, frozen bool, instances map[string]instance.Instance) node.Node {
+		n := node.Node{
+			Instance: instances,
+			Monitor:  node.Monitor{State: state},
+		}
+		if frozen {
+			n.Status.Frozen = time.Now()
+		}
+		return n
+	}
+	clusterStatus := &cluster.Status{
+		Cluster: cluster.Cluster{
+			Node: map[string]node.Node{
+				"n1": newNode(node.MonitorStateIdle, false, map[string]instance.Instance{
+					"svc1":       newInstance(topology.Failover, status.Up, "n1", "n2", "n3"),
+					"svc2":       newInstance(topology.Failover, status.Up, "n1", "n3"),
+					"svc3":       newInstance(topology.Failover, status.Down, "n1", "n2"),
+					"svc4":       newInstance(topology.Flex, status.Up, "n1", "n2"),
+					"svc5":       newInstance(topology.Failover, status.Up, "n1"),
+					"test/cfg/c": newInstance(topology.Failover, status.Up, "n1", "n2"),
+				}),
+				"n2": newNode(node.MonitorStateIdle, false, map[string]instance.Instance{
+					"svc1": newInstance(topology.Failover, status.Down, "n1", "n2", "n3"),
+					"svc3": newInstance(topology.Failover, status.Down, "n1", "n2"),
+					"svc4": newInstance(topology.Flex, status.Up, "n1", "n2"),
+				}),
+				"n3": newNode(node.MonitorStateIdle, true, map[string]instance.Instance{
+					"svc1": newInstance(topology.Failover, status.Down, "n1", "n2", "n3"),
+					"svc2": newInstance(topology.Failover, status.Down, "n1", "n3"),
+				}),
+			},
+		},
+	}
+	o := &nmon{localhost: "n1"}
+	plan := o.evacuationPlan(clusterStatus)
+	assert.Len(t, plan, 3, "only the local failover svc instances up are evacuated")
+
+	assert.Equal(t, "n2", plan["svc1"].Destination)
+	assert.Equal(t, node.EvacuationStatePending, plan["svc1"].State)
+
+	assert.Equal(t, "", plan["svc2"].Destination, "frozen nodes are not eligible")
+	assert.Equal(t, node.EvacuationStateSkipped, plan["svc2"].State)
+
+	assert.Equal(t, node.EvacuationStateSkipped, plan["svc5"].State)
+}
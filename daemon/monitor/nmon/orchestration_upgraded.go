@@ -0,0 +1,261 @@
+package nmon
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/util/key"
+)
+
+// orchestrateUpgraded runs the rolling upgrade of the cluster agents. The
+// nodes upgrade one at a time, in the cluster.nodes order. The global
+// expect update time identifies the rolling upgrade: a node is upgraded
+// when its monitor Upgraded value is this time.
+//
+// idle -> [evacuating -> evacuated ->] upgrade -> (daemon restart) -> rejoin -> idle
+//
+// A node failing its upgrade aborts the orchestration on all nodes, and
+// rolls back:
+//
+// upgrade failed -> rolling back -> idle
+//
+// The orchestration also aborts when the daemon is not restarted after the
+// upgrade, or when a node waits for longer than node.upgrade_timeout for an
+// upgraded node to rejoin.
+//
+// The node is left in the "upgrade failed" state if no rollback command is
+// configured or if the rollback fails. The "node clear" command returns it
+// to idle.
+func (o *nmon) orchestrateUpgraded() {
+	switch o.state.State {
+	case node.MonitorStateIdle:
+		o.upgradeFromIdle()
+	case node.MonitorStateEvacuated:
+		o.doUpgrade()
+	case node.MonitorStateUpgradeFailed:
+		o.abortUpgrade("local node upgrade failed")
+		o.doUpgradeRollback()
+	}
+}
+
+func (o *nmon) upgradeFromIdle() {
+	if o.upgradedClearIfReached() {
+		return
+	}
+	nodes := o.config.GetStrings(key.New("cluster", "nodes"))
+	turn, wait, err := upgradeTurn(o.localhost, nodes, o.state.GlobalExpectUpdated, o.nodeMonitor, o.databus.GetNodeStatusMap())
+	if err != nil {
+		o.abortUpgrade(err.Error())
+		return
+	}
+	if !turn {
+		o.log.Debug().Msgf("upgrade: %s", wait.reason)
+		o.waitUpgradeTurn(wait)
+		return
+	}
+	o.clearUpgradeWait()
+	if o.config.GetBool(key.New("node", "upgrade_evacuate")) {
+		o.log.Info().Msg("upgrade: evacuate the node before upgrade")
+		o.doEvacuate(o.databus.GetStatus())
+		return
+	}
+	o.doUpgrade()
+}
+
+// upgradedClearIfReached unsets the upgraded global expect when the local
+// node has completed the current rolling upgrade.
+func (o *nmon) upgradedClearIfReached() bool {
+	if !o.state.Upgraded.Equal(o.state.GlobalExpectUpdated) {
+		return false
+	}
+	o.log.Info().Msg("node is upgraded, unset global expect")
+	o.clearUpgradeWait()
+	o.change = true
+	o.state.GlobalExpect = node.MonitorGlobalExpectUnset
+	o.state.Evacuation = nil
+	o.updateIfChange()
+	return true
+}
+
+// doUpgrade transitions to upgrade and runs the node upgrade command, then
+// records the rolling upgrade as completed and requests the daemon restart
+// to the os service manager. The restarted daemon rejoins the cluster,
+// which lets the next node upgrade. The upgrade fails, and the record is
+// rolled back, if the restart request fails or if the daemon is not
+// restarted within node.upgrade_timeout.
+func (o *nmon) doUpgrade() {
+	id := o.state.GlobalExpectUpdated
+	timeout := o.upgradeTimeout()
+	o.transitionTo(node.MonitorStateUpgrade)
+	go func() {
+		o.log.Info().Msg("run action upgrade")
+		if err := o.crmUpgrade(); err != nil {
+			o.orchestrateAfterAction(node.MonitorStateUpgrade, node.MonitorStateUpgradeFailed)
+			return
+		}
+		n, err := object.NewNode(object.WithVolatile(true))
+		if err != nil {
+			o.log.Error().Err(err).Msg("upgrade: record completion")
+			o.orchestrateAfterAction(node.MonitorStateUpgrade, node.MonitorStateUpgradeFailed)
+			return
+		}
+		// record the completion before the restart request, as the daemon
+		// may be terminated before the request returns.
+		prev := n.Upgraded()
+		if err := n.SetUpgraded(id); err != nil {
+			o.log.Error().Err(err).Msg("upgrade: record completion")
+			o.orchestrateAfterAction(node.MonitorStateUpgrade, node.MonitorStateUpgradeFailed)
+			return
+		}
+		// the peers continue the rolling upgrade when they see the
+		// completion record, so roll it back on failure.
+		rollback := func() {
+			if err := n.SetUpgraded(prev); err != nil {
+				o.log.Error().Err(err).Msg("upgrade: roll back the completion record")
+			}
+		}
+		o.log.Info().Msg("upgrade: restart the daemon")
+		if err := o.crmDaemonRestart(); err != nil {
+			rollback()
+			o.orchestrateAfterAction(node.MonitorStateUpgrade, node.MonitorStateUpgradeFailed)
+			return
+		}
+		select {
+		case <-o.ctx.Done():
+		case <-time.After(timeout):
+			o.log.Error().Msgf("upgrade: the daemon is not restarted after %s", timeout)
+			rollback()
+			o.orchestrateAfterAction(node.MonitorStateUpgrade, node.MonitorStateUpgradeFailed)
+		}
+	}()
+}
+
+// upgradeTimeout returns the node.upgrade_timeout value.
+func (o *nmon) upgradeTimeout() time.Duration {
+	if d := o.config.GetDuration(key.New("node", "upgrade_timeout")); d != nil {
+		return *d
+	}
+	return 10 * time.Minute
+}
+
+// waitUpgradeTurn records the wait for the local node upgrade turn. A timed
+// wait lasting longer than node.upgrade_timeout aborts the orchestration.
+func (o *nmon) waitUpgradeTurn(wait upgradeWait) {
+	if wait != o.upgradeWait {
+		o.clearUpgradeWait()
+		o.upgradeWait = wait
+		o.upgradeWaitSince = time.Now()
+		if !wait.timed {
+			return
+		}
+		// orchestrate again at the timeout, as the node not rejoined may
+		// not send any more event.
+		ctx, cancel := context.WithTimeout(o.ctx, o.upgradeTimeout())
+		o.upgradeWaitCancel = cancel
+		go func() {
+			<-ctx.Done()
+			if ctx.Err() == context.DeadlineExceeded {
+				o.orchestrateAfterAction(node.MonitorStateIdle, node.MonitorStateIdle)
+			}
+		}()
+		return
+	}
+	if !wait.timed {
+		return
+	}
+	if timeout := o.upgradeTimeout(); time.Since(o.upgradeWaitSince) >= timeout {
+		o.abortUpgrade(fmt.Sprintf("%s for more than %s", wait.reason, timeout))
+	}
+}
+
+// clearUpgradeWait forgets the wait for the local node upgrade turn.
+func (o *nmon) clearUpgradeWait() {
+	if o.upgradeWaitCancel != nil {
+		o.upgradeWaitCancel()
+		o.upgradeWaitCancel = nil
+	}
+	o.upgradeWait = upgradeWait{}
+	o.upgradeWaitSince = time.Time{}
+}
+
+// doUpgradeRollback transitions to rolling back and runs the node upgrade
+// rollback command, then returns to idle. The node stays in the "upgrade
+// failed" state if no rollback command is configured or if it fails.
+func (o *nmon) doUpgradeRollback() {
+	if o.config.GetString(key.New("node", "upgrade_rollback_command")) == "" {
+		o.log.Warn().Msg("upgrade: node.upgrade_rollback_command is not set, leave the upgrade failed state with 'node clear'")
+		return
+	}
+	o.transitionTo(node.MonitorStateRollingBack)
+	go func() {
+		o.log.Info().Msg("run action upgrade rollback")
+		if err := o.crmUpgradeRollback(); err != nil {
+			o.log.Error().Err(err).Msg("upgrade: rollback")
+			o.orchestrateAfterAction(node.MonitorStateRollingBack, node.MonitorStateUpgradeFailed)
+			return
+		}
+		o.orchestrateAfterAction(node.MonitorStateRollingBack, node.MonitorStateIdle)
+	}()
+}
+
+// abortUpgrade sets the aborted global expect, with a new update time so it
+// propagates to the peer nodes.
+func (o *nmon) abortUpgrade(reason string) {
+	o.log.Error().Msgf("abort upgrade: %s", reason)
+	o.clearUpgradeWait()
+	o.change = true
+	o.state.GlobalExpect = node.MonitorGlobalExpectAborted
+	o.state.GlobalExpectUpdated = time.Now()
+	o.updateIfChange()
+}
+
+// upgradeTurn returns true if localhost can start its upgrade in the rolling
+// upgrade identified by id. The nodes before localhost must be upgraded,
+// rejoined, and announce in their heartbeat messages a compat version not
+// lower than the localhost one. If false, the returned upgradeWait explains
+// the wait. An error is returned when the orchestration must abort.
+func upgradeTurn(localhost string, nodes []string, id time.Time, monitors map[string]node.Monitor, statuses map[string]node.Status) (bool, upgradeWait, error) {
+	localCompat := statuses[localhost].Compat
+	before := true
+	for _, nodename := range nodes {
+		if nodename == localhost {
+			before = false
+			continue
+		}
+		mon, ok := monitors[nodename]
+		if !ok {
+			if before {
+				return false, upgradeWait{reason: fmt.Sprintf("wait %s monitor", nodename), timed: true}, nil
+			}
+			continue
+		}
+		switch mon.State {
+		case node.MonitorStateUpgradeFailed:
+			return false, upgradeWait{}, fmt.Errorf("node %s upgrade failed", nodename)
+		case node.MonitorStateUpgrade, node.MonitorStateEvacuating, node.MonitorStateEvacuated:
+			if mon.GlobalExpect == node.MonitorGlobalExpectUpgraded {
+				return false, upgradeWait{reason: fmt.Sprintf("wait %s upgrade", nodename)}, nil
+			}
+		}
+		if !before {
+			continue
+		}
+		if !mon.Upgraded.Equal(id) {
+			return false, upgradeWait{reason: fmt.Sprintf("wait %s upgrade", nodename)}, nil
+		}
+		if mon.State != node.MonitorStateIdle {
+			return false, upgradeWait{reason: fmt.Sprintf("wait %s rejoin", nodename), timed: true}, nil
+		}
+		compat := statuses[nodename].Compat
+		if compat == 0 {
+			return false, upgradeWait{reason: fmt.Sprintf("wait %s compat version", nodename), timed: true}, nil
+		}
+		if compat < localCompat {
+			return false, upgradeWait{}, fmt.Errorf("node %s upgraded to compat version %d, lower than the local compat version %d", nodename, compat, localCompat)
+		}
+	}
+	return true, upgradeWait{}, nil
+}
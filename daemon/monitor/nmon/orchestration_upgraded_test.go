@@ -0,0 +1,106 @@
+package nmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"opensvc.com/opensvc/core/node"
+)
+
+func TestUpgradeTurn(t *testing.T) {
+	id := time.Now()
+	nodes := []string{"n1", "n2", "n3"}
+	upgraded := node.Monitor{
+		State:        node.MonitorStateIdle,
+		GlobalExpect: node.MonitorGlobalExpectUpgraded,
+		Upgraded:     id,
+	}
+	pending := node.Monitor{
+		State:        node.MonitorStateIdle,
+		GlobalExpect: node.MonitorGlobalExpectUpgraded,
+	}
+	cases := map[string]struct {
+		localhost string
+		monitors  map[string]node.Monitor
+		statuses  map[string]node.Status
+		turn      bool
+		timed     bool
+		err       bool
+	}{
+		"first node": {
+			localhost: "n1",
+			monitors:  map[string]node.Monitor{"n1": pending, "n2": pending, "n3": pending},
+			turn:      true,
+		},
+		"previous node not upgraded": {
+			localhost: "n2",
+			monitors:  map[string]node.Monitor{"n1": pending, "n2": pending, "n3": pending},
+		},
+		"previous node upgraded from a previous orchestration": {
+			localhost: "n2",
+			monitors: map[string]node.Monitor{
+				"n1": {State: node.MonitorStateIdle, Upgraded: id.Add(-time.Hour)},
+				"n2": pending,
+			},
+		},
+		"previous node upgraded but not rejoined": {
+			localhost: "n2",
+			monitors: map[string]node.Monitor{
+				"n1": {State: node.MonitorStateRejoin, Upgraded: id},
+				"n2": pending,
+			},
+			statuses: map[string]node.Status{"n1": {Compat: 12}, "n2": {Compat: 12}},
+			timed:    true,
+		},
+		"previous node compat version unknown": {
+			localhost: "n2",
+			monitors:  map[string]node.Monitor{"n1": upgraded, "n2": pending},
+			statuses:  map[string]node.Status{"n2": {Compat: 12}},
+			timed:     true,
+		},
+		"previous node compat version compatible": {
+			localhost: "n2",
+			monitors:  map[string]node.Monitor{"n1": upgraded, "n2": pending, "n3": pending},
+			statuses:  map[string]node.Status{"n1": {Compat: 13}, "n2": {Compat: 12}},
+			turn:      true,
+		},
+		"previous node compat version lower": {
+			localhost: "n2",
+			monitors:  map[string]node.Monitor{"n1": upgraded, "n2": pending},
+			statuses:  map[string]node.Status{"n1": {Compat: 11}, "n2": {Compat: 12}},
+			err:       true,
+		},
+		"peer node upgrade failed": {
+			localhost: "n1",
+			monitors: map[string]node.Monitor{
+				"n1": pending,
+				"n3": {State: node.MonitorStateUpgradeFailed},
+			},
+			err: true,
+		},
+		"next node upgrading": {
+			localhost: "n1",
+			monitors: map[string]node.Monitor{
+				"n1": pending,
+				"n2": {State: node.MonitorStateUpgrade, GlobalExpect: node.MonitorGlobalExpectUpgraded},
+			},
+		},
+	}
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			turn, wait, err := upgradeTurn(c.localhost, nodes, id, c.monitors, c.statuses)
+			if c.err {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, c.turn, turn, wait.reason)
+			assert.Equal(t, c.timed, wait.timed, wait.reason)
+			if !turn {
+				assert.NotEmpty(t, wait.reason)
+			}
+		})
+	}
+}
@@ -9,6 +9,7 @@ package omon
 
 import (
 	"context"
+	"time"
 
 	"github.com/rs/zerolog"
 	"github.com/rs/zerolog/log"
@@ -259,10 +260,24 @@ func (o *T) updateStatus() {
 		}
 	}
 
+	updateFailbackScheduled := func() {
+		o.status.FailbackScheduled = time.Time{}
+		if o.status.PlacementState != placement.NonOptimal {
+			return
+		}
+		for _, instMonitor := range o.instMonitor {
+			if !instMonitor.FailbackScheduled.IsZero() {
+				o.status.FailbackScheduled = instMonitor.FailbackScheduled
+				return
+			}
+		}
+	}
+
 	updateAvailOverall()
 	updateProvisioned()
 	updateFrozen()
 	updatePlacementState()
+	updateFailbackScheduled()
 	o.update()
 }
 
@@ -284,6 +284,10 @@ type (
 		Path  path.T
 		Node  string
 		Value instance.MonitorUpdate
+
+		// Origin describes the requester, recorded in the object monitor
+		// history.
+		Origin string
 	}
 
 	SetNodeMonitor struct {
@@ -0,0 +1,21 @@
+//go:build linux
+
+package poolbtrfs
+
+import (
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/util/btrfs"
+	"opensvc.com/opensvc/util/capabilities"
+)
+
+func init() {
+	capabilities.Register(capabilitiesScanner)
+}
+
+func capabilitiesScanner() ([]string, error) {
+	volDrvID := driver.NewID(driver.GroupVolume, drvID.Name)
+	if btrfs.IsCapable() {
+		return []string{drvID.Cap(), volDrvID.Cap()}, nil
+	}
+	return []string{}, nil
+}
@@ -0,0 +1,92 @@
+//go:build linux
+
+package poolbtrfs
+
+import (
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/btrfs"
+	"opensvc.com/opensvc/util/sizeconv"
+)
+
+type (
+	T struct {
+		pool.T
+	}
+)
+
+var (
+	drvID = driver.NewID(driver.GroupPool, "btrfs")
+)
+
+func init() {
+	driver.Register(drvID, NewPooler)
+}
+
+func NewPooler() pool.Pooler {
+	t := New()
+	var i interface{} = t
+	return i.(pool.Pooler)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+func (t T) Head() string {
+	return t.device()
+}
+
+func (t T) device() string {
+	return t.GetString("dev")
+}
+
+// subvolPrefix returns the path, relative to the filesystem top-level,
+// of the directory hosting the pool volumes subvolumes.
+func (t T) subvolPrefix() string {
+	return strings.Trim(filepath.Clean("/"+t.GetString("subvol")), "/")
+}
+
+func (t T) Capabilities() []string {
+	return []string{"roo", "rwo", "snap"}
+}
+
+func (t T) Usage() (pool.StatusUsage, error) {
+	info, err := btrfs.FilesystemShow(t.device(), nil)
+	if err != nil {
+		return pool.StatusUsage{}, err
+	}
+	var size, free, used int64
+	if info.Size > 0 {
+		size = info.Size / 1024
+		free = info.Free() / 1024
+		used = info.Used / 1024
+	}
+	usage := pool.StatusUsage{
+		Size: float64(size),
+		Free: float64(free),
+		Used: float64(used),
+	}
+	return usage, nil
+}
+
+func (t *T) Translate(name string, size float64, shared bool) ([]string, error) {
+	data := []string{
+		"fs#0.type=btrfs",
+		"fs#0.dev=" + t.device(),
+		"fs#0.subvol=" + filepath.Join(t.subvolPrefix(), name),
+		"fs#0.mnt=" + pool.MountPointFromName(name),
+		"fs#0.size=" + sizeconv.ExactBSizeCompact(size),
+	}
+	if mkfsOpt := t.MkfsOptions(); mkfsOpt != "" {
+		data = append(data, "fs#0.mkfs_opt="+mkfsOpt)
+	}
+	if mntOpt := t.MntOptions(); mntOpt != "" {
+		data = append(data, "fs#0.mnt_opt="+mntOpt)
+	}
+	return data, nil
+}
@@ -0,0 +1,21 @@
+//go:build linux
+
+package pooldrbd
+
+import (
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/drbd"
+)
+
+func init() {
+	capabilities.Register(capabilitiesScanner)
+}
+
+func capabilitiesScanner() ([]string, error) {
+	volDrvID := driver.NewID(driver.GroupVolume, drvID.Name)
+	if drbd.IsCapable() {
+		return []string{drvID.Cap(), volDrvID.Cap()}, nil
+	}
+	return []string{}, nil
+}
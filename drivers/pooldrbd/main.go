@@ -0,0 +1,171 @@
+//go:build linux
+
+package pooldrbd
+
+import (
+	"fmt"
+	"strings"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/pool"
+	"opensvc.com/opensvc/util/df"
+	"opensvc.com/opensvc/util/lvm2"
+	"opensvc.com/opensvc/util/sizeconv"
+	"opensvc.com/opensvc/util/zfs"
+)
+
+type (
+	T struct {
+		pool.T
+	}
+)
+
+var (
+	drvID = driver.NewID(driver.GroupPool, "drbd")
+)
+
+func init() {
+	driver.Register(drvID, NewPooler)
+}
+
+func NewPooler() pool.Pooler {
+	t := New()
+	var i interface{} = t
+	return i.(pool.Pooler)
+}
+
+func New() *T {
+	t := T{}
+	return &t
+}
+
+// Head returns the backing storage of the drbd devices: a volume group,
+// a zpool or a directory.
+func (t T) Head() string {
+	switch {
+	case t.vgName() != "":
+		return "vg " + t.vgName()
+	case t.zpoolName() != "":
+		return "zpool " + t.zpoolName()
+	default:
+		return t.path()
+	}
+}
+
+func (t T) vgName() string {
+	return t.GetString("vg")
+}
+
+func (t T) zpoolName() string {
+	return t.GetString("zpool")
+}
+
+func (t T) path() string {
+	return t.GetString("path")
+}
+
+func (t T) Capabilities() []string {
+	return []string{"roo", "rwo", "blk"}
+}
+
+func (t T) Usage() (pool.StatusUsage, error) {
+	var size, free, used int64
+	switch {
+	case t.vgName() != "":
+		info, err := lvm2.NewVG(t.vgName()).Show("vg_name,vg_free,vg_size")
+		if err != nil {
+			return pool.StatusUsage{}, err
+		}
+		if size, err = sizeconv.FromSize(strings.TrimLeft(info.VGSize, "<>+")); err != nil {
+			return pool.StatusUsage{}, err
+		}
+		if free, err = sizeconv.FromSize(strings.TrimLeft(info.VGFree, "<>+")); err != nil {
+			return pool.StatusUsage{}, err
+		}
+		used = size - free
+	case t.zpoolName() != "":
+		zpool := zfs.Pool{Name: t.zpoolName()}
+		e, err := zpool.Usage()
+		if err != nil {
+			return pool.StatusUsage{}, err
+		}
+		size, free, used = e.Size, e.Free, e.Alloc
+	case t.path() != "":
+		entries, err := df.MountUsage(t.path())
+		if err != nil {
+			return pool.StatusUsage{}, err
+		}
+		if len(entries) == 0 {
+			return pool.StatusUsage{}, nil
+		}
+		size, free, used = entries[0].Total, entries[0].Free, entries[0].Used
+	default:
+		return pool.StatusUsage{}, fmt.Errorf("one of the vg, zpool or path keywords must be set")
+	}
+	usage := pool.StatusUsage{
+		Size: float64(size / 1024),
+		Free: float64(free / 1024),
+		Used: float64(used / 1024),
+	}
+	return usage, nil
+}
+
+func (t *T) Translate(name string, size float64, shared bool) ([]string, error) {
+	data, err := t.BlkTranslate(name, size, shared)
+	if err != nil {
+		return nil, err
+	}
+	// The fs is formatted on the leader only, and mounted on the drbd
+	// primary node.
+	data = append(data, t.AddFS(name, true, 0, 2, "disk#1")...)
+	return data, nil
+}
+
+func (t *T) BlkTranslate(name string, size float64, shared bool) ([]string, error) {
+	data, err := t.backingDiskTranslate(name, size)
+	if err != nil {
+		return nil, err
+	}
+	data = append(data, []string{
+		"disk#1.type=drbd",
+		"disk#1.res=" + name,
+		"disk#1.disk={disk#0.exposed_devs[0]}",
+	}...)
+	return data, nil
+}
+
+// backingDiskTranslate returns the keywords of the disk#0 resource,
+// providing the local backing device of the drbd resource on each node.
+func (t *T) backingDiskTranslate(name string, size float64) ([]string, error) {
+	var data []string
+	switch {
+	case t.vgName() != "":
+		data = []string{
+			"disk#0.type=lv",
+			"disk#0.name=" + name,
+			"disk#0.vg=" + t.vgName(),
+			"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		}
+		if opts := t.MkblkOptions(); opts != "" {
+			data = append(data, "disk#0.create_options="+opts)
+		}
+	case t.zpoolName() != "":
+		data = []string{
+			"disk#0.type=zvol",
+			"disk#0.name=" + t.zpoolName() + "/" + name,
+			"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		}
+		if opts := t.MkblkOptions(); opts != "" {
+			data = append(data, "disk#0.create_options="+opts)
+		}
+	case t.path() != "":
+		data = []string{
+			"disk#0.type=loop",
+			"disk#0.file=" + fmt.Sprintf("%s/%s.img", t.path(), name),
+			"disk#0.size=" + sizeconv.ExactBSizeCompact(size),
+		}
+	default:
+		return nil, fmt.Errorf("one of the vg, zpool or path keywords must be set")
+	}
+	return data, nil
+}
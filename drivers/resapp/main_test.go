@@ -2,6 +2,7 @@ package resapp
 
 import (
 	"context"
+	"syscall"
 	"testing"
 	"time"
 
@@ -86,3 +87,23 @@ func TestT_Info(t *testing.T) {
 		}
 	})
 }
+
+func TestParseSignal(t *testing.T) {
+	cases := map[string]syscall.Signal{
+		"":        syscall.SIGHUP,
+		"hup":     syscall.SIGHUP,
+		"usr1":    syscall.SIGUSR1,
+		"SIGUSR2": syscall.SIGUSR2,
+	}
+	for s, expected := range cases {
+		t.Run(s, func(t *testing.T) {
+			sig, err := parseSignal(s)
+			assert.Nil(t, err)
+			assert.Equal(t, expected, sig)
+		})
+	}
+	t.Run("invalid", func(t *testing.T) {
+		_, err := parseSignal("foo")
+		assert.NotNil(t, err)
+	})
+}
@@ -0,0 +1,97 @@
+//go:build !windows
+
+package resapp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"syscall"
+
+	"github.com/rs/zerolog"
+	"golang.org/x/sys/unix"
+
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+	"opensvc.com/opensvc/util/proc"
+)
+
+// Reload applies the resource reload policy. It is called on the "reload"
+// action, which the daemon executes when a sec or cfg key referenced by
+// secrets_environment or configs_environment changes.
+//
+// The rolling_restart policy is orchestrated by the daemon using the
+// restart action, so it is a noop here.
+func (t *T) Reload(ctx context.Context) error {
+	switch instance.ReloadPolicy(t.ReloadPolicy) {
+	case instance.ReloadPolicySignal:
+		return t.reloadSignal()
+	case instance.ReloadPolicyCommand:
+		return t.reloadCommand()
+	default:
+		return nil
+	}
+}
+
+func (t *T) reloadSignal() error {
+	sig, err := parseSignal(t.ReloadSignal)
+	if err != nil {
+		return err
+	}
+	procs, err := proc.All()
+	if err != nil {
+		return err
+	}
+	ids := []string{
+		"OPENSVC_ID",
+		"OPENSVC_SVC_ID", // compat
+	}
+	procs = procs.FilterByEnvList(ids, t.ObjectID.String())
+	procs = procs.FilterByEnv("OPENSVC_RID", t.RID())
+	if procs.Len() == 0 {
+		t.Log().Info().Msg("no process to signal")
+		return nil
+	}
+	for _, p := range procs.Procs() {
+		t.Log().Info().Str("cmd", p.CommandLine()).Msgf("send %s to process %d", unix.SignalName(sig), p.PID())
+		if err := p.Signal(sig); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *T) reloadCommand() (err error) {
+	var opts []funcopt.O
+	if opts, err = t.GetFuncOpts(t.ReloadCmd, "reload"); err != nil {
+		return err
+	}
+	if len(opts) == 0 {
+		return nil
+	}
+	opts = append(opts,
+		command.WithLogger(t.Log()),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.WarnLevel),
+		command.WithTimeout(t.GetTimeout("reload")),
+	)
+	cmd := command.New(opts...)
+	t.Log().Info().Stringer("cmd", cmd).Msg("run")
+	return cmd.Run()
+}
+
+func parseSignal(s string) (syscall.Signal, error) {
+	if s == "" {
+		s = "hup"
+	}
+	name := strings.ToUpper(s)
+	if !strings.HasPrefix(name, "SIG") {
+		name = "SIG" + name
+	}
+	sig := unix.SignalNum(name)
+	if sig == 0 {
+		return 0, fmt.Errorf("invalid signal %s", s)
+	}
+	return sig, nil
+}
@@ -39,6 +39,9 @@ type (
 		StopCmd      string         `json:"stop"`
 		CheckCmd     string         `json:"check"`
 		InfoCmd      string         `json:"info"`
+		ReloadCmd    string         `json:"reload"`
+		ReloadPolicy string         `json:"reload_policy"`
+		ReloadSignal string         `json:"reload_signal"`
 		StatusLogKw  bool           `json:"status_log"`
 		CheckTimeout *time.Duration `json:"check_timeout"`
 		InfoTimeout  *time.Duration `json:"info_timeout"`
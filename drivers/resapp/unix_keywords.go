@@ -49,6 +49,35 @@ var (
 				" Invalid lines are dropped.",
 			Default: "false",
 		},
+		{
+			Option:   "reload",
+			Attr:     "ReloadCmd",
+			Scopable: true,
+			Text: "``true`` execute :cmd:`<script> reload` on reload action. ``false`` do nothing on reload action." +
+				" ``<shlex expression>`` execute the command on reload action." +
+				" Used when :kw:`reload_policy` is ``command``.",
+		},
+		{
+			Option:     "reload_policy",
+			Attr:       "ReloadPolicy",
+			Scopable:   true,
+			Candidates: []string{"none", "signal", "command", "rolling_restart"},
+			Default:    "none",
+			Text: "The action the daemon takes when a key referenced by :kw:`secrets_environment` or" +
+				" :kw:`configs_environment` changes. ``none`` do nothing, the processes keep the stale values" +
+				" until restarted. ``signal`` send :kw:`reload_signal` to the resource processes." +
+				" ``command`` execute the :kw:`reload` command. ``rolling_restart`` restart the resource" +
+				" on each instance of the object, one node at a time in the scope order.",
+			Example: "signal",
+		},
+		{
+			Option:   "reload_signal",
+			Attr:     "ReloadSignal",
+			Scopable: true,
+			Default:  "hup",
+			Text:     "The signal sent to the resource processes on reload when :kw:`reload_policy` is ``signal``.",
+			Example:  "usr1",
+		},
 		{
 			Option:    "status_log",
 			Attr:      "StatusLogKw",
@@ -1,9 +1,11 @@
 package rescontainerdocker
 
 import (
+	"bufio"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"strings"
@@ -15,6 +17,7 @@ import (
 	"github.com/cpuguy83/go-docker/container"
 	"github.com/cpuguy83/go-docker/container/containerapi"
 	"github.com/cpuguy83/go-docker/container/containerapi/mount"
+	"github.com/cpuguy83/go-docker/container/streamutil"
 	"github.com/cpuguy83/go-docker/errdefs"
 	"github.com/cpuguy83/go-docker/image"
 	"github.com/cpuguy83/go-docker/image/imageapi"
@@ -306,6 +309,105 @@ func (t T) Start(ctx context.Context) error {
 	return nil
 }
 
+// RunOnce creates and starts a container, waits for it to exit and
+// removes it. The container exit code is returned. A zero timeout means
+// no timeout. If logOutputs is true, the container stdout and stderr lines
+// are logged.
+//
+// This is the container.docker driver part of the task.docker driver.
+func (t T) RunOnce(ctx context.Context, timeout time.Duration, logOutputs bool) (int, error) {
+	cs := cli().ContainerService()
+	name := t.ContainerName()
+	if inspect, err := cs.Inspect(ctx, name); err == nil {
+		if inspect.State.Running {
+			return 0, fmt.Errorf("container %s is already running", name)
+		}
+		t.Log().Info().Str("name", name).Msgf("remove leftover container")
+		if err := cs.Remove(ctx, name); err != nil {
+			return 0, err
+		}
+	} else if !errdefs.IsNotFound(err) {
+		return 0, err
+	}
+	if t.ImagePullPolicy == AlwaysPolicy {
+		if err := t.pull(ctx); err != nil {
+			return 0, err
+		}
+	} else if _, err := t.imageInspect(); err != nil {
+		if err := t.pull(ctx); err != nil {
+			return 0, err
+		}
+	}
+
+	// The docker auto-remove would race with the exit code retrieval,
+	// so keep the container after exit and remove it explicitly.
+	t.Detach = true
+	t.Remove = false
+	c, err := t.create(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		t.Log().Debug().Str("name", name).Msg("remove container")
+		if err := cs.Remove(context.Background(), name, container.WithRemoveForce); err != nil {
+			t.Log().Warn().Err(err).Str("name", name).Msg("remove container")
+		}
+	}()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	t.Log().Info().Str("name", name).Msgf("start container (timeout %s)", timeout)
+	if err := c.Start(ctx); err != nil {
+		return 0, err
+	}
+	if logOutputs {
+		go t.logOutputs(ctx, c)
+	}
+	xs, err := c.Wait(ctx, container.WithWaitCondition(container.WaitConditionNotRunning))
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			t.Log().Warn().Str("name", name).Msg("timeout, kill container")
+			_ = c.Kill(context.Background())
+			return 0, fmt.Errorf("timeout")
+		}
+		return 0, err
+	}
+	xc := xs.ExitCode()
+	t.Log().Info().Msgf("exited with code %d", xc)
+	return xc, nil
+}
+
+// logOutputs logs the container stdout lines at info level and the stderr
+// lines at warn level, until the container exits.
+func (t T) logOutputs(ctx context.Context, c *container.Container) {
+	rc, err := c.Logs(ctx, func(cfg *container.LogReadConfig) {
+		cfg.ShowStdout = true
+		cfg.ShowStderr = true
+		cfg.Follow = true
+	})
+	if err != nil {
+		t.Log().Warn().Err(err).Msg("container logs")
+		return
+	}
+	defer rc.Close()
+	outR, outW := io.Pipe()
+	errR, errW := io.Pipe()
+	scan := func(r io.Reader, f func(string)) {
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			f(scanner.Text())
+		}
+	}
+	go scan(outR, func(s string) { t.Log().Info().Str("out", s).Send() })
+	go scan(errR, func(s string) { t.Log().Warn().Str("err", s).Send() })
+	_, _ = streamutil.StdCopy(outW, errW, rc)
+	_ = outW.Close()
+	_ = errW.Close()
+}
+
 func (t T) start(ctx context.Context, c *container.Container) error {
 	errs := make(chan error, 1)
 	go func() {
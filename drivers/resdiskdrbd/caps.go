@@ -0,0 +1,19 @@
+//go:build linux
+
+package resdiskdrbd
+
+import (
+	"opensvc.com/opensvc/util/capabilities"
+	"opensvc.com/opensvc/util/drbd"
+)
+
+func init() {
+	capabilities.Register(capabilitiesScanner)
+}
+
+func capabilitiesScanner() ([]string, error) {
+	if !drbd.IsCapable() {
+		return []string{}, nil
+	}
+	return []string{drvID.Cap()}, nil
+}
@@ -0,0 +1,487 @@
+//go:build linux
+
+package resdiskdrbd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/keyop"
+	"opensvc.com/opensvc/core/object"
+	"opensvc.com/opensvc/core/path"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/resdisk"
+	"opensvc.com/opensvc/util/device"
+	"opensvc.com/opensvc/util/drbd"
+	"opensvc.com/opensvc/util/hostname"
+	"opensvc.com/opensvc/util/key"
+)
+
+type (
+	T struct {
+		resdisk.T
+		Res      string   `json:"res"`
+		Disk     string   `json:"disk"`
+		Addr     string   `json:"addr"`
+		Port     int      `json:"port"`
+		Minor    int      `json:"minor"`
+		MaxPeers int      `json:"max_peers"`
+		Path     path.T   `json:"path"`
+		Nodes    []string `json:"nodes"`
+	}
+)
+
+func New() resource.Driver {
+	t := &T{}
+	return t
+}
+
+// Name returns the drbd resource name.
+func (t T) Name() string {
+	if t.Res != "" {
+		return t.Res
+	}
+	if t.Path.Namespace != "root" {
+		return fmt.Sprintf(
+			"%s.%s.%s",
+			strings.ToLower(t.Path.Namespace),
+			strings.Split(t.Path.Name, ".")[0],
+			strings.ReplaceAll(t.RID(), "#", "."),
+		)
+	} else {
+		return fmt.Sprintf(
+			"%s.%s",
+			strings.Split(t.Path.Name, ".")[0],
+			strings.ReplaceAll(t.RID(), "#", "."),
+		)
+	}
+}
+
+func (t T) drbd() *drbd.T {
+	return drbd.New(t.Name(), drbd.WithLogger(t.Log()))
+}
+
+func (t T) Label() string {
+	return t.Name()
+}
+
+func (t T) Info(ctx context.Context) (resource.InfoKeys, error) {
+	m := resource.InfoKeys{
+		{"res", t.Name()},
+		{"disk", t.Disk},
+		{"port", fmt.Sprint(t.Port)},
+		{"minor", fmt.Sprint(t.Minor)},
+	}
+	return m, nil
+}
+
+func (t T) isAllocated() bool {
+	return t.Port > 0
+}
+
+func (t T) devpath() string {
+	return fmt.Sprintf("/dev/drbd%d", t.Minor)
+}
+
+// Start brings the drbd resource up and promotes the local node to primary.
+// The promotion is never forced here: a node without up-to-date data
+// refuses to become primary. The forced first promotion, initiating the
+// full sync, is done by the provisioning leader.
+func (t T) Start(ctx context.Context) error {
+	dev := t.drbd()
+	if !dev.HasConfig() {
+		return fmt.Errorf("%s is not provisioned: %s not found", t.Label(), dev.ConfigFile())
+	}
+	if err := t.up(); err != nil {
+		return err
+	}
+	if role, err := dev.Role(); err != nil {
+		return err
+	} else if role == drbd.RolePrimary {
+		t.Log().Info().Msgf("drbd %s is already primary", t.Label())
+		return nil
+	}
+	if err := dev.Primary(false); err != nil {
+		return err
+	}
+	actionrollback.Register(ctx, func() error {
+		return dev.Secondary()
+	})
+	return nil
+}
+
+// Stop demotes the local node to secondary. The resource is kept up,
+// so the local disk continues to receive the replication stream.
+func (t T) Stop(ctx context.Context) error {
+	dev := t.drbd()
+	if v, err := dev.IsUp(); err != nil {
+		return err
+	} else if !v {
+		t.Log().Info().Msgf("drbd %s is not up, so not primary", t.Label())
+		return nil
+	}
+	if role, err := dev.Role(); err != nil {
+		return err
+	} else if role != drbd.RolePrimary {
+		t.Log().Info().Msgf("drbd %s is already secondary", t.Label())
+		return nil
+	}
+	return dev.Secondary()
+}
+
+// Boot brings the resource up as secondary, so the local disk receives
+// the replication stream before the object is started.
+func (t T) Boot(ctx context.Context) error {
+	if !t.drbd().HasConfig() {
+		return nil
+	}
+	return t.up()
+}
+
+// UnprovisionStop brings the resource down, as required by wipe-md.
+func (t T) UnprovisionStop(ctx context.Context) error {
+	dev := t.drbd()
+	if !dev.HasConfig() {
+		return nil
+	}
+	if v, err := dev.IsUp(); err != nil {
+		return err
+	} else if !v {
+		return nil
+	}
+	return dev.Down()
+}
+
+func (t T) up() error {
+	dev := t.drbd()
+	if v, err := dev.IsUp(); err != nil {
+		return err
+	} else if v {
+		return dev.Adjust()
+	}
+	return dev.Up()
+}
+
+func (t T) isInitialSync() (bool, error) {
+	local, peers, err := t.drbd().DStates()
+	if err != nil {
+		return false, err
+	}
+	if local == drbd.DStateUpToDate {
+		return false, nil
+	}
+	for _, s := range peers {
+		if s == drbd.DStateUpToDate {
+			return false, nil
+		}
+	}
+	return local == drbd.DStateInconsistent, nil
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	dev := t.drbd()
+	if !dev.HasConfig() {
+		t.StatusLog().Info("%s not found", dev.ConfigFile())
+		return status.Down
+	}
+	if v, err := dev.IsUp(); err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	} else if !v {
+		t.StatusLog().Warn("not up: not replicating")
+		return status.Down
+	}
+	t.statusReplication()
+	role, err := dev.Role()
+	if err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	}
+	if role == drbd.RolePrimary {
+		return status.Up
+	}
+	return status.Down
+}
+
+// statusReplication adds connection and disk states alerts to the status log.
+func (t *T) statusReplication() {
+	dev := t.drbd()
+	if l, err := dev.CStates(); err != nil {
+		t.StatusLog().Warn("cstate: %s", err)
+	} else {
+		for _, s := range l {
+			if s != drbd.CStateConnected {
+				t.StatusLog().Warn("cstate %s", s)
+			}
+		}
+	}
+	if local, peers, err := dev.DStates(); err != nil {
+		t.StatusLog().Warn("dstate: %s", err)
+	} else {
+		if local != drbd.DStateUpToDate {
+			t.StatusLog().Warn("local dstate %s", local)
+		}
+		for _, s := range peers {
+			if s != drbd.DStateUpToDate {
+				t.StatusLog().Warn("peer dstate %s", s)
+			}
+		}
+	}
+}
+
+// ProvisionLeader installs the resource file and creates the drbd meta
+// data on the local backing disk. The resource is not shared, so this
+// runs on every node. On the provisioning leader, the first promotion of
+// a resource with no up-to-date replica is forced, which initiates the
+// full sync to the peers.
+func (t *T) ProvisionLeader(ctx context.Context) error {
+	if err := t.allocate(ctx); err != nil {
+		return err
+	}
+	config, err := t.config()
+	if err != nil {
+		return err
+	}
+	dev := t.drbd()
+	if _, err := dev.WriteConfig(config); err != nil {
+		return err
+	}
+	actionrollback.Register(ctx, func() error {
+		return dev.RemoveConfig()
+	})
+	if v, err := dev.HasMD(); err != nil {
+		return err
+	} else if v {
+		t.Log().Info().Msgf("drbd %s meta data already created", t.Label())
+	} else if err := dev.CreateMD(t.MaxPeers); err != nil {
+		return err
+	}
+	if err := t.up(); err != nil {
+		return err
+	}
+	if !actioncontext.IsLeader(ctx) {
+		return nil
+	}
+	return t.initialSync(ctx)
+}
+
+// initialSync forces the promotion of the local node if no replica holds
+// up-to-date data yet.
+func (t T) initialSync(ctx context.Context) error {
+	if v, err := t.isInitialSync(); err != nil {
+		return err
+	} else if !v {
+		return nil
+	}
+	dev := t.drbd()
+	t.Log().Info().Msgf("drbd %s has no up-to-date replica: force the initial sync from the local disk", t.Label())
+	if err := dev.Primary(true); err != nil {
+		return err
+	}
+	actionrollback.Register(ctx, func() error {
+		return dev.Secondary()
+	})
+	return nil
+}
+
+func (t *T) UnprovisionLeader(ctx context.Context) error {
+	dev := t.drbd()
+	if !dev.HasConfig() {
+		t.Log().Info().Msgf("already unprovisioned")
+		return nil
+	}
+	if v, err := dev.HasMD(); err != nil {
+		return err
+	} else if v {
+		if err := dev.WipeMD(); err != nil {
+			return err
+		}
+	}
+	return dev.RemoveConfig()
+}
+
+func (t T) Provisioned() (provisioned.T, error) {
+	dev := t.drbd()
+	if !dev.HasConfig() {
+		return provisioned.False, nil
+	}
+	v, err := dev.HasMD()
+	return provisioned.FromBool(v), err
+}
+
+// allocate sets the port and minor keywords, if not already set. The
+// minor and port are free both in the resource files installed on this
+// node and in the drbd resources reported by every cluster node. The
+// allocation is done by the first node of the object only, to avoid
+// concurrent allocations.
+func (t *T) allocate(ctx context.Context) error {
+	if t.isAllocated() {
+		return nil
+	}
+	if len(t.Nodes) > 0 && t.Nodes[0] != hostname.Hostname() {
+		return fmt.Errorf("port and minor are not allocated yet: provision on %s first", t.Nodes[0])
+	}
+	alloc, err := drbd.GetAllocations()
+	if err != nil {
+		return err
+	}
+	if err := loadClusterAllocations(alloc); err != nil {
+		return fmt.Errorf("get the cluster drbd allocations: %w", err)
+	}
+	minor := alloc.FreeMinor()
+	port := alloc.FreePort()
+	t.Log().Info().Msgf("allocated drbd minor %d and port %d", minor, port)
+	obj, err := object.NewConfigurer(t.Path)
+	if err != nil {
+		return err
+	}
+	ops := []keyop.T{
+		{Key: key.New(t.RID(), "minor"), Op: keyop.Set, Value: fmt.Sprint(minor)},
+		{Key: key.New(t.RID(), "port"), Op: keyop.Set, Value: fmt.Sprint(port)},
+	}
+	if err := obj.Set(ctx, ops...); err != nil {
+		return err
+	}
+	t.Minor = minor
+	t.Port = port
+	return nil
+}
+
+// loadClusterAllocations adds to alloc the minors and ports of the drbd
+// resources found in the daemon cluster status.
+func loadClusterAllocations(alloc drbd.Allocations) error {
+	var clusterStatus cluster.Status
+	c, err := client.New()
+	if err != nil {
+		return err
+	}
+	b, err := c.NewGetDaemonStatus().Do()
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, &clusterStatus); err != nil {
+		return err
+	}
+	addClusterAllocations(alloc, clusterStatus)
+	return nil
+}
+
+func addClusterAllocations(alloc drbd.Allocations, clusterStatus cluster.Status) {
+	for _, nodeData := range clusterStatus.Cluster.Node {
+		for _, inst := range nodeData.Instance {
+			if inst.Status == nil {
+				continue
+			}
+			for _, resourceData := range inst.Status.Resources {
+				if resourceData.Type != drvID.String() {
+					continue
+				}
+				minor, err := strconv.Atoi(fmt.Sprint(resourceData.Info["minor"]))
+				if err != nil {
+					continue
+				}
+				port, err := strconv.Atoi(fmt.Sprint(resourceData.Info["port"]))
+				if err != nil {
+					continue
+				}
+				alloc.Add(minor, port)
+			}
+		}
+	}
+}
+
+// StatusInfo exposes the allocated minor and port in the instance status,
+// so the other nodes don't allocate them again.
+func (t *T) StatusInfo() map[string]interface{} {
+	data := make(map[string]interface{})
+	if t.isAllocated() {
+		data["minor"] = t.Minor
+		data["port"] = t.Port
+	}
+	return data
+}
+
+// config returns the drbd resource definition, with the disk and addr
+// keywords evaluated as each node of the object.
+func (t T) config() (drbd.Config, error) {
+	c := drbd.Config{
+		Name:  t.Name(),
+		Minor: t.Minor,
+		Port:  t.Port,
+		Hosts: make([]drbd.Host, 0),
+	}
+	obj, err := object.NewConfigurer(t.Path)
+	if err != nil {
+		return c, err
+	}
+	for _, node := range t.Nodes {
+		disk, err := obj.EvalAs(key.New(t.RID(), "disk"), node)
+		if err != nil {
+			return c, err
+		}
+		addr, err := obj.EvalAs(key.New(t.RID(), "addr"), node)
+		if err != nil {
+			return c, err
+		}
+		h := drbd.Host{
+			Name: node,
+			Disk: fmt.Sprint(disk),
+			Addr: fmt.Sprint(addr),
+		}
+		if h.Addr == "" {
+			if h.Addr, err = resolve(node); err != nil {
+				return c, err
+			}
+		}
+		c.Hosts = append(c.Hosts, h)
+	}
+	return c, nil
+}
+
+func resolve(node string) (string, error) {
+	ips, err := net.LookupIP(node)
+	if err != nil {
+		return "", err
+	}
+	for _, ip := range ips {
+		if ip.To4() != nil {
+			return ip.String(), nil
+		}
+	}
+	if len(ips) > 0 {
+		return ips[0].String(), nil
+	}
+	return "", fmt.Errorf("%s resolves to no ip address", node)
+}
+
+func (t T) ExposedDevices() device.L {
+	if !t.isAllocated() {
+		return device.L{}
+	}
+	return device.L{device.New(t.devpath(), device.WithLogger(t.Log()))}
+}
+
+func (t T) SubDevices() device.L {
+	if t.Disk == "" {
+		return device.L{}
+	}
+	return device.L{device.New(t.Disk, device.WithLogger(t.Log()))}
+}
+
+func (t T) ClaimedDevices() device.L {
+	return t.SubDevices()
+}
+
+func (t *T) ReservableDevices() device.L {
+	return t.SubDevices()
+}
@@ -0,0 +1,94 @@
+//go:build linux
+
+package resdiskdrbd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/cluster"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/node"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/util/drbd"
+)
+
+// setupCommands installs fake drbdadm and drbdsetup commands in the PATH
+// and a drbd config dir with the res resource file. The resource is up,
+// drbdadm reports role and dstate, and records the other command lines.
+// It returns a func returning the recorded drbdadm command lines.
+func setupCommands(t *testing.T, res, role, dstate string) func() []string {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "drbdadm.log")
+	drbdadm := fmt.Sprintf(`#!/bin/sh
+case "$1" in
+role) echo %s;;
+dstate) echo %s;;
+*) echo "$*" >>%s;;
+esac
+`, role, dstate, logFile)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "drbdadm"), []byte(drbdadm), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "drbdsetup"), []byte("#!/bin/sh\nexit 0\n"), 0755))
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+	configDir := drbd.ConfigDir
+	drbd.ConfigDir = t.TempDir()
+	t.Cleanup(func() { drbd.ConfigDir = configDir })
+	require.NoError(t, os.WriteFile(filepath.Join(drbd.ConfigDir, res+".res"), []byte{}, 0644))
+
+	return func() []string {
+		b, err := os.ReadFile(logFile)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+}
+
+func TestStopDemotes(t *testing.T) {
+	calls := setupCommands(t, "r1", drbd.RolePrimary, "UpToDate/UpToDate")
+	r := &T{Res: "r1"}
+	require.NoError(t, r.Stop(context.Background()))
+	assert.Equal(t, []string{"secondary r1"}, calls())
+}
+
+func TestStartNeverForces(t *testing.T) {
+	calls := setupCommands(t, "r1", drbd.RoleSecondary, "Inconsistent/DUnknown")
+	r := &T{Res: "r1"}
+	require.NoError(t, r.Start(context.Background()))
+	assert.Equal(t, []string{"adjust r1", "primary r1"}, calls())
+}
+
+func TestAddClusterAllocations(t *testing.T) {
+	var clusterStatus cluster.Status
+	clusterStatus.Cluster.Node = map[string]node.Node{
+		"n2": {
+			Instance: map[string]instance.Instance{
+				"svc1": {Status: &instance.Status{
+					Resources: []resource.ExposedStatus{
+						{Rid: "disk#1", Type: "disk.drbd", Info: map[string]any{"minor": float64(0), "port": float64(7289)}},
+						{Rid: "disk#2", Type: "disk.drbd"},
+						{Rid: "ip#1", Type: "ip.host", Info: map[string]any{"minor": float64(1)}},
+					},
+				}},
+				"svc2": {},
+			},
+		},
+	}
+	configDir := drbd.ConfigDir
+	drbd.ConfigDir = t.TempDir()
+	t.Cleanup(func() { drbd.ConfigDir = configDir })
+	alloc, err := drbd.GetAllocations()
+	require.NoError(t, err)
+	addClusterAllocations(alloc, clusterStatus)
+	assert.Equal(t, 1, alloc.FreeMinor())
+	assert.Equal(t, 7290, alloc.FreePort())
+}
@@ -0,0 +1,87 @@
+//go:build linux
+
+package resdiskdrbd
+
+import (
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/drivers/resdisk"
+	"opensvc.com/opensvc/util/converters"
+)
+
+var (
+	drvID = driver.NewID(driver.GroupDisk, "drbd")
+)
+
+func init() {
+	driver.Register(drvID, New)
+}
+
+// Manifest exposes to the core the input expected by the driver.
+func (t T) Manifest() *manifest.T {
+	m := manifest.New(drvID, t)
+	m.AddKeyword(resdisk.BaseKeywords...)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+		{
+			Key:  "nodes",
+			Attr: "Nodes",
+			Ref:  "object.nodes",
+		},
+	}...)
+	m.AddKeyword([]keywords.Keyword{
+		{
+			Option:  "res",
+			Attr:    "Res",
+			Text:    "The name of the drbd resource. If not set, the name is derived from the object path and the resource id.",
+			Example: "svc1.disk.1",
+		},
+		{
+			Option:       "disk",
+			Attr:         "Disk",
+			Scopable:     true,
+			Required:     true,
+			Provisioning: true,
+			Text:         "The path of the local backing block device. Different devices can be set up on different nodes using the ``disk@nodename`` syntax.",
+			Example:      "/dev/vg1/svc1",
+		},
+		{
+			Option:       "addr",
+			Attr:         "Addr",
+			Scopable:     true,
+			Provisioning: true,
+			Text:         "The ip address the node listens on for the drbd replication. Set different addresses on different nodes using the ``addr@nodename`` syntax. If not set, the address the node name resolves to is used.",
+			Example:      "10.0.0.1",
+		},
+		{
+			Option:       "port",
+			Attr:         "Port",
+			Converter:    converters.Int,
+			Provisioning: true,
+			Text:         "The tcp port used by the drbd replication. If not set, the first node of the object allocates the first port not used by the installed drbd resource files on provision, and stores it in this keyword.",
+			Example:      "7289",
+		},
+		{
+			Option:       "minor",
+			Attr:         "Minor",
+			Converter:    converters.Int,
+			Provisioning: true,
+			Text:         "The minor of the /dev/drbd<minor> device. If not set, the first node of the object allocates the first minor not used by the installed drbd resource files on provision, and stores it in this keyword.",
+			Example:      "1",
+		},
+		{
+			Option:       "max_peers",
+			Attr:         "MaxPeers",
+			Converter:    converters.Int,
+			Provisioning: true,
+			Text:         "The number of peers to reserve space for in the drbd meta data. If not set, the drbdadm default applies.",
+			Example:      "3",
+		},
+	}...)
+	return m
+}
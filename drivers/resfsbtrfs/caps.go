@@ -0,0 +1,17 @@
+package resfsbtrfs
+
+import (
+	"opensvc.com/opensvc/util/btrfs"
+	"opensvc.com/opensvc/util/capabilities"
+)
+
+func init() {
+	capabilities.Register(capabilitiesScanner)
+}
+
+func capabilitiesScanner() ([]string, error) {
+	if !btrfs.IsCapable() {
+		return []string{}, nil
+	}
+	return []string{drvID.Cap()}, nil
+}
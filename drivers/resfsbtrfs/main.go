@@ -0,0 +1,557 @@
+package resfsbtrfs
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"opensvc.com/opensvc/core/actionrollback"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/resfsdir"
+	"opensvc.com/opensvc/util/btrfs"
+	"opensvc.com/opensvc/util/device"
+	"opensvc.com/opensvc/util/file"
+	"opensvc.com/opensvc/util/filesystems"
+	"opensvc.com/opensvc/util/findmnt"
+)
+
+type (
+	T struct {
+		resource.T
+		resource.SCSIPersistentReservation
+		MountPoint   string         `json:"mnt"`
+		Device       string         `json:"dev"`
+		Subvol       string         `json:"subvol"`
+		MountOptions string         `json:"mnt_opt"`
+		StatTimeout  *time.Duration `json:"stat_timeout"`
+		Size         *int64         `json:"size"`
+		MKFSOptions  []string       `json:"mkfs_opt"`
+		FSCK         string         `json:"fsck"`
+		User         *user.User     `json:"user"`
+		Group        *user.Group    `json:"group"`
+		Perm         *os.FileMode   `json:"perm"`
+		PromoteRW    bool           `json:"promote_rw"`
+	}
+)
+
+const (
+	// snapshotDir is the directory, relative to the subvolume root,
+	// hosting the snapshots of the subvolume. They must be destroyed
+	// before the subvolume.
+	snapshotDir = ".snapshots"
+
+	// topLevelSubvolID is the id of the btrfs top-level subvolume,
+	// always present.
+	topLevelSubvolID = "5"
+)
+
+func New() resource.Driver {
+	t := &T{}
+	return t
+}
+
+func (t T) Start(ctx context.Context) error {
+	if err := t.mount(ctx); err != nil {
+		return err
+	}
+	if err := t.fsDir().Start(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t T) Stop(ctx context.Context) error {
+	if v, err := t.isMounted(); err != nil {
+		return err
+	} else if !v {
+		t.Log().Info().Msgf("%s already umounted from %s", t.Label(), t.mountPoint())
+		return nil
+	}
+	if err := t.fs().Umount(t.mountPoint()); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	if t.Device == "" {
+		t.StatusLog().Info("dev is not defined")
+		return status.NotApplicable
+	}
+	if t.MountPoint == "" {
+		t.StatusLog().Info("mnt is not defined")
+		return status.NotApplicable
+	}
+	if v, err := t.isMounted(); err != nil {
+		t.StatusLog().Error("%s", err)
+		return status.Undef
+	} else if !v {
+		return status.Down
+	}
+	if err := t.statMountPoint(); err != nil {
+		t.StatusLog().Warn("%s", err)
+		return status.Warn
+	}
+	return status.Up
+}
+
+// statMountPoint returns an error if the mount point does not respond to
+// a stat call before the stat_timeout keyword value expires. The stat is
+// left running in the background on timeout.
+func (t T) statMountPoint() error {
+	if t.StatTimeout == nil || *t.StatTimeout <= 0 {
+		return nil
+	}
+	mnt := t.mountPoint()
+	errC := make(chan error, 1)
+	go func() {
+		_, err := os.Stat(mnt)
+		errC <- err
+	}()
+	select {
+	case err := <-errC:
+		return err
+	case <-time.After(*t.StatTimeout):
+		return fmt.Errorf("stat %s timeout after %s", mnt, *t.StatTimeout)
+	}
+}
+
+func (t T) Label() string {
+	s := t.Device
+	if t.Subvol != "" {
+		s += ":" + t.subvol()
+	}
+	m := t.mountPoint()
+	if m != "" {
+		s += "@" + m
+	}
+	return s
+}
+
+func (t T) Info(ctx context.Context) (resource.InfoKeys, error) {
+	m := resource.InfoKeys{
+		{"dev", t.Device},
+		{"subvol", t.subvol()},
+		{"mnt", t.mountPoint()},
+		{"mnt_opt", t.mountOptions()},
+	}
+	return m, nil
+}
+
+func (t T) Provisioned() (provisioned.T, error) {
+	return provisioned.NotApplicable, nil
+}
+
+func (t T) Head() string {
+	return t.MountPoint
+}
+
+func (t T) fsDir() *resfsdir.T {
+	r := resfsdir.New().(*resfsdir.T)
+	r.SetRID(t.RID())
+	r.SetObject(t.GetObject())
+	r.Path = t.MountPoint
+	r.User = t.User
+	r.Group = t.Group
+	r.Perm = t.Perm
+	return r
+}
+
+func (t T) fs() filesystems.I {
+	fs := filesystems.FromType("btrfs")
+	fs.SetLog(t.Log())
+	return fs
+}
+
+func (t T) mountPoint() string {
+	// add zonepath translation, and cache ?
+	return filepath.Clean(t.MountPoint)
+}
+
+// subvol returns the subvolume path relative to the filesystem top-level,
+// without leading and trailing slashes.
+func (t T) subvol() string {
+	return strings.Trim(filepath.Clean("/"+t.Subvol), "/")
+}
+
+// mountOptions returns the mnt_opt keyword value, with the subvol option
+// added if the subvol keyword is set and mnt_opt does not already select
+// a subvolume.
+func (t T) mountOptions() string {
+	l := make([]string, 0)
+	for _, s := range strings.Split(t.MountOptions, ",") {
+		if s != "" {
+			l = append(l, s)
+		}
+	}
+	if subvol := t.subvol(); subvol != "" {
+		hasSubvol := false
+		for _, s := range l {
+			if strings.HasPrefix(s, "subvol=") || strings.HasPrefix(s, "subvolid=") {
+				hasSubvol = true
+				break
+			}
+		}
+		if !hasSubvol {
+			l = append(l, "subvol="+subvol)
+		}
+	}
+	return strings.Join(l, ",")
+}
+
+func (t T) isByUUID() bool {
+	return strings.HasPrefix(t.Device, "UUID=")
+}
+
+func (t T) isByLabel() bool {
+	return strings.HasPrefix(t.Device, "LABEL=")
+}
+
+func (t *T) validateDevice() error {
+	if t.Device == "" {
+		return fmt.Errorf("device keyword not set or evaluates to None")
+	}
+	if t.isByLabel() || t.isByUUID() {
+		return nil
+	}
+	if !file.Exists(t.Device) {
+		return fmt.Errorf("device does not exist: %s", t.Device)
+	}
+	return nil
+}
+
+func (t *T) isMounted() (bool, error) {
+	v, err := findmnt.Has(t.Device, t.mountPoint())
+	return v, err
+}
+
+func (t *T) mount(ctx context.Context) error {
+	if err := t.validateDevice(); err != nil {
+		return err
+	}
+	if err := t.promoteDevicesReadWrite(ctx); err != nil {
+		return err
+	}
+	if v, err := t.isMounted(); err != nil {
+		return err
+	} else if v {
+		t.Log().Info().Msgf("%s already mounted on %s", t.Label(), t.mountPoint())
+		return nil
+	}
+	if err := t.createMountPoint(ctx); err != nil {
+		return err
+	}
+	if err := t.fsck(); err != nil {
+		return err
+	}
+	if err := t.fs().Mount(t.Device, t.mountPoint(), t.mountOptions()); err != nil {
+		return err
+	}
+	actionrollback.Register(ctx, func() error {
+		return t.fs().Umount(t.mountPoint())
+	})
+	return nil
+}
+
+func (t *T) createMountPoint(ctx context.Context) error {
+	if file.ExistsAndDir(t.MountPoint) {
+		return nil
+	}
+	if file.Exists(t.MountPoint) {
+		return fmt.Errorf("mountpoint %s already exists but is not a directory", t.MountPoint)
+	}
+	t.Log().Info().Msgf("create missing mountpoint %s", t.MountPoint)
+	if err := os.MkdirAll(t.MountPoint, 0755); err != nil {
+		return fmt.Errorf("error creating mountpoint %s: %s", t.MountPoint, err)
+	}
+	return nil
+}
+
+func (t T) removeMountPoint() error {
+	mnt := t.mountPoint()
+	if mnt == "" {
+		return nil
+	}
+	if file.IsProtected(mnt) {
+		return fmt.Errorf("dir %s is protected: refuse to remove", mnt)
+	}
+	if !file.Exists(mnt) {
+		t.Log().Info().Msgf("dir %s is already removed", mnt)
+		return nil
+	}
+	return os.RemoveAll(mnt)
+}
+
+// topLevelMountPoint is the private mount point where the filesystem
+// top-level subvolume is mounted to manage the subvolumes.
+func (t T) topLevelMountPoint() string {
+	return filepath.Join(t.VarDir(), "top")
+}
+
+// withTopLevel mounts the filesystem top-level subvolume, executes <fn>
+// with the top-level mount point as argument, and umounts.
+func (t T) withTopLevel(fn func(string) error) error {
+	mnt := t.topLevelMountPoint()
+	fs := t.fs()
+	if err := os.MkdirAll(mnt, 0700); err != nil {
+		return err
+	}
+	if v, err := findmnt.Has(t.Device, mnt); err != nil {
+		return err
+	} else if !v {
+		if err := fs.Mount(t.Device, mnt, "subvolid="+topLevelSubvolID); err != nil {
+			return err
+		}
+		defer func() {
+			if err := fs.Umount(mnt); err != nil {
+				t.Log().Warn().Err(err).Msgf("umount %s", mnt)
+			}
+		}()
+	}
+	return fn(mnt)
+}
+
+func (t T) subvolume(topLevel string) *btrfs.Subvolume {
+	return &btrfs.Subvolume{
+		Path: filepath.Join(topLevel, t.subvol()),
+		Log:  t.Log(),
+	}
+}
+
+func (t *T) mkfs() error {
+	if t.isByLabel() || t.isByUUID() {
+		t.Log().Debug().Msgf("skip mkfs: %s implies an existing filesystem", t.Device)
+		return nil
+	}
+	fs := t.fs()
+	if v, err := fs.(filesystems.IsFormateder).IsFormated(t.Device); err != nil {
+		return err
+	} else if v {
+		t.Log().Info().Msgf("%s is already formated", t.Device)
+		return nil
+	}
+	return fs.(filesystems.MKFSer).MKFS(t.Device, t.MKFSOptions)
+}
+
+func (t *T) ProvisionLeader(ctx context.Context) error {
+	if err := t.validateDevice(); err != nil {
+		return err
+	}
+	if err := t.mkfs(); err != nil {
+		return err
+	}
+	return t.withTopLevel(func(topLevel string) error {
+		if t.subvol() != "" {
+			sv := t.subvolume(topLevel)
+			if v, err := sv.Exists(); err != nil {
+				return errors.Wrap(err, "subvolume existance check")
+			} else if v {
+				t.Log().Info().Msgf("subvolume %s already exists", t.subvol())
+			} else if err := sv.Create(btrfs.SubvolumeCreateWithParents(true)); err != nil {
+				return err
+			}
+		}
+		return t.provisionQgroup(topLevel)
+	})
+}
+
+func (t *T) provisionQgroup(topLevel string) error {
+	if t.Size == nil {
+		return nil
+	}
+	if t.subvol() == "" {
+		t.Log().Warn().Msgf("skip qgroup limit: can not limit the top-level subvolume")
+		return nil
+	}
+	if err := btrfs.EnableQuota(topLevel, t.Log()); err != nil {
+		return err
+	}
+	return t.subvolume(topLevel).SetQgroupLimit(t.Size)
+}
+
+func (t *T) UnprovisionLeader(ctx context.Context) error {
+	if t.subvol() == "" {
+		t.Log().Info().Msgf("the top-level subvolume is not destroyed on unprovision")
+		return t.removeMountPoint()
+	}
+	err := t.withTopLevel(func(topLevel string) error {
+		sv := t.subvolume(topLevel)
+		if v, err := sv.Exists(); err != nil {
+			return errors.Wrap(err, "subvolume existance check")
+		} else if !v {
+			t.Log().Info().Msgf("subvolume %s is already destroyed", t.subvol())
+			return nil
+		}
+		if err := t.deleteSnapshots(sv.Path); err != nil {
+			return err
+		}
+		return sv.Delete(btrfs.SubvolumeDeleteWithCommit(true))
+	})
+	if err != nil {
+		return err
+	}
+	return t.removeMountPoint()
+}
+
+// Snapshot creates a snapshot of the subvolume, named <name>, in the
+// .snapshots directory of the subvolume, and returns the snapshot path
+// under the resource mount point. The snapshot is created through the
+// top-level mount, so it does not depend on the mnt_opt subvolume
+// selection. Sync drivers use read-only snapshots as a consistent source
+// for their transfers.
+func (t T) Snapshot(ctx context.Context, name string, readonly bool) (string, error) {
+	if v, err := t.isMounted(); err != nil {
+		return "", err
+	} else if !v {
+		return "", fmt.Errorf("%s is not mounted", t.Label())
+	}
+	err := t.withTopLevel(func(topLevel string) error {
+		return t.snapshot(topLevel, name, readonly)
+	})
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(t.mountPoint(), snapshotDir, name), nil
+}
+
+// DeleteSnapshot destroys the snapshot named <name> created by Snapshot().
+func (t T) DeleteSnapshot(ctx context.Context, name string) error {
+	return t.withTopLevel(func(topLevel string) error {
+		return t.deleteSnapshot(topLevel, name)
+	})
+}
+
+func (t T) snapshot(topLevel, name string, readonly bool) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+	sv := t.subvolume(topLevel)
+	dst := filepath.Join(sv.Path, snapshotDir, name)
+	_, err := sv.Snapshot(dst, btrfs.SubvolumeSnapshotWithReadOnly(readonly))
+	return err
+}
+
+func (t T) deleteSnapshot(topLevel, name string) error {
+	if err := validateSnapshotName(name); err != nil {
+		return err
+	}
+	sv := btrfs.Subvolume{
+		Path: filepath.Join(t.subvolume(topLevel).Path, snapshotDir, name),
+		Log:  t.Log(),
+	}
+	if v, err := sv.Exists(); err != nil {
+		return err
+	} else if !v {
+		t.Log().Info().Msgf("snapshot %s is already destroyed", sv.Path)
+		return nil
+	}
+	return sv.Delete()
+}
+
+// validateSnapshotName returns an error if <name> can not be a snapshot
+// basename in the .snapshots directory.
+func validateSnapshotName(name string) error {
+	switch {
+	case name == "", name == ".", name == "..", strings.Contains(name, "/"):
+		return fmt.Errorf("invalid snapshot name '%s'", name)
+	}
+	return nil
+}
+
+func (t T) deleteSnapshots(head string) error {
+	dir := filepath.Join(head, snapshotDir)
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		sv := btrfs.Subvolume{Path: filepath.Join(dir, e.Name()), Log: t.Log()}
+		if err := sv.Delete(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *T) promoteDevicesReadWrite(ctx context.Context) error {
+	if !t.PromoteRW {
+		return nil
+	}
+	for _, dev := range t.SubDevices() {
+		currentRO, err := dev.IsReadOnly()
+		if err != nil {
+			return err
+		}
+		if !currentRO {
+			t.Log().Debug().Stringer("dev", dev).Msgf("already read-write")
+			continue
+		}
+		t.Log().Info().Stringer("dev", dev).Msgf("promote read-write")
+		if err := dev.SetReadWrite(); err != nil {
+			return err
+		}
+		actionrollback.Register(ctx, func() error {
+			return dev.SetReadOnly()
+		})
+	}
+	return nil
+}
+
+func (t *T) fsck() error {
+	fs := t.fs()
+	switch t.FSCK {
+	case "never":
+		t.Log().Debug().Msgf("skip fsck, disabled by policy")
+		return nil
+	case "required":
+		if !filesystems.HasFSCK(fs) {
+			return errors.Errorf("fsck is required by policy but not implemented for type %s", fs)
+		}
+		if err := filesystems.CanFSCK(fs); err != nil {
+			return errors.Wrap(err, "fsck is required by policy")
+		}
+	default:
+		if !filesystems.HasFSCK(fs) {
+			t.Log().Debug().Msgf("skip fsck, not implemented for type %s", fs)
+			return nil
+		}
+		if err := filesystems.CanFSCK(fs); err != nil {
+			t.Log().Warn().Msgf("skip fsck: %s", err)
+			return nil
+		}
+	}
+	return filesystems.DevicesFSCK(fs, t)
+}
+
+func (t T) ClaimedDevices() device.L {
+	return t.SubDevices()
+}
+
+func (t T) ReservableDevices() device.L {
+	return t.SubDevices()
+}
+
+func (t T) SubDevices() device.L {
+	l := make(device.L, 0)
+	paths, err := filesystems.MemberDevices(t.fs(), t.Device)
+	if err != nil {
+		t.Log().Debug().Err(err).Msgf("list %s member devices", t.Device)
+		if !t.isByLabel() && !t.isByUUID() {
+			l = append(l, device.New(t.Device, device.WithLogger(t.Log())))
+		}
+		return l
+	}
+	for _, p := range paths {
+		l = append(l, device.New(p, device.WithLogger(t.Log())))
+	}
+	return l
+}
@@ -0,0 +1,120 @@
+package resfsbtrfs
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/resource"
+)
+
+var _ resource.Snapshoter = (*T)(nil)
+
+// setupCommands installs fake btrfs and findmnt commands in the PATH. The
+// btrfs command records its arguments, and findmnt reports dev mounted on
+// mnt. It returns a func returning the recorded btrfs command lines.
+func setupCommands(t *testing.T, dev, mnt string) func() []string {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "btrfs.log")
+	btrfs := fmt.Sprintf("#!/bin/sh\necho \"$*\" >>%s\n", logFile)
+	findmnt := fmt.Sprintf("#!/bin/sh\necho '{\"filesystems\": [{\"source\": \"%s\", \"target\": \"%s\"}]}'\n", dev, mnt)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "btrfs"), []byte(btrfs), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "findmnt"), []byte(findmnt), 0755))
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+	return func() []string {
+		b, err := os.ReadFile(logFile)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		require.NoError(t, err)
+		return strings.Split(strings.TrimSpace(string(b)), "\n")
+	}
+}
+
+func TestProvisionQgroup(t *testing.T) {
+	size := int64(1024 * 1024)
+	topLevel := t.TempDir()
+
+	t.Run("limits the subvolume qgroup", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Subvol: "/data/svc1/", Size: &size}
+		require.NoError(t, r.provisionQgroup(topLevel))
+		assert.Equal(t, []string{
+			"quota enable " + topLevel,
+			"qgroup limit 1048576 " + filepath.Join(topLevel, "data/svc1"),
+		}, calls())
+	})
+
+	t.Run("skips the top-level subvolume", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Size: &size}
+		require.NoError(t, r.provisionQgroup(topLevel))
+		assert.Empty(t, calls())
+	})
+
+	t.Run("skips without size", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Subvol: "data/svc1"}
+		require.NoError(t, r.provisionQgroup(topLevel))
+		assert.Empty(t, calls())
+	})
+}
+
+func TestManifestKeywordsParity(t *testing.T) {
+	m := T{}.Manifest()
+	options := make(map[string]bool)
+	for _, kw := range m.Keywords {
+		options[kw.Option] = true
+	}
+	for _, option := range []string{"mnt", "dev", "mnt_opt", "stat_timeout", "mkfs_opt", "fsck", "prkey", "scsireserv", "no_preempt_abort", "promote_rw", "user", "group", "perm"} {
+		assert.Truef(t, options[option], "keyword %s is not declared", option)
+	}
+}
+
+func TestStatMountPoint(t *testing.T) {
+	timeout := time.Second
+	r := &T{MountPoint: t.TempDir(), StatTimeout: &timeout}
+	assert.NoError(t, r.statMountPoint())
+
+	r.MountPoint = filepath.Join(r.MountPoint, "missing")
+	assert.Error(t, r.statMountPoint())
+}
+
+func TestSnapshot(t *testing.T) {
+	topLevel := t.TempDir()
+
+	t.Run("creates a read-only snapshot in the subvolume", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Subvol: "data/svc1"}
+		require.NoError(t, r.snapshot(topLevel, "sync1", true))
+		src := filepath.Join(topLevel, "data/svc1")
+		assert.Equal(t, []string{
+			"subvolume snapshot -r " + src + " " + filepath.Join(src, ".snapshots/sync1"),
+		}, calls())
+	})
+
+	t.Run("deletes the snapshot", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Subvol: "data/svc1"}
+		require.NoError(t, r.deleteSnapshot(topLevel, "sync1"))
+		snap := filepath.Join(topLevel, "data/svc1/.snapshots/sync1")
+		assert.Equal(t, []string{
+			"subvolume show " + snap,
+			"subvolume delete " + snap,
+		}, calls())
+	})
+
+	t.Run("rejects invalid names", func(t *testing.T) {
+		calls := setupCommands(t, "/dev/fake", topLevel)
+		r := &T{Device: "/dev/fake", Subvol: "data/svc1"}
+		assert.Error(t, r.snapshot(topLevel, "../sync1", true))
+		assert.Error(t, r.deleteSnapshot(topLevel, ".."))
+		assert.Empty(t, calls())
+	})
+}
@@ -0,0 +1,52 @@
+package resfsbtrfs
+
+import (
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/drivers/resfshost"
+	"opensvc.com/opensvc/util/converters"
+)
+
+var (
+	drvID = driver.NewID(driver.GroupFS, "btrfs")
+)
+
+func init() {
+	driver.Register(drvID, New)
+}
+
+// Manifest exposes to the core the input expected by the driver.
+func (t T) Manifest() *manifest.T {
+	m := manifest.New(drvID, t)
+	m.AddKeyword([]keywords.Keyword{
+		resfshost.KeywordMountPoint,
+		resfshost.KeywordDevice,
+		resfshost.KeywordMountOptions,
+		resfshost.KeywordStatTimeout,
+		resfshost.KeywordMKFSOptions,
+		resfshost.KeywordFSCK,
+		resfshost.KeywordUser,
+		resfshost.KeywordGroup,
+		resfshost.KeywordPerm,
+		resfshost.KeywordPromoteRW,
+		keywords.Keyword{
+			Option:   "subvol",
+			Attr:     "Subvol",
+			Scopable: true,
+			Example:  "data/svc1",
+			Text:     "The path of the subvolume to mount, relative to the btrfs filesystem top-level. Missing parent directories are created on provision. If not set, the top-level subvolume is mounted.",
+		},
+		keywords.Keyword{
+			Option:       "size",
+			Attr:         "Size",
+			Converter:    converters.Size,
+			Scopable:     true,
+			Text:         "If set, the quota group of the subvolume is limited to this referenced size on provision. The quota accounting is enabled on the filesystem if needed.",
+			Provisioning: true,
+		},
+	}...)
+	m.AddKeyword(resource.SCSIPersistentReservationKeywords...)
+	return m
+}
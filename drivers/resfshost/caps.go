@@ -13,6 +13,9 @@ func init() {
 func capabilitiesScanner() ([]string, error) {
 	l := []string{}
 	for _, t := range filesystems.Types() {
+		if t == "btrfs" {
+			continue
+		}
 		if !filesystems.IsCapable(t) {
 			continue
 		}
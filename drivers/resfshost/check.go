@@ -0,0 +1,43 @@
+package resfshost
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func init() {
+	xconfig.RegisterSemanticCheck(xconfig.SemanticCheck{
+		Name:  "fs-mnt-unique",
+		Level: xconfig.ValidateAlertLevelError,
+		Text:  "The fs resources have different mount points.",
+		Func:  checkMntUnique,
+	})
+}
+
+// checkMntUnique verifies no two fs resources mount on the same mount
+// point, as evaluated on the local node.
+func checkMntUnique(t *xconfig.T) []xconfig.SemanticFinding {
+	l := make([]xconfig.SemanticFinding, 0)
+	seen := make(map[string]string)
+	for _, section := range t.ResourceSections(driver.GroupFS) {
+		k := key.New(section, "mnt")
+		mnt := t.GetString(k)
+		if mnt == "" {
+			continue
+		}
+		mnt = filepath.Clean(mnt)
+		if other, ok := seen[mnt]; ok {
+			l = append(l, xconfig.SemanticFinding{
+				Key:     k,
+				Comment: fmt.Sprintf("%s is also the mount point of %s", mnt, other),
+			})
+			continue
+		}
+		seen[mnt] = section
+	}
+	return l
+}
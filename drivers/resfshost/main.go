@@ -36,6 +36,7 @@ type (
 		Zone            string         `json:"zone"`
 		PRKey           string         `json:"prkey"`
 		MKFSOptions     []string       `json:"mkfs_opt"`
+		FSCK            string         `json:"fsck"`
 		User            *user.User     `json:"user"`
 		Group           *user.Group    `json:"group"`
 		Perm            *os.FileMode   `json:"perm"`
@@ -285,14 +286,7 @@ func (t *T) ReservableDevices() device.L {
 }
 
 func (t *T) SubDevices() device.L {
-	l := make(device.L, 0)
-	fs := t.fs()
-	if !fs.IsMultiDevice() {
-		l = append(l, t.device())
-		return l
-	}
-	t.Log().Warn().Msg("TODO: multi dev SubDevices()")
-	return l
+	return device.L{t.device()}
 }
 
 func (t *T) promoteDevicesReadWrite(ctx context.Context) error {
@@ -328,13 +322,26 @@ func (t T) fs() filesystems.I {
 
 func (t *T) fsck() error {
 	fs := t.fs()
-	if !filesystems.HasFSCK(fs) {
-		t.Log().Debug().Msgf("skip fsck, not implemented for type %s", fs)
-		return nil
-	}
-	if err := filesystems.CanFSCK(fs); err != nil {
-		t.Log().Warn().Msgf("skip fsck: %s", err)
+	switch t.FSCK {
+	case "never":
+		t.Log().Debug().Msgf("skip fsck, disabled by policy")
 		return nil
+	case "required":
+		if !filesystems.HasFSCK(fs) {
+			return errors.Errorf("fsck is required by policy but not implemented for type %s", fs)
+		}
+		if err := filesystems.CanFSCK(fs); err != nil {
+			return errors.Wrap(err, "fsck is required by policy")
+		}
+	default:
+		if !filesystems.HasFSCK(fs) {
+			t.Log().Debug().Msgf("skip fsck, not implemented for type %s", fs)
+			return nil
+		}
+		if err := filesystems.CanFSCK(fs); err != nil {
+			t.Log().Warn().Msgf("skip fsck: %s", err)
+			return nil
+		}
 	}
 	return filesystems.DevicesFSCK(fs, t)
 }
@@ -356,14 +363,14 @@ func (t *T) ProvisionLeader(ctx context.Context) error {
 		return errors.Errorf("%s real dev path is empty", t.Device)
 	}
 	if v, err := i1.IsFormated(devpath); err != nil {
-		t.Log().Warn().Msgf("skip mkfs: %s", err)
+		return errors.Wrapf(err, "%s formatted detection", devpath)
 	} else if v {
-		t.Log().Info().Msgf("%s is already formated", t.Device)
+		t.Log().Info().Msgf("%s is already formated", devpath)
 		return nil
 	}
 	i2, ok := fs.(filesystems.MKFSer)
 	if ok {
-		return i2.MKFS(t.Device, t.MKFSOptions)
+		return i2.MKFS(devpath, t.MKFSOptions)
 	}
 	t.Log().Info().Msgf("skip mkfs, not implemented for type %s", fs)
 	return nil
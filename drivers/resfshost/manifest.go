@@ -45,6 +45,14 @@ var (
 		Scopable:     true,
 		Text:         "Eventual mkfs additional options.",
 	}
+	KeywordFSCK = keywords.Keyword{
+		Option:     "fsck",
+		Attr:       "FSCK",
+		Candidates: []string{"auto", "never", "required"},
+		Default:    "auto",
+		Scopable:   true,
+		Text:       "The filesystem check policy applied before mount. ``auto`` runs the type-specific check, a preen for ext and vfat or a read-only check for xfs and btrfs, when implemented and when the check command is installed. ``never`` disables the check. ``required`` aborts the start if the check is not implemented for the type, if the check command is not installed, or if the check reports uncorrected errors.",
+	}
 	KeywordStatTimeout = keywords.Keyword{
 		Option:    "stat_timeout",
 		Attr:      "StatTimeout",
@@ -121,6 +129,7 @@ var (
 		KeywordNoPreemptAbort,
 		KeywordPromoteRW,
 		KeywordMKFSOptions,
+		KeywordFSCK,
 		KeywordZone,
 		KeywordUser,
 		KeywordGroup,
@@ -136,6 +145,7 @@ var (
 		KeywordSCSIReservation,
 		KeywordNoPreemptAbort,
 		KeywordMKFSOptions,
+		KeywordFSCK,
 		KeywordZone,
 		KeywordUser,
 		KeywordGroup,
@@ -145,6 +155,10 @@ var (
 
 func init() {
 	for _, t := range filesystems.Types() {
+		if t == "btrfs" {
+			// served by the dedicated resfsbtrfs driver
+			continue
+		}
 		driver.Register(driver.NewID(driver.GroupFS, t), NewF(t))
 	}
 }
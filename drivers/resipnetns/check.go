@@ -0,0 +1,64 @@
+//go:build linux
+
+package resipnetns
+
+import (
+	"fmt"
+
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/resourceid"
+	"opensvc.com/opensvc/core/xconfig"
+	"opensvc.com/opensvc/util/key"
+)
+
+func init() {
+	xconfig.RegisterSemanticCheck(xconfig.SemanticCheck{
+		Name:  "ip-netns-container",
+		Level: xconfig.ValidateAlertLevelError,
+		Text:  "The netns of the netns ip resources is a container resource defined in the configuration.",
+		Func:  checkNetNSContainer,
+	})
+}
+
+// checkNetNSContainer verifies the netns keyword, or its container_rid
+// alias, of the ip resources driven by this driver references a container
+// resource section.
+func checkNetNSContainer(t *xconfig.T) []xconfig.SemanticFinding {
+	l := make([]xconfig.SemanticFinding, 0)
+	for _, section := range t.ResourceSections(driver.GroupIP) {
+		switch t.Get(key.New(section, "type")) {
+		case drvID.Name, altDrvID.Name:
+		default:
+			continue
+		}
+		k := key.New(section, "netns")
+		if !t.HasKey(k) {
+			k = key.New(section, "container_rid")
+		}
+		did := driver.NewID(driver.GroupIP, t.Get(key.New(section, "type")))
+		rid := t.GetString(k)
+		switch {
+		case rid == "":
+			l = append(l, xconfig.SemanticFinding{
+				Key:     key.New(section, "netns"),
+				Driver:  did,
+				Comment: "no container resource set",
+			})
+		case !t.HasSectionString(rid):
+			l = append(l, xconfig.SemanticFinding{
+				Key:     k,
+				Driver:  did,
+				Comment: fmt.Sprintf("the container resource %s is not defined", rid),
+			})
+		default:
+			if r, err := resourceid.Parse(rid); err != nil || r.DriverGroup() != driver.GroupContainer {
+				l = append(l, xconfig.SemanticFinding{
+					Key:     k,
+					Driver:  did,
+					Comment: fmt.Sprintf("%s is not a container resource", rid),
+				})
+			}
+		}
+	}
+	return l
+}
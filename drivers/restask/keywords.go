@@ -0,0 +1,52 @@
+package restask
+
+import (
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/util/converters"
+)
+
+var (
+	KWSchedule = keywords.Keyword{
+		Option:        "schedule",
+		DefaultOption: "run_schedule",
+		Attr:          "Schedule",
+		Scopable:      true,
+		Text:          "Set the this task run schedule. See ``/usr/share/doc/opensvc/schedule`` for the schedule syntax reference.",
+		Example:       "00:00-01:00 mon",
+	}
+	KWSnooze = keywords.Keyword{
+		Option:    "snooze",
+		Attr:      "Snooze",
+		Converter: converters.Duration,
+		Scopable:  true,
+		Text:      "Snooze the task status alert before running the task, so if the command is known to fail or cause a service status degradation the user can decide to silence the alert for the duration set as value. While snoozed, a failed last run is reported as n/a with a status log entry. An already failing task can be snoozed with the snooze action.",
+		Example:   "10m",
+	}
+	KWLogOutputs = keywords.Keyword{
+		Option:    "log",
+		Attr:      "LogOutputs",
+		Converter: converters.Bool,
+		Text:      "Log the task outputs in the service log.",
+	}
+	KWOnErrorCmd = keywords.Keyword{
+		Option:   "on_error",
+		Attr:     "OnErrorCmd",
+		Scopable: true,
+		Text:     "A command to execute on :c-action:`run` action if :kw:`command` returned an error.",
+		Example:  "/srv/{name}/data/scripts/task_on_error.sh",
+	}
+	KWCheck = keywords.Keyword{
+		Option:     "check",
+		Attr:       "Check",
+		Candidates: []string{"last_run", ""},
+		Scopable:   true,
+		Text:       "If set to 'last_run', the last run retcode is used to report a task resource status. If not set (default), the status of a task is always n/a.",
+		Example:    "last_run",
+	}
+	KWConfirmation = keywords.Keyword{
+		Option:    "confirmation",
+		Attr:      "Confirmation",
+		Converter: converters.Bool,
+		Text:      "If set to True, ask for an interactive confirmation to run the task. This flag can be used for dangerous tasks like data-restore.",
+	}
+)
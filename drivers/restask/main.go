@@ -0,0 +1,247 @@
+// Package restask provides the keywords and helpers shared by the task
+// drivers.
+package restask
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/client"
+	"opensvc.com/opensvc/core/instance"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/util/confirmation"
+)
+
+type (
+	// Locker is the interface of the task resources the run lock helpers
+	// can be applied on.
+	Locker interface {
+		resource.Driver
+		DoWithLock(disable bool, timeout time.Duration, intent string, f func() error) error
+	}
+
+	freshStatuser interface {
+		FreshStatus(context.Context) (instance.Status, error)
+	}
+)
+
+var (
+	// running is the set of run lock paths held by this process.
+	// The run lock is a fcntl lock, so testing it from the process
+	// holding it would succeed and release it.
+	running   = make(map[string]bool)
+	runningMu sync.Mutex
+)
+
+func runLockFile(r resource.Driver) string {
+	return filepath.Join(r.VarDir(), "run")
+}
+
+func setRunning(r resource.Driver, v bool) {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	if v {
+		running[runLockFile(r)] = true
+	} else {
+		delete(running, runLockFile(r))
+	}
+}
+
+func isRunningHere(r resource.Driver) bool {
+	runningMu.Lock()
+	defer runningMu.Unlock()
+	return running[runLockFile(r)]
+}
+
+// IsRunning returns true if the run lock of the task is held, by this
+// process or another.
+func IsRunning(r Locker) bool {
+	if isRunningHere(r) {
+		return true
+	}
+	err := r.DoWithLock(false, time.Second*0, "run", func() error {
+		return nil
+	})
+	return err != nil
+}
+
+// DoWithLock acquires the task run lock, rewrites the object instance
+// status so the task appears in the running resources list, and calls f.
+func DoWithLock(ctx context.Context, r Locker, f func() error) error {
+	disable := actioncontext.IsLockDisabled(ctx)
+	timeout := actioncontext.LockTimeout(ctx)
+	return r.DoWithLock(disable, timeout, "run", func() error {
+		setRunning(r, true)
+		defer setRunning(r, false)
+		refreshStatus(ctx, r)
+		return f()
+	})
+}
+
+func refreshStatus(ctx context.Context, r resource.Driver) {
+	i, ok := r.GetObject().(freshStatuser)
+	if !ok {
+		return
+	}
+	if _, err := i.FreshStatus(ctx); err != nil {
+		r.Log().Debug().Err(err).Msg("status refresh after run lock acquire")
+	}
+}
+
+// HandleConfirmation returns nil if the run is confirmed, either by the
+// --confirm command line option or interactively.
+func HandleConfirmation(ctx context.Context, r resource.Driver) error {
+	if actioncontext.IsConfirm(ctx) {
+		r.Log().Info().Msg("run confirmed by --confirm command line option")
+		return nil
+	}
+	if actioncontext.IsCron(ctx) {
+		// as set by the daemon scheduler subsystem
+		return fmt.Errorf("run aborted (--cron)")
+	}
+	if !isatty.IsTerminal(os.Stdin.Fd()) {
+		return fmt.Errorf("run aborted (stdin is not a tty)")
+	}
+	description := fmt.Sprintf(`The resource %s requires a run confirmation.
+Please make sure you fully understand its role and effects before confirming the run.
+Enter "yes" if you really want to run.`, r.RID())
+	s, err := confirmation.ReadLn(description, time.Second*30)
+	if err != nil {
+		return errors.Wrap(err, "read confirmation")
+	}
+	if s == "yes" {
+		r.Log().Info().Msg("run confirmed interactively")
+		return nil
+	}
+	return fmt.Errorf("run aborted")
+}
+
+// NotifyRunDone tells the daemon the run of the task is done, so it can
+// update the scheduler and instance status.
+func NotifyRunDone(r resource.Driver, p string) error {
+	c, err := client.New()
+	if err != nil {
+		return err
+	}
+	req := c.NewPostRunDone()
+	req.RIDs = []string{r.RID()}
+	req.Action = "run"
+	req.Path = p
+	_, err = req.Do()
+	if err != nil {
+		r.Log().Warn().Msgf("failed to notify the daemon the run is done: %s", err)
+		return err
+	}
+	r.Log().Debug().Msg("daemon notified the run is done")
+	return nil
+}
+
+func lastRunFile(r resource.Driver) string {
+	return filepath.Join(r.VarDir(), "last_run_retcode")
+}
+
+// WriteLastRun stores the exit code of the last task run.
+func WriteLastRun(r resource.Driver, retcode int) error {
+	p := lastRunFile(r)
+	f, err := os.Create(p)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	fmt.Fprintf(f, "%d\n", retcode)
+	return nil
+}
+
+// ReadLastRun returns the exit code of the last task run.
+func ReadLastRun(r resource.Driver) (int, error) {
+	p := lastRunFile(r)
+	if b, err := os.ReadFile(p); err != nil {
+		return 0, err
+	} else {
+		return strconv.Atoi(strings.TrimSpace(string(b)))
+	}
+}
+
+func snoozeFile(r resource.Driver) string {
+	return filepath.Join(r.VarDir(), "snooze_until")
+}
+
+// Snooze silences the task status alert for the duration d. A zero
+// duration ends the snooze.
+func Snooze(r resource.Driver, d time.Duration) error {
+	if d <= 0 {
+		return unsnooze(r)
+	}
+	until := time.Now().Add(d)
+	r.Log().Info().Msgf("snooze the status alert until %s", until.Format(time.RFC3339))
+	return os.WriteFile(snoozeFile(r), []byte(until.Format(time.RFC3339)+"\n"), 0644)
+}
+
+func unsnooze(r resource.Driver) error {
+	err := os.Remove(snoozeFile(r))
+	switch {
+	case err == nil:
+		r.Log().Info().Msg("snooze of the status alert ended")
+		return nil
+	case os.IsNotExist(err):
+		return nil
+	default:
+		return err
+	}
+}
+
+// SnoozedUntil returns the time the task status alert is snoozed until,
+// or the zero time if the alert is not snoozed.
+func SnoozedUntil(r resource.Driver) time.Time {
+	b, err := os.ReadFile(snoozeFile(r))
+	if err != nil {
+		return time.Time{}
+	}
+	until, err := time.Parse(time.RFC3339, strings.TrimSpace(string(b)))
+	if err != nil {
+		return time.Time{}
+	}
+	if time.Now().After(until) {
+		return time.Time{}
+	}
+	return until
+}
+
+// StatusLastRun returns the task resource status from the last run exit
+// code, converted by the driver-specific toStatus function. A failed last
+// run is reported as n/a while the status alert is snoozed.
+func StatusLastRun(ctx context.Context, r resource.Driver, toStatus func(int) (status.T, error)) status.T {
+	if err := resource.StatusCheckRequires(ctx, r); err != nil {
+		r.StatusLog().Info("requirements not met")
+		return status.NotApplicable
+	}
+	i, err := ReadLastRun(r)
+	if err != nil {
+		r.StatusLog().Info("never run")
+		return status.NotApplicable
+	}
+	s, err := toStatus(i)
+	if err != nil {
+		r.StatusLog().Info("%s", err)
+	}
+	if s == status.Up {
+		return s
+	}
+	if until := SnoozedUntil(r); !until.IsZero() {
+		r.StatusLog().Info("last run failed (%d), snoozed until %s", i, until.Format(time.RFC3339))
+		return status.NotApplicable
+	}
+	r.StatusLog().Info("last run failed (%d)", i)
+	return s
+}
@@ -0,0 +1,115 @@
+package restask
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/core/provisioned"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+)
+
+type (
+	testObject struct {
+		varDir string
+		log    zerolog.Logger
+	}
+
+	testTask struct {
+		resource.T
+	}
+)
+
+func (t *testObject) Log() *zerolog.Logger                                    { return &t.log }
+func (t *testObject) VarDir() string                                          { return t.varDir }
+func (t *testObject) ResourceByID(string) resource.Driver                     { return nil }
+func (t *testObject) ResourcesByDrivergroups([]driver.Group) resource.Drivers { return nil }
+
+func (t *testTask) Label() string                       { return "test" }
+func (t *testTask) Start(context.Context) error         { return nil }
+func (t *testTask) Stop(context.Context) error          { return nil }
+func (t *testTask) Provision(context.Context) error     { return nil }
+func (t *testTask) Unprovision(context.Context) error   { return nil }
+func (t *testTask) Provisioned() (provisioned.T, error) { return provisioned.NotApplicable, nil }
+func (t *testTask) Manifest() *manifest.T {
+	return manifest.New(driver.NewID(driver.GroupTask, "test"), t)
+}
+func (t *testTask) Status(ctx context.Context) status.T {
+	return StatusLastRun(ctx, t, exitCodeToStatus)
+}
+func (t *testTask) SnoozeAlert(d time.Duration) error { return Snooze(t, d) }
+
+func exitCodeToStatus(exitCode int) (status.T, error) {
+	if exitCode == 0 {
+		return status.Up, nil
+	}
+	return status.Down, nil
+}
+
+func newTestTask(t *testing.T) *testTask {
+	r := &testTask{}
+	require.NoError(t, r.SetRID("task#1"))
+	varDir := t.TempDir()
+	r.SetObject(&testObject{varDir: varDir, log: zerolog.Nop()})
+	require.NoError(t, os.MkdirAll(r.VarDir(), 0755))
+	return r
+}
+
+func TestLastRun(t *testing.T) {
+	r := newTestTask(t)
+	_, err := ReadLastRun(r)
+	assert.Error(t, err)
+	require.NoError(t, WriteLastRun(r, 3))
+	i, err := ReadLastRun(r)
+	require.NoError(t, err)
+	assert.Equal(t, 3, i)
+}
+
+func TestStatusLastRun(t *testing.T) {
+	ctx := actioncontext.WithProps(context.Background(), actioncontext.Status)
+	r := newTestTask(t)
+	assert.Equal(t, status.NotApplicable, r.Status(ctx), "never run")
+
+	require.NoError(t, WriteLastRun(r, 0))
+	assert.Equal(t, status.Up, r.Status(ctx))
+
+	require.NoError(t, WriteLastRun(r, 1))
+	assert.Equal(t, status.Down, r.Status(ctx))
+}
+
+func TestSnoozeFailingTask(t *testing.T) {
+	ctx := actioncontext.WithProps(context.Background(), actioncontext.Status)
+	r := newTestTask(t)
+	require.NoError(t, WriteLastRun(r, 1))
+	require.Equal(t, status.Down, r.Status(ctx))
+
+	require.NoError(t, r.SnoozeAlert(time.Hour))
+	until := SnoozedUntil(r)
+	assert.WithinDuration(t, time.Now().Add(time.Hour), until, time.Minute)
+	r.StatusLog().Reset()
+	assert.Equal(t, status.NotApplicable, r.Status(ctx), "a snoozed failing task is n/a")
+	entries := r.StatusLog().Entries()
+	require.Len(t, entries, 1)
+	assert.Contains(t, entries[0].Message, "snoozed until")
+
+	require.NoError(t, r.SnoozeAlert(0))
+	assert.True(t, SnoozedUntil(r).IsZero())
+	assert.Equal(t, status.Down, r.Status(ctx), "the end of the snooze restores the alert")
+	assert.NoError(t, r.SnoozeAlert(0), "ending a snooze twice is not an error")
+}
+
+func TestSnoozeExpired(t *testing.T) {
+	r := newTestTask(t)
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	require.NoError(t, os.WriteFile(snoozeFile(r), []byte(past+"\n"), 0644))
+	assert.True(t, SnoozedUntil(r).IsZero())
+}
@@ -0,0 +1,152 @@
+package restaskdocker
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kballard/go-shellquote"
+
+	"opensvc.com/opensvc/core/env"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/rescontainerdocker"
+	"opensvc.com/opensvc/drivers/restask"
+	"opensvc.com/opensvc/util/command"
+)
+
+// T is the driver structure.
+type T struct {
+	rescontainerdocker.T
+	OnErrorCmd   string
+	Check        string
+	Schedule     string
+	Confirmation bool
+	LogOutputs   bool
+	RunTimeout   *time.Duration
+	Snooze       *time.Duration
+}
+
+func New() resource.Driver {
+	return &T{}
+}
+
+func (t T) IsRunning() bool {
+	return restask.IsRunning(&t)
+}
+
+// Start is a noop for tasks.
+func (t T) Start(ctx context.Context) error {
+	return nil
+}
+
+// Stop is a noop for tasks.
+func (t *T) Stop(ctx context.Context) error {
+	return nil
+}
+
+func (t T) Run(ctx context.Context) error {
+	return restask.DoWithLock(ctx, &t, func() error {
+		return t.lockedRun(ctx)
+	})
+}
+
+func (t T) lockedRun(ctx context.Context) error {
+	if !env.HasDaemonOrigin() {
+		defer restask.NotifyRunDone(&t, t.Path.String())
+	}
+	if t.Confirmation {
+		if err := restask.HandleConfirmation(ctx, &t); err != nil {
+			return err
+		}
+	}
+	if err := t.ApplyPGChain(ctx); err != nil {
+		return err
+	}
+	if t.Snooze != nil && *t.Snooze > 0 {
+		if err := restask.Snooze(&t, *t.Snooze); err != nil {
+			t.Log().Warn().Err(err).Msg("snooze")
+		}
+	}
+	var timeout time.Duration
+	if t.RunTimeout != nil {
+		timeout = *t.RunTimeout
+	}
+	exitCode, err := t.RunOnce(ctx, timeout, t.LogOutputs)
+	if err != nil && exitCode == 0 {
+		// the container did not run to completion, a timeout for
+		// example. Record a failure for the last_run status check.
+		exitCode = 1
+	}
+	if err := restask.WriteLastRun(&t, exitCode); err != nil {
+		return err
+	}
+	if err != nil {
+		if err := t.onError(); err != nil {
+			t.Log().Warn().Msgf("%s", err)
+		}
+		return err
+	}
+	if exitCode != 0 {
+		if err := t.onError(); err != nil {
+			t.Log().Warn().Msgf("%s", err)
+		}
+		return fmt.Errorf("container exited with code %d", exitCode)
+	}
+	return nil
+}
+
+func (t T) onError() error {
+	if t.OnErrorCmd == "" {
+		return nil
+	}
+	argv, err := shellquote.Split(t.OnErrorCmd)
+	if err != nil {
+		return err
+	}
+	if len(argv) == 0 {
+		return nil
+	}
+	cmd := command.New(
+		command.WithName(argv[0]),
+		command.WithVarArgs(argv[1:]...),
+		command.WithLogger(t.Log()),
+	)
+	t.Log().Info().Stringer("cmd", cmd).Msg("on error run")
+	return cmd.Run()
+}
+
+// SnoozeAlert silences the task status alert for the duration d, for
+// example when the last run is known to have failed.
+func (t *T) SnoozeAlert(d time.Duration) error {
+	return restask.Snooze(t, d)
+}
+
+func (t *T) Status(ctx context.Context) status.T {
+	switch t.Check {
+	case "last_run":
+		return restask.StatusLastRun(ctx, t, exitCodeToStatus)
+	default:
+		return status.NotApplicable
+	}
+}
+
+func exitCodeToStatus(exitCode int) (status.T, error) {
+	if exitCode == 0 {
+		return status.Up, nil
+	}
+	return status.Down, nil
+}
+
+// Label returns a formatted short description of the Resource
+func (t T) Label() string {
+	return t.Image
+}
+
+func (t T) ScheduleOptions() resource.ScheduleOptions {
+	return resource.ScheduleOptions{
+		Action: "run",
+		Option: "schedule",
+		Base:   "",
+	}
+}
@@ -0,0 +1,91 @@
+package restaskdocker
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"opensvc.com/opensvc/core/actioncontext"
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/resource"
+	"opensvc.com/opensvc/core/status"
+	"opensvc.com/opensvc/drivers/restask"
+)
+
+type testObject struct {
+	varDir string
+	log    zerolog.Logger
+}
+
+func (t *testObject) Log() *zerolog.Logger                                    { return &t.log }
+func (t *testObject) VarDir() string                                          { return t.varDir }
+func (t *testObject) ResourceByID(string) resource.Driver                     { return nil }
+func (t *testObject) ResourcesByDrivergroups([]driver.Group) resource.Drivers { return nil }
+
+func newTestTask(t *testing.T) *T {
+	r := &T{}
+	require.NoError(t, r.SetRID("task#1"))
+	r.SetObject(&testObject{varDir: t.TempDir(), log: zerolog.Nop()})
+	require.NoError(t, os.MkdirAll(r.VarDir(), 0755))
+	return r
+}
+
+func TestStatus(t *testing.T) {
+	ctx := actioncontext.WithProps(context.Background(), actioncontext.Status)
+
+	t.Run("is n/a without check", func(t *testing.T) {
+		r := newTestTask(t)
+		require.NoError(t, restask.WriteLastRun(r, 1))
+		assert.Equal(t, status.NotApplicable, r.Status(ctx))
+	})
+
+	t.Run("reports the last run exit code", func(t *testing.T) {
+		r := newTestTask(t)
+		r.Check = "last_run"
+		require.NoError(t, restask.WriteLastRun(r, 0))
+		assert.Equal(t, status.Up, r.Status(ctx))
+		require.NoError(t, restask.WriteLastRun(r, 125))
+		assert.Equal(t, status.Down, r.Status(ctx))
+	})
+
+	t.Run("is n/a while snoozed", func(t *testing.T) {
+		r := newTestTask(t)
+		r.Check = "last_run"
+		require.NoError(t, restask.WriteLastRun(r, 1))
+		require.NoError(t, r.SnoozeAlert(time.Hour))
+		assert.Equal(t, status.NotApplicable, r.Status(ctx))
+		require.NoError(t, r.SnoozeAlert(0))
+		assert.Equal(t, status.Down, r.Status(ctx))
+	})
+}
+
+func TestOnError(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "onerror.log")
+	script := fmt.Sprintf("#!/bin/sh\necho \"$*\" >>%s\n", logFile)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "onerror"), []byte(script), 0755))
+	t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+	r := newTestTask(t)
+	require.NoError(t, r.onError(), "no on_error command is not an error")
+	assert.NoFileExists(t, logFile)
+
+	r.OnErrorCmd = "onerror 'a b' c"
+	require.NoError(t, r.onError())
+	b, err := os.ReadFile(logFile)
+	require.NoError(t, err)
+	assert.Equal(t, "a b c\n", string(b))
+}
+
+func TestScheduleOptions(t *testing.T) {
+	r := newTestTask(t)
+	assert.Equal(t, "run", r.ScheduleOptions().Action)
+	assert.Equal(t, "schedule", r.ScheduleOptions().Option)
+}
@@ -0,0 +1,101 @@
+package restaskdocker
+
+import (
+	"opensvc.com/opensvc/core/driver"
+	"opensvc.com/opensvc/core/keywords"
+	"opensvc.com/opensvc/core/manifest"
+	"opensvc.com/opensvc/drivers/rescontainerdocker"
+	"opensvc.com/opensvc/drivers/restask"
+	"opensvc.com/opensvc/util/converters"
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+var (
+	drvID = driver.NewID(driver.GroupTask, "docker")
+
+	// containerKeywords are the container.docker keywords relevant to a
+	// one-shot container. The lifecycle keywords like detach, rm or the
+	// start and stop timeouts are driven by the task.
+	containerKeywords = []string{
+		"name",
+		"hostname",
+		"dns_search",
+		"image",
+		"image_pull_policy",
+		"cwd",
+		"command",
+		"run_args",
+		"entrypoint",
+		"privileged",
+		"interactive",
+		"tty",
+		"volume_mounts",
+		"environment",
+		"configs_environment",
+		"secrets_environment",
+		"devices",
+		"netns",
+		"userns",
+		"pidns",
+		"ipcns",
+		"utsns",
+		"registry_creds",
+		"pull_timeout",
+	}
+
+	Keywords = []keywords.Keyword{
+		restask.KWSchedule,
+		{
+			Option:    "timeout",
+			Attr:      "RunTimeout",
+			Converter: converters.Duration,
+			Scopable:  true,
+			Text:      "Wait for <duration> before declaring the task run action a failure and killing the container. If no timeout is set, the agent waits indefinitely for the container to exit.",
+			Example:   "5m",
+		},
+		restask.KWSnooze,
+		restask.KWLogOutputs,
+		restask.KWOnErrorCmd,
+		restask.KWCheck,
+		restask.KWConfirmation,
+	}
+)
+
+func init() {
+	driver.Register(drvID, New)
+}
+
+// Manifest exposes to the core the input expected by the driver.
+func (t T) Manifest() *manifest.T {
+	m := manifest.New(drvID, t)
+	m.AddContext([]manifest.Context{
+		{
+			Key:  "path",
+			Attr: "Path",
+			Ref:  "object.path",
+		},
+		{
+			Key:  "object_id",
+			Attr: "ObjectID",
+			Ref:  "object.id",
+		},
+		{
+			Key:  "dns",
+			Attr: "DNS",
+			Ref:  "node.dns",
+		},
+	}...)
+	seen := make(map[string]interface{})
+	for _, kw := range rescontainerdocker.Keywords {
+		if !stringslice.Has(kw.Option, containerKeywords) {
+			continue
+		}
+		if _, ok := seen[kw.Option]; ok {
+			continue
+		}
+		seen[kw.Option] = nil
+		m.AddKeyword(kw)
+	}
+	m.AddKeyword(Keywords...)
+	return m
+}
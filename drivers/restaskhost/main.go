@@ -1,31 +1,19 @@
 package restaskhost
 
-// TODO
-// * snooze
-// * status.json rewrite after lock acquire
-
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strconv"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/mattn/go-isatty"
-	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 
-	"opensvc.com/opensvc/core/actioncontext"
-	"opensvc.com/opensvc/core/client"
 	"opensvc.com/opensvc/core/env"
 	"opensvc.com/opensvc/core/resource"
 	"opensvc.com/opensvc/core/status"
 	"opensvc.com/opensvc/drivers/resapp"
+	"opensvc.com/opensvc/drivers/restask"
 	"opensvc.com/opensvc/util/command"
-	"opensvc.com/opensvc/util/confirmation"
 	"opensvc.com/opensvc/util/funcopt"
 	"opensvc.com/opensvc/util/proc"
 )
@@ -47,10 +35,7 @@ func New() resource.Driver {
 }
 
 func (t T) IsRunning() bool {
-	err := t.DoWithLock(false, time.Second*0, "run", func() error {
-		return nil
-	})
-	return err != nil
+	return restask.IsRunning(&t)
 }
 
 // Start the Resource
@@ -59,12 +44,9 @@ func (t T) Start(ctx context.Context) (err error) {
 }
 
 func (t T) Run(ctx context.Context) error {
-	disable := actioncontext.IsLockDisabled(ctx)
-	timeout := actioncontext.LockTimeout(ctx)
-	err := t.DoWithLock(disable, timeout, "run", func() error {
+	return restask.DoWithLock(ctx, &t, func() error {
 		return t.lockedRun(ctx)
 	})
-	return err
 }
 
 func (t T) lockedRun(ctx context.Context) (err error) {
@@ -81,6 +63,11 @@ func (t T) lockedRun(ctx context.Context) (err error) {
 	if len(opts) == 0 {
 		return nil
 	}
+	if t.Snooze != nil && *t.Snooze > 0 {
+		if err := restask.Snooze(&t, *t.Snooze); err != nil {
+			t.Log().Warn().Err(err).Msg("snooze")
+		}
+	}
 	if err := t.ApplyPGChain(ctx); err != nil {
 		return err
 	}
@@ -98,7 +85,7 @@ func (t T) lockedRun(ctx context.Context) (err error) {
 	cmd := command.New(opts...)
 	t.Log().Info().Stringer("cmd", cmd).Msg("run")
 	err = cmd.Run()
-	if err := t.writeLastRun(cmd.ExitCode()); err != nil {
+	if err := restask.WriteLastRun(&t, cmd.ExitCode()); err != nil {
 		return err
 	}
 	if err != nil {
@@ -176,59 +163,21 @@ func (t *T) stop(ctx context.Context) error {
 	return fmt.Errorf("waited too long for process %s to disappear", procs)
 }
 
+// SnoozeAlert silences the task status alert for the duration d, for
+// example when the last run is known to have failed.
+func (t *T) SnoozeAlert(d time.Duration) error {
+	return restask.Snooze(t, d)
+}
+
 func (t *T) Status(ctx context.Context) status.T {
 	switch t.Check {
 	case "last_run":
-		return t.statusLastRun(ctx)
+		return restask.StatusLastRun(ctx, t, t.ExitCodeToStatus)
 	default:
 		return status.NotApplicable
 	}
 }
 
-func (t T) writeLastRun(retcode int) error {
-	p := t.lastRunFile()
-	f, err := os.Create(p)
-	if err != nil {
-		return err
-	}
-	defer f.Close()
-	fmt.Fprintf(f, "%d\n", retcode)
-	return nil
-}
-
-func (t T) readLastRun() (int, error) {
-	p := t.lastRunFile()
-	if b, err := os.ReadFile(p); err != nil {
-		return 0, err
-	} else {
-		return strconv.Atoi(strings.TrimSpace(string(b)))
-	}
-}
-
-func (t T) lastRunFile() string {
-	return filepath.Join(t.VarDir(), "last_run_retcode")
-}
-
-func (t *T) statusLastRun(ctx context.Context) status.T {
-	if err := resource.StatusCheckRequires(ctx, t); err != nil {
-		t.StatusLog().Info("requirements not met")
-		return status.NotApplicable
-	}
-	if i, err := t.readLastRun(); err != nil {
-		t.StatusLog().Info("never run")
-		return status.NotApplicable
-	} else {
-		s, err := t.ExitCodeToStatus(i)
-		if err != nil {
-			t.StatusLog().Info("%s", err)
-		}
-		if s != status.Up {
-			t.StatusLog().Info("last run failed (%d)", i)
-		}
-		return s
-	}
-}
-
 func (t *T) running(ctx context.Context) bool {
 	var s status.T
 	if t.CheckCmd != "" {
@@ -280,47 +229,11 @@ func (t T) handleConfirmation(ctx context.Context) error {
 	if !t.Confirmation {
 		return nil
 	}
-	if actioncontext.IsConfirm(ctx) {
-		t.Log().Info().Msg("run confirmed by --confirm command line option")
-		return nil
-	}
-	if actioncontext.IsCron(ctx) {
-		// as set by the daemon scheduler subsystem
-		return fmt.Errorf("run aborted (--cron)")
-	}
-	if !isatty.IsTerminal(os.Stdin.Fd()) {
-		return fmt.Errorf("run aborted (stdin is not a tty)")
-	}
-	description := fmt.Sprintf(`The resource %s requires a run confirmation.
-Please make sure you fully understand its role and effects before confirming the run.
-Enter "yes" if you really want to run.`, t.RID())
-	s, err := confirmation.ReadLn(description, time.Second*30)
-	if err != nil {
-		return errors.Wrap(err, "read confirmation")
-	}
-	if s == "yes" {
-		t.Log().Info().Msg("run confirmed interactively")
-		return nil
-	}
-	return fmt.Errorf("run aborted")
+	return restask.HandleConfirmation(ctx, &t)
 }
 
 func (t T) notifyRunDone() error {
-	c, err := client.New()
-	if err != nil {
-		return err
-	}
-	req := c.NewPostRunDone()
-	req.RIDs = []string{t.RID()}
-	req.Action = "run"
-	req.Path = t.Path.String()
-	_, err = req.Do()
-	if err != nil {
-		t.Log().Warn().Msgf("failed to notify the daemon the run is done: %s", err)
-		return err
-	}
-	t.Log().Debug().Msg("daemon notified the run is done")
-	return nil
+	return restask.NotifyRunDone(&t, t.Path.String())
 }
 
 func (t T) ScheduleOptions() resource.ScheduleOptions {
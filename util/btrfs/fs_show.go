@@ -0,0 +1,81 @@
+package btrfs
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	// FilesystemInfo is the parsed output of "btrfs filesystem show --raw".
+	// Sizes are in bytes.
+	FilesystemInfo struct {
+		Label   string
+		UUID    string
+		Devices []string
+		Size    int64
+		Used    int64
+	}
+)
+
+// Free returns the estimated free space, in bytes.
+func (t FilesystemInfo) Free() int64 {
+	return t.Size - t.Used
+}
+
+func parseFilesystemInfo(b []byte) (FilesystemInfo, error) {
+	data := FilesystemInfo{
+		Devices: make([]string, 0),
+	}
+	parseInt := func(s string) (int64, error) {
+		return strconv.ParseInt(s, 10, 64)
+	}
+	for _, line := range strings.Split(string(b), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) >= 4 && fields[0] == "Label:":
+			data.Label = strings.Trim(fields[1], "'")
+			data.UUID = fields[len(fields)-1]
+		case len(fields) == 7 && fields[0] == "Total" && fields[3] == "FS":
+			i, err := parseInt(fields[6])
+			if err != nil {
+				return FilesystemInfo{}, errors.Wrapf(err, "unexpected line: %s", line)
+			}
+			data.Used = i
+		case len(fields) >= 8 && fields[0] == "devid":
+			i, err := parseInt(fields[3])
+			if err != nil {
+				return FilesystemInfo{}, errors.Wrapf(err, "unexpected line: %s", line)
+			}
+			data.Size += i
+			data.Devices = append(data.Devices, fields[len(fields)-1])
+		}
+	}
+	if len(data.Devices) == 0 {
+		return FilesystemInfo{}, errors.Errorf("unexpected 'btrfs filesystem show --raw' output: %s", string(b))
+	}
+	return data, nil
+}
+
+// FilesystemShow returns the information about the btrfs filesystem
+// hosted by <dev>. <dev> can be any member device, a mount point, a
+// label or an uuid. The filesystem does not need to be mounted.
+func FilesystemShow(dev string, log *zerolog.Logger) (FilesystemInfo, error) {
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("filesystem", "show", "--raw", dev),
+		command.WithBufferedStdout(),
+		command.WithLogger(log),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+	)
+	b, err := cmd.Output()
+	if err != nil {
+		return FilesystemInfo{}, err
+	}
+	return parseFilesystemInfo(b)
+}
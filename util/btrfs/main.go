@@ -0,0 +1,10 @@
+package btrfs
+
+import "os/exec"
+
+func IsCapable() bool {
+	if _, err := exec.LookPath("btrfs"); err != nil {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,34 @@
+package btrfs
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFilesystemInfo(t *testing.T) {
+	b := []byte(`Label: 'data'  uuid: 5a1b5f1e-3b8c-4bd1-9b4f-1a8d2d5d3e2a
+	Total devices 2 FS bytes used 147456
+	devid    1 size 5368709120 used 1098907648 path /dev/sdb
+	devid    2 size 5368709120 used 1082130432 path /dev/sdc
+
+`)
+	info, err := parseFilesystemInfo(b)
+	require.Nil(t, err)
+	require.Equal(t, "data", info.Label)
+	require.Equal(t, "5a1b5f1e-3b8c-4bd1-9b4f-1a8d2d5d3e2a", info.UUID)
+	require.Equal(t, []string{"/dev/sdb", "/dev/sdc"}, info.Devices)
+	require.Equal(t, int64(10737418240), info.Size)
+	require.Equal(t, int64(147456), info.Used)
+	require.Equal(t, int64(10737270784), info.Free())
+
+	_, err = parseFilesystemInfo([]byte("ERROR: not a valid btrfs filesystem: /dev/sdd"))
+	require.NotNil(t, err)
+}
+
+func TestSubvolSnapshotOptsToArgs(t *testing.T) {
+	opts := subvolSnapshotOpts{Src: "/srv/data", Dst: "/srv/data/.snapshots/s1"}
+	require.Equal(t, []string{"subvolume", "snapshot", "/srv/data", "/srv/data/.snapshots/s1"}, subvolSnapshotOptsToArgs(opts))
+	opts.ReadOnly = true
+	require.Equal(t, []string{"subvolume", "snapshot", "-r", "/srv/data", "/srv/data/.snapshots/s1"}, subvolSnapshotOptsToArgs(opts))
+}
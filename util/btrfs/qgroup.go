@@ -0,0 +1,40 @@
+package btrfs
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+// EnableQuota enables the quota groups accounting on the btrfs filesystem
+// mounted at <mnt>. The operation is idempotent.
+func EnableQuota(mnt string, log *zerolog.Logger) error {
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("quota", "enable", mnt),
+		command.WithLogger(log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+// SetQgroupLimit sets the referenced space limit of the subvolume quota group.
+// A nil size removes the limit.
+func (t *Subvolume) SetQgroupLimit(size *int64) error {
+	limit := "none"
+	if size != nil {
+		limit = fmt.Sprint(*size)
+	}
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("qgroup", "limit", limit, t.Path),
+		command.WithLogger(t.Log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,36 @@
+package btrfs
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	// Subvolume is a btrfs subvolume, identified by its path in a
+	// mounted btrfs filesystem.
+	Subvolume struct {
+		Path string
+		Log  *zerolog.Logger
+	}
+)
+
+func (t *Subvolume) Exists() (bool, error) {
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("subvolume", "show", t.Path),
+		command.WithLogger(t.Log),
+		command.WithBufferedStderr(),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+	)
+	err := cmd.Run()
+	if err == nil {
+		return true, nil
+	} else if b := cmd.Stderr(); strings.Contains(string(b), "No such file or directory") || strings.Contains(string(b), "Not a Btrfs subvolume") {
+		return false, nil
+	} else {
+		return false, err
+	}
+}
@@ -0,0 +1,45 @@
+package btrfs
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	subvolCreateOpts struct {
+		Parents bool
+	}
+)
+
+// SubvolumeCreateWithParents creates the missing parent directories of
+// the subvolume path.
+func SubvolumeCreateWithParents(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*subvolCreateOpts)
+		t.Parents = v
+		return nil
+	})
+}
+
+func (t *Subvolume) Create(fopts ...funcopt.O) error {
+	opts := &subvolCreateOpts{}
+	funcopt.Apply(opts, fopts...)
+	if opts.Parents {
+		if err := os.MkdirAll(filepath.Dir(t.Path), 0755); err != nil {
+			return err
+		}
+	}
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("subvolume", "create", t.Path),
+		command.WithLogger(t.Log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,49 @@
+package btrfs
+
+import (
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/args"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	subvolDeleteOpts struct {
+		Path   string
+		Commit bool
+	}
+)
+
+// SubvolumeDeleteWithCommit waits for the transaction commit at the end
+// of the operation, so the freed space is accounted when the command returns.
+func SubvolumeDeleteWithCommit(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*subvolDeleteOpts)
+		t.Commit = v
+		return nil
+	})
+}
+
+func subvolDeleteOptsToArgs(t subvolDeleteOpts) []string {
+	a := args.New()
+	a.Append("subvolume", "delete")
+	if t.Commit {
+		a.Append("--commit-after")
+	}
+	a.Append(t.Path)
+	return a.Get()
+}
+
+func (t *Subvolume) Delete(fopts ...funcopt.O) error {
+	opts := &subvolDeleteOpts{Path: t.Path}
+	funcopt.Apply(opts, fopts...)
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithArgs(subvolDeleteOptsToArgs(*opts)),
+		command.WithLogger(t.Log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
@@ -0,0 +1,61 @@
+package btrfs
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/args"
+	"opensvc.com/opensvc/util/command"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	subvolSnapshotOpts struct {
+		Src      string
+		Dst      string
+		ReadOnly bool
+	}
+)
+
+// SubvolumeSnapshotWithReadOnly creates a read-only snapshot, as
+// required by "btrfs send".
+func SubvolumeSnapshotWithReadOnly(v bool) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*subvolSnapshotOpts)
+		t.ReadOnly = v
+		return nil
+	})
+}
+
+func subvolSnapshotOptsToArgs(t subvolSnapshotOpts) []string {
+	a := args.New()
+	a.Append("subvolume", "snapshot")
+	if t.ReadOnly {
+		a.Append("-r")
+	}
+	a.Append(t.Src, t.Dst)
+	return a.Get()
+}
+
+// Snapshot creates a snapshot of the subvolume at the <dst> path,
+// and returns the snapshot as a Subvolume.
+func (t *Subvolume) Snapshot(dst string, fopts ...funcopt.O) (*Subvolume, error) {
+	opts := &subvolSnapshotOpts{Src: t.Path, Dst: dst}
+	funcopt.Apply(opts, fopts...)
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return nil, err
+	}
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithArgs(subvolSnapshotOptsToArgs(*opts)),
+		command.WithLogger(t.Log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	if err := cmd.Run(); err != nil {
+		return nil, err
+	}
+	return &Subvolume{Path: dst, Log: t.Log}, nil
+}
@@ -71,6 +71,9 @@ func (t T) GetObjectData(p path.T, modsets []string) (Data, error) {
 }
 
 func (t T) GetData(modsets []string) (Data, error) {
+	if t.isLocal() {
+		return t.localData(modsets)
+	}
 	if t.objectPath.IsZero() {
 		return t.GetNodeData(modsets)
 	} else {
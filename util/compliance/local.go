@@ -0,0 +1,315 @@
+package compliance
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	// Source is the origin of the rulesets, modulesets and attachments.
+	Source string
+
+	// DefinitionsFunc returns ruleset and moduleset definitions stored
+	// outside the compliance var dir, like cfg object keys, indexed by
+	// "rulesets/<name>" or "modulesets/<name>".
+	DefinitionsFunc func() (map[string][]byte, error)
+
+	// LocalModuleset is the format of a moduleset definition of the
+	// local source.
+	LocalModuleset struct {
+		Modules    []ModulesetModule `json:"modules"`
+		Modulesets []string          `json:"modulesets"`
+		Rulesets   []string          `json:"rulesets"`
+	}
+
+	// localDefinitions is the rulesets and modulesets known to the local
+	// source, indexed by name.
+	localDefinitions struct {
+		rulesets   Rulesets
+		modulesets map[string]LocalModuleset
+	}
+
+	// localAttachments is the format of the local attachments file.
+	localAttachments struct {
+		Rulesets   []string `json:"rulesets"`
+		Modulesets []string `json:"modulesets"`
+	}
+)
+
+const (
+	// SourceCollector makes the compliance use the collector jsonrpc api.
+	SourceCollector Source = "collector"
+
+	// SourceLocal makes the compliance use the definitions found in the
+	// <var>/compliance/rulesets/<name>.json, <var>/compliance/modulesets/<name>.json
+	// files and in the DefinitionsFunc, and store the attachments locally.
+	SourceLocal Source = "local"
+
+	filterExplicit             = "explicit attachment"
+	filterExplicitViaModuleset = "explicit attachment via moduleset"
+)
+
+// ParseSource returns the Source from its string representation. The empty
+// string is the collector source.
+func ParseSource(s string) (Source, error) {
+	switch Source(s) {
+	case "", SourceCollector:
+		return SourceCollector, nil
+	case SourceLocal:
+		return SourceLocal, nil
+	default:
+		return "", errors.Errorf("invalid compliance source %s: expected %s or %s", s, SourceCollector, SourceLocal)
+	}
+}
+
+func (t T) isLocal() bool {
+	return t.source == SourceLocal
+}
+
+func (t T) loadLocalDefinitions() (localDefinitions, error) {
+	defs := localDefinitions{
+		rulesets:   make(Rulesets),
+		modulesets: make(map[string]LocalModuleset),
+	}
+	m := make(map[string][]byte)
+	if t.definitionsFunc != nil {
+		if extra, err := t.definitionsFunc(); err != nil {
+			return defs, err
+		} else {
+			for k, b := range extra {
+				m[k] = b
+			}
+		}
+	}
+	// the var dir definitions override the DefinitionsFunc definitions
+	for _, kind := range []string{"rulesets", "modulesets"} {
+		matches, err := filepath.Glob(filepath.Join(t.varDir, kind, "*.json"))
+		if err != nil {
+			return defs, err
+		}
+		for _, p := range matches {
+			b, err := os.ReadFile(p)
+			if err != nil {
+				return defs, err
+			}
+			m[kind+"/"+strings.TrimSuffix(filepath.Base(p), ".json")] = b
+		}
+	}
+	for k, b := range m {
+		l := strings.SplitN(k, "/", 2)
+		if len(l) != 2 || l[1] == "" {
+			continue
+		}
+		switch l[0] {
+		case "rulesets":
+			var rset Ruleset
+			if err := json.Unmarshal(b, &rset); err != nil {
+				return defs, errors.Wrapf(err, "ruleset %s", l[1])
+			}
+			rset.Name = l[1]
+			defs.rulesets[l[1]] = rset
+		case "modulesets":
+			var modset LocalModuleset
+			if err := json.Unmarshal(b, &modset); err != nil {
+				return defs, errors.Wrapf(err, "moduleset %s", l[1])
+			}
+			defs.modulesets[l[1]] = modset
+		}
+	}
+	return defs, nil
+}
+
+func (t T) localAttachmentsFile() string {
+	if t.objectPath.IsZero() {
+		return filepath.Join(t.varDir, "attachments.json")
+	}
+	return filepath.Join(t.objectPath.VarDir(), "compliance_attachments.json")
+}
+
+func (t T) readLocalAttachments() (localAttachments, error) {
+	var data localAttachments
+	b, err := os.ReadFile(t.localAttachmentsFile())
+	if os.IsNotExist(err) {
+		return data, nil
+	} else if err != nil {
+		return data, err
+	}
+	err = json.Unmarshal(b, &data)
+	return data, err
+}
+
+func (t T) writeLocalAttachments(data localAttachments) error {
+	sort.Strings(data.Rulesets)
+	sort.Strings(data.Modulesets)
+	b, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	p := t.localAttachmentsFile()
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return err
+	}
+	return os.WriteFile(p, b, 0600)
+}
+
+func (t T) localAttach(rset, modset string) error {
+	defs, err := t.loadLocalDefinitions()
+	if err != nil {
+		return err
+	}
+	data, err := t.readLocalAttachments()
+	if err != nil {
+		return err
+	}
+	switch {
+	case rset != "":
+		if _, ok := defs.rulesets[rset]; !ok {
+			return errors.Errorf("ruleset %s is not defined", rset)
+		}
+		if !stringslice.Has(rset, data.Rulesets) {
+			data.Rulesets = append(data.Rulesets, rset)
+		}
+		t.log.Info().Msgf("ruleset %s attached", rset)
+	case modset != "":
+		if _, ok := defs.modulesets[modset]; !ok {
+			return errors.Errorf("moduleset %s is not defined", modset)
+		}
+		if !stringslice.Has(modset, data.Modulesets) {
+			data.Modulesets = append(data.Modulesets, modset)
+		}
+		t.log.Info().Msgf("moduleset %s attached", modset)
+	}
+	return t.writeLocalAttachments(data)
+}
+
+func (t T) localDetach(rset, modset string) error {
+	data, err := t.readLocalAttachments()
+	if err != nil {
+		return err
+	}
+	switch {
+	case rset == "all":
+		data.Rulesets = []string{}
+	case rset != "":
+		data.Rulesets = removeString(data.Rulesets, rset)
+	case modset == "all":
+		data.Modulesets = []string{}
+	case modset != "":
+		data.Modulesets = removeString(data.Modulesets, modset)
+	}
+	return t.writeLocalAttachments(data)
+}
+
+// localData returns the compliance data of the attached modulesets and
+// rulesets, plus the modulesets selected by name.
+func (t T) localData(modsets []string) (Data, error) {
+	data := Data{
+		Modsets:             make(Modulesets),
+		Rsets:               make(Rulesets),
+		ModsetRsetRelations: make(ModulesetRulesetRelations),
+		ModsetRelations:     make(ModulesetRelations),
+	}
+	defs, err := t.loadLocalDefinitions()
+	if err != nil {
+		return data, err
+	}
+	attachments, err := t.readLocalAttachments()
+	if err != nil {
+		return data, err
+	}
+	for _, name := range attachments.Rulesets {
+		rset, ok := defs.rulesets[name]
+		if !ok {
+			t.log.Warn().Msgf("attached ruleset %s is not defined", name)
+			continue
+		}
+		rset.Filter = filterExplicit
+		data.Rsets[name] = rset
+	}
+	var addModset func(string) error
+	addModset = func(name string) error {
+		if _, ok := data.Modsets[name]; ok {
+			return nil
+		}
+		modset, ok := defs.modulesets[name]
+		if !ok {
+			return errors.Errorf("moduleset %s is not defined", name)
+		}
+		data.Modsets[name] = modset.Modules
+		if len(modset.Modulesets) > 0 {
+			data.ModsetRelations[name] = modset.Modulesets
+		}
+		if len(modset.Rulesets) > 0 {
+			data.ModsetRsetRelations[name] = modset.Rulesets
+		}
+		for _, rsetName := range modset.Rulesets {
+			if _, ok := data.Rsets[rsetName]; ok {
+				continue
+			}
+			rset, ok := defs.rulesets[rsetName]
+			if !ok {
+				return errors.Errorf("moduleset %s ruleset %s is not defined", name, rsetName)
+			}
+			rset.Filter = filterExplicitViaModuleset
+			data.Rsets[rsetName] = rset
+		}
+		for _, child := range modset.Modulesets {
+			if err := addModset(child); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, name := range append(attachments.Modulesets, modsets...) {
+		if err := addModset(name); err != nil {
+			return data, err
+		}
+	}
+	return data, nil
+}
+
+func (t T) localList(kind, filter string) ([]string, error) {
+	defs, err := t.loadLocalDefinitions()
+	if err != nil {
+		return nil, err
+	}
+	pattern := strings.ReplaceAll(filter, "%", "*")
+	if pattern == "" {
+		pattern = "*"
+	}
+	l := make([]string, 0)
+	match := func(name string) {
+		if ok, _ := filepath.Match(pattern, name); ok {
+			l = append(l, name)
+		}
+	}
+	switch kind {
+	case "rulesets":
+		for name := range defs.rulesets {
+			match(name)
+		}
+	case "modulesets":
+		for name := range defs.modulesets {
+			match(name)
+		}
+	}
+	sort.Strings(l)
+	return l, nil
+}
+
+func removeString(l []string, s string) []string {
+	r := make([]string, 0, len(l))
+	for _, e := range l {
+		if e != s {
+			r = append(r, e)
+		}
+	}
+	return r
+}
@@ -0,0 +1,67 @@
+package compliance
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalSource(t *testing.T) {
+	varDir := t.TempDir()
+	comp := New()
+	comp.SetVarDir(varDir)
+	comp.SetSource(SourceLocal)
+	comp.SetDefinitionsFunc(func() (map[string][]byte, error) {
+		return map[string][]byte{
+			"rulesets/base":    []byte(`{"vars": [["ntp_servers", "ntp1 ntp2", "raw"]]}`),
+			"modulesets/child": []byte(`{"modules": [["ntp", true]]}`),
+			"other/ignored":    []byte(`garbage`),
+		}, nil
+	})
+	write := func(p, s string) {
+		p = filepath.Join(varDir, p)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte(s), 0600))
+	}
+	write("rulesets/web.json", `{"vars": [["port", 80, "raw"]]}`)
+	write("modulesets/web.json", `{"modules": [["nginx", false]], "modulesets": ["child"], "rulesets": ["web"]}`)
+
+	l, err := comp.ListRulesets("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"base", "web"}, l)
+
+	l, err = comp.ListModulesets("w%")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, l)
+
+	assert.Error(t, comp.AttachRuleset("undefined"))
+	require.NoError(t, comp.AttachRuleset("base"))
+	require.NoError(t, comp.AttachModuleset("web"))
+
+	data, err := comp.GetData([]string{})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"web", "child"}, keys(data.Modsets))
+	assert.Equal(t, []string{"child"}, data.ModsetRelations["web"])
+	assert.Equal(t, []string{"web"}, data.ModsetRsetRelations["web"])
+	assert.Equal(t, "explicit attachment", data.Rsets["base"].Filter)
+	assert.Equal(t, "explicit attachment via moduleset", data.Rsets["web"].Filter)
+	assert.Equal(t, "OSVC_COMP_NTP_SERVERS=ntp1 ntp2", data.Rsets["base"].Vars[0].String())
+	assert.Len(t, data.ExpandModules([]string{"web"}, nil), 2)
+
+	require.NoError(t, comp.DetachModuleset("web"))
+	data, err = comp.GetData([]string{})
+	require.NoError(t, err)
+	assert.Len(t, data.Modsets, 0)
+	assert.Len(t, data.Rsets, 1)
+}
+
+func keys(m Modulesets) []string {
+	l := make([]string, 0, len(m))
+	for k := range m {
+		l = append(l, k)
+	}
+	return l
+}
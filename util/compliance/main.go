@@ -16,6 +16,8 @@ type (
 		objectPath      path.T
 		log             zerolog.Logger
 		varDir          string
+		source          Source
+		definitionsFunc DefinitionsFunc
 
 		// variable
 		rulesets Rulesets
@@ -26,6 +28,7 @@ func New() *T {
 	t := &T{
 		log:    log.With().Str("c", "compliance").Logger(),
 		varDir: filepath.Join(rawconfig.Paths.Var, "compliance"),
+		source: SourceCollector,
 	}
 	return t
 }
@@ -45,3 +48,11 @@ func (t *T) SetObjectPath(s path.T) {
 func (t *T) SetVarDir(s string) {
 	t.varDir = s
 }
+
+func (t *T) SetSource(v Source) {
+	t.source = v
+}
+
+func (t *T) SetDefinitionsFunc(f DefinitionsFunc) {
+	t.definitionsFunc = f
+}
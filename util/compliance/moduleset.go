@@ -126,6 +126,9 @@ func (t T) ListModulesets(filter string) ([]string, error) {
 	if filter == "" {
 		filter = "%"
 	}
+	if t.isLocal() {
+		return t.localList("modulesets", filter)
+	}
 	err = t.collectorClient.CallFor(&data, "comp_list_modulesets", filter)
 	if err != nil {
 		return nil, err
@@ -147,6 +150,9 @@ func (t T) AttachModuleset(s string) error {
 		response *jsonrpc.RPCResponse
 		err      error
 	)
+	if t.isLocal() {
+		return t.localAttach("", s)
+	}
 	if t.objectPath.IsZero() {
 		response, err = t.collectorClient.Call("comp_attach_moduleset", hostname.Hostname(), s)
 	} else {
@@ -164,6 +170,9 @@ func (t T) DetachModuleset(s string) error {
 		response *jsonrpc.RPCResponse
 		err      error
 	)
+	if t.isLocal() {
+		return t.localDetach("", s)
+	}
 	if t.objectPath.IsZero() {
 		response, err = t.collectorClient.Call("comp_detach_moduleset", hostname.Hostname(), s)
 	} else {
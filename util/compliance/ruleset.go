@@ -18,6 +18,10 @@ type (
 )
 
 func (t T) GetRulesets() (Rulesets, error) {
+	if t.isLocal() {
+		data, err := t.localData([]string{})
+		return data.Rsets, err
+	}
 	rulesets := make(Rulesets)
 	err := t.collectorClient.CallFor(&rulesets, "comp_get_ruleset", hostname.Hostname())
 	if err != nil {
@@ -72,6 +76,9 @@ func (t T) ListRulesets(filter string) ([]string, error) {
 	if filter == "" {
 		filter = "%"
 	}
+	if t.isLocal() {
+		return t.localList("rulesets", filter)
+	}
 	err = t.collectorClient.CallFor(&data, "comp_list_rulesets", filter, hostname.Hostname())
 	if err != nil {
 		return nil, err
@@ -84,6 +91,9 @@ func (t T) AttachRuleset(s string) error {
 		response *jsonrpc.RPCResponse
 		err      error
 	)
+	if t.isLocal() {
+		return t.localAttach(s, "")
+	}
 	if t.objectPath.IsZero() {
 		response, err = t.collectorClient.Call("comp_attach_ruleset", hostname.Hostname(), s)
 	} else {
@@ -101,6 +111,9 @@ func (t T) DetachRuleset(s string) error {
 		response *jsonrpc.RPCResponse
 		err      error
 	)
+	if t.isLocal() {
+		return t.localDetach(s, "")
+	}
 	if t.objectPath.IsZero() {
 		response, err = t.collectorClient.Call("comp_detach_ruleset", hostname.Hostname(), s)
 	} else {
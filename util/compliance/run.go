@@ -393,6 +393,10 @@ func (t Run) Push() error {
 	if len(t.ModuleActions) == 0 {
 		return nil
 	}
+	if t.main.isLocal() {
+		// no collector to push the run log to
+		return nil
+	}
 	vars := []string{
 		"run_nodename",
 		"run_module",
@@ -0,0 +1,428 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+type (
+	CompAuthKeys struct {
+		*Obj
+	}
+	CompAuthKey struct {
+		User    string `json:"user"`
+		Key     string `json:"key"`
+		Action  string `json:"action"`
+		Options string `json:"options,omitempty"`
+	}
+
+	// authKeyLine is a parsed authorized_keys line.
+	authKeyLine struct {
+		Options string
+		Type    string
+		Blob    string
+	}
+)
+
+const (
+	authKeyActionAdd = "add"
+	authKeyActionDel = "del"
+)
+
+var compAuthKeyInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_AUTHKEY_",
+	ExampleValue: CompAuthKey{
+		User:    "appuser",
+		Key:     "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIB8G2yVbmbVIGHfdxAdTnKqUu4rmb4Gh+iTq3Vbkd3Zi admin@corp.com",
+		Action:  "add",
+		Options: `from="10.0.0.0/8",no-agent-forwarding`,
+	},
+	Description: `* Verify a ssh public key is present in or absent from a local user authorized_keys file.
+* The keys are compared by type and blob. The comment is ignored.
+* For present keys, verify the options string, if set.
+* In the 'fix' the key line is added, updated or removed. The <home>/.ssh directory and the authorized_keys file are created if needed, owned by the user. A symlinked <home>/.ssh directory or authorized_keys file is refused.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A ssh authorized key rule, fed to the 'authkey' compliance object to authorize or revoke a ssh public key for a local user.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: authkey
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: user
+    Label: User name
+    DisplayModeLabel: user
+    LabelCss: guy16
+    Mandatory: Yes
+    Help: The local user owning the authorized_keys file.
+    Type: string
+
+  -
+    Id: key
+    Label: Public key
+    DisplayModeLabel: key
+    LabelCss: key
+    Mandatory: Yes
+    Help: The ssh public key, in the '<type> <blob> [<comment>]' format.
+    Type: string
+
+  -
+    Id: action
+    Label: Action
+    DisplayModeLabel: action
+    LabelCss: action16
+    Mandatory: Yes
+    Default: add
+    Candidates:
+      - add
+      - del
+    Help: Authorize (add) or revoke (del) the key.
+    Type: string
+
+  -
+    Id: options
+    Label: Options
+    DisplayModeLabel: options
+    LabelCss: action16
+    Help: The authorized_keys options string. Example from="10.0.0.0/8",no-agent-forwarding
+    Type: string
+`,
+}
+
+func init() {
+	m["authkey"] = NewCompAuthKeys
+}
+
+func NewCompAuthKeys() interface{} {
+	return &CompAuthKeys{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompAuthKeys) Add(s string) error {
+	var data CompAuthKey
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.User == "" {
+		t.Errorf("user should be in the dict: %s\n", s)
+		return nil
+	}
+	if _, ok := parseAuthKeyLine(data.Key); !ok {
+		t.Errorf("key should be in the '<type> <blob> [<comment>]' format: %s\n", s)
+		return nil
+	}
+	switch data.Action {
+	case "":
+		data.Action = authKeyActionAdd
+	case authKeyActionAdd, authKeyActionDel:
+	default:
+		t.Errorf("action should be %s or %s: %s\n", authKeyActionAdd, authKeyActionDel, s)
+		return nil
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+func isAuthKeyType(s string) bool {
+	return strings.HasPrefix(s, "ssh-") || strings.HasPrefix(s, "ecdsa-") || strings.HasPrefix(s, "sk-")
+}
+
+// splitAuthKeyOptions returns the options string heading the line, and the
+// rest of the line. The options are separated from the key by the first
+// whitespace out of a double-quoted string.
+func splitAuthKeyOptions(line string) (string, string) {
+	quoted := false
+	for i, c := range line {
+		switch {
+		case c == '"':
+			quoted = !quoted
+		case !quoted && (c == ' ' || c == '\t'):
+			return line[:i], strings.TrimSpace(line[i:])
+		}
+	}
+	return line, ""
+}
+
+// parseAuthKeyLine parses a '[<options>] <type> <blob> [<comment>]' line.
+func parseAuthKeyLine(line string) (authKeyLine, bool) {
+	var k authKeyLine
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") {
+		return k, false
+	}
+	l := strings.Fields(line)
+	if !isAuthKeyType(l[0]) {
+		k.Options, line = splitAuthKeyOptions(line)
+		l = strings.Fields(line)
+	}
+	if len(l) < 2 || !isAuthKeyType(l[0]) {
+		return k, false
+	}
+	k.Type = l[0]
+	k.Blob = l[1]
+	return k, true
+}
+
+func (t CompAuthKey) String() string {
+	key := strings.TrimSpace(t.Key)
+	if t.Options == "" {
+		return key
+	}
+	return t.Options + " " + key
+}
+
+// authorizedKeysFile returns the path of the user authorized_keys file.
+func authorizedKeysFile(user string) (string, passwdEntry, error) {
+	e, ok, err := getPasswdEntry(user)
+	if err != nil {
+		return "", e, err
+	}
+	if !ok {
+		return "", e, os.ErrNotExist
+	}
+	return filepath.Join(e.Home, ".ssh", "authorized_keys"), e, nil
+}
+
+// findAuthKey returns the authorized_keys lines matching the rule key.
+func findAuthKey(p string, rule CompAuthKey) ([]authKeyLine, error) {
+	l := make([]authKeyLine, 0)
+	ref, _ := parseAuthKeyLine(rule.Key)
+	f, err := os.OpenFile(p, os.O_RDONLY|unix.O_NOFOLLOW, 0)
+	if os.IsNotExist(err) {
+		return l, nil
+	} else if err != nil {
+		return l, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		k, ok := parseAuthKeyLine(scanner.Text())
+		if ok && k.Type == ref.Type && k.Blob == ref.Blob {
+			l = append(l, k)
+		}
+	}
+	return l, scanner.Err()
+}
+
+func (t CompAuthKeys) CheckRule(rule CompAuthKey) ExitCode {
+	p, _, err := authorizedKeysFile(rule.User)
+	if err != nil {
+		t.VerboseErrorf("authkey user %s: %s\n", rule.User, err)
+		return ExitNok
+	}
+	found, err := findAuthKey(p, rule)
+	if err != nil {
+		t.VerboseErrorf("authkey user %s: %s\n", rule.User, err)
+		return ExitNok
+	}
+	ref, _ := parseAuthKeyLine(rule.Key)
+	switch rule.Action {
+	case authKeyActionDel:
+		if len(found) > 0 {
+			t.VerboseErrorf("authkey %s is authorized in %s, should not be\n", ref.Type, p)
+			return ExitNok
+		}
+		t.VerboseInfof("authkey %s is not authorized in %s\n", ref.Type, p)
+	default:
+		if len(found) == 0 {
+			t.VerboseErrorf("authkey %s is not authorized in %s, should be\n", ref.Type, p)
+			return ExitNok
+		}
+		if len(found) > 1 {
+			t.VerboseErrorf("authkey %s is authorized %d times in %s\n", ref.Type, len(found), p)
+			return ExitNok
+		}
+		if rule.Options != "" && found[0].Options != rule.Options {
+			t.VerboseErrorf("authkey %s options in %s are '%s', should be '%s'\n", ref.Type, p, found[0].Options, rule.Options)
+			return ExitNok
+		}
+		t.VerboseInfof("authkey %s is authorized in %s\n", ref.Type, p)
+	}
+	return ExitOk
+}
+
+// FixRule rewrites the authorized_keys file without the lines of the rule
+// key, then appends the rule key line if the action is add.
+func (t CompAuthKeys) FixRule(rule CompAuthKey) ExitCode {
+	if e := t.CheckRule(rule); e != ExitNok {
+		return ExitOk
+	}
+	p, pw, err := authorizedKeysFile(rule.User)
+	if err != nil {
+		t.Errorf("authkey user %s: %s\n", rule.User, err)
+		return ExitNok
+	}
+	dir := filepath.Dir(p)
+	dirFile, err := openSSHDir(dir, pw)
+	if err != nil {
+		t.Errorf("authkey open dir %s: %s\n", dir, err)
+		return ExitNok
+	}
+	defer dirFile.Close()
+	current, err := readAuthorizedKeys(dirFile, filepath.Base(p))
+	if err != nil {
+		t.Errorf("authkey read %s: %s\n", p, err)
+		return ExitNok
+	}
+	ref, _ := parseAuthKeyLine(rule.Key)
+	var buf bytes.Buffer
+	scanner := bufio.NewScanner(bytes.NewReader(current))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if k, ok := parseAuthKeyLine(line); ok && k.Type == ref.Type && k.Blob == ref.Blob {
+			continue
+		}
+		buf.WriteString(line + "\n")
+	}
+	if rule.Action != authKeyActionDel {
+		buf.WriteString(rule.String() + "\n")
+	}
+	if _, err := backup(p); err != nil {
+		t.Errorf("authkey backup %s: %s\n", p, err)
+		return ExitNok
+	}
+	if err := writeAuthorizedKeys(dirFile, filepath.Base(p), buf.Bytes(), pw); err != nil {
+		t.Errorf("authkey write %s: %s\n", p, err)
+		return ExitNok
+	}
+	if rule.Action == authKeyActionDel {
+		t.Infof("authkey %s revoked in %s\n", ref.Type, p)
+	} else {
+		t.Infof("authkey %s authorized in %s\n", ref.Type, p)
+	}
+	return ExitOk
+}
+
+// openSSHDir opens the user .ssh directory dir, creating it owned by the
+// user if it does not exist. A symlink is refused, as the user could point
+// it to a file the fix would then overwrite as root.
+func openSSHDir(dir string, pw passwdEntry) (*os.File, error) {
+	fi, err := os.Lstat(dir)
+	switch {
+	case os.IsNotExist(err):
+		if err := os.Mkdir(dir, 0700); err != nil {
+			return nil, err
+		}
+		if err := os.Lchown(dir, pw.UID, pw.GID); err != nil {
+			return nil, err
+		}
+	case err != nil:
+		return nil, err
+	case fi.Mode()&os.ModeSymlink != 0:
+		return nil, fmt.Errorf("%s is a symlink", dir)
+	case !fi.IsDir():
+		return nil, fmt.Errorf("%s is not a directory", dir)
+	}
+	fd, err := unix.Open(dir, unix.O_RDONLY|unix.O_DIRECTORY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	if err != nil {
+		return nil, &os.PathError{Op: "open", Path: dir, Err: err}
+	}
+	return os.NewFile(uintptr(fd), dir), nil
+}
+
+// readAuthorizedKeys returns the content of the name file in the dirFile
+// directory, or nil if it does not exist. A symlink or a non-regular file
+// is refused.
+func readAuthorizedKeys(dirFile *os.File, name string) ([]byte, error) {
+	fd, err := unix.Openat(int(dirFile.Fd()), name, unix.O_RDONLY|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0)
+	switch {
+	case errors.Is(err, unix.ENOENT):
+		return nil, nil
+	case errors.Is(err, unix.ELOOP):
+		return nil, fmt.Errorf("%s is a symlink", name)
+	case err != nil:
+		return nil, err
+	}
+	f := os.NewFile(uintptr(fd), name)
+	defer f.Close()
+	if fi, err := f.Stat(); err != nil {
+		return nil, err
+	} else if !fi.Mode().IsRegular() {
+		return nil, fmt.Errorf("%s is not a regular file", name)
+	}
+	return io.ReadAll(f)
+}
+
+// writeAuthorizedKeys replaces the name file in the dirFile directory by
+// a temporary file with the content b, owned by the user.
+func writeAuthorizedKeys(dirFile *os.File, name string, b []byte, pw passwdEntry) error {
+	dirFd := int(dirFile.Fd())
+	tmpName := fmt.Sprintf(".%s.%d", name, os.Getpid())
+	_ = unix.Unlinkat(dirFd, tmpName, 0)
+	fd, err := unix.Openat(dirFd, tmpName, unix.O_WRONLY|unix.O_CREAT|unix.O_EXCL|unix.O_NOFOLLOW|unix.O_CLOEXEC, 0600)
+	if err != nil {
+		return err
+	}
+	f := os.NewFile(uintptr(fd), tmpName)
+	err = func() error {
+		if _, err := f.Write(b); err != nil {
+			return err
+		}
+		if err := f.Chown(pw.UID, pw.GID); err != nil {
+			return err
+		}
+		return f.Sync()
+	}()
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = unix.Renameat(dirFd, tmpName, dirFd, name)
+	}
+	if err != nil {
+		_ = unix.Unlinkat(dirFd, tmpName, 0)
+	}
+	return err
+}
+
+func (t CompAuthKeys) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompAuthKey)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompAuthKeys) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompAuthKey)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompAuthKeys) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompAuthKeys) Info() ObjInfo {
+	return compAuthKeyInfo
+}
@@ -0,0 +1,314 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"opensvc.com/opensvc/util/stringslice"
+)
+
+type (
+	CompGroups struct {
+		*Obj
+	}
+	CompGroup struct {
+		Group     string   `json:"group"`
+		GID       *int     `json:"gid,omitempty"`
+		Members   []string `json:"members,omitempty"`
+		Exclusive bool     `json:"exclusive,omitempty"`
+	}
+
+	// groupEntry is a parsed /etc/group line.
+	groupEntry struct {
+		Name    string
+		GID     int
+		Members []string
+	}
+)
+
+var groupFile = "/etc/group"
+
+var compGroupInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_GROUP_",
+	ExampleValue: CompGroup{
+		Group:   "app",
+		GID:     intPtr(1001),
+		Members: []string{"appuser", "appadm"},
+	},
+	Description: `* Verify a local group exists with the specified gid.
+* Verify the listed users are members of the group.
+* If exclusive is set, verify the group has no other member.
+* In the 'fix' the group is created with groupadd or modified with groupmod, and the members are added or removed with gpasswd.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A local group rule, fed to the 'group' compliance object to create or modify a local group and its members.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: group
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: group
+    Label: Group name
+    DisplayModeLabel: group
+    LabelCss: guys16
+    Mandatory: Yes
+    Help: The local group name.
+    Type: string
+
+  -
+    Id: gid
+    Label: Group id
+    DisplayModeLabel: gid
+    LabelCss: guys16
+    Help: The local group id.
+    Type: integer
+
+  -
+    Id: members
+    Label: Members
+    DisplayModeLabel: members
+    LabelCss: guy16
+    Help: The users that must be member of the group.
+    Type: list of string
+
+  -
+    Id: exclusive
+    Label: Exclusive
+    DisplayModeLabel: exclusive
+    LabelCss: action16
+    Help: If set, the users not listed in members are removed from the group.
+    Type: boolean
+`,
+}
+
+func init() {
+	m["group"] = NewCompGroups
+}
+
+func NewCompGroups() interface{} {
+	return &CompGroups{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompGroups) Add(s string) error {
+	var data CompGroup
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.Group == "" {
+		t.Errorf("group should be in the dict: %s\n", s)
+		return nil
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+// getGroupEntry returns the group entry of the group, and false if the
+// group does not exist.
+func getGroupEntry(name string) (groupEntry, bool, error) {
+	data, err := readColumned(groupFile)
+	if err != nil {
+		return groupEntry{}, false, err
+	}
+	l, ok := data[name]
+	if !ok {
+		return groupEntry{}, false, nil
+	}
+	if len(l) < 4 {
+		return groupEntry{}, false, fmt.Errorf("%s: invalid entry for group %s", groupFile, name)
+	}
+	e := groupEntry{
+		Name:    l[0],
+		Members: make([]string, 0),
+	}
+	if e.GID, err = strconv.Atoi(l[2]); err != nil {
+		return e, true, fmt.Errorf("%s: invalid gid for group %s: %s", groupFile, name, err)
+	}
+	for _, member := range strings.Split(l[3], ",") {
+		if member = strings.TrimSpace(member); member != "" {
+			e.Members = append(e.Members, member)
+		}
+	}
+	return e, true, nil
+}
+
+// membersDelta returns the users to add to and to remove from the group.
+func (t CompGroup) membersDelta(e groupEntry) ([]string, []string) {
+	toAdd := make([]string, 0)
+	toDel := make([]string, 0)
+	for _, member := range t.Members {
+		if !stringslice.Has(member, e.Members) {
+			toAdd = append(toAdd, member)
+		}
+	}
+	if t.Exclusive {
+		for _, member := range e.Members {
+			if !stringslice.Has(member, t.Members) {
+				toDel = append(toDel, member)
+			}
+		}
+	}
+	return toAdd, toDel
+}
+
+func (t CompGroups) checkGroup(rule CompGroup) ExitCode {
+	e, ok, err := getGroupEntry(rule.Group)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("group %s: %s\n", rule.Group, err)
+		return ExitNok
+	case !ok:
+		t.VerboseErrorf("group %s does not exist\n", rule.Group)
+		return ExitNok
+	case rule.GID != nil && *rule.GID != e.GID:
+		t.VerboseErrorf("group %s gid is %d, should be %d\n", rule.Group, e.GID, *rule.GID)
+		return ExitNok
+	}
+	t.VerboseInfof("group %s exists with gid %d\n", rule.Group, e.GID)
+	return ExitOk
+}
+
+func (t CompGroups) fixGroup(rule CompGroup) ExitCode {
+	_, ok, err := getGroupEntry(rule.Group)
+	if err != nil {
+		t.Errorf("group %s: %s\n", rule.Group, err)
+		return ExitNok
+	}
+	args := make([]string, 0)
+	if rule.GID != nil {
+		args = append(args, "-g", fmt.Sprint(*rule.GID))
+	}
+	args = append(args, rule.Group)
+	if !ok {
+		if err := identityRun("groupadd", args...); err != nil {
+			t.Errorf("group %s create: %s\n", rule.Group, err)
+			return ExitNok
+		}
+		t.Infof("group %s created\n", rule.Group)
+		return ExitOk
+	}
+	if err := identityRun("groupmod", args...); err != nil {
+		t.Errorf("group %s modify: %s\n", rule.Group, err)
+		return ExitNok
+	}
+	t.Infof("group %s gid set to %d\n", rule.Group, *rule.GID)
+	return ExitOk
+}
+
+func (t CompGroups) checkMembers(rule CompGroup) ExitCode {
+	if len(rule.Members) == 0 && !rule.Exclusive {
+		return ExitNotApplicable
+	}
+	e, ok, err := getGroupEntry(rule.Group)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("group %s: %s\n", rule.Group, err)
+		return ExitNok
+	case !ok:
+		t.VerboseErrorf("group %s does not exist\n", rule.Group)
+		return ExitNok
+	}
+	toAdd, toDel := rule.membersDelta(e)
+	if len(toAdd) > 0 {
+		t.VerboseErrorf("group %s misses members: %s\n", rule.Group, strings.Join(toAdd, ", "))
+	}
+	if len(toDel) > 0 {
+		t.VerboseErrorf("group %s has unexpected members: %s\n", rule.Group, strings.Join(toDel, ", "))
+	}
+	if len(toAdd)+len(toDel) > 0 {
+		return ExitNok
+	}
+	t.VerboseInfof("group %s members are ok\n", rule.Group)
+	return ExitOk
+}
+
+func (t CompGroups) fixMembers(rule CompGroup) ExitCode {
+	e, _, err := getGroupEntry(rule.Group)
+	if err != nil {
+		t.Errorf("group %s: %s\n", rule.Group, err)
+		return ExitNok
+	}
+	toAdd, toDel := rule.membersDelta(e)
+	for _, member := range toAdd {
+		if err := identityRun("gpasswd", "-a", member, rule.Group); err != nil {
+			t.Errorf("group %s add member %s: %s\n", rule.Group, member, err)
+			return ExitNok
+		}
+		t.Infof("group %s member %s added\n", rule.Group, member)
+	}
+	for _, member := range toDel {
+		if err := identityRun("gpasswd", "-d", member, rule.Group); err != nil {
+			t.Errorf("group %s remove member %s: %s\n", rule.Group, member, err)
+			return ExitNok
+		}
+		t.Infof("group %s member %s removed\n", rule.Group, member)
+	}
+	return ExitOk
+}
+
+func (t CompGroups) CheckRule(rule CompGroup) ExitCode {
+	e := t.checkGroup(rule)
+	if e == ExitNok {
+		return e
+	}
+	return e.Merge(t.checkMembers(rule))
+}
+
+func (t CompGroups) FixRule(rule CompGroup) ExitCode {
+	if e := t.checkGroup(rule); e == ExitNok {
+		if e := t.fixGroup(rule); e == ExitNok {
+			return e
+		}
+	}
+	if e := t.checkMembers(rule); e == ExitNok {
+		if e := t.fixMembers(rule); e == ExitNok {
+			return e
+		}
+	}
+	return ExitOk
+}
+
+func (t CompGroups) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompGroup)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompGroups) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompGroup)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompGroups) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompGroups) Info() ObjInfo {
+	return compGroupInfo
+}
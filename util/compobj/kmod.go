@@ -0,0 +1,361 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	CompKmods struct {
+		*Obj
+	}
+	CompKmod struct {
+		Module string `json:"module"`
+		State  string `json:"state"`
+	}
+)
+
+const (
+	kmodStateLoaded      = "loaded"
+	kmodStateUnloaded    = "unloaded"
+	kmodStateBlacklisted = "blacklisted"
+)
+
+var (
+	// kmodProcModules lists the loaded kernel modules.
+	kmodProcModules = "/proc/modules"
+
+	// kmodModprobeDir hosts the modprobe configuration files.
+	kmodModprobeDir = "/etc/modprobe.d"
+
+	// kmodBlacklistFile is the modprobe configuration file where the
+	// blacklisted modules are added.
+	kmodBlacklistFile = "opensvc-compliance-blacklist.conf"
+
+	// kmodModprobe executes a modprobe command loading or unloading a
+	// kernel module.
+	kmodModprobe = func(args ...string) error {
+		cmd := command.New(
+			command.WithName("modprobe"),
+			command.WithArgs(args),
+			command.WithOnStdoutLine(fo),
+			command.WithOnStderrLine(fe),
+		)
+		return cmd.Run()
+	}
+)
+
+var compKmodInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_KMOD_",
+	ExampleValue: CompKmod{
+		Module: "usb_storage",
+		State:  "blacklisted",
+	},
+	Description: `* Verify a kernel module is loaded, unloaded or blacklisted.
+* A blacklisted module is also verified unloaded.
+* In the 'fix' the module is loaded or unloaded using modprobe.
+* In the 'fix' the blacklisted modules are added to /etc/modprobe.d/opensvc-compliance-blacklist.conf.
+* A module to load is removed from this file if blacklisted there.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A kernel module rule, fed to the 'kmod' compliance object to load, unload or blacklist a kernel module.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: kmod
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: module
+    Label: Module
+    DisplayModeLabel: module
+    LabelCss: action16
+    Mandatory: Yes
+    Help: The kernel module name.
+    Type: string
+
+  -
+    Id: state
+    Label: State
+    DisplayModeLabel: state
+    LabelCss: action16
+    Mandatory: Yes
+    Default: loaded
+    Candidates:
+      - loaded
+      - unloaded
+      - blacklisted
+    Help: The kernel module target state.
+    Type: string
+`,
+}
+
+func init() {
+	m["kmod"] = NewCompKmods
+}
+
+func NewCompKmods() interface{} {
+	return &CompKmods{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompKmods) Add(s string) error {
+	var data CompKmod
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.Module == "" {
+		t.Errorf("module should be in the dict: %s\n", s)
+		return nil
+	}
+	switch data.State {
+	case "":
+		data.State = kmodStateLoaded
+	case kmodStateLoaded, kmodStateUnloaded, kmodStateBlacklisted:
+	default:
+		t.Errorf("state should be one of %s, %s or %s: %s\n", kmodStateLoaded, kmodStateUnloaded, kmodStateBlacklisted, s)
+		return nil
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+// kmodName returns the module name as listed in /proc/modules, where the
+// dashes are replaced by underscores.
+func kmodName(s string) string {
+	return strings.ReplaceAll(s, "-", "_")
+}
+
+func isKmodLoaded(name string) (bool, error) {
+	f, err := os.Open(kmodProcModules)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+	name = kmodName(name)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if l := strings.Fields(scanner.Text()); len(l) > 0 && l[0] == name {
+			return true, nil
+		}
+	}
+	return false, scanner.Err()
+}
+
+// kmodBlacklistedIn returns the modprobe configuration files blacklisting
+// the module.
+func kmodBlacklistedIn(name string) ([]string, error) {
+	files, err := filepath.Glob(filepath.Join(kmodModprobeDir, "*.conf"))
+	if err != nil {
+		return nil, err
+	}
+	name = kmodName(name)
+	l := make([]string, 0)
+	for _, p := range files {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(string(b), "\n") {
+			if words := strings.Fields(line); len(words) == 2 && words[0] == "blacklist" && kmodName(words[1]) == name {
+				l = append(l, p)
+				break
+			}
+		}
+	}
+	return l, nil
+}
+
+func (t CompKmods) checkLoaded(rule CompKmod, target bool) ExitCode {
+	loaded, err := isKmodLoaded(rule.Module)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("kmod %s get loaded state: %s\n", rule.Module, err)
+		return ExitNok
+	case loaded && !target:
+		t.VerboseErrorf("kmod %s is loaded, should be unloaded\n", rule.Module)
+		return ExitNok
+	case !loaded && target:
+		t.VerboseErrorf("kmod %s is not loaded, should be loaded\n", rule.Module)
+		return ExitNok
+	case loaded:
+		t.VerboseInfof("kmod %s is loaded\n", rule.Module)
+	default:
+		t.VerboseInfof("kmod %s is not loaded\n", rule.Module)
+	}
+	return ExitOk
+}
+
+func (t CompKmods) checkBlacklisted(rule CompKmod, target bool) ExitCode {
+	files, err := kmodBlacklistedIn(rule.Module)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("kmod %s get blacklisted state: %s\n", rule.Module, err)
+		return ExitNok
+	case len(files) > 0 && !target:
+		t.VerboseErrorf("kmod %s is blacklisted in %s, should not be\n", rule.Module, strings.Join(files, ", "))
+		return ExitNok
+	case len(files) == 0 && target:
+		t.VerboseErrorf("kmod %s is not blacklisted, should be\n", rule.Module)
+		return ExitNok
+	case len(files) > 0:
+		t.VerboseInfof("kmod %s is blacklisted in %s\n", rule.Module, strings.Join(files, ", "))
+	default:
+		t.VerboseInfof("kmod %s is not blacklisted\n", rule.Module)
+	}
+	return ExitOk
+}
+
+func (t CompKmods) fixLoaded(rule CompKmod, target bool) ExitCode {
+	if target {
+		if err := kmodModprobe(rule.Module); err != nil {
+			t.Errorf("kmod %s load: %s\n", rule.Module, err)
+			return ExitNok
+		}
+		t.Infof("kmod %s loaded\n", rule.Module)
+	} else {
+		if err := kmodModprobe("-r", rule.Module); err != nil {
+			t.Errorf("kmod %s unload: %s\n", rule.Module, err)
+			return ExitNok
+		}
+		t.Infof("kmod %s unloaded\n", rule.Module)
+	}
+	return ExitOk
+}
+
+// fixBlacklisted adds or removes the module blacklist line of the
+// opensvc compliance modprobe configuration file. A module blacklisted in
+// another file can not be un-blacklisted by this object.
+func (t CompKmods) fixBlacklisted(rule CompKmod, target bool) ExitCode {
+	p := filepath.Join(kmodModprobeDir, kmodBlacklistFile)
+	b, err := os.ReadFile(p)
+	if err != nil && !os.IsNotExist(err) {
+		t.Errorf("kmod %s read %s: %s\n", rule.Module, p, err)
+		return ExitNok
+	}
+	name := kmodName(rule.Module)
+	lines := make([]string, 0)
+	for _, line := range strings.Split(strings.TrimRight(string(b), "\n"), "\n") {
+		if words := strings.Fields(line); len(words) == 2 && words[0] == "blacklist" && kmodName(words[1]) == name {
+			continue
+		}
+		if line == "" && len(lines) == 0 {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if target {
+		lines = append(lines, fmt.Sprintf("blacklist %s", rule.Module))
+	}
+	if err := os.MkdirAll(kmodModprobeDir, 0755); err != nil {
+		t.Errorf("kmod %s create dir %s: %s\n", rule.Module, kmodModprobeDir, err)
+		return ExitNok
+	}
+	if _, err := backup(p); err != nil {
+		t.Errorf("kmod %s backup %s: %s\n", rule.Module, p, err)
+		return ExitNok
+	}
+	s := ""
+	if len(lines) > 0 {
+		s = strings.Join(lines, "\n") + "\n"
+	}
+	if err := os.WriteFile(p, []byte(s), 0644); err != nil {
+		t.Errorf("kmod %s write %s: %s\n", rule.Module, p, err)
+		return ExitNok
+	}
+	if target {
+		t.Infof("kmod %s blacklisted in %s\n", rule.Module, p)
+	} else {
+		t.Infof("kmod %s removed from blacklist %s\n", rule.Module, p)
+	}
+	if e := t.checkBlacklisted(rule, target); e == ExitNok {
+		t.Errorf("kmod %s is still blacklisted in other modprobe configuration files\n", rule.Module)
+		return ExitNok
+	}
+	return ExitOk
+}
+
+func (t CompKmods) CheckRule(rule CompKmod) ExitCode {
+	switch rule.State {
+	case kmodStateBlacklisted:
+		e := t.checkBlacklisted(rule, true)
+		return e.Merge(t.checkLoaded(rule, false))
+	case kmodStateUnloaded:
+		return t.checkLoaded(rule, false)
+	default:
+		e := t.checkBlacklisted(rule, false)
+		return e.Merge(t.checkLoaded(rule, true))
+	}
+}
+
+func (t CompKmods) FixRule(rule CompKmod) ExitCode {
+	var blacklisted, loaded bool
+	switch rule.State {
+	case kmodStateBlacklisted:
+		blacklisted, loaded = true, false
+	case kmodStateUnloaded:
+		loaded = false
+	default:
+		blacklisted, loaded = false, true
+	}
+	if rule.State != kmodStateUnloaded {
+		if e := t.checkBlacklisted(rule, blacklisted); e == ExitNok {
+			if e := t.fixBlacklisted(rule, blacklisted); e == ExitNok {
+				return e
+			}
+		}
+	}
+	if e := t.checkLoaded(rule, loaded); e == ExitNok {
+		if e := t.fixLoaded(rule, loaded); e == ExitNok {
+			return e
+		}
+	}
+	return ExitOk
+}
+
+func (t CompKmods) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompKmod)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompKmods) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompKmod)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompKmods) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompKmods) Info() ObjInfo {
+	return compKmodInfo
+}
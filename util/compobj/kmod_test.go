@@ -0,0 +1,108 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupKmod installs a fake /proc/modules, modprobe configuration directory
+// and modprobe command loading and unloading the modules of the fake
+// /proc/modules.
+func setupKmod(t *testing.T, loaded ...string) (string, *[]string) {
+	savedProcModules, savedModprobeDir, savedModprobe := kmodProcModules, kmodModprobeDir, kmodModprobe
+	t.Cleanup(func() {
+		kmodProcModules, kmodModprobeDir, kmodModprobe = savedProcModules, savedModprobeDir, savedModprobe
+	})
+	dir := t.TempDir()
+	kmodProcModules = filepath.Join(dir, "modules")
+	kmodModprobeDir = filepath.Join(dir, "modprobe.d")
+	writeModules := func() {
+		s := ""
+		for _, name := range loaded {
+			s += name + " 16384 0 - Live 0x0000000000000000\n"
+		}
+		require.NoError(t, os.WriteFile(kmodProcModules, []byte(s), 0644))
+	}
+	writeModules()
+	executed := make([]string, 0)
+	kmodModprobe = func(args ...string) error {
+		executed = append(executed, "modprobe "+strings.Join(args, " "))
+		if args[0] == "-r" {
+			l := make([]string, 0)
+			for _, name := range loaded {
+				if name != kmodName(args[1]) {
+					l = append(l, name)
+				}
+			}
+			loaded = l
+		} else {
+			loaded = append(loaded, kmodName(args[0]))
+		}
+		writeModules()
+		return nil
+	}
+	return dir, &executed
+}
+
+func TestCompKmodsLoad(t *testing.T) {
+	_, executed := setupKmod(t, "ext4")
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"module": "dm-mod"}`))
+	require.NoError(t, obj.Add(`{"module": "ext4", "state": "loaded"}`))
+	require.Len(t, obj.Rules(), 2)
+	assert.Equal(t, kmodStateLoaded, obj.Rules()[0].(CompKmod).State, "default state")
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Equal(t, []string{"modprobe dm-mod"}, *executed)
+}
+
+func TestCompKmodsUnload(t *testing.T) {
+	_, executed := setupKmod(t, "usb_storage", "ext4")
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"module": "usb-storage", "state": "unloaded"}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Equal(t, []string{"modprobe -r usb-storage"}, *executed)
+}
+
+func TestCompKmodsBlacklist(t *testing.T) {
+	dir, executed := setupKmod(t, "ext4")
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"module": "floppy", "state": "blacklisted"}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Empty(t, *executed)
+	b, err := os.ReadFile(filepath.Join(dir, "modprobe.d", kmodBlacklistFile))
+	require.NoError(t, err)
+	assert.Equal(t, "blacklist floppy\n", string(b))
+}
+
+func TestCompKmodsUnblacklist(t *testing.T) {
+	dir, _ := setupKmod(t, "floppy")
+	p := filepath.Join(dir, "modprobe.d", kmodBlacklistFile)
+	require.NoError(t, os.MkdirAll(filepath.Dir(p), 0755))
+	require.NoError(t, os.WriteFile(p, []byte("blacklist pcspkr\nblacklist floppy\n"), 0644))
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"module": "floppy"}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	b, err := os.ReadFile(p)
+	require.NoError(t, err)
+	assert.Equal(t, "blacklist pcspkr\n", string(b))
+}
+
+func TestCompKmodsAdd(t *testing.T) {
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"state": "loaded"}`))
+	require.NoError(t, obj.Add(`{"module": "floppy", "state": "removed"}`))
+	assert.Empty(t, obj.Rules())
+}
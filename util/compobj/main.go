@@ -60,6 +60,9 @@ func (t ExitCode) Exit() {
 	os.Exit(int(t))
 }
 
+// Merge returns the aggregated exit code of two rules checks or fixes:
+// ExitNok if any is ExitNok, else ExitOk if any is ExitOk, else
+// ExitNotApplicable.
 func (t ExitCode) Merge(o ExitCode) ExitCode {
 	switch {
 	case t == ExitOk && o == ExitOk:
@@ -70,8 +73,14 @@ func (t ExitCode) Merge(o ExitCode) ExitCode {
 		return ExitOk
 	case t == ExitNok && o == ExitOk:
 		return ExitNok
+	case t == ExitNok && o == ExitNok:
+		return ExitNok
 	case t == ExitNok && o == ExitNotApplicable:
 		return ExitNok
+	case t == ExitNotApplicable && o == ExitOk:
+		return ExitOk
+	case t == ExitNotApplicable && o == ExitNok:
+		return ExitNok
 	case t == ExitNotApplicable && o == ExitNotApplicable:
 		return ExitNotApplicable
 	default:
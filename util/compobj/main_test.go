@@ -0,0 +1,36 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExitCodeMerge(t *testing.T) {
+	cases := []struct {
+		t, o, expected ExitCode
+	}{
+		{ExitOk, ExitOk, ExitOk},
+		{ExitOk, ExitNok, ExitNok},
+		{ExitOk, ExitNotApplicable, ExitOk},
+		{ExitNok, ExitOk, ExitNok},
+		{ExitNok, ExitNok, ExitNok},
+		{ExitNok, ExitNotApplicable, ExitNok},
+		{ExitNotApplicable, ExitOk, ExitOk},
+		{ExitNotApplicable, ExitNok, ExitNok},
+		{ExitNotApplicable, ExitNotApplicable, ExitNotApplicable},
+	}
+	for _, c := range cases {
+		assert.Equal(t, c.expected, c.t.Merge(c.o), "%d merge %d", c.t, c.o)
+	}
+}
+
+func TestExitCodeMergeRules(t *testing.T) {
+	// two failing checks of the same object must report ExitNok, not an
+	// invalid exit code
+	setupKmod(t, "floppy")
+	obj := NewCompKmods().(*CompKmods)
+	require.NoError(t, obj.Add(`{"module": "floppy", "state": "blacklisted"}`))
+	assert.Equal(t, ExitNok, obj.Check())
+}
@@ -0,0 +1,290 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type (
+	CompSysctls struct {
+		*Obj
+	}
+	CompSysctl struct {
+		Key   string `json:"key"`
+		Value string `json:"value"`
+		File  string `json:"file,omitempty"`
+	}
+)
+
+var (
+	// sysctlProcDir is the root of the kernel parameters runtime values.
+	sysctlProcDir = "/proc/sys"
+
+	// sysctlDefaultFile is the file where the kernel parameters are persisted
+	// when the rule does not specify one.
+	sysctlDefaultFile = "/etc/sysctl.d/99-opensvc-compliance.conf"
+)
+
+var compSysctlInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_SYSCTL_",
+	ExampleValue: CompSysctl{
+		Key:   "vm.swappiness",
+		Value: "10",
+		File:  "/etc/sysctl.d/99-opensvc-compliance.conf",
+	},
+	Description: `* Verify a kernel parameter runtime value.
+* Verify the kernel parameter value is persisted in a sysctl configuration file.
+* In the 'fix' the runtime value is set and the persisted value is added or replaced.
+* Multi-values parameters are compared after whitespace normalization.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A sysctl rule, fed to the 'sysctl' compliance object to set a kernel parameter runtime and persisted value.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: sysctl
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: key
+    Label: Key
+    DisplayModeLabel: key
+    LabelCss: action16
+    Mandatory: Yes
+    Help: The kernel parameter name, in the dotted notation. Example vm.swappiness
+    Type: string
+
+  -
+    Id: value
+    Label: Value
+    DisplayModeLabel: value
+    LabelCss: action16
+    Mandatory: Yes
+    Help: The kernel parameter target value. Multi-values are separated by spaces.
+    Type: string
+
+  -
+    Id: file
+    Label: Persistence file
+    DisplayModeLabel: file
+    LabelCss: hd16
+    Help: The sysctl configuration file where the value is persisted. Defaults to /etc/sysctl.d/99-opensvc-compliance.conf
+    Type: string
+`,
+}
+
+func init() {
+	m["sysctl"] = NewCompSysctls
+}
+
+func NewCompSysctls() interface{} {
+	return &CompSysctls{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompSysctls) Add(s string) error {
+	var data CompSysctl
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.Key == "" {
+		t.Errorf("key should be in the dict: %s\n", s)
+		return nil
+	}
+	if data.File == "" {
+		data.File = sysctlDefaultFile
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+// procPath returns the /proc/sys path of the kernel parameter.
+func (t CompSysctl) procPath() string {
+	return filepath.Join(sysctlProcDir, strings.ReplaceAll(t.Key, ".", "/"))
+}
+
+// normalizeSysctlValue returns the value with the fields separated by a
+// single space, so "1  2\t3" and "1 2 3" compare equal.
+func normalizeSysctlValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// parseSysctlLine returns the key and value of a "key = value" sysctl
+// configuration line.
+func parseSysctlLine(line string) (string, string, bool) {
+	line = strings.TrimSpace(line)
+	if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+		return "", "", false
+	}
+	l := strings.SplitN(line, "=", 2)
+	if len(l) != 2 {
+		return "", "", false
+	}
+	key := strings.TrimPrefix(strings.TrimSpace(l[0]), "-")
+	key = strings.ReplaceAll(key, "/", ".")
+	return key, normalizeSysctlValue(l[1]), true
+}
+
+func (t CompSysctls) checkRuntime(rule CompSysctl) ExitCode {
+	b, err := os.ReadFile(rule.procPath())
+	if err != nil {
+		t.VerboseErrorf("sysctl %s get runtime value: %s\n", rule.Key, err)
+		return ExitNok
+	}
+	current := normalizeSysctlValue(string(b))
+	if current != normalizeSysctlValue(rule.Value) {
+		t.VerboseErrorf("sysctl %s runtime value is %s, should be %s\n", rule.Key, current, rule.Value)
+		return ExitNok
+	}
+	t.VerboseInfof("sysctl %s runtime value is %s\n", rule.Key, current)
+	return ExitOk
+}
+
+func (t CompSysctls) fixRuntime(rule CompSysctl) ExitCode {
+	if err := os.WriteFile(rule.procPath(), []byte(normalizeSysctlValue(rule.Value)+"\n"), 0644); err != nil {
+		t.Errorf("sysctl %s set runtime value: %s\n", rule.Key, err)
+		return ExitNok
+	}
+	t.Infof("sysctl %s runtime value set to %s\n", rule.Key, rule.Value)
+	return ExitOk
+}
+
+func (t CompSysctls) checkPersist(rule CompSysctl) ExitCode {
+	f, err := os.Open(rule.File)
+	if os.IsNotExist(err) {
+		t.VerboseErrorf("sysctl %s not persisted: %s does not exist\n", rule.Key, rule.File)
+		return ExitNok
+	} else if err != nil {
+		t.VerboseErrorf("sysctl %s get persisted value: %s\n", rule.Key, err)
+		return ExitNok
+	}
+	defer f.Close()
+	var (
+		found   bool
+		current string
+	)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if key, value, ok := parseSysctlLine(scanner.Text()); ok && key == rule.Key {
+			// the last definition wins
+			found = true
+			current = value
+		}
+	}
+	switch {
+	case !found:
+		t.VerboseErrorf("sysctl %s not persisted in %s\n", rule.Key, rule.File)
+		return ExitNok
+	case current != normalizeSysctlValue(rule.Value):
+		t.VerboseErrorf("sysctl %s persisted value is %s in %s, should be %s\n", rule.Key, current, rule.File, rule.Value)
+		return ExitNok
+	}
+	t.VerboseInfof("sysctl %s persisted value is %s in %s\n", rule.Key, current, rule.File)
+	return ExitOk
+}
+
+func (t CompSysctls) fixPersist(rule CompSysctl) ExitCode {
+	current, err := os.ReadFile(rule.File)
+	if err != nil && !os.IsNotExist(err) {
+		t.Errorf("sysctl %s read %s: %s\n", rule.Key, rule.File, err)
+		return ExitNok
+	}
+	var buf bytes.Buffer
+	replaced := false
+	target := fmt.Sprintf("%s = %s", rule.Key, normalizeSysctlValue(rule.Value))
+	scanner := bufio.NewScanner(bytes.NewReader(current))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if key, _, ok := parseSysctlLine(line); ok && key == rule.Key {
+			if replaced {
+				// drop the duplicate definitions
+				continue
+			}
+			line = target
+			replaced = true
+		}
+		buf.WriteString(line + "\n")
+	}
+	if !replaced {
+		buf.WriteString(target + "\n")
+	}
+	if err := os.MkdirAll(filepath.Dir(rule.File), 0755); err != nil {
+		t.Errorf("sysctl %s create dir %s: %s\n", rule.Key, filepath.Dir(rule.File), err)
+		return ExitNok
+	}
+	if _, err := backup(rule.File); err != nil {
+		t.Errorf("sysctl %s backup %s: %s\n", rule.Key, rule.File, err)
+		return ExitNok
+	}
+	if err := os.WriteFile(rule.File, buf.Bytes(), 0644); err != nil {
+		t.Errorf("sysctl %s write %s: %s\n", rule.Key, rule.File, err)
+		return ExitNok
+	}
+	t.Infof("sysctl %s persisted in %s\n", rule.Key, rule.File)
+	return ExitOk
+}
+
+func (t CompSysctls) CheckRule(rule CompSysctl) ExitCode {
+	e := t.checkRuntime(rule)
+	return e.Merge(t.checkPersist(rule))
+}
+
+func (t CompSysctls) FixRule(rule CompSysctl) ExitCode {
+	if e := t.checkRuntime(rule); e == ExitNok {
+		if e := t.fixRuntime(rule); e == ExitNok {
+			return e
+		}
+	}
+	if e := t.checkPersist(rule); e == ExitNok {
+		if e := t.fixPersist(rule); e == ExitNok {
+			return e
+		}
+	}
+	return ExitOk
+}
+
+func (t CompSysctls) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompSysctl)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompSysctls) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompSysctl)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompSysctls) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompSysctls) Info() ObjInfo {
+	return compSysctlInfo
+}
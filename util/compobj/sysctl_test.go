@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompSysctls(t *testing.T) {
+	savedProcDir := sysctlProcDir
+	t.Cleanup(func() {
+		sysctlProcDir = savedProcDir
+	})
+	dir := t.TempDir()
+	sysctlProcDir = filepath.Join(dir, "proc")
+	procFile := filepath.Join(sysctlProcDir, "vm", "swappiness")
+	require.NoError(t, os.MkdirAll(filepath.Dir(procFile), 0755))
+	require.NoError(t, os.WriteFile(procFile, []byte("60\n"), 0644))
+	persistFile := filepath.Join(dir, "etc", "sysctl.d", "99-test.conf")
+	require.NoError(t, os.MkdirAll(filepath.Dir(persistFile), 0755))
+	require.NoError(t, os.WriteFile(persistFile, []byte("# comment\nvm.swappiness = 60\nkernel.pid_max = 65536\n"), 0644))
+
+	t.Setenv("OSVC_COMP_SWAPPINESS", "10")
+	obj := NewCompSysctls().(*CompSysctls)
+	require.NoError(t, obj.Add(`{"key": "vm.swappiness", "value": "%%ENV:SWAPPINESS%%", "file": "`+persistFile+`"}`))
+	require.Len(t, obj.Rules(), 1)
+	assert.Equal(t, "10", obj.Rules()[0].(CompSysctl).Value, "value substitution")
+
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+
+	b, err := os.ReadFile(procFile)
+	require.NoError(t, err)
+	assert.Equal(t, "10\n", string(b))
+	b, err = os.ReadFile(persistFile)
+	require.NoError(t, err)
+	assert.Equal(t, "# comment\nvm.swappiness = 10\nkernel.pid_max = 65536\n", string(b))
+}
+
+func TestParseSysctlLine(t *testing.T) {
+	cases := map[string]struct {
+		key   string
+		value string
+		ok    bool
+	}{
+		"net.ipv4.ip_local_port_range = 1024 \t 65000": {"net.ipv4.ip_local_port_range", "1024 65000", true},
+		"-net/ipv4/ip_forward=1":                       {"net.ipv4.ip_forward", "1", true},
+		"# vm.swappiness = 10":                         {"", "", false},
+		"vm.swappiness":                                {"", "", false},
+	}
+	for line, c := range cases {
+		key, value, ok := parseSysctlLine(line)
+		assert.Equal(t, c.ok, ok, line)
+		assert.Equal(t, c.key, key, line)
+		assert.Equal(t, c.value, value, line)
+	}
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"encoding/json"
+	"os/exec"
+	"strings"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	CompSystemdUnits struct {
+		*Obj
+	}
+	CompSystemdUnit struct {
+		Unit    string `json:"unit"`
+		Enabled *bool  `json:"enabled,omitempty"`
+		Active  *bool  `json:"active,omitempty"`
+		Masked  *bool  `json:"masked,omitempty"`
+	}
+)
+
+var (
+	// systemctlOutput returns the trimmed stdout of a systemctl command.
+	// The exit code is ignored, as the is-enabled and is-active commands
+	// exit non-zero on negative answers.
+	systemctlOutput = func(args ...string) string {
+		b, _ := exec.Command("systemctl", args...).Output()
+		return strings.TrimSpace(string(b))
+	}
+
+	// systemctlRun executes a systemctl command changing a unit state.
+	systemctlRun = func(args ...string) error {
+		cmd := command.New(
+			command.WithName("systemctl"),
+			command.WithArgs(args),
+			command.WithOnStdoutLine(fo),
+			command.WithOnStderrLine(fe),
+		)
+		return cmd.Run()
+	}
+)
+
+var compSystemdUnitInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_SYSTEMD_",
+	ExampleValue: CompSystemdUnit{
+		Unit:    "chronyd.service",
+		Enabled: boolPtr(true),
+		Active:  boolPtr(true),
+	},
+	Description: `* Verify a systemd unit enabled, active and masked states.
+* Only the states set in the rule are verified.
+* In the 'fix' the unit is masked or unmasked first, then enabled or disabled, then started or stopped.
+* A masked unit can not be enabled nor started.
+* The enabled state is not applicable to static, alias, indirect and generated units.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A systemd unit rule, fed to the 'systemd' compliance object to enable, disable, start, stop, mask or unmask a unit.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: systemd
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: unit
+    Label: Unit
+    DisplayModeLabel: unit
+    LabelCss: action16
+    Mandatory: Yes
+    Help: The systemd unit name. Example chronyd.service
+    Type: string
+
+  -
+    Id: enabled
+    Label: Enabled
+    DisplayModeLabel: enabled
+    LabelCss: action16
+    Help: If set, verify the unit is enabled (true) or disabled (false).
+    Type: boolean
+
+  -
+    Id: active
+    Label: Active
+    DisplayModeLabel: active
+    LabelCss: action16
+    Help: If set, verify the unit is started (true) or stopped (false).
+    Type: boolean
+
+  -
+    Id: masked
+    Label: Masked
+    DisplayModeLabel: masked
+    LabelCss: action16
+    Help: If set, verify the unit is masked (true) or unmasked (false).
+    Type: boolean
+`,
+}
+
+func init() {
+	m["systemd"] = NewCompSystemdUnits
+}
+
+func boolPtr(v bool) *bool {
+	return &v
+}
+
+func NewCompSystemdUnits() interface{} {
+	return &CompSystemdUnits{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompSystemdUnits) Add(s string) error {
+	var data CompSystemdUnit
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.Unit == "" {
+		t.Errorf("unit should be in the dict: %s\n", s)
+		return nil
+	}
+	if data.Masked != nil && *data.Masked {
+		if data.Enabled != nil && *data.Enabled {
+			t.Errorf("a masked unit can not be enabled: %s\n", s)
+			return nil
+		}
+		if data.Active != nil && *data.Active {
+			t.Errorf("a masked unit can not be active: %s\n", s)
+			return nil
+		}
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+func isSystemdUnitMasked(unit string) bool {
+	switch systemctlOutput("is-enabled", unit) {
+	case "masked", "masked-runtime":
+		return true
+	default:
+		return false
+	}
+}
+
+func isSystemdUnitEnabled(unit string) bool {
+	switch systemctlOutput("is-enabled", unit) {
+	case "enabled", "enabled-runtime":
+		return true
+	default:
+		return false
+	}
+}
+
+// isSystemdUnitEnableable returns false and the is-enabled state if the
+// unit has no install section to enable or disable, like static units, or
+// if its enabled state is decided by another unit or by a generator.
+func isSystemdUnitEnableable(unit string) (bool, string) {
+	switch s := systemctlOutput("is-enabled", unit); s {
+	case "static", "alias", "indirect", "generated":
+		return false, s
+	default:
+		return true, s
+	}
+}
+
+func isSystemdUnitActive(unit string) bool {
+	switch systemctlOutput("is-active", unit) {
+	case "active", "activating", "reloading":
+		return true
+	default:
+		return false
+	}
+}
+
+// checkState returns ExitOk if the current state matches the target state,
+// and ExitNotApplicable if the rule has no target for this state.
+func (t CompSystemdUnits) checkState(rule CompSystemdUnit, name string, target *bool, current func(string) bool) ExitCode {
+	if target == nil {
+		return ExitNotApplicable
+	}
+	v := current(rule.Unit)
+	if v != *target {
+		if *target {
+			t.VerboseErrorf("systemd unit %s is not %s, should be\n", rule.Unit, name)
+		} else {
+			t.VerboseErrorf("systemd unit %s is %s, should not be\n", rule.Unit, name)
+		}
+		return ExitNok
+	}
+	if v {
+		t.VerboseInfof("systemd unit %s is %s\n", rule.Unit, name)
+	} else {
+		t.VerboseInfof("systemd unit %s is not %s\n", rule.Unit, name)
+	}
+	return ExitOk
+}
+
+func (t CompSystemdUnits) checkMasked(rule CompSystemdUnit) ExitCode {
+	return t.checkState(rule, "masked", rule.Masked, isSystemdUnitMasked)
+}
+
+func (t CompSystemdUnits) checkEnabled(rule CompSystemdUnit) ExitCode {
+	if rule.Enabled == nil {
+		return ExitNotApplicable
+	}
+	if v, s := isSystemdUnitEnableable(rule.Unit); !v {
+		t.VerboseInfof("systemd unit %s is %s, enabled state not applicable\n", rule.Unit, s)
+		return ExitNotApplicable
+	}
+	return t.checkState(rule, "enabled", rule.Enabled, isSystemdUnitEnabled)
+}
+
+func (t CompSystemdUnits) checkActive(rule CompSystemdUnit) ExitCode {
+	return t.checkState(rule, "active", rule.Active, isSystemdUnitActive)
+}
+
+// fixState runs the systemctl <on> or <off> action, depending on the target
+// state.
+func (t CompSystemdUnits) fixState(rule CompSystemdUnit, target *bool, on, off string) ExitCode {
+	action := off
+	if *target {
+		action = on
+	}
+	if err := systemctlRun(action, rule.Unit); err != nil {
+		t.Errorf("systemd unit %s %s: %s\n", rule.Unit, action, err)
+		return ExitNok
+	}
+	t.Infof("systemd unit %s %s done\n", rule.Unit, action)
+	return ExitOk
+}
+
+func (t CompSystemdUnits) CheckRule(rule CompSystemdUnit) ExitCode {
+	e := ExitOk
+	e = e.Merge(t.checkMasked(rule))
+	e = e.Merge(t.checkEnabled(rule))
+	e = e.Merge(t.checkActive(rule))
+	return e
+}
+
+func (t CompSystemdUnits) FixRule(rule CompSystemdUnit) ExitCode {
+	if e := t.checkMasked(rule); e == ExitNok {
+		if e := t.fixState(rule, rule.Masked, "mask", "unmask"); e == ExitNok {
+			return e
+		}
+	}
+	if e := t.checkEnabled(rule); e == ExitNok {
+		if e := t.fixState(rule, rule.Enabled, "enable", "disable"); e == ExitNok {
+			return e
+		}
+	}
+	if e := t.checkActive(rule); e == ExitNok {
+		if e := t.fixState(rule, rule.Active, "start", "stop"); e == ExitNok {
+			return e
+		}
+	}
+	return ExitOk
+}
+
+func (t CompSystemdUnits) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompSystemdUnit)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompSystemdUnits) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompSystemdUnit)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompSystemdUnits) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompSystemdUnits) Info() ObjInfo {
+	return compSystemdUnitInfo
+}
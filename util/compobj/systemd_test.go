@@ -0,0 +1,105 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSystemdUnit struct {
+	enabled string
+	active  string
+}
+
+// setupSystemctl installs a fake systemctl serving and changing the units
+// states, and recording the executed state changes.
+func setupSystemctl(t *testing.T, units map[string]*fakeSystemdUnit) *[]string {
+	savedOutput, savedRun := systemctlOutput, systemctlRun
+	t.Cleanup(func() {
+		systemctlOutput, systemctlRun = savedOutput, savedRun
+	})
+	executed := make([]string, 0)
+	systemctlOutput = func(args ...string) string {
+		unit, ok := units[args[1]]
+		if !ok {
+			return ""
+		}
+		switch args[0] {
+		case "is-enabled":
+			return unit.enabled
+		case "is-active":
+			return unit.active
+		default:
+			return ""
+		}
+	}
+	systemctlRun = func(args ...string) error {
+		executed = append(executed, strings.Join(args, " "))
+		unit := units[args[1]]
+		switch args[0] {
+		case "enable":
+			unit.enabled = "enabled"
+		case "disable", "unmask":
+			unit.enabled = "disabled"
+		case "mask":
+			unit.enabled = "masked"
+		case "start":
+			unit.active = "active"
+		case "stop":
+			unit.active = "inactive"
+		}
+		return nil
+	}
+	return &executed
+}
+
+func TestCompSystemdUnitsEnable(t *testing.T) {
+	executed := setupSystemctl(t, map[string]*fakeSystemdUnit{
+		"chronyd.service": {enabled: "disabled", active: "active"},
+		"sshd.service":    {enabled: "enabled", active: "active"},
+	})
+	obj := NewCompSystemdUnits().(*CompSystemdUnits)
+	require.NoError(t, obj.Add(`{"unit": "chronyd.service", "enabled": true, "active": true}`))
+	require.NoError(t, obj.Add(`{"unit": "sshd.service", "enabled": true}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Equal(t, []string{"enable chronyd.service"}, *executed)
+}
+
+func TestCompSystemdUnitsUnmask(t *testing.T) {
+	executed := setupSystemctl(t, map[string]*fakeSystemdUnit{
+		"chronyd.service": {enabled: "masked", active: "inactive"},
+	})
+	obj := NewCompSystemdUnits().(*CompSystemdUnits)
+	require.NoError(t, obj.Add(`{"unit": "chronyd.service", "masked": false}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Equal(t, []string{"unmask chronyd.service"}, *executed)
+}
+
+func TestCompSystemdUnitsEnabledNotApplicable(t *testing.T) {
+	for _, state := range []string{"static", "alias", "indirect", "generated"} {
+		t.Run(state, func(t *testing.T) {
+			executed := setupSystemctl(t, map[string]*fakeSystemdUnit{
+				"systemd-journald.service": {enabled: state, active: "active"},
+			})
+			obj := NewCompSystemdUnits().(*CompSystemdUnits)
+			require.NoError(t, obj.Add(`{"unit": "systemd-journald.service", "enabled": false, "active": true}`))
+			assert.Equal(t, ExitOk, obj.Check())
+			assert.Equal(t, ExitOk, obj.Fix())
+			assert.Empty(t, *executed, "no enable nor disable on a %s unit", state)
+		})
+	}
+}
+
+func TestCompSystemdUnitsAdd(t *testing.T) {
+	obj := NewCompSystemdUnits().(*CompSystemdUnits)
+	require.NoError(t, obj.Add(`{"enabled": true}`))
+	require.NoError(t, obj.Add(`{"unit": "chronyd.service", "masked": true, "enabled": true}`))
+	require.NoError(t, obj.Add(`{"unit": "chronyd.service", "masked": true, "active": true}`))
+	assert.Empty(t, obj.Rules())
+}
@@ -0,0 +1,445 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	CompUsers struct {
+		*Obj
+	}
+	CompUser struct {
+		User   string `json:"user"`
+		UID    *int   `json:"uid,omitempty"`
+		GID    *int   `json:"gid,omitempty"`
+		Shell  string `json:"shell,omitempty"`
+		Home   string `json:"home,omitempty"`
+		Locked *bool  `json:"locked,omitempty"`
+	}
+
+	// passwdEntry is the account of a user, as resolved by the name
+	// service switch.
+	passwdEntry struct {
+		Name string
+		UID  int
+		GID  int
+		Home string
+	}
+)
+
+var (
+	// lookupUser, lookupUserShell and lookupUserPassword resolve the user
+	// accounts through the name service switch, so the accounts of the
+	// directory services are found too.
+	lookupUser      = user.Lookup
+	lookupUserShell = func(name string) (string, error) {
+		cmd := command.New(
+			command.WithName("getent"),
+			command.WithVarArgs("passwd", name),
+			command.WithBufferedStdout(),
+		)
+		b, err := cmd.Output()
+		if err != nil {
+			return "", err
+		}
+		l := strings.Split(strings.TrimSpace(string(b)), ":")
+		if len(l) < 7 {
+			return "", fmt.Errorf("getent passwd %s: invalid entry", name)
+		}
+		return l[6], nil
+	}
+
+	// lookupUserPassword returns the password hash field of the user
+	// shadow entry, and false if the name service has no shadow entry
+	// for the user, like for most directory service accounts.
+	lookupUserPassword = func(name string) (string, bool, error) {
+		cmd := command.New(
+			command.WithName("getent"),
+			command.WithVarArgs("shadow", name),
+			command.WithBufferedStdout(),
+			command.WithIgnoredExitCodes(0, 2),
+		)
+		if err := cmd.Run(); err != nil {
+			return "", false, err
+		}
+		if cmd.ExitCode() == 2 {
+			return "", false, nil
+		}
+		l := strings.Split(strings.TrimSpace(string(cmd.Stdout())), ":")
+		if len(l) < 2 {
+			return "", false, fmt.Errorf("getent shadow %s: invalid entry", name)
+		}
+		return l[1], true, nil
+	}
+
+	// identityRun executes the user and group management commands.
+	identityRun = func(name string, args ...string) error {
+		cmd := command.New(
+			command.WithName(name),
+			command.WithArgs(args),
+			command.WithOnStdoutLine(fo),
+			command.WithOnStderrLine(fe),
+		)
+		return cmd.Run()
+	}
+)
+
+var compUserInfo = ObjInfo{
+	DefaultPrefix: "OSVC_COMP_USER_",
+	ExampleValue: CompUser{
+		User:   "appuser",
+		UID:    intPtr(1001),
+		GID:    intPtr(1001),
+		Shell:  "/bin/bash",
+		Home:   "/home/appuser",
+		Locked: boolPtr(false),
+	},
+	Description: `* Verify a local user exists with the specified uid, gid, shell and home.
+* Verify the local user password is locked or unlocked. Not applicable to the users without a shadow entry, like most directory service users.
+* Only the properties set in the rule are verified.
+* In the 'fix' the user is created with useradd, or modified with usermod.
+
+Special wildcards::
+
+  %%ENV:VARNAME%%   Any environment variable value
+  %%HOSTNAME%%      Hostname
+  %%SHORT_HOSTNAME%%    Short hostname
+`,
+	FormDefinition: `Desc: |
+  A local user rule, fed to the 'user' compliance object to create or modify a local user account.
+Css: comp48
+
+Outputs:
+  -
+    Dest: compliance variable
+    Class: user
+    Type: json
+    Format: dict
+
+Inputs:
+  -
+    Id: user
+    Label: User name
+    DisplayModeLabel: user
+    LabelCss: guy16
+    Mandatory: Yes
+    Help: The local user name.
+    Type: string
+
+  -
+    Id: uid
+    Label: User id
+    DisplayModeLabel: uid
+    LabelCss: guy16
+    Help: The local user id.
+    Type: integer
+
+  -
+    Id: gid
+    Label: Group id
+    DisplayModeLabel: gid
+    LabelCss: guys16
+    Help: The local user primary group id.
+    Type: integer
+
+  -
+    Id: shell
+    Label: Login shell
+    DisplayModeLabel: shell
+    LabelCss: action16
+    Help: The local user login shell.
+    Type: string
+
+  -
+    Id: home
+    Label: Home directory
+    DisplayModeLabel: home
+    LabelCss: action16
+    Help: The local user home directory.
+    Type: string
+
+  -
+    Id: locked
+    Label: Locked
+    DisplayModeLabel: locked
+    LabelCss: lock
+    Help: If set, verify the user password is locked (true) or unlocked (false).
+    Type: boolean
+`,
+}
+
+func init() {
+	m["user"] = NewCompUsers
+}
+
+func intPtr(v int) *int {
+	return &v
+}
+
+func NewCompUsers() interface{} {
+	return &CompUsers{
+		Obj: NewObj(),
+	}
+}
+
+func (t *CompUsers) Add(s string) error {
+	var data CompUser
+	if err := json.Unmarshal(subst([]byte(s)), &data); err != nil {
+		return err
+	}
+	if data.User == "" {
+		t.Errorf("user should be in the dict: %s\n", s)
+		return nil
+	}
+	t.Obj.Add(data)
+	return nil
+}
+
+// readColumned returns the ':' separated fields of the lines of the file
+// p, indexed by the first field.
+func readColumned(p string) (map[string][]string, error) {
+	data := make(map[string][]string)
+	f, err := os.Open(p)
+	if err != nil {
+		return data, err
+	}
+	defer f.Close()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		l := strings.Split(line, ":")
+		data[l[0]] = l
+	}
+	return data, scanner.Err()
+}
+
+// getPasswdEntry returns the account of the user, and false if the user
+// does not exist.
+func getPasswdEntry(name string) (passwdEntry, bool, error) {
+	u, err := lookupUser(name)
+	if errors.As(err, new(user.UnknownUserError)) {
+		return passwdEntry{}, false, nil
+	} else if err != nil {
+		return passwdEntry{}, false, err
+	}
+	e := passwdEntry{
+		Name: u.Username,
+		Home: u.HomeDir,
+	}
+	if e.UID, err = strconv.Atoi(u.Uid); err != nil {
+		return e, true, fmt.Errorf("invalid uid for user %s: %s", name, err)
+	}
+	if e.GID, err = strconv.Atoi(u.Gid); err != nil {
+		return e, true, fmt.Errorf("invalid gid for user %s: %s", name, err)
+	}
+	return e, true, nil
+}
+
+// isUserLocked returns true if the user password hash is prefixed by the
+// '!' lock marker, and false as second value if the user has no shadow
+// entry.
+func isUserLocked(name string) (bool, bool, error) {
+	hash, ok, err := lookupUserPassword(name)
+	if err != nil || !ok {
+		return false, ok, err
+	}
+	return strings.HasPrefix(hash, "!"), true, nil
+}
+
+// checkProperties returns the usermod arguments required to fix the
+// properties of the existing user.
+func (t CompUsers) checkProperties(rule CompUser, e passwdEntry) ([]string, error) {
+	args := make([]string, 0)
+	if rule.UID != nil && *rule.UID != e.UID {
+		t.VerboseErrorf("user %s uid is %d, should be %d\n", rule.User, e.UID, *rule.UID)
+		args = append(args, "-u", fmt.Sprint(*rule.UID))
+	}
+	if rule.GID != nil && *rule.GID != e.GID {
+		t.VerboseErrorf("user %s gid is %d, should be %d\n", rule.User, e.GID, *rule.GID)
+		args = append(args, "-g", fmt.Sprint(*rule.GID))
+	}
+	if rule.Shell != "" {
+		shell, err := lookupUserShell(rule.User)
+		if err != nil {
+			return args, err
+		}
+		if rule.Shell != shell {
+			t.VerboseErrorf("user %s shell is %s, should be %s\n", rule.User, shell, rule.Shell)
+			args = append(args, "-s", rule.Shell)
+		}
+	}
+	if rule.Home != "" && rule.Home != e.Home {
+		t.VerboseErrorf("user %s home is %s, should be %s\n", rule.User, e.Home, rule.Home)
+		args = append(args, "-d", rule.Home)
+	}
+	return args, nil
+}
+
+func (t CompUsers) checkUser(rule CompUser) ExitCode {
+	e, ok, err := getPasswdEntry(rule.User)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("user %s: %s\n", rule.User, err)
+		return ExitNok
+	case !ok:
+		t.VerboseErrorf("user %s does not exist\n", rule.User)
+		return ExitNok
+	}
+	if args, err := t.checkProperties(rule, e); err != nil {
+		t.VerboseErrorf("user %s: %s\n", rule.User, err)
+		return ExitNok
+	} else if len(args) > 0 {
+		return ExitNok
+	}
+	t.VerboseInfof("user %s properties are ok\n", rule.User)
+	return ExitOk
+}
+
+func (t CompUsers) fixUser(rule CompUser) ExitCode {
+	e, ok, err := getPasswdEntry(rule.User)
+	if err != nil {
+		t.Errorf("user %s: %s\n", rule.User, err)
+		return ExitNok
+	}
+	if !ok {
+		args := make([]string, 0)
+		if rule.UID != nil {
+			args = append(args, "-u", fmt.Sprint(*rule.UID))
+		}
+		if rule.GID != nil {
+			args = append(args, "-g", fmt.Sprint(*rule.GID))
+		}
+		if rule.Shell != "" {
+			args = append(args, "-s", rule.Shell)
+		}
+		if rule.Home != "" {
+			args = append(args, "-d", rule.Home)
+		}
+		args = append(args, rule.User)
+		if err := identityRun("useradd", args...); err != nil {
+			t.Errorf("user %s create: %s\n", rule.User, err)
+			return ExitNok
+		}
+		t.Infof("user %s created\n", rule.User)
+		return ExitOk
+	}
+	args, err := t.checkProperties(rule, e)
+	if err != nil {
+		t.Errorf("user %s: %s\n", rule.User, err)
+		return ExitNok
+	}
+	if len(args) == 0 {
+		return ExitOk
+	}
+	args = append(args, rule.User)
+	if err := identityRun("usermod", args...); err != nil {
+		t.Errorf("user %s modify: %s\n", rule.User, err)
+		return ExitNok
+	}
+	t.Infof("user %s modified: %s\n", rule.User, strings.Join(args[:len(args)-1], " "))
+	return ExitOk
+}
+
+func (t CompUsers) checkLocked(rule CompUser) ExitCode {
+	if rule.Locked == nil {
+		return ExitNotApplicable
+	}
+	locked, ok, err := isUserLocked(rule.User)
+	switch {
+	case err != nil:
+		t.VerboseErrorf("user %s get locked state: %s\n", rule.User, err)
+		return ExitNok
+	case !ok:
+		t.VerboseInfof("user %s has no shadow entry, the locked state is not applicable\n", rule.User)
+		return ExitNotApplicable
+	case locked && !*rule.Locked:
+		t.VerboseErrorf("user %s is locked, should not be\n", rule.User)
+		return ExitNok
+	case !locked && *rule.Locked:
+		t.VerboseErrorf("user %s is not locked, should be\n", rule.User)
+		return ExitNok
+	case locked:
+		t.VerboseInfof("user %s is locked\n", rule.User)
+	default:
+		t.VerboseInfof("user %s is not locked\n", rule.User)
+	}
+	return ExitOk
+}
+
+func (t CompUsers) fixLocked(rule CompUser) ExitCode {
+	flag, done := "-U", "unlocked"
+	if *rule.Locked {
+		flag, done = "-L", "locked"
+	}
+	if err := identityRun("usermod", flag, rule.User); err != nil {
+		t.Errorf("user %s set %s: %s\n", rule.User, done, err)
+		return ExitNok
+	}
+	t.Infof("user %s %s\n", rule.User, done)
+	return ExitOk
+}
+
+func (t CompUsers) CheckRule(rule CompUser) ExitCode {
+	e := t.checkUser(rule)
+	if e == ExitNok {
+		// the locked state of a missing user is meaningless
+		return e
+	}
+	return e.Merge(t.checkLocked(rule))
+}
+
+func (t CompUsers) FixRule(rule CompUser) ExitCode {
+	if e := t.checkUser(rule); e == ExitNok {
+		if e := t.fixUser(rule); e == ExitNok {
+			return e
+		}
+	}
+	if e := t.checkLocked(rule); e == ExitNok {
+		if e := t.fixLocked(rule); e == ExitNok {
+			return e
+		}
+	}
+	return ExitOk
+}
+
+func (t CompUsers) Check() ExitCode {
+	t.SetVerbose(true)
+	e := ExitOk
+	for _, i := range t.Rules() {
+		rule := i.(CompUser)
+		o := t.CheckRule(rule)
+		e = e.Merge(o)
+	}
+	return e
+}
+
+func (t CompUsers) Fix() ExitCode {
+	t.SetVerbose(false)
+	for _, i := range t.Rules() {
+		rule := i.(CompUser)
+		if e := t.FixRule(rule); e == ExitNok {
+			return ExitNok
+		}
+	}
+	return ExitOk
+}
+
+func (t CompUsers) Fixable() ExitCode {
+	return ExitNotApplicable
+}
+
+func (t CompUsers) Info() ObjInfo {
+	return compUserInfo
+}
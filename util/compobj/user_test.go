@@ -0,0 +1,166 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupIdentityFiles installs fake user and password lookups, a group file,
+// and a command runner recording the executed commands. The appuser home
+// directory is created. The originals are restored when the test ends.
+func setupIdentityFiles(t *testing.T) (string, *[]string) {
+	savedLookupUser, savedLookupUserShell, savedLookupUserPassword := lookupUser, lookupUserShell, lookupUserPassword
+	savedGroupFile, savedIdentityRun := groupFile, identityRun
+	t.Cleanup(func() {
+		lookupUser, lookupUserShell, lookupUserPassword = savedLookupUser, savedLookupUserShell, savedLookupUserPassword
+		groupFile, identityRun = savedGroupFile, savedIdentityRun
+	})
+	dir := t.TempDir()
+	home := filepath.Join(dir, "home", "appuser")
+	require.NoError(t, os.MkdirAll(home, 0755))
+	accounts := map[string]user.User{
+		"root":     {Username: "root", Uid: "0", Gid: "0", HomeDir: "/root"},
+		"appuser":  {Username: "appuser", Uid: fmt.Sprint(os.Getuid()), Gid: fmt.Sprint(os.Getgid()), HomeDir: home},
+		"ldapuser": {Username: "ldapuser", Uid: "10001", Gid: "10001", HomeDir: "/home/ldapuser"},
+	}
+	shells := map[string]string{
+		"root":     "/bin/bash",
+		"appuser":  "/bin/sh",
+		"ldapuser": "/bin/bash",
+	}
+	passwords := map[string]string{
+		"root":    "!",
+		"appuser": "$6$x",
+	}
+	lookupUser = func(name string) (*user.User, error) {
+		if u, ok := accounts[name]; ok {
+			return &u, nil
+		}
+		return nil, user.UnknownUserError(name)
+	}
+	lookupUserShell = func(name string) (string, error) {
+		return shells[name], nil
+	}
+	lookupUserPassword = func(name string) (string, bool, error) {
+		hash, ok := passwords[name]
+		return hash, ok, nil
+	}
+	groupFile = filepath.Join(dir, "group")
+	require.NoError(t, os.WriteFile(groupFile, []byte("root:x:0:\napp:x:1001:appuser,olduser\n"), 0644))
+	executed := make([]string, 0)
+	identityRun = func(name string, args ...string) error {
+		executed = append(executed, name+" "+strings.Join(args, " "))
+		return nil
+	}
+	return home, &executed
+}
+
+func TestCompUsers(t *testing.T) {
+	_, executed := setupIdentityFiles(t)
+	obj := NewCompUsers().(*CompUsers)
+	require.NoError(t, obj.Add(`{"user": "root", "uid": 0, "shell": "/bin/bash", "locked": true}`))
+	require.NoError(t, obj.Add(`{"user": "appuser", "shell": "/bin/bash", "locked": true}`))
+	require.NoError(t, obj.Add(`{"user": "newuser", "uid": 1002, "home": "/home/newuser"}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, []string{
+		"usermod -s /bin/bash appuser",
+		"usermod -L appuser",
+		"useradd -u 1002 -d /home/newuser newuser",
+	}, *executed)
+}
+
+func TestCompUsersLockedWithoutShadowEntry(t *testing.T) {
+	_, executed := setupIdentityFiles(t)
+	obj := NewCompUsers().(*CompUsers)
+	require.NoError(t, obj.Add(`{"user": "ldapuser", "shell": "/bin/bash", "locked": true}`))
+	assert.Equal(t, ExitOk, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Empty(t, *executed)
+}
+
+func TestCompGroups(t *testing.T) {
+	_, executed := setupIdentityFiles(t)
+	obj := NewCompGroups().(*CompGroups)
+	require.NoError(t, obj.Add(`{"group": "app", "gid": 1001, "members": ["appuser", "root"], "exclusive": true}`))
+	require.NoError(t, obj.Add(`{"group": "root", "gid": 0}`))
+	assert.Equal(t, ExitNok, obj.Check())
+	assert.Equal(t, ExitOk, obj.Fix())
+	assert.Equal(t, []string{
+		"gpasswd -a root app",
+		"gpasswd -d olduser app",
+	}, *executed)
+}
+
+func TestCompAuthKeys(t *testing.T) {
+	home, _ := setupIdentityFiles(t)
+	p := filepath.Join(home, ".ssh", "authorized_keys")
+	key1 := "ssh-ed25519 AAAAkey1 admin@%%SHORT_HOSTNAME%%"
+	key2 := "ssh-rsa AAAAkey2 old@corp.com"
+
+	t.Run("add creates the file", func(t *testing.T) {
+		obj := NewCompAuthKeys().(*CompAuthKeys)
+		require.NoError(t, obj.Add(`{"user": "appuser", "key": "`+key1+`", "action": "add"}`))
+		assert.Equal(t, ExitNok, obj.Check())
+		assert.Equal(t, ExitOk, obj.Fix())
+		assert.Equal(t, ExitOk, obj.Check())
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "%%SHORT_HOSTNAME%%")
+	})
+
+	t.Run("options are updated and other keys are kept", func(t *testing.T) {
+		f, err := os.OpenFile(p, os.O_APPEND|os.O_WRONLY, 0600)
+		require.NoError(t, err)
+		_, err = f.WriteString(key2 + "\n")
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		obj := NewCompAuthKeys().(*CompAuthKeys)
+		require.NoError(t, obj.Add(`{"user": "appuser", "key": "ssh-ed25519 AAAAkey1", "options": "from=\"10.0.0.0/8,192.168.0.1\",no-pty"}`))
+		assert.Equal(t, ExitNok, obj.Check())
+		assert.Equal(t, ExitOk, obj.Fix())
+		assert.Equal(t, ExitOk, obj.Check())
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+		assert.Equal(t, key2+"\n"+`from="10.0.0.0/8,192.168.0.1",no-pty ssh-ed25519 AAAAkey1`+"\n", string(b))
+	})
+
+	t.Run("del removes the key", func(t *testing.T) {
+		obj := NewCompAuthKeys().(*CompAuthKeys)
+		require.NoError(t, obj.Add(`{"user": "appuser", "key": "`+key2+`", "action": "del"}`))
+		assert.Equal(t, ExitNok, obj.Check())
+		assert.Equal(t, ExitOk, obj.Fix())
+		assert.Equal(t, ExitOk, obj.Check())
+		b, err := os.ReadFile(p)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), "AAAAkey2")
+	})
+
+	t.Run("symlinks are refused", func(t *testing.T) {
+		target := filepath.Join(t.TempDir(), "authorized_keys")
+		require.NoError(t, os.WriteFile(target, []byte(key2+"\n"), 0644))
+		require.NoError(t, os.Remove(p))
+		require.NoError(t, os.Symlink(target, p))
+		obj := NewCompAuthKeys().(*CompAuthKeys)
+		require.NoError(t, obj.Add(`{"user": "appuser", "key": "`+key2+`", "action": "del"}`))
+		assert.Equal(t, ExitNok, obj.Fix())
+		b, err := os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, key2+"\n", string(b), "the symlink target must not be modified")
+
+		sshDir := filepath.Dir(p)
+		require.NoError(t, os.RemoveAll(sshDir))
+		require.NoError(t, os.Symlink(filepath.Dir(target), sshDir))
+		assert.Equal(t, ExitNok, obj.Fix())
+		b, err = os.ReadFile(target)
+		require.NoError(t, err)
+		assert.Equal(t, key2+"\n", string(b), "the symlinked directory content must not be modified")
+	})
+}
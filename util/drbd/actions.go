@@ -0,0 +1,105 @@
+package drbd
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+func (t T) drbdadm(args ...string) error {
+	cmd := command.New(
+		command.WithName("drbdadm"),
+		command.WithArgs(args),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+func (t T) drbdadmOutput(args ...string) (string, error) {
+	cmd := command.New(
+		command.WithName("drbdadm"),
+		command.WithArgs(args),
+		command.WithBufferedStdout(),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.DebugLevel),
+	)
+	b, err := cmd.Output()
+	return strings.TrimSpace(string(b)), err
+}
+
+// CreateMD initializes the drbd meta data on the local backing disk.
+func (t T) CreateMD(maxPeers int) error {
+	args := []string{"create-md", "--force"}
+	if maxPeers > 0 {
+		args = append(args, "--max-peers", strconv.Itoa(maxPeers))
+	}
+	args = append(args, t.res)
+	return t.drbdadm(args...)
+}
+
+// WipeMD destroys the drbd meta data on the local backing disk.
+func (t T) WipeMD() error {
+	return t.drbdadm("wipe-md", "--force", t.res)
+}
+
+// HasMD returns true if valid drbd meta data is found on the local
+// backing disk.
+func (t T) HasMD() (bool, error) {
+	cmd := command.New(
+		command.WithName("drbdadm"),
+		command.WithVarArgs("dump-md", t.res),
+		command.WithLogger(t.log),
+		command.WithBufferedStdout(),
+		command.WithBufferedStderr(),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithIgnoredExitCodes(),
+	)
+	if err := cmd.Run(); err != nil {
+		return false, err
+	}
+	switch cmd.ExitCode() {
+	case 0:
+		return true, nil
+	case 10:
+		// Device is attached: the meta data can not be dumped, but exists.
+		return true, nil
+	default:
+		return false, nil
+	}
+}
+
+// Up attaches the backing disk and connects the peers.
+func (t T) Up() error {
+	return t.drbdadm("up", t.res)
+}
+
+// Adjust synchronizes the kernel state with the resource file.
+func (t T) Adjust() error {
+	return t.drbdadm("adjust", t.res)
+}
+
+// Down disconnects the peers and detaches the backing disk.
+func (t T) Down() error {
+	return t.drbdadm("down", t.res)
+}
+
+// Primary promotes the local node. The force option allows promotion
+// of a node with inconsistent data, as required by the initial sync.
+func (t T) Primary(force bool) error {
+	if force {
+		return t.drbdadm("primary", "--force", t.res)
+	}
+	return t.drbdadm("primary", t.res)
+}
+
+// Secondary demotes the local node.
+func (t T) Secondary() error {
+	return t.drbdadm("secondary", t.res)
+}
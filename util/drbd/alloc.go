@@ -0,0 +1,95 @@
+package drbd
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+)
+
+type (
+	// Allocations is the set of minors and ports used by the installed
+	// drbd resource files, and by the resources of the peer nodes.
+	Allocations struct {
+		Minors map[int]any
+		Ports  map[int]any
+	}
+)
+
+const (
+	// MinPort is the first tcp port allocated to drbd resources.
+	MinPort = 7289
+)
+
+var (
+	reMinor = regexp.MustCompile(`(?m)(?:/dev/drbd|minor\s+)(\d+)`)
+	rePort  = regexp.MustCompile(`(?m)address\s+(?:ipv[46]\s+)?\S+:(\d+)\s*;`)
+)
+
+func newAllocations() Allocations {
+	return Allocations{
+		Minors: make(map[int]any),
+		Ports:  make(map[int]any),
+	}
+}
+
+func (t Allocations) parse(b []byte) {
+	for _, m := range reMinor.FindAllSubmatch(b, -1) {
+		if i, err := strconv.Atoi(string(m[1])); err == nil {
+			t.Minors[i] = nil
+		}
+	}
+	for _, m := range rePort.FindAllSubmatch(b, -1) {
+		if i, err := strconv.Atoi(string(m[1])); err == nil {
+			t.Ports[i] = nil
+		}
+	}
+}
+
+// GetAllocations returns the minors and ports used by the drbd resource
+// files installed in ConfigDir.
+func GetAllocations() (Allocations, error) {
+	data := newAllocations()
+	matches, err := filepath.Glob(filepath.Join(ConfigDir, "*.res"))
+	if err != nil {
+		return data, err
+	}
+	for _, p := range matches {
+		b, err := os.ReadFile(p)
+		if err != nil {
+			return data, err
+		}
+		data.parse(b)
+	}
+	return data, nil
+}
+
+// Add marks the minor and port as used, for example by a resource
+// installed on a peer node.
+func (t Allocations) Add(minor, port int) {
+	t.Minors[minor] = nil
+	t.Ports[port] = nil
+}
+
+// FreeMinor returns the lowest minor not used by an installed resource.
+func (t Allocations) FreeMinor() int {
+	i := 0
+	for {
+		if _, ok := t.Minors[i]; !ok {
+			return i
+		}
+		i++
+	}
+}
+
+// FreePort returns the lowest port, starting from MinPort, not used by
+// an installed resource.
+func (t Allocations) FreePort() int {
+	i := MinPort
+	for {
+		if _, ok := t.Ports[i]; !ok {
+			return i
+		}
+		i++
+	}
+}
@@ -0,0 +1,103 @@
+package drbd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"strings"
+
+	"opensvc.com/opensvc/util/file"
+)
+
+type (
+	// Config is the definition of a drbd resource, rendered as a drbd 9
+	// resource file.
+	Config struct {
+		Name  string
+		Minor int
+		Port  int
+		Hosts []Host
+	}
+
+	// Host is the definition of a drbd resource node.
+	Host struct {
+		Name string
+		Disk string
+		Addr string
+	}
+)
+
+// Device returns the path of the drbd device exposed on every host.
+func (t Config) Device() string {
+	return fmt.Sprintf("/dev/drbd%d", t.Minor)
+}
+
+// Render returns the drbd resource file content.
+func (t Config) Render() []byte {
+	var b bytes.Buffer
+	names := make([]string, len(t.Hosts))
+	fmt.Fprintf(&b, "resource %s {\n", t.Name)
+	for i, h := range t.Hosts {
+		names[i] = h.Name
+		fmt.Fprintf(&b, "    on %s {\n", h.Name)
+		fmt.Fprintf(&b, "        device    %s;\n", t.Device())
+		fmt.Fprintf(&b, "        disk      %s;\n", h.Disk)
+		fmt.Fprintf(&b, "        meta-disk internal;\n")
+		fmt.Fprintf(&b, "        address   %s;\n", formatAddr(h.Addr, t.Port))
+		fmt.Fprintf(&b, "        node-id   %d;\n", i)
+		fmt.Fprintf(&b, "    }\n")
+	}
+	if len(names) > 1 {
+		fmt.Fprintf(&b, "    connection-mesh {\n")
+		fmt.Fprintf(&b, "        hosts %s;\n", strings.Join(names, " "))
+		fmt.Fprintf(&b, "    }\n")
+	}
+	fmt.Fprintf(&b, "}\n")
+	return b.Bytes()
+}
+
+func formatAddr(addr string, port int) string {
+	if strings.Contains(addr, ":") {
+		return fmt.Sprintf("ipv6 [%s]:%d", addr, port)
+	}
+	return fmt.Sprintf("%s:%d", addr, port)
+}
+
+// WriteConfig installs the rendered resource file, if its content changed.
+// It returns true if the file was written.
+func (t T) WriteConfig(c Config) (bool, error) {
+	b := c.Render()
+	p := t.ConfigFile()
+	if file.Exists(p) {
+		if current, err := os.ReadFile(p); err == nil && bytes.Equal(current, b) {
+			return false, nil
+		}
+	}
+	if err := os.MkdirAll(ConfigDir, 0755); err != nil {
+		return false, err
+	}
+	if t.log != nil {
+		t.log.Info().Msgf("install %s", p)
+	}
+	if err := os.WriteFile(p, b, 0644); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RemoveConfig removes the drbd resource file.
+func (t T) RemoveConfig() error {
+	p := t.ConfigFile()
+	if !file.Exists(p) {
+		return nil
+	}
+	if t.log != nil {
+		t.log.Info().Msgf("remove %s", p)
+	}
+	return os.Remove(p)
+}
+
+// HasConfig returns true if the drbd resource file is installed.
+func (t T) HasConfig() bool {
+	return file.Exists(t.ConfigFile())
+}
@@ -0,0 +1,56 @@
+// Package drbd wraps the drbdadm and drbdsetup commands to manage the
+// DRBD replicated block devices, and generates their resource files.
+package drbd
+
+import (
+	"os/exec"
+	"path/filepath"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/funcopt"
+)
+
+type (
+	T struct {
+		res string
+		log *zerolog.Logger
+	}
+)
+
+var (
+	// ConfigDir is the directory hosting the drbd resource files.
+	ConfigDir = "/etc/drbd.d"
+)
+
+func IsCapable() bool {
+	if _, err := exec.LookPath("drbdadm"); err != nil {
+		return false
+	}
+	return true
+}
+
+func New(res string, opts ...funcopt.O) *T {
+	t := T{
+		res: res,
+	}
+	_ = funcopt.Apply(&t, opts...)
+	return &t
+}
+
+func WithLogger(log *zerolog.Logger) funcopt.O {
+	return funcopt.F(func(i interface{}) error {
+		t := i.(*T)
+		t.log = log
+		return nil
+	})
+}
+
+// Name returns the drbd resource name.
+func (t T) Name() string {
+	return t.res
+}
+
+// ConfigFile returns the path of the drbd resource file.
+func (t T) ConfigFile() string {
+	return filepath.Join(ConfigDir, t.res+".res")
+}
@@ -0,0 +1,57 @@
+package drbd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestConfigRender(t *testing.T) {
+	c := Config{
+		Name:  "svc1.disk.1",
+		Minor: 3,
+		Port:  7290,
+		Hosts: []Host{
+			{Name: "n1", Disk: "/dev/vg1/svc1", Addr: "10.0.0.1"},
+			{Name: "n2", Disk: "/dev/vg1/svc1", Addr: "fd00::2"},
+		},
+	}
+	expected := `resource svc1.disk.1 {
+    on n1 {
+        device    /dev/drbd3;
+        disk      /dev/vg1/svc1;
+        meta-disk internal;
+        address   10.0.0.1:7290;
+        node-id   0;
+    }
+    on n2 {
+        device    /dev/drbd3;
+        disk      /dev/vg1/svc1;
+        meta-disk internal;
+        address   ipv6 [fd00::2]:7290;
+        node-id   1;
+    }
+    connection-mesh {
+        hosts n1 n2;
+    }
+}
+`
+	require.Equal(t, expected, string(c.Render()))
+}
+
+func TestAllocations(t *testing.T) {
+	data := newAllocations()
+	data.parse(Config{Name: "a", Minor: 0, Port: 7289, Hosts: []Host{{Name: "n1", Addr: "10.0.0.1"}}}.Render())
+	data.parse(Config{Name: "b", Minor: 2, Port: 7290, Hosts: []Host{{Name: "n1", Addr: "fd00::1"}}}.Render())
+	require.Equal(t, 1, data.FreeMinor())
+	require.Equal(t, 7291, data.FreePort())
+}
+
+func TestParseStates(t *testing.T) {
+	require.Equal(t, "Primary", parseRole("Primary\n"))
+	require.Equal(t, "Secondary", parseRole("Secondary/Primary"))
+	require.Equal(t, []string{"Connected", "StandAlone"}, parseLines("Connected\nStandAlone\n"))
+	local, peers := parseDStates("UpToDate/Inconsistent\nUpToDate/UpToDate\n")
+	require.Equal(t, "UpToDate", local)
+	require.Equal(t, []string{"Inconsistent", "UpToDate"}, peers)
+}
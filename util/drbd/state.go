@@ -0,0 +1,96 @@
+package drbd
+
+import (
+	"strings"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+const (
+	RolePrimary   = "Primary"
+	RoleSecondary = "Secondary"
+
+	CStateConnected  = "Connected"
+	CStateStandAlone = "StandAlone"
+
+	DStateUpToDate     = "UpToDate"
+	DStateInconsistent = "Inconsistent"
+	DStateDiskless     = "Diskless"
+)
+
+// IsUp returns true if the resource is configured in the kernel.
+func (t T) IsUp() (bool, error) {
+	cmd := command.New(
+		command.WithName("drbdsetup"),
+		command.WithVarArgs("status", t.res),
+		command.WithLogger(t.log),
+		command.WithBufferedStdout(),
+		command.WithBufferedStderr(),
+		command.WithCommandLogLevel(zerolog.DebugLevel),
+		command.WithIgnoredExitCodes(),
+	)
+	if err := cmd.Run(); err != nil {
+		return false, err
+	}
+	return cmd.ExitCode() == 0, nil
+}
+
+// Role returns the local role of the resource: Primary or Secondary.
+func (t T) Role() (string, error) {
+	s, err := t.drbdadmOutput("role", t.res)
+	if err != nil {
+		return "", err
+	}
+	return parseRole(s), nil
+}
+
+// CStates returns the state of the connection to each peer.
+func (t T) CStates() ([]string, error) {
+	s, err := t.drbdadmOutput("cstate", t.res)
+	if err != nil {
+		return nil, err
+	}
+	return parseLines(s), nil
+}
+
+// DStates returns the state of the local disk and the state of the
+// peers disk, as seen from the local node.
+func (t T) DStates() (string, []string, error) {
+	s, err := t.drbdadmOutput("dstate", t.res)
+	if err != nil {
+		return "", nil, err
+	}
+	local, peers := parseDStates(s)
+	return local, peers, nil
+}
+
+func parseRole(s string) string {
+	l := strings.SplitN(strings.TrimSpace(s), "/", 2)
+	return l[0]
+}
+
+func parseLines(s string) []string {
+	l := make([]string, 0)
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		l = append(l, line)
+	}
+	return l
+}
+
+func parseDStates(s string) (string, []string) {
+	var local string
+	peers := make([]string, 0)
+	for i, line := range parseLines(s) {
+		l := strings.Split(line, "/")
+		if i == 0 {
+			local = l[0]
+		}
+		peers = append(peers, l[1:]...)
+	}
+	return local, peers
+}
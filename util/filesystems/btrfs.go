@@ -0,0 +1,81 @@
+package filesystems
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/btrfs"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	T_Btrfs struct{ T }
+)
+
+func init() {
+	registerFS(NewBtrfs())
+}
+
+func NewBtrfs() *T_Btrfs {
+	t := T_Btrfs{
+		T{fsType: "btrfs", isMultiDevice: true},
+	}
+	return &t
+}
+
+func (t T_Btrfs) CanFSCK() error {
+	if _, err := exec.LookPath("btrfs"); err != nil {
+		return err
+	}
+	return nil
+}
+
+// FSCK checks the btrfs filesystem on <s> without modifying it.
+func (t T_Btrfs) FSCK(s string) error {
+	cmd := command.New(
+		command.WithName("btrfs"),
+		command.WithVarArgs("check", "--readonly", s),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+func (t T_Btrfs) IsFormated(s string) (bool, error) {
+	return isFormated(s)
+}
+
+// MemberDevices returns the paths of all the devices of the multi-device
+// filesystem <s> is a member of.
+func (t T_Btrfs) MemberDevices(s string) ([]string, error) {
+	info, err := btrfs.FilesystemShow(s, t.log)
+	if err != nil {
+		return nil, err
+	}
+	return info.Devices, nil
+}
+
+func (t T_Btrfs) MKFS(devpath string, args []string) error {
+	if _, err := exec.LookPath("mkfs.btrfs"); err != nil {
+		return fmt.Errorf("mkfs.btrfs not found")
+	}
+	cmd := command.New(
+		command.WithName("mkfs.btrfs"),
+		command.WithArgs(append(append([]string{}, args...), "-f", "-q", devpath)),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+func (t T_Btrfs) IsCapable() bool {
+	if _, err := exec.LookPath("mkfs.btrfs"); err != nil {
+		return false
+	}
+	return true
+}
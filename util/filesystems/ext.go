@@ -1,7 +1,6 @@
 package filesystems
 
 import (
-	"errors"
 	"fmt"
 	"os/exec"
 
@@ -35,28 +34,13 @@ func extFSCK(s string) error {
 	}
 }
 
-func extIsFormated(s string) (bool, error) {
-	if _, err := exec.LookPath("tune2fs"); err != nil {
-		return false, errors.New("tune2fs not found")
-	}
-	cmd := exec.Command("tune2fs", "-l", s)
-	cmd.Start()
-	cmd.Wait()
-	exitCode := cmd.ProcessState.ExitCode()
-	switch exitCode {
-	case 0: // All good
-		return true, nil
-	default:
-		return false, nil
-	}
-}
-
 func xMKFS(x string, s string, xargs []string, log *zerolog.Logger) error {
 	if _, err := exec.LookPath(x); err != nil {
 		return fmt.Errorf("%s not found", x)
 	}
-	args := []string{"-F", "-q", s}
+	args := []string{"-F", "-q"}
 	args = append(args, xargs...)
+	args = append(args, s)
 	cmd := command.New(
 		command.WithName(x),
 		command.WithArgs(args),
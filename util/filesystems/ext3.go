@@ -24,7 +24,7 @@ func (t T_Ext3) FSCK(s string) error {
 }
 
 func (t T_Ext3) IsFormated(s string) (bool, error) {
-	return extIsFormated(s)
+	return isFormated(s)
 }
 
 func (t T_Ext3) MKFS(s string, args []string) error {
@@ -24,7 +24,7 @@ func (t T_Ext4) FSCK(s string) error {
 }
 
 func (t T_Ext4) IsFormated(s string) (bool, error) {
-	return extIsFormated(s)
+	return isFormated(s)
 }
 
 func (t T_Ext4) MKFS(s string, args []string) error {
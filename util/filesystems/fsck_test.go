@@ -0,0 +1,79 @@
+package filesystems
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"opensvc.com/opensvc/util/device"
+)
+
+func TestFSCK(t *testing.T) {
+	cases := []struct {
+		fsType string
+		name   string
+		args   string
+	}{
+		{fsType: "xfs", name: "xfs_repair", args: "-n /dev/fake"},
+		{fsType: "btrfs", name: "btrfs", args: "check --readonly /dev/fake"},
+	}
+	for _, c := range cases {
+		t.Run(c.fsType, func(t *testing.T) {
+			dir := t.TempDir()
+			logFile := filepath.Join(dir, "log")
+			script := fmt.Sprintf("#!/bin/sh\necho \"$*\" >>%s\n[ \"$FAKE_FSCK_FAIL\" = \"\" ]\n", logFile)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, c.name), []byte(script), 0755))
+			t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+			fs := FromType(c.fsType)
+			require.True(t, HasFSCK(fs))
+			require.NoError(t, CanFSCK(fs))
+			require.NoError(t, fs.(FSCKer).FSCK("/dev/fake"))
+			b, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			require.Equal(t, c.args, strings.TrimSpace(string(b)))
+
+			t.Setenv("FAKE_FSCK_FAIL", "1")
+			require.Error(t, fs.(FSCKer).FSCK("/dev/fake"))
+		})
+	}
+}
+
+type fakeSubDeviceLister []string
+
+func (t fakeSubDeviceLister) SubDevices() device.L {
+	l := make(device.L, 0)
+	for _, p := range t {
+		l = append(l, device.New(p))
+	}
+	return l
+}
+
+func TestDevicesFSCK(t *testing.T) {
+	cases := []struct {
+		fsType string
+		name   string
+		calls  []string
+	}{
+		{fsType: "xfs", name: "xfs_repair", calls: []string{"-n /dev/fake1", "-n /dev/fake2"}},
+		{fsType: "btrfs", name: "btrfs", calls: []string{"check --readonly /dev/fake1"}},
+	}
+	for _, c := range cases {
+		t.Run(c.fsType, func(t *testing.T) {
+			dir := t.TempDir()
+			logFile := filepath.Join(dir, "log")
+			script := fmt.Sprintf("#!/bin/sh\necho \"$*\" >>%s\n", logFile)
+			require.NoError(t, os.WriteFile(filepath.Join(dir, c.name), []byte(script), 0755))
+			t.Setenv("PATH", dir+":"+os.Getenv("PATH"))
+
+			fs := FromType(c.fsType)
+			require.NoError(t, DevicesFSCK(fs, fakeSubDeviceLister{"/dev/fake1", "/dev/fake2"}))
+			b, err := os.ReadFile(logFile)
+			require.NoError(t, err)
+			require.Equal(t, c.calls, strings.Split(strings.TrimSpace(string(b)), "\n"))
+		})
+	}
+}
@@ -54,6 +54,9 @@ type (
 	MKFSer interface {
 		MKFS(string, []string) error
 	}
+	MemberDeviceser interface {
+		MemberDevices(string) ([]string, error)
+	}
 )
 
 var (
@@ -66,8 +69,6 @@ func init() {
 	registerFS(&T{fsType: "none", isFileBacked: true})
 	registerFS(&T{fsType: "bind", isFileBacked: true})
 	registerFS(&T{fsType: "lofs", isFileBacked: true})
-	registerFS(&T{fsType: "btrfs", isMultiDevice: true})
-	registerFS(&T{fsType: "vfat"})
 	registerFS(&T{fsType: "reiserfs"})
 	registerFS(&T{fsType: "jfs"})
 	registerFS(&T{fsType: "jfs2"})
@@ -173,12 +174,18 @@ func HasFSCK(fs any) bool {
 	return ok
 }
 
+// DevicesFSCK checks the filesystem on each device returned by the
+// SubDevices() method of <dl>. A multi-device filesystem is checked once,
+// through its first member device.
 func DevicesFSCK(fs any, dl subDeviceLister) error {
 	i, ok := fs.(FSCKer)
 	if !ok {
 		return nil
 	}
 	devices := dl.SubDevices()
+	if m, ok := fs.(I); ok && m.IsMultiDevice() && len(devices) > 1 {
+		devices = devices[:1]
+	}
 	for _, dev := range devices {
 		if err := i.FSCK(dev.Path()); err != nil {
 			return err
@@ -187,6 +194,8 @@ func DevicesFSCK(fs any, dl subDeviceLister) error {
 	return nil
 }
 
+// DevicesFormated returns true if all the devices returned by the
+// SubDevices() method of <dl> have a filesystem signature.
 func DevicesFormated(fs any, dl subDeviceLister) (bool, error) {
 	i, ok := fs.(IsFormateder)
 	if !ok {
@@ -208,6 +217,16 @@ func DevicesFormated(fs any, dl subDeviceLister) (bool, error) {
 	return true, nil
 }
 
+// MemberDevices returns the paths of the devices hosting the filesystem
+// found on <devpath>. Single-device filesystem types return <devpath>.
+func MemberDevices(fs any, devpath string) ([]string, error) {
+	i, ok := fs.(MemberDeviceser)
+	if !ok {
+		return []string{devpath}, nil
+	}
+	return i.MemberDevices(devpath)
+}
+
 func FromType(s string) I {
 	if t, ok := db[s]; ok {
 		return t.(I)
@@ -0,0 +1,304 @@
+package filesystems
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+type (
+	// superblock is a prefix of a block device or image file, large
+	// enough to contain the superblocks of all the probed filesystems.
+	superblock []byte
+
+	prober struct {
+		fsType string
+		match  func(superblock) bool
+	}
+)
+
+const (
+	// probeSize is the number of bytes to read from the device head.
+	// The first zfs uberblock is the farthest, at 128k.
+	probeSize = 0x20000 + 0x1000
+
+	// mdTailSize is the number of bytes to read from the device tail,
+	// where the 0.90 and 1.0 md raid superblocks are.
+	mdTailSize = 0x20000
+
+	mdMagic  = 0xa92b4efc
+	zfsMagic = 0x00bab10c
+
+	extSuperblockOffset = 0x400
+
+	extFeatureCompatHasJournal   = 0x0004
+	extFeatureIncompatExtents    = 0x0040
+	extFeatureIncompat64Bit      = 0x0080
+	extFeatureIncompatFlexBG     = 0x0200
+	extFeatureIncompatJournalDev = 0x0008
+)
+
+var (
+	// probers are tried in order. The most specific signatures must
+	// come first.
+	probers = []prober{
+		{fsType: "xfs", match: isXFS},
+		{fsType: "btrfs", match: isBtrfs},
+		{fsType: "ext4", match: isExt4},
+		{fsType: "ext3", match: isExt3},
+		{fsType: "ext2", match: isExt2},
+		{fsType: "vfat", match: isVfat},
+		{fsType: "ntfs", match: isNTFS},
+		{fsType: "jbd", match: isExtJournalDev},
+
+		// not filesystems, but signatures of data a mkfs would destroy
+		{fsType: "LVM2_member", match: isLVM2},
+		{fsType: "crypto_LUKS", match: isLUKS},
+		{fsType: "swap", match: isSwap},
+		{fsType: "linux_raid_member", match: isMDRaid},
+		{fsType: "zfs_member", match: isZFS},
+	}
+)
+
+func (t superblock) has(offset int, magic []byte) bool {
+	end := offset + len(magic)
+	if end > len(t) {
+		return false
+	}
+	return bytes.Equal(t[offset:end], magic)
+}
+
+func (t superblock) le16(offset int) uint16 {
+	if offset+2 > len(t) {
+		return 0
+	}
+	return binary.LittleEndian.Uint16(t[offset : offset+2])
+}
+
+func (t superblock) le32(offset int) uint32 {
+	if offset+4 > len(t) {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(t[offset : offset+4])
+}
+
+func isXFS(sb superblock) bool {
+	return sb.has(0, []byte("XFSB"))
+}
+
+func isBtrfs(sb superblock) bool {
+	return sb.has(0x10040, []byte("_BHRfS_M"))
+}
+
+func isExt(sb superblock) bool {
+	return sb.le16(extSuperblockOffset+0x38) == 0xEF53
+}
+
+func extFeatureCompat(sb superblock) uint32 {
+	return sb.le32(extSuperblockOffset + 0x5C)
+}
+
+func extFeatureIncompat(sb superblock) uint32 {
+	return sb.le32(extSuperblockOffset + 0x60)
+}
+
+func isExt4(sb superblock) bool {
+	if !isExt(sb) {
+		return false
+	}
+	incompat := extFeatureIncompat(sb)
+	if incompat&extFeatureIncompatJournalDev != 0 {
+		return false
+	}
+	return incompat&(extFeatureIncompatExtents|extFeatureIncompat64Bit|extFeatureIncompatFlexBG) != 0
+}
+
+func isExt3(sb superblock) bool {
+	if !isExt(sb) || isExt4(sb) {
+		return false
+	}
+	return extFeatureCompat(sb)&extFeatureCompatHasJournal != 0
+}
+
+func isExt2(sb superblock) bool {
+	if !isExt(sb) || isExt4(sb) || isExt3(sb) {
+		return false
+	}
+	return extFeatureIncompat(sb)&extFeatureIncompatJournalDev == 0
+}
+
+func isVfat(sb superblock) bool {
+	if !sb.has(0x1FE, []byte{0x55, 0xAA}) {
+		return false
+	}
+	return sb.has(0x36, []byte("FAT12")) || sb.has(0x36, []byte("FAT16")) || sb.has(0x52, []byte("FAT32")) || sb.has(0x03, []byte("MSDOS")) || sb.has(0x03, []byte("mkfs.fat")) || sb.has(0x03, []byte("mkdosfs"))
+}
+
+// isExtJournalDev detects an ext3/ext4 external journal device.
+func isExtJournalDev(sb superblock) bool {
+	return isExt(sb) && extFeatureIncompat(sb)&extFeatureIncompatJournalDev != 0
+}
+
+func isNTFS(sb superblock) bool {
+	return sb.has(0x03, []byte("NTFS    "))
+}
+
+// isLVM2 detects a LVM2 physical volume label, in one of the first four
+// sectors.
+func isLVM2(sb superblock) bool {
+	for sector := 0; sector < 4; sector++ {
+		offset := sector * 0x200
+		if sb.has(offset, []byte("LABELONE")) && sb.has(offset+0x18, []byte("LVM2 001")) {
+			return true
+		}
+	}
+	return false
+}
+
+// isLUKS detects the LUKS1 and LUKS2 headers.
+func isLUKS(sb superblock) bool {
+	return sb.has(0, []byte{'L', 'U', 'K', 'S', 0xBA, 0xBE})
+}
+
+// isSwap detects a swap signature, at the end of the first page for the
+// supported page sizes.
+func isSwap(sb superblock) bool {
+	for _, pageSize := range []int{0x1000, 0x2000, 0x4000, 0x10000} {
+		if sb.has(pageSize-10, []byte("SWAPSPACE2")) || sb.has(pageSize-10, []byte("SWAP-SPACE")) {
+			return true
+		}
+	}
+	return false
+}
+
+// isMDRaid detects the 1.1 and 1.2 md raid superblocks, at 0 and 4k. The
+// 0.90 and 1.0 superblocks, at the device tail, are detected by isMDRaidTail.
+func isMDRaid(sb superblock) bool {
+	return sb.le32(0) == mdMagic || sb.le32(0x1000) == mdMagic
+}
+
+// isZFS detects the first uberblock of the first zfs vdev label.
+func isZFS(sb superblock) bool {
+	const offset = 0x20000
+	if offset+8 > len(sb) {
+		return false
+	}
+	b := sb[offset : offset+8]
+	return binary.LittleEndian.Uint64(b) == zfsMagic || binary.BigEndian.Uint64(b) == zfsMagic
+}
+
+// isMDRaidTail detects the 0.90 and 1.0 md raid superblocks in <tail>, the
+// last bytes of a device of <size> bytes.
+func isMDRaidTail(tail []byte, size int64) bool {
+	at := func(offset int64) bool {
+		i := offset - (size - int64(len(tail)))
+		if i < 0 || i+4 > int64(len(tail)) {
+			return false
+		}
+		return binary.LittleEndian.Uint32(tail[i:i+4]) == mdMagic || binary.BigEndian.Uint32(tail[i:i+4]) == mdMagic
+	}
+	// 0.90: 64k aligned, in the last 64k-128k
+	if size >= 0x20000 && at((size&^0xFFFF)-0x10000) {
+		return true
+	}
+	// 1.0: 4k aligned, 8k from the end
+	if size >= 0x2000 && at((size-0x2000)&^0xFFF) {
+		return true
+	}
+	return false
+}
+
+func readSuperblock(devpath string) (superblock, error) {
+	f, err := os.Open(devpath)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	b := make([]byte, probeSize)
+	n, err := io.ReadFull(f, b)
+	switch err {
+	case nil, io.EOF, io.ErrUnexpectedEOF:
+		return superblock(b[:n]), nil
+	default:
+		return nil, err
+	}
+}
+
+// readTail returns the last bytes of <devpath> and its size. Seeking to
+// the end also works for block devices, whose stat size is zero.
+func readTail(devpath string) ([]byte, int64, error) {
+	f, err := os.Open(devpath)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+	size, err := f.Seek(0, io.SeekEnd)
+	if err != nil {
+		return nil, 0, err
+	}
+	offset := size - mdTailSize
+	if offset < 0 {
+		offset = 0
+	}
+	b := make([]byte, size-offset)
+	if _, err := f.ReadAt(b, offset); err != nil && err != io.EOF {
+		return nil, 0, err
+	}
+	return b, size, nil
+}
+
+// Probe returns the type of the filesystem or of the other data found on
+// the block device or image file <devpath>, detected from the superblock
+// magic, using the blkid type names. An empty string is returned if no
+// known signature is found.
+func Probe(devpath string) (string, error) {
+	sb, err := readSuperblock(devpath)
+	if err != nil {
+		return "", err
+	}
+	return probe(devpath, sb)
+}
+
+func probe(devpath string, sb superblock) (string, error) {
+	for _, p := range probers {
+		if p.match(sb) {
+			return p.fsType, nil
+		}
+	}
+	tail, size, err := readTail(devpath)
+	if err != nil {
+		return "", err
+	}
+	if isMDRaidTail(tail, size) {
+		return "linux_raid_member", nil
+	}
+	return "", nil
+}
+
+// isFormated returns true if a known signature is found on <devpath>,
+// whatever its type. Callers use this to decide if a mkfs is safe, so a
+// signature of a foreign type must also prevent the format.
+//
+// An error is returned if the device head contains data with no known
+// signature, as formatting it could also destroy data.
+func isFormated(devpath string) (bool, error) {
+	sb, err := readSuperblock(devpath)
+	if err != nil {
+		return false, err
+	}
+	fsType, err := probe(devpath, sb)
+	if err != nil {
+		return false, err
+	}
+	if fsType != "" {
+		return true, nil
+	}
+	for _, b := range sb {
+		if b != 0 {
+			return false, fmt.Errorf("%s head contains data with no known signature: refuse to format, zero the first %d bytes to allow", devpath, probeSize)
+		}
+	}
+	return false, nil
+}
@@ -0,0 +1,103 @@
+package filesystems
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestProbe(t *testing.T) {
+	ext := func(compat, incompat uint32) []byte {
+		b := make([]byte, 4096)
+		binary.LittleEndian.PutUint16(b[extSuperblockOffset+0x38:], 0xEF53)
+		binary.LittleEndian.PutUint32(b[extSuperblockOffset+0x5C:], compat)
+		binary.LittleEndian.PutUint32(b[extSuperblockOffset+0x60:], incompat)
+		return b
+	}
+	xfs := func() []byte {
+		b := make([]byte, 4096)
+		copy(b, "XFSB")
+		return b
+	}
+	btrfs := func() []byte {
+		b := make([]byte, probeSize)
+		copy(b[0x10040:], "_BHRfS_M")
+		return b
+	}
+	vfat := func() []byte {
+		b := make([]byte, 4096)
+		copy(b[0x03:], "mkfs.fat")
+		copy(b[0x52:], "FAT32   ")
+		b[0x1FE] = 0x55
+		b[0x1FF] = 0xAA
+		return b
+	}
+	at := func(size, offset int, magic []byte) []byte {
+		b := make([]byte, size)
+		copy(b[offset:], magic)
+		return b
+	}
+	le32 := func(size, offset int, v uint32) []byte {
+		b := make([]byte, size)
+		binary.LittleEndian.PutUint32(b[offset:], v)
+		return b
+	}
+	lvm2 := func() []byte {
+		b := at(4096, 0x200, []byte("LABELONE"))
+		copy(b[0x218:], "LVM2 001")
+		return b
+	}
+	zfs := func() []byte {
+		b := make([]byte, probeSize)
+		binary.LittleEndian.PutUint64(b[0x20000:], zfsMagic)
+		return b
+	}
+	cases := map[string]struct {
+		data     []byte
+		expected string
+		unknown  bool
+	}{
+		"empty":       {data: make([]byte, 8192), expected: ""},
+		"short":       {data: []byte("XF"), expected: "", unknown: true},
+		"garbage":     {data: at(8192, 100, []byte("some data")), expected: "", unknown: true},
+		"ext2":        {data: ext(0, 0x0002), expected: "ext2"},
+		"ext3":        {data: ext(extFeatureCompatHasJournal, 0x0002), expected: "ext3"},
+		"ext4":        {data: ext(extFeatureCompatHasJournal, 0x0002|extFeatureIncompatExtents|extFeatureIncompatFlexBG), expected: "ext4"},
+		"ext-jdev":    {data: ext(0, extFeatureIncompatJournalDev), expected: "jbd"},
+		"xfs":         {data: xfs(), expected: "xfs"},
+		"btrfs":       {data: btrfs(), expected: "btrfs"},
+		"vfat":        {data: vfat(), expected: "vfat"},
+		"ntfs":        {data: at(4096, 0x03, []byte("NTFS    ")), expected: "ntfs"},
+		"lvm2":        {data: lvm2(), expected: "LVM2_member"},
+		"luks":        {data: at(4096, 0, []byte{'L', 'U', 'K', 'S', 0xBA, 0xBE}), expected: "crypto_LUKS"},
+		"swap":        {data: at(8192, 4096-10, []byte("SWAPSPACE2")), expected: "swap"},
+		"mdraid-1.2":  {data: le32(8192, 0x1000, mdMagic), expected: "linux_raid_member"},
+		"mdraid-0.90": {data: le32(0x100000, 0x100000-0x10000, mdMagic), expected: "linux_raid_member"},
+		"mdraid-1.0":  {data: le32(0x100000, 0x100000-0x2000, mdMagic), expected: "linux_raid_member"},
+		"zfs":         {data: zfs(), expected: "zfs_member"},
+	}
+	dir := t.TempDir()
+	for name, c := range cases {
+		t.Run(name, func(t *testing.T) {
+			p := filepath.Join(dir, name)
+			require.Nil(t, os.WriteFile(p, c.data, 0600))
+			fsType, err := Probe(p)
+			require.Nil(t, err)
+			require.Equal(t, c.expected, fsType)
+			v, err := isFormated(p)
+			if c.unknown {
+				require.NotNil(t, err, "unknown data must refuse the format")
+				return
+			}
+			require.Nil(t, err)
+			require.Equal(t, c.expected != "", v)
+		})
+	}
+	t.Run("missing", func(t *testing.T) {
+		_, err := Probe(filepath.Join(dir, "missing"))
+		require.NotNil(t, err)
+	})
+}
@@ -0,0 +1,67 @@
+package filesystems
+
+import (
+	"fmt"
+	"os/exec"
+
+	"github.com/rs/zerolog"
+	"opensvc.com/opensvc/util/command"
+)
+
+type (
+	T_Vfat struct{ T }
+)
+
+func init() {
+	registerFS(NewVfat())
+}
+
+func NewVfat() *T_Vfat {
+	t := T_Vfat{
+		T{fsType: "vfat"},
+	}
+	return &t
+}
+
+func (t T_Vfat) CanFSCK() error {
+	if _, err := exec.LookPath("fsck.vfat"); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (t T_Vfat) FSCK(s string) error {
+	cmd := exec.Command("fsck.vfat", "-a", s)
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	_ = cmd.Wait()
+	exitCode := cmd.ProcessState.ExitCode()
+	switch exitCode {
+	case 0: // All good
+		return nil
+	case 1: // File system errors corrected
+		return nil
+	default:
+		return fmt.Errorf("%s exit code: %d", cmd, exitCode)
+	}
+}
+
+func (t T_Vfat) IsFormated(s string) (bool, error) {
+	return isFormated(s)
+}
+
+func (t T_Vfat) MKFS(devpath string, args []string) error {
+	if _, err := exec.LookPath("mkfs.vfat"); err != nil {
+		return fmt.Errorf("mkfs.vfat not found")
+	}
+	cmd := command.New(
+		command.WithName("mkfs.vfat"),
+		command.WithArgs(append(append([]string{}, args...), devpath)),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.InfoLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
@@ -1,7 +1,6 @@
 package filesystems
 
 import (
-	"errors"
 	"fmt"
 	"os/exec"
 
@@ -24,20 +23,29 @@ func NewXFS() *T_XFS {
 	return &t
 }
 
-func (t T_XFS) IsFormated(s string) (bool, error) {
-	if _, err := exec.LookPath("xfs_admin"); err != nil {
-		return false, errors.New("xfs_admin not found")
-	}
-	cmd := exec.Command("xfs_admin", "-l", s)
-	cmd.Start()
-	cmd.Wait()
-	exitCode := cmd.ProcessState.ExitCode()
-	switch exitCode {
-	case 0: // All good
-		return true, nil
-	default:
-		return false, nil
+func (t T_XFS) CanFSCK() error {
+	if _, err := exec.LookPath("xfs_repair"); err != nil {
+		return err
 	}
+	return nil
+}
+
+// FSCK checks the xfs filesystem on <s> without modifying it. xfs repairs
+// its log on mount, so a dirty filesystem is reported but not repaired.
+func (t T_XFS) FSCK(s string) error {
+	cmd := command.New(
+		command.WithName("xfs_repair"),
+		command.WithVarArgs("-n", s),
+		command.WithLogger(t.log),
+		command.WithCommandLogLevel(zerolog.InfoLevel),
+		command.WithStdoutLogLevel(zerolog.DebugLevel),
+		command.WithStderrLogLevel(zerolog.ErrorLevel),
+	)
+	return cmd.Run()
+}
+
+func (t T_XFS) IsFormated(s string) (bool, error) {
+	return isFormated(s)
 }
 
 func (t T_XFS) MKFS(devpath string, args []string) error {
@@ -46,7 +54,7 @@ func (t T_XFS) MKFS(devpath string, args []string) error {
 	}
 	cmd := command.New(
 		command.WithName("mkfs.xfs"),
-		command.WithArgs(append(args, "-f", "-q", devpath)),
+		command.WithArgs(append(append([]string{}, args...), "-f", "-q", devpath)),
 		command.WithLogger(t.log),
 		command.WithCommandLogLevel(zerolog.InfoLevel),
 		command.WithStdoutLogLevel(zerolog.InfoLevel),
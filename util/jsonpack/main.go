@@ -0,0 +1,241 @@
+// Package jsonpack provides a compact binary encoding of json documents.
+//
+// The packed document is a stream of tokens. A string is written once, and
+// referenced by its index in a string table on its later occurrences, which
+// makes the encoding efficient for the object keys and values repeated in
+// large documents. The integers are written as varints.
+//
+// Pack and Unpack preserve the document semantic, not its formatting, so the
+// unpacked document can be decoded by the usual json decoders, with their
+// custom unmarshalers.
+package jsonpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	tObjectBegin byte = iota + 1
+	tObjectEnd
+	tArrayBegin
+	tArrayEnd
+	tNull
+	tTrue
+	tFalse
+	tInt
+	tNumber
+	tString
+	tStringRef
+)
+
+type (
+	// frame is the state of a container during unpack.
+	frame struct {
+		object bool
+		n      int
+	}
+)
+
+var (
+	ErrMalformed = errors.New("malformed packed json")
+)
+
+// Pack returns the binary encoding of the json document b.
+func Pack(b []byte) ([]byte, error) {
+	var buf []byte
+	strings := make(map[string]uint64)
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			return buf, nil
+		} else if err != nil {
+			return nil, err
+		}
+		switch v := tok.(type) {
+		case json.Delim:
+			switch v {
+			case '{':
+				buf = append(buf, tObjectBegin)
+			case '}':
+				buf = append(buf, tObjectEnd)
+			case '[':
+				buf = append(buf, tArrayBegin)
+			case ']':
+				buf = append(buf, tArrayEnd)
+			}
+		case nil:
+			buf = append(buf, tNull)
+		case bool:
+			if v {
+				buf = append(buf, tTrue)
+			} else {
+				buf = append(buf, tFalse)
+			}
+		case json.Number:
+			s := v.String()
+			if i, err := strconv.ParseInt(s, 10, 64); err == nil && strconv.FormatInt(i, 10) == s {
+				buf = append(buf, tInt)
+				buf = binary.AppendVarint(buf, i)
+			} else {
+				buf = append(buf, tNumber)
+				buf = binary.AppendUvarint(buf, uint64(len(s)))
+				buf = append(buf, s...)
+			}
+		case string:
+			if i, ok := strings[v]; ok {
+				buf = append(buf, tStringRef)
+				buf = binary.AppendUvarint(buf, i)
+			} else {
+				strings[v] = uint64(len(strings))
+				buf = append(buf, tString)
+				buf = binary.AppendUvarint(buf, uint64(len(v)))
+				buf = append(buf, v...)
+			}
+		}
+	}
+}
+
+// Unpack returns the json document encoded by Pack in b.
+func Unpack(b []byte) ([]byte, error) {
+	var (
+		buf     bytes.Buffer
+		stack   []frame
+		strings []string
+	)
+	r := bytes.NewReader(b)
+
+	// separate writes the separator expected before the next key or value
+	separate := func() {
+		if len(stack) == 0 {
+			return
+		}
+		top := &stack[len(stack)-1]
+		switch {
+		case top.object && top.n%2 == 1:
+			buf.WriteByte(':')
+		case top.n > 0:
+			buf.WriteByte(',')
+		}
+		top.n++
+	}
+	end := func(object bool, c byte) error {
+		if len(stack) == 0 {
+			return ErrMalformed
+		}
+		top := stack[len(stack)-1]
+		if top.object != object || (object && top.n%2 == 1) {
+			return ErrMalformed
+		}
+		stack = stack[:len(stack)-1]
+		buf.WriteByte(c)
+		return nil
+	}
+	readString := func() (string, error) {
+		n, err := binary.ReadUvarint(r)
+		if err != nil {
+			return "", ErrMalformed
+		}
+		if n > uint64(r.Len()) {
+			return "", ErrMalformed
+		}
+		s := make([]byte, n)
+		if _, err := io.ReadFull(r, s); err != nil {
+			return "", ErrMalformed
+		}
+		return string(s), nil
+	}
+	writeString := func(s string) error {
+		q, err := json.Marshal(s)
+		if err != nil {
+			return err
+		}
+		buf.Write(q)
+		return nil
+	}
+
+	for {
+		t, err := r.ReadByte()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		switch t {
+		case tObjectEnd:
+			if err := end(true, '}'); err != nil {
+				return nil, err
+			}
+			continue
+		case tArrayEnd:
+			if err := end(false, ']'); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if len(stack) > 0 {
+			top := stack[len(stack)-1]
+			if top.object && top.n%2 == 0 && t != tString && t != tStringRef {
+				return nil, fmt.Errorf("%w: object key is not a string", ErrMalformed)
+			}
+		}
+		separate()
+		switch t {
+		case tObjectBegin:
+			stack = append(stack, frame{object: true})
+			buf.WriteByte('{')
+		case tArrayBegin:
+			stack = append(stack, frame{})
+			buf.WriteByte('[')
+		case tNull:
+			buf.WriteString("null")
+		case tTrue:
+			buf.WriteString("true")
+		case tFalse:
+			buf.WriteString("false")
+		case tInt:
+			i, err := binary.ReadVarint(r)
+			if err != nil {
+				return nil, ErrMalformed
+			}
+			buf.WriteString(strconv.FormatInt(i, 10))
+		case tNumber:
+			s, err := readString()
+			if err != nil {
+				return nil, err
+			}
+			if _, err := strconv.ParseFloat(s, 64); err != nil {
+				return nil, fmt.Errorf("%w: invalid number %s", ErrMalformed, s)
+			}
+			buf.WriteString(s)
+		case tString:
+			s, err := readString()
+			if err != nil {
+				return nil, err
+			}
+			strings = append(strings, s)
+			if err := writeString(s); err != nil {
+				return nil, err
+			}
+		case tStringRef:
+			i, err := binary.ReadUvarint(r)
+			if err != nil || i >= uint64(len(strings)) {
+				return nil, ErrMalformed
+			}
+			if err := writeString(strings[i]); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("%w: unexpected token %d", ErrMalformed, t)
+		}
+	}
+	if len(stack) > 0 {
+		return nil, fmt.Errorf("%w: unterminated container", ErrMalformed)
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,66 @@
+package jsonpack
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPackUnpack(t *testing.T) {
+	cases := map[string]string{
+		"empty object":  `{}`,
+		"empty array":   `[]`,
+		"scalars":       `[null,true,false,0,-1,42,9223372036854775807,1.5,-2e10,1.0,""]`,
+		"nested":        `{"a":{"b":[1,{"c":"d"}],"e":[]},"f":{}}`,
+		"repeated":      `[{"state":"idle","node":"n1"},{"state":"idle","node":"n2"},{"state":"up","node":"n1"}]`,
+		"escaped":       `{"q\"uo\\te":"<a&b>\n\té"}`,
+		"key as value":  `{"idle":"idle","x":["idle",{"idle":1}]}`,
+		"large integer": `[18446744073709551615,-9223372036854775809]`,
+	}
+	for name, doc := range cases {
+		t.Run(name, func(t *testing.T) {
+			packed, err := Pack([]byte(doc))
+			require.NoError(t, err)
+			unpacked, err := Unpack(packed)
+			require.NoError(t, err)
+			var expected, found any
+			require.NoError(t, json.Unmarshal([]byte(doc), &expected))
+			require.NoErrorf(t, json.Unmarshal(unpacked, &found), "unpacked: %s", unpacked)
+			assert.Equal(t, expected, found)
+		})
+	}
+}
+
+func TestPackSize(t *testing.T) {
+	l := make([]string, 0)
+	for i := 0; i < 100; i++ {
+		l = append(l, `{"avail":"up","overall":"up","frozen":false,"provisioned":true,"updated":1700000000}`)
+	}
+	doc := "[" + strings.Join(l, ",") + "]"
+	packed, err := Pack([]byte(doc))
+	require.NoError(t, err)
+	assert.Less(t, len(packed), len(doc)/3)
+}
+
+func TestUnpackMalformed(t *testing.T) {
+	cases := map[string][]byte{
+		"unterminated object":  {tObjectBegin},
+		"unexpected end":       {tArrayEnd},
+		"mismatched end":       {tObjectBegin, tArrayEnd},
+		"key not a string":     {tObjectBegin, tInt, 2, tInt, 2, tObjectEnd},
+		"missing object value": {tObjectBegin, tString, 1, 'a', tObjectEnd},
+		"bad string ref":       {tStringRef, 3},
+		"truncated string":     {tString, 10, 'a'},
+		"invalid number":       {tNumber, 3, '1', ',', '2'},
+		"unknown token":        {0xff},
+	}
+	for name, b := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := Unpack(b)
+			assert.ErrorIs(t, err, ErrMalformed)
+		})
+	}
+}
@@ -113,6 +113,13 @@ type (
 		resp     chan<- error
 	}
 
+	cmdSubDelFilter struct {
+		id       uuid.UUID
+		labels   labelMap
+		dataType string
+		resp     chan<- error
+	}
+
 	cmdSub struct {
 		name      string
 		resp      chan<- *Subscription
@@ -258,6 +265,8 @@ func (b *Bus) Start(ctx context.Context) {
 					b.onPubCmd(c)
 				case cmdSubAddFilter:
 					b.onSubAddFilter(c)
+				case cmdSubDelFilter:
+					b.onSubDelFilter(c)
 				case cmdSub:
 					b.onSubCmd(c)
 				case cmdUnsub:
@@ -339,6 +348,36 @@ func (b *Bus) onSubAddFilter(c cmdSubAddFilter) {
 	c.resp <- nil
 }
 
+func (b *Bus) onSubDelFilter(c cmdSubDelFilter) {
+	sub, ok := b.subs[c.id]
+	if !ok {
+		c.resp <- nil
+		return
+	}
+	del := filter{
+		dataType: c.dataType,
+		labels:   c.labels,
+	}
+	key := del.key()
+	filters := make([]filter, 0, len(sub.filters))
+	found := false
+	for _, f := range sub.filters {
+		if !found && f.key() == key {
+			found = true
+			continue
+		}
+		filters = append(filters, f)
+	}
+	sub.filters = filters
+	b.subs[c.id] = sub
+	b.subMap.Del(c.id, key)
+	if len(sub.filters) > 0 {
+		// keep the key routed if another filter uses it
+		b.subMap.Add(c.id, sub.keys()...)
+	}
+	c.resp <- nil
+}
+
 func (b *Bus) drain() {
 	b.log.Info().Msg("draining")
 	defer b.log.Info().Msg("drained")
@@ -549,6 +588,14 @@ func (cmd cmdSubAddFilter) String() string {
 	return s
 }
 
+func (cmd cmdSubDelFilter) String() string {
+	s := fmt.Sprintf("delete subscription %s filter type %s", cmd.id, cmd.dataType)
+	if len(cmd.labels) > 0 {
+		s += " with " + cmd.labels.String()
+	}
+	return s
+}
+
 func (cmd cmdSub) String() string {
 	s := fmt.Sprintf("subscribe '%s'", cmd.name)
 	return s
@@ -642,6 +689,32 @@ func (sub *Subscription) AddFilter(v any, labels ...Label) {
 	}
 }
 
+// DelFilter removes a filter added by AddFilter with the same type and
+// labels.
+func (sub *Subscription) DelFilter(v any, labels ...Label) {
+	respC := make(chan error)
+	op := cmdSubDelFilter{
+		id:     sub.id,
+		labels: newLabels(labels...),
+		resp:   respC,
+	}
+	dataType := reflect.TypeOf(v)
+	if dataType != nil {
+		op.dataType = dataType.String()
+	}
+	select {
+	case sub.bus.cmdC <- op:
+	case <-sub.bus.ctx.Done():
+		return
+	}
+	select {
+	case <-respC:
+		return
+	case <-sub.bus.ctx.Done():
+		return
+	}
+}
+
 func (sub *Subscription) Start() {
 	if len(sub.filters) == 0 {
 		// listen all until AddFilter is called
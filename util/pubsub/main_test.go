@@ -128,6 +128,33 @@ func TestSub(t *testing.T) {
 	}
 }
 
+func TestDelFilter(t *testing.T) {
+	bus := newRun(t.Name())
+	defer bus.Stop()
+	sub := bus.Sub(t.Name())
+	sub.AddFilter("", Label{"path", "a"})
+	sub.AddFilter("", Label{"path", "b"})
+	sub.Start()
+	defer func() {
+		assert.NoError(t, sub.Stop())
+	}()
+
+	sub.DelFilter("", Label{"path", "a"})
+	bus.Pub("to a", Label{"path", "a"})
+	bus.Pub("to b", Label{"path", "b"})
+	select {
+	case i := <-sub.C:
+		assert.Equal(t, "to b", i, "the deleted filter must not route publications")
+	case <-time.After(time.Second):
+		require.Fail(t, "publication not received")
+	}
+	select {
+	case i := <-sub.C:
+		assert.Fail(t, "unexpected publication", "%v", i)
+	case <-time.After(5 * time.Millisecond):
+	}
+}
+
 func TestDropSlowSubscription(t *testing.T) {
 	timeout := 10 * time.Millisecond
 	for x := 2; x < 5; x++ {
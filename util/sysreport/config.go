@@ -696,6 +696,13 @@ func (t *T) Do() error {
 	if err := t.updateStatsStat(); err != nil {
 		return err
 	}
+	if err := t.recordSnapshot(); err != nil {
+		return errors.Wrap(err, "record sysreport snapshot")
+	}
+	if t.collectorClient == nil {
+		srLog.Info().Msg("no collector client, skip push")
+		return nil
+	}
 	if err := t.send(); err != nil {
 		return err
 	}
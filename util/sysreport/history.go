@@ -0,0 +1,428 @@
+package sysreport
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hexops/gotextdiff"
+	"github.com/hexops/gotextdiff/myers"
+	"github.com/hexops/gotextdiff/span"
+	"github.com/pkg/errors"
+
+	"opensvc.com/opensvc/util/converters"
+)
+
+type (
+	// Snapshot is a local, timestamped copy of the collected tree. The
+	// files are indexed by their path relative to the collect dir, and
+	// valued by the checksum of their content in the history object store.
+	Snapshot struct {
+		Time  time.Time         `json:"time"`
+		Files map[string]string `json:"files"`
+	}
+
+	// Change describes the change of a collected file between two
+	// snapshots.
+	Change struct {
+		Path   string `json:"path"`
+		Change string `json:"change"`
+		Diff   string `json:"diff,omitempty"`
+	}
+
+	// Diff is the list of changes between two snapshots.
+	Diff struct {
+		From    time.Time `json:"from"`
+		To      time.Time `json:"to"`
+		Changes []Change  `json:"changes"`
+	}
+)
+
+const (
+	ChangeAdded   = "added"
+	ChangeChanged = "changed"
+	ChangeDeleted = "deleted"
+
+	snapshotTimeFormat = "20060102T150405.000000000Z"
+)
+
+var (
+	// MaxSnapshots is the number of local sysreport snapshots kept.
+	MaxSnapshots = 100
+
+	ErrNoSnapshot = errors.New("no sysreport snapshot")
+)
+
+func (t T) historyDir() string {
+	return filepath.Join(t.sysreportDir(), "history")
+}
+
+func (t T) historySnapshotDir() string {
+	return filepath.Join(t.historyDir(), "snapshots")
+}
+
+func (t T) historyObjectFile(sum string) string {
+	return filepath.Join(t.historyDir(), "objects", sum[:2], sum)
+}
+
+// snapshotFiles returns the collected files and command outputs, indexed
+// by their path relative to the collect dir. The stat cache is excluded, as
+// it changes on every collect.
+func (t T) snapshotFiles() (map[string]string, error) {
+	m := make(map[string]string)
+	head := t.collectDir()
+	for _, dir := range []string{t.collectCmdDir(), t.collectFileDir()} {
+		err := filepath.Walk(dir, func(path string, info fs.FileInfo, err error) error {
+			if err != nil {
+				if os.IsNotExist(err) {
+					return nil
+				}
+				return err
+			}
+			if !info.Mode().IsRegular() || path == t.collectStatFile() {
+				return nil
+			}
+			m[relPath(head+"/", path)] = path
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
+
+// storeObject adds the file content to the history object store, unless
+// already stored, and returns its checksum.
+func (t T) storeObject(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.Sum256(b)
+	sum := hex.EncodeToString(h[:])
+	p := t.historyObjectFile(sum)
+	if _, err := os.Stat(p); err == nil {
+		return sum, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return "", err
+	}
+	return sum, nil
+}
+
+// recordSnapshot adds the collected tree to the local history, unless it
+// is identical to the last snapshot, and drops the snapshots exceeding
+// MaxSnapshots.
+func (t T) recordSnapshot() error {
+	files, err := t.snapshotFiles()
+	if err != nil {
+		return errors.Wrap(err, "list collected files")
+	}
+	snap := Snapshot{
+		Time:  time.Now().UTC(),
+		Files: make(map[string]string),
+	}
+	for rel, path := range files {
+		sum, err := t.storeObject(path)
+		if err != nil {
+			return errors.Wrapf(err, "store %s", rel)
+		}
+		snap.Files[rel] = sum
+	}
+	snaps, err := t.snapshotTimes()
+	if err != nil {
+		return err
+	}
+	if n := len(snaps); n > 0 {
+		last, err := t.loadSnapshot(snaps[n-1])
+		if err != nil {
+			return err
+		}
+		if isSameFiles(last.Files, snap.Files) {
+			srLog.Debug().Msgf("no change since the %s snapshot", last.Time.Format(time.RFC3339))
+			return nil
+		}
+	}
+	b, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(t.historySnapshotDir(), 0700); err != nil {
+		return err
+	}
+	p := filepath.Join(t.historySnapshotDir(), snap.Time.Format(snapshotTimeFormat)+".json")
+	if err := os.WriteFile(p, b, 0600); err != nil {
+		return err
+	}
+	srLog.Info().Str("path", p).Int("files", len(snap.Files)).Msg("sysreport snapshot recorded")
+	snaps = append(snaps, snap.Time)
+	if len(snaps) > MaxSnapshots {
+		for _, tm := range snaps[:len(snaps)-MaxSnapshots] {
+			_ = os.Remove(filepath.Join(t.historySnapshotDir(), tm.Format(snapshotTimeFormat)+".json"))
+		}
+		return t.pruneObjects()
+	}
+	return nil
+}
+
+// pruneObjects removes the stored objects not referenced by any snapshot.
+func (t T) pruneObjects() error {
+	snaps, err := t.snapshotTimes()
+	if err != nil {
+		return err
+	}
+	used := make(map[string]any)
+	for _, tm := range snaps {
+		snap, err := t.loadSnapshot(tm)
+		if err != nil {
+			return err
+		}
+		for _, sum := range snap.Files {
+			used[sum] = nil
+		}
+	}
+	matches, err := filepath.Glob(filepath.Join(t.historyDir(), "objects", "*", "*"))
+	if err != nil {
+		return err
+	}
+	for _, p := range matches {
+		if _, ok := used[filepath.Base(p)]; !ok {
+			_ = os.Remove(p)
+		}
+	}
+	return nil
+}
+
+func isSameFiles(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for k, v := range a {
+		if b[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
+// snapshotTimes returns the times of the recorded snapshots, sorted from
+// the oldest to the newest.
+func (t T) snapshotTimes() ([]time.Time, error) {
+	l := make([]time.Time, 0)
+	matches, err := filepath.Glob(filepath.Join(t.historySnapshotDir(), "*.json"))
+	if err != nil {
+		return l, err
+	}
+	for _, p := range matches {
+		tm, err := time.Parse(snapshotTimeFormat, strings.TrimSuffix(filepath.Base(p), ".json"))
+		if err != nil {
+			continue
+		}
+		l = append(l, tm)
+	}
+	sort.Slice(l, func(i, j int) bool { return l[i].Before(l[j]) })
+	return l, nil
+}
+
+func (t T) loadSnapshot(tm time.Time) (Snapshot, error) {
+	var snap Snapshot
+	p := filepath.Join(t.historySnapshotDir(), tm.Format(snapshotTimeFormat)+".json")
+	b, err := os.ReadFile(p)
+	if err != nil {
+		return snap, err
+	}
+	err = json.Unmarshal(b, &snap)
+	return snap, err
+}
+
+// snapshotAt returns the last snapshot recorded at or before tm. The zero
+// time selects the last snapshot.
+func (t T) snapshotAt(tm time.Time) (Snapshot, error) {
+	snaps, err := t.snapshotTimes()
+	if err != nil {
+		return Snapshot{}, err
+	}
+	if len(snaps) == 0 {
+		return Snapshot{}, ErrNoSnapshot
+	}
+	if tm.IsZero() {
+		return t.loadSnapshot(snaps[len(snaps)-1])
+	}
+	for i := len(snaps) - 1; i >= 0; i-- {
+		if !snaps[i].After(tm) {
+			return t.loadSnapshot(snaps[i])
+		}
+	}
+	return Snapshot{}, errors.Wrapf(ErrNoSnapshot, "at or before %s", tm.Format(time.RFC3339))
+}
+
+func (t T) readObject(sum string) ([]byte, error) {
+	return os.ReadFile(t.historyObjectFile(sum))
+}
+
+// displayPath returns the user facing path of a snapshot file: the
+// original path of the collected files, and cmd/<name> for the collected
+// command outputs.
+func displayPath(rel string) string {
+	if s := strings.TrimPrefix(rel, "file/"); s != rel {
+		return "/" + s
+	}
+	return rel
+}
+
+// snapshotPath is the reverse of displayPath.
+func snapshotPath(s string) string {
+	if strings.HasPrefix(s, "/") {
+		return "file" + s
+	}
+	return s
+}
+
+// ParseTime returns the time represented by s, either a date, a date and
+// time, or a duration relative to now, like "1d" or "2h30m".
+func ParseTime(s string, now time.Time) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+	if d, err := converters.ParseDuration(s); err == nil {
+		return now.Add(-d), nil
+	}
+	if tm, err := time.Parse(time.RFC3339, s); err == nil {
+		return tm, nil
+	}
+	for _, layout := range []string{"2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if tm, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return tm, nil
+		}
+	}
+	return time.Time{}, errors.Errorf("invalid date or duration: %s", s)
+}
+
+// HistoryDiff returns the changes of the collected tree between the last
+// snapshot recorded at or before since and the last snapshot. The zero
+// since selects the snapshot before the last.
+func (t T) HistoryDiff(since time.Time) (Diff, error) {
+	var (
+		from, to Snapshot
+		err      error
+	)
+	if since.IsZero() {
+		snaps, err := t.snapshotTimes()
+		if err != nil {
+			return Diff{}, err
+		}
+		switch len(snaps) {
+		case 0:
+			return Diff{}, ErrNoSnapshot
+		case 1:
+			// diff against nothing: all files are added
+			from = Snapshot{Time: time.Time{}, Files: map[string]string{}}
+		default:
+			if from, err = t.loadSnapshot(snaps[len(snaps)-2]); err != nil {
+				return Diff{}, err
+			}
+		}
+	} else if from, err = t.snapshotAt(since); err != nil {
+		return Diff{}, err
+	}
+	if to, err = t.snapshotAt(time.Time{}); err != nil {
+		return Diff{}, err
+	}
+	diff := Diff{
+		From:    from.Time,
+		To:      to.Time,
+		Changes: make([]Change, 0),
+	}
+	keys := make(map[string]any)
+	for k := range from.Files {
+		keys[k] = nil
+	}
+	for k := range to.Files {
+		keys[k] = nil
+	}
+	for _, rel := range sortedKeys(keys) {
+		a, inFrom := from.Files[rel]
+		b, inTo := to.Files[rel]
+		change := Change{Path: displayPath(rel)}
+		switch {
+		case inFrom && inTo && a == b:
+			continue
+		case !inFrom:
+			change.Change = ChangeAdded
+		case !inTo:
+			change.Change = ChangeDeleted
+		default:
+			change.Change = ChangeChanged
+		}
+		var ab, bb []byte
+		if inFrom {
+			if ab, err = t.readObject(a); err != nil {
+				return diff, err
+			}
+		}
+		if inTo {
+			if bb, err = t.readObject(b); err != nil {
+				return diff, err
+			}
+		}
+		change.Diff = unifiedDiff(change.Path, ab, bb, from.Time, to.Time)
+		diff.Changes = append(diff.Changes, change)
+	}
+	return diff, nil
+}
+
+func unifiedDiff(path string, a, b []byte, aTime, bTime time.Time) string {
+	if bytes.IndexByte(a, 0) >= 0 || bytes.IndexByte(b, 0) >= 0 {
+		return "binary content differs\n"
+	}
+	aName := path + "@" + formatSnapshotTime(aTime)
+	bName := path + "@" + formatSnapshotTime(bTime)
+	edits := myers.ComputeEdits(span.URIFromPath(aName), string(a), string(b))
+	return fmt.Sprint(gotextdiff.ToUnified(aName, bName, string(a), edits))
+}
+
+func formatSnapshotTime(tm time.Time) string {
+	if tm.IsZero() {
+		return "-"
+	}
+	return tm.Local().Format(time.RFC3339)
+}
+
+// HistoryShow returns the content of the collected file or command output
+// in the snapshot recorded at or before tm. The zero tm selects the last
+// snapshot.
+func (t T) HistoryShow(path string, tm time.Time) ([]byte, error) {
+	snap, err := t.snapshotAt(tm)
+	if err != nil {
+		return nil, err
+	}
+	sum, ok := snap.Files[snapshotPath(path)]
+	if !ok {
+		return nil, errors.Errorf("%s not found in the %s snapshot", path, formatSnapshotTime(snap.Time))
+	}
+	return t.readObject(sum)
+}
+
+// Render returns the human representation of the diff.
+func (t Diff) Render() string {
+	s := fmt.Sprintf("from %s to %s\n", formatSnapshotTime(t.From), formatSnapshotTime(t.To))
+	if len(t.Changes) == 0 {
+		return s + "no change\n"
+	}
+	for _, c := range t.Changes {
+		s += fmt.Sprintf("\n%s %s\n%s", c.Change, c.Path, c.Diff)
+	}
+	return s
+}
@@ -0,0 +1,111 @@
+package sysreport
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistory(t *testing.T) {
+	sr := &T{varDir: t.TempDir()}
+	write := func(rel, s string) {
+		p := filepath.Join(sr.collectDir(), rel)
+		require.NoError(t, os.MkdirAll(filepath.Dir(p), 0700))
+		require.NoError(t, os.WriteFile(p, []byte(s), 0600))
+	}
+	snapshotCount := func() int {
+		l, err := sr.snapshotTimes()
+		require.NoError(t, err)
+		return len(l)
+	}
+
+	_, err := sr.HistoryDiff(time.Time{})
+	assert.ErrorIs(t, err, ErrNoSnapshot)
+
+	write("file/etc/hosts", "127.0.0.1 localhost\n")
+	write("file/etc/fstab", "/dev/sda1 / ext4\n")
+	write("file/stat", "[]")
+	write("cmd/lsmod", "dm_mod\n")
+	require.NoError(t, sr.recordSnapshot())
+	require.Equal(t, 1, snapshotCount())
+	first := time.Now()
+
+	t.Run("unchanged tree is not recorded", func(t *testing.T) {
+		write("file/stat", "[{}]")
+		require.NoError(t, sr.recordSnapshot())
+		assert.Equal(t, 1, snapshotCount())
+	})
+
+	time.Sleep(10 * time.Millisecond)
+	write("file/etc/hosts", "127.0.0.1 localhost\n10.0.0.1 db\n")
+	require.NoError(t, os.Remove(filepath.Join(sr.collectDir(), "file/etc/fstab")))
+	write("cmd/lsmod", "dm_mod\nxfs\n")
+	write("file/etc/resolv.conf", "nameserver 10.0.0.2\n")
+	require.NoError(t, sr.recordSnapshot())
+	require.Equal(t, 2, snapshotCount())
+
+	t.Run("diff since previous snapshot", func(t *testing.T) {
+		diff, err := sr.HistoryDiff(time.Time{})
+		require.NoError(t, err)
+		changes := make(map[string]string)
+		for _, c := range diff.Changes {
+			changes[c.Path] = c.Change
+		}
+		assert.Equal(t, map[string]string{
+			"/etc/hosts":       ChangeChanged,
+			"/etc/fstab":       ChangeDeleted,
+			"/etc/resolv.conf": ChangeAdded,
+			"cmd/lsmod":        ChangeChanged,
+		}, changes)
+		assert.Contains(t, diff.Render(), "+10.0.0.1 db")
+	})
+
+	t.Run("diff since date", func(t *testing.T) {
+		diff, err := sr.HistoryDiff(first)
+		require.NoError(t, err)
+		assert.Len(t, diff.Changes, 4)
+		_, err = sr.HistoryDiff(first.Add(-time.Hour))
+		assert.ErrorIs(t, err, ErrNoSnapshot)
+	})
+
+	t.Run("show", func(t *testing.T) {
+		b, err := sr.HistoryShow("/etc/hosts", first)
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1 localhost\n", string(b))
+		b, err = sr.HistoryShow("/etc/hosts", time.Time{})
+		require.NoError(t, err)
+		assert.Equal(t, "127.0.0.1 localhost\n10.0.0.1 db\n", string(b))
+		_, err = sr.HistoryShow("/etc/fstab", time.Time{})
+		assert.ErrorContains(t, err, "not found")
+	})
+
+	t.Run("prune", func(t *testing.T) {
+		MaxSnapshots = 1
+		defer func() { MaxSnapshots = 100 }()
+		write("file/etc/hosts", "127.0.0.1 localhost\n10.0.0.3 web\n")
+		require.NoError(t, sr.recordSnapshot())
+		assert.Equal(t, 1, snapshotCount())
+		objects, err := filepath.Glob(filepath.Join(sr.historyDir(), "objects", "*", "*"))
+		require.NoError(t, err)
+		assert.Len(t, objects, 3)
+	})
+}
+
+func TestParseTime(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.Local)
+	tm, err := ParseTime("1d", now)
+	require.NoError(t, err)
+	assert.Equal(t, now.Add(-24*time.Hour), tm)
+	tm, err = ParseTime("2024-03-01", now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2024, 3, 1, 0, 0, 0, 0, time.Local), tm)
+	tm, err = ParseTime("", now)
+	require.NoError(t, err)
+	assert.True(t, tm.IsZero())
+	_, err = ParseTime("yesterday", now)
+	assert.Error(t, err)
+}
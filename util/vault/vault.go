@@ -0,0 +1,245 @@
+// Package vault is a client of the HashiCorp Vault compatible key-value
+// secret stores, with token or AppRole authentication and a read cache.
+package vault
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+type (
+	// Client reads secrets from a Vault compatible server.
+	Client struct {
+		// URL is the server base url. ex: https://vault.example.com:8200
+		URL string
+
+		// Namespace is the optional enterprise namespace, sent as the
+		// X-Vault-Namespace header.
+		Namespace string
+
+		// Token authenticates the requests. If empty, the client logs in
+		// with RoleID and SecretID on the AppRoleMount auth method.
+		Token        string
+		RoleID       string
+		SecretID     string
+		AppRoleMount string
+
+		// TTL is the duration the read secrets are cached. Zero disables
+		// the cache.
+		TTL time.Duration
+
+		HTTPClient *http.Client
+
+		mu          sync.Mutex
+		token       string
+		tokenExpire time.Time
+		cache       map[string]cacheEntry
+	}
+
+	cacheEntry struct {
+		data    map[string]any
+		expires time.Time
+	}
+
+	response struct {
+		Data   map[string]any `json:"data"`
+		Errors []string       `json:"errors"`
+		Auth   *struct {
+			ClientToken   string `json:"client_token"`
+			LeaseDuration int    `json:"lease_duration"`
+		} `json:"auth"`
+	}
+)
+
+const (
+	// RefScheme is the prefix of the secret references.
+	RefScheme = "vault://"
+
+	// DefaultAppRoleMount is the default AppRole auth method mount path.
+	DefaultAppRoleMount = "approle"
+)
+
+var (
+	ErrNotFound = errors.New("secret not found")
+	ErrNoField  = errors.New("secret field not found")
+	ErrNoAuth   = errors.New("no token nor approle credentials")
+	ErrDenied   = errors.New("permission denied")
+)
+
+// IsRef returns true if s is a secret reference.
+func IsRef(s string) bool {
+	return strings.HasPrefix(s, RefScheme)
+}
+
+// ParseRef splits a <scheme><path>[#<field>] secret reference into its path
+// and field. The scheme is any "<name>://" prefix, so the parser also serves
+// the {safe://<path>#<field>} configuration references. Only the vault://
+// scheme is a sec key value reference, see IsRef.
+func ParseRef(ref string) (string, string, error) {
+	if i := strings.Index(ref, "://"); i >= 0 {
+		ref = ref[i+3:]
+	}
+	p, field, _ := strings.Cut(ref, "#")
+	p = strings.Trim(p, "/")
+	if p == "" {
+		return "", "", fmt.Errorf("invalid secret reference %s: empty path", ref)
+	}
+	return p, field, nil
+}
+
+// Resolve returns the value of the secret reference. A reference without
+// field resolves to the json encoded secret data.
+func (t *Client) Resolve(ref string) ([]byte, error) {
+	p, field, err := ParseRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	data, err := t.Read(p)
+	if err != nil {
+		return nil, err
+	}
+	if field == "" {
+		return json.Marshal(data)
+	}
+	v, ok := data[field]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s#%s", ErrNoField, p, field)
+	}
+	switch o := v.(type) {
+	case string:
+		return []byte(o), nil
+	default:
+		return json.Marshal(o)
+	}
+}
+
+// Read returns the data of the secret at the api path p, relative to /v1/.
+// The KV version 2 data is unwrapped, so the returned data is the secret
+// key-value map for both KV versions.
+func (t *Client) Read(p string) (map[string]any, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e, ok := t.cache[p]; ok && time.Now().Before(e.expires) {
+		return e.data, nil
+	}
+	token, err := t.getToken()
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.do(http.MethodGet, p, token, nil)
+	if errors.Is(err, ErrDenied) && t.Token == "" {
+		// the approle token may be revoked before its lease expiration:
+		// drop it, login again and retry once.
+		t.token = ""
+		if token, err = t.getToken(); err != nil {
+			return nil, err
+		}
+		resp, err = t.do(http.MethodGet, p, token, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	data := resp.Data
+	if inner, ok := data["data"].(map[string]any); ok {
+		if _, ok := data["metadata"]; ok {
+			data = inner
+		}
+	}
+	if t.TTL > 0 {
+		if t.cache == nil {
+			t.cache = make(map[string]cacheEntry)
+		}
+		t.cache[p] = cacheEntry{data: data, expires: time.Now().Add(t.TTL)}
+	}
+	return data, nil
+}
+
+// getToken returns the static token, or a token obtained from an AppRole
+// login, reused until its lease expires.
+func (t *Client) getToken() (string, error) {
+	if t.Token != "" {
+		return t.Token, nil
+	}
+	if t.token != "" && (t.tokenExpire.IsZero() || time.Now().Before(t.tokenExpire)) {
+		return t.token, nil
+	}
+	if t.RoleID == "" {
+		return "", ErrNoAuth
+	}
+	mount := t.AppRoleMount
+	if mount == "" {
+		mount = DefaultAppRoleMount
+	}
+	body, err := json.Marshal(map[string]string{
+		"role_id":   t.RoleID,
+		"secret_id": t.SecretID,
+	})
+	if err != nil {
+		return "", err
+	}
+	resp, err := t.do(http.MethodPost, "auth/"+mount+"/login", "", body)
+	if err != nil {
+		return "", fmt.Errorf("approle login: %w", err)
+	}
+	if resp.Auth == nil || resp.Auth.ClientToken == "" {
+		return "", fmt.Errorf("approle login: no client token in response")
+	}
+	t.token = resp.Auth.ClientToken
+	t.tokenExpire = time.Time{}
+	if resp.Auth.LeaseDuration > 0 {
+		t.tokenExpire = time.Now().Add(time.Duration(resp.Auth.LeaseDuration) * time.Second)
+	}
+	return t.token, nil
+}
+
+func (t *Client) do(method, p, token string, body []byte) (*response, error) {
+	u := strings.TrimRight(t.URL, "/") + "/v1/" + p
+	req, err := http.NewRequest(method, u, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("X-Vault-Token", token)
+	}
+	if t.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", t.Namespace)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	c := t.HTTPClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	r, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Body.Close()
+	b, err := io.ReadAll(r.Body)
+	if err != nil {
+		return nil, err
+	}
+	var resp response
+	if len(b) > 0 {
+		if err := json.Unmarshal(b, &resp); err != nil {
+			return nil, fmt.Errorf("%s %s: %d: %w", method, p, r.StatusCode, err)
+		}
+	}
+	switch {
+	case r.StatusCode == http.StatusNotFound:
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, p)
+	case r.StatusCode == http.StatusForbidden:
+		return nil, fmt.Errorf("%w: %s %s: %s", ErrDenied, method, p, strings.Join(resp.Errors, ", "))
+	case r.StatusCode >= 300:
+		return nil, fmt.Errorf("%s %s: %d: %s", method, p, r.StatusCode, strings.Join(resp.Errors, ", "))
+	}
+	return &resp, nil
+}
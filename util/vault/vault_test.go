@@ -0,0 +1,169 @@
+package vault
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newStandIn returns a local http server implementing the subset of the
+// Vault api used by the client, and the counter of secret reads.
+func newStandIn(t *testing.T) (*httptest.Server, *int) {
+	reads := 0
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if body["role_id"] != "role" || body["secret_id"] != "secret" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"errors":["invalid role or secret id"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"approle-token","lease_duration":3600}}`))
+	})
+	secret := func(body string) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			switch r.Header.Get("X-Vault-Token") {
+			case "static-token", "approle-token":
+			default:
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+				return
+			}
+			reads++
+			_, _ = w.Write([]byte(body))
+		}
+	}
+	mux.HandleFunc("/v1/secret/data/db", secret(`{"data":{"data":{"password":"s3cr3t","port":5432},"metadata":{"version":3}}}`))
+	mux.HandleFunc("/v1/kv/app", secret(`{"data":{"api_key":"abc"}}`))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = w.Write([]byte(`{"errors":[]}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+	return srv, &reads
+}
+
+func TestClientTokenRevoked(t *testing.T) {
+	logins := 0
+	token := ""
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/auth/approle/login", func(w http.ResponseWriter, r *http.Request) {
+		logins++
+		token = fmt.Sprintf("approle-token-%d", logins)
+		_, _ = w.Write([]byte(`{"auth":{"client_token":"` + token + `","lease_duration":3600}}`))
+	})
+	mux.HandleFunc("/v1/kv/app", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			w.WriteHeader(http.StatusForbidden)
+			_, _ = w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"api_key":"abc"}}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	c := &Client{URL: srv.URL, RoleID: "role", SecretID: "secret"}
+	_, err := c.Resolve("vault://kv/app#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, 1, logins)
+
+	// revoke the token before its lease expiration
+	token = "revoked"
+	b, err := c.Resolve("vault://kv/app#api_key")
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(b))
+	assert.Equal(t, 2, logins, "a denied read logs in again")
+
+	t.Run("a static token is not retried", func(t *testing.T) {
+		c := &Client{URL: srv.URL, Token: "static-token"}
+		_, err := c.Resolve("vault://kv/app#api_key")
+		assert.ErrorIs(t, err, ErrDenied)
+		assert.Equal(t, 2, logins)
+	})
+}
+
+func TestParseRef(t *testing.T) {
+	p, field, err := ParseRef("vault://secret/data/db#password")
+	require.NoError(t, err)
+	assert.Equal(t, "secret/data/db", p)
+	assert.Equal(t, "password", field)
+	p, field, err = ParseRef("safe://kv/app")
+	require.NoError(t, err)
+	assert.Equal(t, "kv/app", p)
+	assert.Equal(t, "", field)
+	_, _, err = ParseRef("vault://#password")
+	assert.Error(t, err)
+}
+
+func TestIsRef(t *testing.T) {
+	assert.True(t, IsRef("vault://secret/data/db#password"))
+	assert.False(t, IsRef("safe://secret/data/db#password"), "safe:// is a config reference, not a sec key value reference")
+	assert.False(t, IsRef("plain value"))
+}
+
+func TestClient(t *testing.T) {
+	srv, reads := newStandIn(t)
+
+	t.Run("token auth and kv version 2", func(t *testing.T) {
+		c := &Client{URL: srv.URL, Token: "static-token"}
+		b, err := c.Resolve("vault://secret/data/db#password")
+		require.NoError(t, err)
+		assert.Equal(t, "s3cr3t", string(b))
+		b, err = c.Resolve("vault://secret/data/db#port")
+		require.NoError(t, err)
+		assert.Equal(t, "5432", string(b))
+	})
+
+	t.Run("approle auth and kv version 1", func(t *testing.T) {
+		c := &Client{URL: srv.URL, RoleID: "role", SecretID: "secret"}
+		b, err := c.Resolve("vault://kv/app#api_key")
+		require.NoError(t, err)
+		assert.Equal(t, "abc", string(b))
+		b, err = c.Resolve("vault://kv/app")
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"api_key":"abc"}`, string(b))
+	})
+
+	t.Run("bad approle credentials", func(t *testing.T) {
+		c := &Client{URL: srv.URL, RoleID: "role", SecretID: "bad"}
+		_, err := c.Resolve("vault://kv/app#api_key")
+		assert.ErrorContains(t, err, "invalid role or secret id")
+	})
+
+	t.Run("no auth", func(t *testing.T) {
+		c := &Client{URL: srv.URL}
+		_, err := c.Resolve("vault://kv/app#api_key")
+		assert.ErrorIs(t, err, ErrNoAuth)
+	})
+
+	t.Run("missing secret and field", func(t *testing.T) {
+		c := &Client{URL: srv.URL, Token: "static-token"}
+		_, err := c.Resolve("vault://secret/data/nope#password")
+		assert.ErrorIs(t, err, ErrNotFound)
+		_, err = c.Resolve("vault://secret/data/db#user")
+		assert.ErrorIs(t, err, ErrNoField)
+	})
+
+	t.Run("cache", func(t *testing.T) {
+		c := &Client{URL: srv.URL, Token: "static-token", TTL: time.Minute}
+		before := *reads
+		for i := 0; i < 3; i++ {
+			_, err := c.Resolve("vault://secret/data/db#password")
+			require.NoError(t, err)
+		}
+		assert.Equal(t, before+1, *reads)
+		c.cache["secret/data/db"] = cacheEntry{data: c.cache["secret/data/db"].data, expires: time.Now().Add(-time.Second)}
+		_, err := c.Resolve("vault://secret/data/db#password")
+		require.NoError(t, err)
+		assert.Equal(t, before+2, *reads, "expired entries are read again")
+	})
+}